@@ -1,12 +1,16 @@
 package command
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/miraicantsleep/myits-event-be/constants"
 	"github.com/miraicantsleep/myits-event-be/migrations"
+	"github.com/miraicantsleep/myits-event-be/migrations/seeds"
 	"github.com/miraicantsleep/myits-event-be/script"
 	"github.com/samber/do"
 	"gorm.io/gorm"
@@ -18,45 +22,133 @@ func Commands(injector *do.Injector) bool {
 	var scriptName string
 
 	migrate := false
+	migrateStatus := false
+	migrateDown := 0
+	migrateCreate := ""
 	seed := false
+	seedSet := ""
+	scale := 0
 	run := false
 	scriptFlag := false
+	scriptList := false
+	dryRun := false
+	scriptArgs := map[string]string{}
 
-	for _, arg := range os.Args[1:] {
+	args := os.Args[1:]
+	for i, arg := range args {
 		if arg == "--migrate" {
 			migrate = true
 		}
+		if arg == "--migrate:status" {
+			migrateStatus = true
+		}
+		// --migrate:down N and --migrate:create name take the next argument
+		if arg == "--migrate:down" {
+			steps, err := strconv.Atoi(argAt(args, i+1))
+			if err != nil || steps < 1 {
+				fatal("usage: --migrate:down N, with N the number of migrations to roll back")
+			}
+			migrateDown = steps
+		}
+		if arg == "--migrate:create" {
+			migrateCreate = argAt(args, i+1)
+			if migrateCreate == "" {
+				fatal("usage: --migrate:create name")
+			}
+		}
 		if arg == "--seed" {
 			seed = true
 		}
+		// --seed:demo --scale=1000
+		if strings.HasPrefix(arg, "--seed:") {
+			seedSet = strings.TrimPrefix(arg, "--seed:")
+		}
+		if strings.HasPrefix(arg, "--scale=") {
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "--scale="))
+			if err != nil || n < 1 {
+				fatal("usage: --scale=N, with N the number of generated students")
+			}
+			scale = n
+		}
 		if arg == "--run" {
 			run = true
 		}
-		if strings.HasPrefix(arg, "--script:") {
+		if arg == "--script:list" {
+			scriptList = true
+		} else if strings.HasPrefix(arg, "--script:") {
 			scriptFlag = true
 			scriptName = strings.TrimPrefix(arg, "--script:")
 		}
+		if arg == "--dry-run" {
+			dryRun = true
+		}
+		// script arguments: --script:anonymise_old_users inactive_days=365
+		if key, value, ok := strings.Cut(arg, "="); ok && !strings.HasPrefix(arg, "--") {
+			scriptArgs[key] = value
+		}
+	}
+
+	if migrateCreate != "" {
+		paths, err := migrations.Create(migrateCreate)
+		if err != nil {
+			fatal("error creating migration", "error", err)
+		}
+		for _, path := range paths {
+			slog.Info("created migration", "path", path)
+		}
+	}
+
+	if migrateDown > 0 {
+		if err := migrations.Rollback(db, migrateDown); err != nil {
+			fatal("error rolling back migration", "error", err)
+		}
+		slog.Info("rollback completed successfully")
 	}
 
 	if migrate {
 		if err := migrations.Migrate(db); err != nil {
-			log.Fatalf("error migration: %v", err)
+			fatal("error migration", "error", err)
+		}
+		slog.Info("migration completed successfully")
+	}
+
+	if migrateStatus {
+		if err := migrations.Status(db); err != nil {
+			fatal("error migration status", "error", err)
 		}
-		log.Println("migration completed successfully")
 	}
 
 	if seed {
 		if err := migrations.Seeder(db); err != nil {
-			log.Fatalf("error migration seeder: %v", err)
+			fatal("error migration seeder", "error", err)
+		}
+		slog.Info("seeder completed successfully")
+	}
+
+	if seedSet != "" {
+		if err := migrations.SeedSet(db, seedSet, scale); err != nil {
+			if errors.Is(err, seeds.ErrUnknownSet) {
+				for _, set := range seeds.Sets() {
+					fmt.Printf("  --seed:%s\t%s\n", set.Name, set.Description)
+				}
+			}
+			fatal("error seeding", "set", seedSet, "error", err)
 		}
-		log.Println("seeder completed successfully")
+		slog.Info("seed set completed successfully", "set", seedSet)
+	}
+
+	if scriptList {
+		printScripts()
 	}
 
 	if scriptFlag {
-		if err := script.Script(scriptName, db); err != nil {
-			log.Fatalf("error script: %v", err)
+		if err := script.Run(db, scriptName, scriptArgs, dryRun); err != nil {
+			if errors.Is(err, script.ErrUnknownScript) {
+				printScripts()
+			}
+			fatal("error running script", "script", scriptName, "error", err)
 		}
-		log.Println("script run successfully")
+		slog.Info("script run successfully", "script", scriptName)
 	}
 
 	if run {
@@ -65,3 +157,25 @@ func Commands(injector *do.Injector) bool {
 
 	return false
 }
+
+func argAt(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+// fatal logs msg as an error and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+func printScripts() {
+	for _, s := range script.Scripts() {
+		fmt.Printf("  --script:%s\t%s\n", s.Name, s.Description)
+		for _, arg := range s.Args {
+			fmt.Printf("      %s=%s\t%s\n", arg.Name, arg.Default, arg.Description)
+		}
+	}
+}
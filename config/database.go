@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/miraicantsleep/myits-event-be/constants"
 	"github.com/joho/godotenv"
+	"github.com/miraicantsleep/myits-event-be/constants"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -34,7 +34,7 @@ func SetUpDatabaseConnection() *gorm.DB {
 		DSN:                  dsn,
 		PreferSimpleProtocol: true,
 	}), &gorm.Config{
-		Logger: SetupLogger(),
+		Logger: NewGormLogger(NewLogConfig().SlowQuery),
 	})
 	if err != nil {
 		panic(err)
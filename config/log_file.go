@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var logFileName = regexp.MustCompile(`^app-(\d{4}-\d{2}-\d{2})(?:\.(\d+))?\.log$`)
+
+type (
+	// RotatingFile writes to app-<date>.log in its directory, starting a new
+	// file every day and whenever the current one reaches maxSize, as
+	// app-<date>.1.log, app-<date>.2.log and so on. Files older than the
+	// retention are deleted whenever a file is opened. Every instance keeps
+	// its own files, so pruning is not left to a scheduled job.
+	RotatingFile struct {
+		dir       string
+		maxSize   int64
+		retention time.Duration
+
+		mu   sync.Mutex
+		file *os.File
+		day  string
+		seq  int
+		size int64
+	}
+
+	LogFile struct {
+		Path string
+		Day  time.Time
+		Seq  int
+	}
+)
+
+func NewRotatingFile(dir string, maxSize int64, retention time.Duration) (*RotatingFile, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	f := &RotatingFile{dir: dir, maxSize: maxSize, retention: retention}
+	if err := f.open(time.Now()); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	full := f.size > 0 && f.size+int64(len(p)) > f.maxSize
+	if now.Format(time.DateOnly) != f.day || full {
+		if err := f.open(now); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// open switches to the first file of the day with room left
+func (f *RotatingFile) open(now time.Time) error {
+	day := now.Format(time.DateOnly)
+	seq := 0
+	if day == f.day {
+		seq = f.seq + 1
+	}
+
+	for {
+		name := filepath.Join(f.dir, logFilePath(day, seq))
+		info, err := os.Stat(name)
+		if err == nil && info.Size() >= f.maxSize {
+			seq++
+			continue
+		}
+
+		file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		if f.file != nil {
+			f.file.Close()
+		}
+
+		f.file, f.day, f.seq, f.size = file, day, seq, 0
+		if info != nil {
+			f.size = info.Size()
+		}
+		break
+	}
+
+	f.prune(now)
+	return nil
+}
+
+func (f *RotatingFile) prune(now time.Time) {
+	files, err := LogFiles(f.dir)
+	if err != nil {
+		return
+	}
+
+	cutoff := now.Add(-f.retention)
+	for _, file := range files {
+		// a file holds a whole day, so it goes once the day is past the cutoff
+		if file.Day.AddDate(0, 0, 1).Before(cutoff) {
+			os.Remove(file.Path)
+		}
+	}
+}
+
+// LogFiles lists the log files in dir, oldest first
+func LogFiles(dir string) ([]LogFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var files []LogFile
+	for _, entry := range entries {
+		match := logFileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		day, err := time.ParseInLocation(time.DateOnly, match[1], time.Local)
+		if err != nil {
+			continue
+		}
+		seq, _ := strconv.Atoi(match[2])
+		files = append(files, LogFile{Path: filepath.Join(dir, entry.Name()), Day: day, Seq: seq})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].Day.Equal(files[j].Day) {
+			return files[i].Day.Before(files[j].Day)
+		}
+		return files[i].Seq < files[j].Seq
+	})
+	return files, nil
+}
+
+func logFilePath(day string, seq int) string {
+	if seq == 0 {
+		return "app-" + day + ".log"
+	}
+	return fmt.Sprintf("app-%s.%d.log", day, seq)
+}
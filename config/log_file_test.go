@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LogFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"app-2026-10-19.log",
+		"app-2026-10-18.2.log",
+		"app-2026-10-19.10.log",
+		"app-2026-10-18.log",
+		"app-2026-10-19.1.log",
+		"app-2026-10-19.log.gz",
+		"app-19-10-2026.log",
+		"notes.txt",
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "app-2026-10-17.log"), 0o755))
+
+	files, err := LogFiles(dir)
+	assert.NoError(t, err)
+
+	var names []string
+	for _, file := range files {
+		names = append(names, filepath.Base(file.Path))
+	}
+	assert.Equal(t, []string{
+		"app-2026-10-18.log",
+		"app-2026-10-18.2.log",
+		"app-2026-10-19.log",
+		"app-2026-10-19.1.log",
+		"app-2026-10-19.10.log",
+	}, names)
+
+	files, err = LogFiles(filepath.Join(dir, "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func Test_RotatingFile(t *testing.T) {
+	today := time.Now().Format(time.DateOnly)
+	old := time.Now().AddDate(0, 0, -3).Format(time.DateOnly)
+
+	tests := []struct {
+		name     string
+		existing map[string]int
+		writes   []string
+		want     map[string]string
+		wantGone []string
+	}{
+		{
+			name:   "one file while there is room",
+			writes: []string{"12345", "678"},
+			want:   map[string]string{logFilePath(today, 0): "12345678"},
+		},
+		{
+			name:   "rotated when full",
+			writes: []string{"12345678", "abc", "defghijkl", "mn"},
+			want: map[string]string{
+				logFilePath(today, 0): "12345678",
+				logFilePath(today, 1): "abc",
+				logFilePath(today, 2): "defghijkl",
+				logFilePath(today, 3): "mn",
+			},
+		},
+		{
+			name:     "full files of a restarted instance are skipped",
+			existing: map[string]int{logFilePath(today, 0): 10, logFilePath(today, 1): 4},
+			writes:   []string{"abc"},
+			want:     map[string]string{logFilePath(today, 1): "xxxxabc"},
+		},
+		{
+			name:     "files past the retention are pruned",
+			existing: map[string]int{logFilePath(old, 0): 1, logFilePath(old, 1): 1},
+			writes:   []string{"abc"},
+			want:     map[string]string{logFilePath(today, 0): "abc"},
+			wantGone: []string{logFilePath(old, 0), logFilePath(old, 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, size := range tt.existing {
+				content := make([]byte, size)
+				for i := range content {
+					content[i] = 'x'
+				}
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, name), content, 0o644))
+			}
+
+			f, err := NewRotatingFile(dir, 10, 24*time.Hour)
+			assert.NoError(t, err)
+			defer f.file.Close()
+			for _, write := range tt.writes {
+				n, err := f.Write([]byte(write))
+				assert.NoError(t, err)
+				assert.Equal(t, len(write), n)
+			}
+
+			for name, want := range tt.want {
+				got, err := os.ReadFile(filepath.Join(dir, name))
+				assert.NoError(t, err, name)
+				assert.Equal(t, want, string(got), name)
+			}
+			for _, name := range tt.wantGone {
+				_, err := os.Stat(filepath.Join(dir, name))
+				assert.True(t, os.IsNotExist(err), name)
+			}
+		})
+	}
+}
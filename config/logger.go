@@ -1,41 +1,190 @@
 package config
 
 import (
-	"log"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
 	"os"
-	"strings"
+	"strconv"
 	"time"
 
+	"github.com/joho/godotenv"
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
 const (
-	LOG_DIR = "./config/logs/query_log"
+	LOG_DIR = "./config/logs"
 )
 
-func SetupLogger() logger.Interface {
-	err := os.MkdirAll(LOG_DIR, os.ModePerm)
-	if err != nil {
-		log.Fatalf("failed to create log directory: %v", err)
+type (
+	// LogConfig is read from the environment:
+	//   - LOG_LEVEL: debug, info, warn or error, info by default. Every SQL
+	//     query is logged at debug.
+	//   - LOG_DIR: where the JSON log files are written, ./config/logs by default
+	//   - LOG_MAX_SIZE_MB: size at which the file of the day is rotated, 50 MB
+	//   - LOG_RETENTION_DAYS: days log files are kept, 14
+	//   - LOG_SLOW_QUERY_MS: queries slower than this are logged as warnings, 200
+	LogConfig struct {
+		Level     slog.Level
+		Dir       string
+		MaxSize   int64
+		Retention time.Duration
+		SlowQuery time.Duration
+	}
+
+	// LogFields are added to every entry logged with a request context. UserID
+	// is filled in once the request is authenticated.
+	LogFields struct {
+		RequestID string
+		UserID    string
+	}
+
+	logFieldsKey struct{}
+
+	// contextHandler adds the LogFields of the context to each record
+	contextHandler struct {
+		slog.Handler
 	}
 
-	currentMonth := time.Now().Format("January")
-	currentMonth = strings.ToLower(currentMonth)
-	logFileName := currentMonth + "_query.log"
+	// gormLogger sends GORM's query log to slog
+	gormLogger struct {
+		slowThreshold time.Duration
+	}
+)
+
+func NewLogConfig() LogConfig {
+	// the database connection loads .env too, but the logger comes first
+	if os.Getenv("APP_ENV") != constants.ENUM_RUN_PRODUCTION {
+		_ = godotenv.Load(".env")
+	}
+
+	config := LogConfig{
+		Level:     slog.LevelInfo,
+		Dir:       os.Getenv("LOG_DIR"),
+		MaxSize:   int64(envInt("LOG_MAX_SIZE_MB", 50)) << 20,
+		Retention: time.Duration(envInt("LOG_RETENTION_DAYS", 14)) * 24 * time.Hour,
+		SlowQuery: time.Duration(envInt("LOG_SLOW_QUERY_MS", 200)) * time.Millisecond,
+	}
+	if config.Dir == "" {
+		config.Dir = LOG_DIR
+	}
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		if err := config.Level.UnmarshalText([]byte(level)); err != nil {
+			config.Level = slog.LevelInfo
+		}
+	}
+	return config
+}
 
-	logFile, err := os.OpenFile(LOG_DIR+"/"+logFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+// SetUpLogger makes slog's default logger write JSON to stdout and to the
+// rotating files in config.Dir
+func SetUpLogger(config LogConfig) error {
+	file, err := NewRotatingFile(config.Dir, config.MaxSize, config.Retention)
 	if err != nil {
-		log.Fatalf("failed to open log file: %v", err)
+		return err
+	}
+
+	handler := slog.NewJSONHandler(io.MultiWriter(os.Stdout, file), &slog.HandlerOptions{Level: config.Level})
+	slog.SetDefault(slog.New(contextHandler{handler}))
+	return nil
+}
+
+// WithLogFields returns a context whose log entries carry fields
+func WithLogFields(ctx context.Context, fields *LogFields) context.Context {
+	return context.WithValue(ctx, logFieldsKey{}, fields)
+}
+
+// LogFieldsFromContext returns the fields of the request, nil outside one
+func LogFieldsFromContext(ctx context.Context) *LogFields {
+	fields, _ := ctx.Value(logFieldsKey{}).(*LogFields)
+	return fields
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if fields := LogFieldsFromContext(ctx); fields != nil {
+		record.AddAttrs(slog.String("request_id", fields.RequestID))
+		if fields.UserID != "" {
+			record.AddAttrs(slog.String("user_id", fields.UserID))
+		}
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// NewGormLogger logs failed queries as errors, queries slower than
+// slowThreshold as warnings and the others at debug
+func NewGormLogger(slowThreshold time.Duration) logger.Interface {
+	return &gormLogger{slowThreshold: slowThreshold}
+}
+
+// LogMode is a no-op, the level is set on slog
+func (l *gormLogger) LogMode(logger.LogLevel) logger.Interface {
+	return l
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...any) {
+	slog.InfoContext(ctx, msg, "source", "gorm", "data", data)
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...any) {
+	slog.WarnContext(ctx, msg, "source", "gorm", "data", data)
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...any) {
+	slog.ErrorContext(ctx, msg, "source", "gorm", "data", data)
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	latency := time.Since(begin)
+	slow := l.slowThreshold > 0 && latency > l.slowThreshold
+
+	var level slog.Level
+	msg := "query"
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level, msg = slog.LevelError, "query failed"
+	case slow:
+		level, msg = slog.LevelWarn, "slow query"
+	default:
+		level = slog.LevelDebug
+	}
+	if !slog.Default().Enabled(ctx, level) {
+		return
 	}
 
-	newLogger := logger.New(
-		log.New(logFile, "\r\n", log.LstdFlags),
-		logger.Config{
-			SlowThreshold: time.Second,
-			LogLevel:      logger.Info,
-			Colorful:      false,
-		},
-	)
+	sql, rows := fc()
+	attrs := []slog.Attr{
+		slog.String("source", "gorm"),
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Int64("latency_ms", latency.Milliseconds()),
+	}
+	if level == slog.LevelError {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	slog.LogAttrs(ctx, level, msg, attrs...)
+}
 
-	return newLogger
+// ParamsFilter keeps bound values, such as password hashes and tokens, out of
+// the logged SQL
+func (l *gormLogger) ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any) {
+	return sql, nil
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 1 {
+		return fallback
+	}
+	return value
 }
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ContextHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields *LogFields
+		want   map[string]any
+	}{
+		{"outside a request", nil, map[string]any{}},
+		{"anonymous request", &LogFields{RequestID: "trace-1234"}, map[string]any{"request_id": "trace-1234"}},
+		{"authenticated request", &LogFields{RequestID: "trace-1234", UserID: "alice"}, map[string]any{"request_id": "trace-1234", "user_id": "alice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(contextHandler{slog.NewJSONHandler(&buf, nil)}).With("component", "test")
+
+			ctx := context.Background()
+			if tt.fields != nil {
+				ctx = WithLogFields(ctx, tt.fields)
+			}
+			logger.InfoContext(ctx, "hello")
+
+			var entry map[string]any
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+			assert.Equal(t, "test", entry["component"])
+			for _, key := range []string{"request_id", "user_id"} {
+				want, ok := tt.want[key]
+				if !ok {
+					assert.NotContains(t, entry, key)
+					continue
+				}
+				assert.Equal(t, want, entry[key])
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// TrustedProxies reads TRUSTED_PROXIES, the comma separated addresses or
+// CIDR ranges of the reverse proxies in front of the API. X-Forwarded-For is
+// only honoured on requests from them. Without any, the default, the client
+// IP is the address of the connection, since rate limits and the login
+// throttle key on it and any client can send the header.
+func TrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TrustedProxies(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", nil},
+		{" , ", nil},
+		{"10.0.0.2", []string{"10.0.0.2"}},
+		{"10.0.0.2, 172.16.0.0/12 ,", []string{"10.0.0.2", "172.16.0.0/12"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("TRUSTED_PROXIES", tt.value)
+			assert.Equal(t, tt.want, TrustedProxies())
+		})
+	}
+}
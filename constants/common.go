@@ -12,8 +12,30 @@ const (
 	ENUM_PAGINATION_PER_PAGE = 10
 	ENUM_PAGINATION_PAGE     = 1
 
-	DB         = "db"
-	JWTService = "JWTService"
+	DB          = "db"
+	JWTService  = "JWTService"
+	FileStorage = "FileStorage"
+
+	RateLimitStore = "RateLimitStore"
+
+	// APIKeyService is shared by every route group through Authenticate
+	APIKeyService = "APIKeyService"
+
+	// jobs are provided as JobPrefix + job name and collected by the scheduler
+	SchedulerService = "SchedulerService"
+	JobPrefix        = "job:"
+
+	// NotificationService is a singleton, open streams are woken through it
+	NotificationService = "NotificationService"
+
+	// subscribers are provided as SubscriberPrefix + topic + ":" + name and
+	// collected by the event dispatcher
+	EventBus         = "EventBus"
+	EventDispatcher  = "EventDispatcher"
+	SubscriberPrefix = "subscriber:"
+
+	// WebhookService is a singleton, it also runs the delivery sender
+	WebhookService = "WebhookService"
 
 	// Booking Request
 	BookingRequestRepository = "BookingRequestRepository"
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/miraicantsleep/myits-event-be/utils"
+)
+
+type (
+	APIKeyController interface {
+		Create(ctx *gin.Context)
+		GetAll(ctx *gin.Context)
+		GetByID(ctx *gin.Context)
+		Revoke(ctx *gin.Context)
+	}
+
+	apiKeyController struct {
+		apiKeyService service.APIKeyService
+	}
+)
+
+func NewAPIKeyController(aks service.APIKeyService) APIKeyController {
+	return &apiKeyController{
+		apiKeyService: aks,
+	}
+}
+
+func (c *apiKeyController) Create(ctx *gin.Context) {
+	var req dto.APIKeyCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.apiKeyService.Create(ctx.Request.Context(), req, userId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_API_KEY, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_API_KEY, result)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *apiKeyController) GetAll(ctx *gin.Context) {
+	result, err := c.apiKeyService.GetAll(ctx.Request.Context())
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_API_KEYS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_API_KEYS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *apiKeyController) GetByID(ctx *gin.Context) {
+	result, err := c.apiKeyService.GetByID(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_API_KEY, err.Error(), nil)
+		ctx.JSON(http.StatusNotFound, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_API_KEY, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *apiKeyController) Revoke(ctx *gin.Context) {
+	if err := c.apiKeyService.Revoke(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REVOKE_API_KEY, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REVOKE_API_KEY, nil)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/miraicantsleep/myits-event-be/utils"
+)
+
+type (
+	AuditController interface {
+		GetAll(ctx *gin.Context)
+		Export(ctx *gin.Context)
+	}
+
+	auditController struct {
+		auditService service.AuditService
+	}
+)
+
+func NewAuditController(as service.AuditService) AuditController {
+	return &auditController{
+		auditService: as,
+	}
+}
+
+func (c *auditController) GetAll(ctx *gin.Context) {
+	var req dto.AuditLogListRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.auditService.GetAll(ctx.Request.Context(), req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_AUDIT_LOGS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	resp := utils.Response{
+		Status:  true,
+		Message: dto.MESSAGE_SUCCESS_GET_AUDIT_LOGS,
+		Data:    result.Data,
+		Meta:    result.PaginationResponse,
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// Export streams the filtered audit log as a CSV download
+func (c *auditController) Export(ctx *gin.Context) {
+	var req dto.AuditLogFilter
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	if _, _, err := req.TimeRange(); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_EXPORT_AUDIT_LOGS, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", `attachment; filename="audit-logs-`+time.Now().Format("20060102-150405")+`.csv"`)
+	ctx.Status(http.StatusOK)
+
+	if err := c.auditService.Export(ctx.Request.Context(), req, ctx.Writer); err != nil {
+		// the status is gone with the first row, the client sees a cut short file
+		_ = ctx.Error(err)
+	}
+}
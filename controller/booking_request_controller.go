@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -38,7 +39,7 @@ func (c *bookingRequestController) Create(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.bookingRequestService.CreateBookingRequest(ctx.Request.Context(), req)
+	result, err := c.bookingRequestService.CreateBookingRequest(ctx.Request.Context(), req, actingOrganization(ctx))
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_BOOKING_REQUEST, err.Error(), nil)
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -99,13 +100,21 @@ func (c *bookingRequestController) Update(ctx *gin.Context) {
 	role := ctx.MustGet("role").(string)
 
 	// MODIFY THIS LINE to pass the role
-	result, err := c.bookingRequestService.UpdateBookingRequest(ctx.Request.Context(), id, req, role)
+	result, err := c.bookingRequestService.UpdateBookingRequest(ctx.Request.Context(), id, req, role, actingOrganization(ctx))
 
 	// MODIFY this error handling block
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_BOOKING_REQUEST, err.Error(), nil)
+		if errors.Is(err, dto.ErrBookingStatusChange) {
+			ctx.JSON(http.StatusBadRequest, res)
+			return
+		}
+		if errors.Is(err, dto.ErrBookingNotPending) {
+			ctx.JSON(http.StatusConflict, res)
+			return
+		}
 		// Use StatusForbidden for permission errors
-		if err.Error() == "ormawa users are not permitted to change the booking status" {
+		if errors.Is(err, dto.ErrOrganizationForbidden) {
 			ctx.JSON(http.StatusForbidden, res)
 			return
 		}
@@ -125,7 +134,7 @@ func (c *bookingRequestController) Delete(ctx *gin.Context) {
 		return
 	}
 
-	err := c.bookingRequestService.DeleteBookingRequest(ctx.Request.Context(), id)
+	err := c.bookingRequestService.DeleteBookingRequest(ctx.Request.Context(), id, actingOrganization(ctx))
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_BOOKING_REQUEST, err.Error(), nil)
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -147,6 +156,10 @@ func (c *bookingRequestController) Approve(ctx *gin.Context) {
 	err := c.bookingRequestService.ApproveBookingRequest(ctx.Request.Context(), id)
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_APPROVE_BOOKING_REQUEST, err.Error(), nil)
+		if errors.Is(err, dto.ErrBookingNotPending) {
+			ctx.JSON(http.StatusConflict, res)
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, res)
 		return
 	}
@@ -166,6 +179,10 @@ func (c *bookingRequestController) Reject(ctx *gin.Context) {
 	err := c.bookingRequestService.RejectBookingRequest(ctx.Request.Context(), id)
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REJECT_BOOKING_REQUEST, err.Error(), nil)
+		if errors.Is(err, dto.ErrBookingNotPending) {
+			ctx.JSON(http.StatusConflict, res)
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, res)
 		return
 	}
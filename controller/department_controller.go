@@ -16,18 +16,25 @@ type (
 		GetAllDepartment(ctx *gin.Context)
 		Update(ctx *gin.Context)
 		Delete(ctx *gin.Context)
+		GetOperatingHours(ctx *gin.Context)
+		SetOperatingHours(ctx *gin.Context)
+		GetBlackouts(ctx *gin.Context)
+		CreateBlackout(ctx *gin.Context)
+		DeleteBlackout(ctx *gin.Context)
 	}
 
 	departmentController struct {
 		departmentService service.DepartmentService
 		userService       service.UserService
+		scheduleService   service.RoomScheduleService
 	}
 )
 
-func NewDepartmentController(ds service.DepartmentService, us service.UserService) DepartmentController {
+func NewDepartmentController(ds service.DepartmentService, us service.UserService, ss service.RoomScheduleService) DepartmentController {
 	return &departmentController{
 		departmentService: ds,
 		userService:       us,
+		scheduleService:   ss,
 	}
 }
 
@@ -132,3 +139,87 @@ func (c *departmentController) Delete(ctx *gin.Context) {
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_DEPARTMENT, nil)
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (c *departmentController) GetOperatingHours(ctx *gin.Context) {
+	departmentId := ctx.Param("id")
+	result, err := c.scheduleService.GetDepartmentOperatingHours(ctx.Request.Context(), departmentId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_OPERATING_HOURS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_OPERATING_HOURS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *departmentController) SetOperatingHours(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	var req dto.OperatingHoursUpdateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	departmentId := ctx.Param("id")
+	result, err := c.scheduleService.SetDepartmentOperatingHours(ctx.Request.Context(), departmentId, req, userId, role)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_SET_OPERATING_HOURS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_SET_OPERATING_HOURS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *departmentController) GetBlackouts(ctx *gin.Context) {
+	departmentId := ctx.Param("id")
+	result, err := c.scheduleService.GetDepartmentBlackouts(ctx.Request.Context(), departmentId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_BLACKOUTS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_BLACKOUTS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *departmentController) CreateBlackout(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	var req dto.BlackoutCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	departmentId := ctx.Param("id")
+	result, err := c.scheduleService.CreateDepartmentBlackout(ctx.Request.Context(), departmentId, req, userId, role)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_BLACKOUT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_BLACKOUT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *departmentController) DeleteBlackout(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	blackoutId := ctx.Param("blackout_id")
+	if err := c.scheduleService.DeleteBlackout(ctx.Request.Context(), blackoutId, userId, role); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_BLACKOUT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_BLACKOUT, nil)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/miraicantsleep/myits-event-be/utils"
+)
+
+type (
+	EquipmentController interface {
+		Create(ctx *gin.Context)
+		GetEquipmentByID(ctx *gin.Context)
+		GetAllEquipment(ctx *gin.Context)
+		Update(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+		GetAvailability(ctx *gin.Context)
+	}
+
+	equipmentController struct {
+		equipmentService service.EquipmentService
+	}
+)
+
+func NewEquipmentController(es service.EquipmentService) EquipmentController {
+	return &equipmentController{
+		equipmentService: es,
+	}
+}
+
+func (c *equipmentController) Create(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	var req dto.EquipmentCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.equipmentService.Create(ctx.Request.Context(), req, userId, role)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_EQUIPMENT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_EQUIPMENT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *equipmentController) GetEquipmentByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, "Equipment ID is required", nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.equipmentService.GetEquipmentByID(ctx.Request.Context(), id)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_EQUIPMENT_BY_ID, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_EQUIPMENT_BY_ID, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *equipmentController) GetAllEquipment(ctx *gin.Context) {
+	var filter dto.EquipmentFilterRequest
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.equipmentService.GetAllEquipment(ctx.Request.Context(), filter)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_ALL_EQUIPMENT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_ALL_EQUIPMENT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *equipmentController) Update(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, "Equipment ID is required", nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	var req dto.EquipmentUpdateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.equipmentService.Update(ctx.Request.Context(), id, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_EQUIPMENT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_EQUIPMENT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *equipmentController) Delete(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, "Equipment ID is required", nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := c.equipmentService.Delete(ctx.Request.Context(), id); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_EQUIPMENT, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_EQUIPMENT, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *equipmentController) GetAvailability(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req dto.EquipmentAvailabilityRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.equipmentService.GetAvailability(ctx.Request.Context(), id, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_EQUIPMENT_AVAILABILITY, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_EQUIPMENT_AVAILABILITY, result)
+	ctx.JSON(http.StatusOK, res)
+}
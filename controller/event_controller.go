@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"mime"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -17,6 +18,12 @@ type (
 		Update(ctx *gin.Context)
 		Delete(ctx *gin.Context)
 		GetEventAttendees(ctx *gin.Context)
+		GetAttendanceStats(ctx *gin.Context)
+		UploadPoster(ctx *gin.Context)
+		DeletePoster(ctx *gin.Context)
+		UploadAttachment(ctx *gin.Context)
+		DeleteAttachment(ctx *gin.Context)
+		DownloadAttachment(ctx *gin.Context)
 		GetAllUserAttendances(ctx *gin.Context)
 	}
 
@@ -40,7 +47,7 @@ func (c *eventController) Create(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.eventService.Create(ctx.Request.Context(), req, userId)
+	result, err := c.eventService.Create(ctx.Request.Context(), req, userId, actingOrganization(ctx))
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_EVENT, err.Error(), nil)
 		ctx.JSON(http.StatusBadRequest, res)
@@ -53,7 +60,6 @@ func (c *eventController) Create(ctx *gin.Context) {
 
 func (c *eventController) GetAllEvent(ctx *gin.Context) {
 	user_role := ctx.MustGet("role").(string)
-	user_id := ctx.MustGet("user_id").(string)
 	var req dto.PaginationRequest
 	if err := ctx.ShouldBind(&req); err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
@@ -61,7 +67,7 @@ func (c *eventController) GetAllEvent(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.eventService.GetAllEventWithPagination(ctx.Request.Context(), req, user_role, user_id)
+	result, err := c.eventService.GetAllEventWithPagination(ctx.Request.Context(), req, user_role, actingOrganization(ctx))
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_EVENT, err.Error(), nil)
 		ctx.JSON(http.StatusBadRequest, res)
@@ -80,7 +86,7 @@ func (c *eventController) GetEventByID(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.eventService.GetEventById(ctx.Request.Context(), eventId)
+	result, err := c.eventService.GetEventById(ctx.Request.Context(), eventId, actingOrganization(ctx))
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_EVENT, err.Error(), nil)
 		ctx.JSON(http.StatusBadRequest, res)
@@ -106,7 +112,7 @@ func (c *eventController) Update(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.eventService.Update(ctx.Request.Context(), req, eventId)
+	result, err := c.eventService.Update(ctx.Request.Context(), req, eventId, actingOrganization(ctx))
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_EVENT, err.Error(), nil)
 		ctx.JSON(http.StatusBadRequest, res)
@@ -125,7 +131,7 @@ func (c *eventController) Delete(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.eventService.Delete(ctx.Request.Context(), eventId); err != nil {
+	if err := c.eventService.Delete(ctx.Request.Context(), eventId, actingOrganization(ctx)); err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_EVENT, err.Error(), nil)
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
@@ -143,7 +149,7 @@ func (c *eventController) GetEventAttendees(ctx *gin.Context) {
 		return
 	}
 
-	attendees, err := c.eventService.GetEventAttendees(ctx.Request.Context(), eventId)
+	attendees, err := c.eventService.GetEventAttendees(ctx.Request.Context(), eventId, actingOrganization(ctx))
 	if err != nil {
 		res := utils.BuildResponseFailed("Failed to get event attendees", err.Error(), nil)
 		ctx.JSON(http.StatusInternalServerError, res)
@@ -154,6 +160,20 @@ func (c *eventController) GetEventAttendees(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, res)
 }
 
+func (c *eventController) GetAttendanceStats(ctx *gin.Context) {
+	eventId := ctx.Param("id")
+
+	result, err := c.eventService.GetAttendanceStats(ctx.Request.Context(), eventId, actingOrganization(ctx))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_ATTENDANCE_STATS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_ATTENDANCE_STATS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
 func (c *eventController) GetAllUserAttendances(ctx *gin.Context) {
 	var req dto.PaginationRequest
 	// Gunakan ShouldBindQuery untuk GET request
@@ -179,3 +199,78 @@ func (c *eventController) GetAllUserAttendances(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusOK, resp)
 }
+
+func (c *eventController) UploadPoster(ctx *gin.Context) {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.eventService.UploadPoster(ctx.Request.Context(), ctx.Param("id"), actingOrganization(ctx), file)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPLOAD_POSTER, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPLOAD_POSTER, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *eventController) DeletePoster(ctx *gin.Context) {
+	if err := c.eventService.DeletePoster(ctx.Request.Context(), ctx.Param("id"), actingOrganization(ctx)); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_POSTER, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_POSTER, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *eventController) UploadAttachment(ctx *gin.Context) {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.eventService.UploadAttachment(ctx.Request.Context(), ctx.Param("id"), actingOrganization(ctx), file)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPLOAD_ATTACHMENT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPLOAD_ATTACHMENT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *eventController) DeleteAttachment(ctx *gin.Context) {
+	if err := c.eventService.DeleteAttachment(ctx.Request.Context(), ctx.Param("id"), ctx.Param("attachment_id"), actingOrganization(ctx)); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_ATTACHMENT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_ATTACHMENT, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *eventController) DownloadAttachment(ctx *gin.Context) {
+	attachment, reader, err := c.eventService.OpenAttachment(ctx.Request.Context(), ctx.Param("id"), ctx.Param("attachment_id"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DOWNLOAD_ATTACHMENT, err.Error(), nil)
+		ctx.JSON(http.StatusNotFound, res)
+		return
+	}
+	defer reader.Close()
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})
+	ctx.DataFromReader(http.StatusOK, attachment.Size, attachment.MimeType, reader, map[string]string{
+		"Content-Disposition": disposition,
+	})
+}
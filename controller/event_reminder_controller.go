@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/miraicantsleep/myits-event-be/utils"
+)
+
+type (
+	EventReminderController interface {
+		GetReminders(ctx *gin.Context)
+		UpdateReminders(ctx *gin.Context)
+	}
+
+	eventReminderController struct {
+		reminderService service.EventReminderService
+	}
+)
+
+func NewEventReminderController(rs service.EventReminderService) EventReminderController {
+	return &eventReminderController{
+		reminderService: rs,
+	}
+}
+
+func (c *eventReminderController) GetReminders(ctx *gin.Context) {
+	result, err := c.reminderService.GetReminders(ctx.Request.Context(), ctx.Param("id"), actingOrganization(ctx))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_EVENT_REMINDERS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_EVENT_REMINDERS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *eventReminderController) UpdateReminders(ctx *gin.Context) {
+	var req dto.EventReminderRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.reminderService.UpdateReminders(ctx.Request.Context(), ctx.Param("id"), actingOrganization(ctx), req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_EVENT_REMINDERS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_EVENT_REMINDERS, result)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,6 +23,7 @@ type (
 		ScanQRCode(ctx *gin.Context)
 		AcceptRSVP(ctx *gin.Context)  // New method
 		DeclineRSVP(ctx *gin.Context) // New method
+		JoinOnlineEvent(ctx *gin.Context)
 	}
 
 	invitationController struct {
@@ -47,7 +49,7 @@ func (c *invitationController) Create(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
 	}
-	result, err := c.invitationService.Create(ctx.Request.Context(), req)
+	result, err := c.invitationService.Create(ctx.Request.Context(), req, actingOrganization(ctx))
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_INVITATION, err.Error(), nil)
 		ctx.JSON(http.StatusBadRequest, res)
@@ -64,10 +66,12 @@ func (c *invitationController) GetInvitationByID(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
 	}
-	result, err := c.invitationService.GetInvitationByID(ctx.Request.Context(), invitationId)
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	result, err := c.invitationService.GetInvitationByID(ctx.Request.Context(), invitationId, userId, role, actingOrganization(ctx))
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_INVITATION_BY_ID, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		ctx.JSON(invitationErrorStatus(err), res)
 		return
 	}
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_INVITATION_BY_ID, result)
@@ -81,10 +85,10 @@ func (c *invitationController) GetInvitationByEventID(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
 	}
-	result, err := c.invitationService.GetInvitationByEventID(ctx.Request.Context(), eventId)
+	result, err := c.invitationService.GetInvitationByEventID(ctx.Request.Context(), eventId, actingOrganization(ctx))
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_INVITATION_BY_EVENT_ID, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		ctx.JSON(invitationErrorStatus(err), res)
 		return
 	}
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_INVITATION_BY_EVENT_ID, result)
@@ -102,10 +106,12 @@ func (c *invitationController) GetInvitationByUserID(ctx *gin.Context) {
 	}
 
 	// Call the service
-	invitations, err := c.invitationService.GetInvitationByUserID(ctx.Request.Context(), userID)
+	callerId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	invitations, err := c.invitationService.GetInvitationByUserID(ctx.Request.Context(), userID, callerId, role, actingOrganization(ctx))
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_INVITATION_BY_USER_ID, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		ctx.JSON(invitationErrorStatus(err), res)
 		return
 	}
 
@@ -139,10 +145,10 @@ func (c *invitationController) Update(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.invitationService.Update(ctx.Request.Context(), invitationId, req)
+	result, err := c.invitationService.Update(ctx.Request.Context(), invitationId, req, actingOrganization(ctx))
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_INVITATION, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		ctx.JSON(invitationErrorStatus(err), res)
 		return
 	}
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_INVITATION, result)
@@ -157,10 +163,10 @@ func (c *invitationController) Delete(ctx *gin.Context) {
 		return
 	}
 
-	err := c.invitationService.Delete(ctx.Request.Context(), invitationId)
+	err := c.invitationService.Delete(ctx.Request.Context(), invitationId, actingOrganization(ctx))
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_INVITATION, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		ctx.JSON(invitationErrorStatus(err), res)
 		return
 	}
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_INVITATION, nil)
@@ -203,7 +209,23 @@ func (c *invitationController) AcceptRSVP(ctx *gin.Context) {
 
 	// Call the service method (to be created in the next plan step)
 	// For now, assume it's called ProcessRSVP and it's part of invitationService interface
-	err := c.invitationService.ProcessRSVP(ctx.Request.Context(), token, entity.RSVPStatusAccepted) // entity.RSVPStatusAccepted = "accepted"
+	// hybrid events link to /rsvp/accept/:token/in-person or /online
+	var mode string
+	switch ctx.Param("mode") {
+	case "":
+	case "in-person":
+		mode = entity.AttendanceModeInPerson
+	case "online":
+		mode = entity.AttendanceModeOnline
+	default:
+		ctx.HTML(http.StatusBadRequest, "rsvp_error.html", gin.H{
+			"title":   "RSVP Error",
+			"message": dto.ErrInvalidAttendanceMode.Error(),
+		})
+		return
+	}
+
+	err := c.invitationService.ProcessRSVP(ctx.Request.Context(), token, entity.RSVPStatusAccepted, mode) // entity.RSVPStatusAccepted = "accepted"
 
 	if err != nil {
 		// Determine message based on error type if possible
@@ -233,7 +255,7 @@ func (c *invitationController) DeclineRSVP(ctx *gin.Context) {
 		return
 	}
 
-	err := c.invitationService.ProcessRSVP(ctx.Request.Context(), token, entity.RSVPStatusDeclined) // entity.RSVPStatusDeclined = "declined"
+	err := c.invitationService.ProcessRSVP(ctx.Request.Context(), token, entity.RSVPStatusDeclined, "") // entity.RSVPStatusDeclined = "declined"
 
 	if err != nil {
 		ctx.HTML(http.StatusOK, "rsvp_error.html", gin.H{
@@ -249,3 +271,36 @@ func (c *invitationController) DeclineRSVP(ctx *gin.Context) {
 		"status":  "Declined",
 	})
 }
+
+// JoinOnlineEvent marks attendance and redirects the invitee to the meeting.
+func (c *invitationController) JoinOnlineEvent(ctx *gin.Context) {
+	token := ctx.Param("token")
+	if token == "" {
+		ctx.HTML(http.StatusBadRequest, "rsvp_error.html", gin.H{
+			"title":   "Join Error",
+			"message": "Invalid join link. No token provided.",
+		})
+		return
+	}
+
+	meetingURL, err := c.invitationService.JoinOnlineEvent(ctx.Request.Context(), token)
+	if err != nil {
+		ctx.HTML(http.StatusOK, "rsvp_error.html", gin.H{
+			"title":   "Unable to Join",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, meetingURL)
+}
+
+func invitationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, dto.ErrInvitationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, dto.ErrInvitationForbidden), errors.Is(err, dto.ErrOrganizationForbidden):
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
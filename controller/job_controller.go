@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/miraicantsleep/myits-event-be/utils"
+)
+
+type (
+	JobController interface {
+		GetAll(ctx *gin.Context)
+		GetRuns(ctx *gin.Context)
+		Trigger(ctx *gin.Context)
+	}
+
+	jobController struct {
+		schedulerService service.SchedulerService
+	}
+)
+
+func NewJobController(ss service.SchedulerService) JobController {
+	return &jobController{
+		schedulerService: ss,
+	}
+}
+
+func (c *jobController) GetAll(ctx *gin.Context) {
+	result, err := c.schedulerService.GetJobs(ctx.Request.Context())
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_JOBS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_JOBS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *jobController) GetRuns(ctx *gin.Context) {
+	result, err := c.schedulerService.GetRuns(ctx.Request.Context(), ctx.Param("name"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_JOB_RUNS, err.Error(), nil)
+		ctx.JSON(http.StatusNotFound, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_JOB_RUNS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *jobController) Trigger(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.schedulerService.Trigger(ctx.Request.Context(), ctx.Param("name"), userId)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, dto.ErrJobNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, dto.ErrJobRunning):
+			status = http.StatusConflict
+		}
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_TRIGGER_JOB, err.Error(), nil)
+		ctx.JSON(status, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_TRIGGER_JOB, result)
+	ctx.JSON(http.StatusAccepted, res)
+}
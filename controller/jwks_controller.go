@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/service"
+)
+
+type (
+	JWKSController interface {
+		GetJWKS(ctx *gin.Context)
+	}
+
+	jwksController struct {
+		jwtService service.JWTService
+	}
+)
+
+func NewJWKSController(js service.JWTService) JWKSController {
+	return &jwksController{
+		jwtService: js,
+	}
+}
+
+// GetJWKS serves the key set as is rather than wrapped in utils.Response,
+// since JWKS clients expect the standard document
+func (c *jwksController) GetJWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, c.jwtService.JWKS())
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/miraicantsleep/myits-event-be/utils"
+)
+
+type (
+	LogController interface {
+		GetAll(ctx *gin.Context)
+	}
+
+	logController struct {
+		logService service.LogService
+	}
+)
+
+func NewLogController(ls service.LogService) LogController {
+	return &logController{
+		logService: ls,
+	}
+}
+
+func (c *logController) GetAll(ctx *gin.Context) {
+	var req dto.LogListRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.logService.GetAll(ctx.Request.Context(), req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LOGS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	resp := utils.Response{
+		Status:  true,
+		Message: dto.MESSAGE_SUCCESS_GET_LOGS,
+		Data:    result.Data,
+		Meta:    result.PaginationResponse,
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/miraicantsleep/myits-event-be/utils"
+)
+
+// notificationPollInterval bounds how late a stream learns about
+// notifications created by another instance, and doubles as the heartbeat
+const notificationPollInterval = 15 * time.Second
+
+type (
+	NotificationController interface {
+		GetAll(ctx *gin.Context)
+		GetUnreadCount(ctx *gin.Context)
+		MarkRead(ctx *gin.Context)
+		MarkAllRead(ctx *gin.Context)
+		Stream(ctx *gin.Context)
+	}
+
+	notificationController struct {
+		notificationService service.NotificationService
+	}
+)
+
+func NewNotificationController(ns service.NotificationService) NotificationController {
+	return &notificationController{
+		notificationService: ns,
+	}
+}
+
+func (c *notificationController) GetAll(ctx *gin.Context) {
+	var req dto.NotificationListRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.notificationService.GetNotifications(ctx.Request.Context(), userId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_NOTIFICATIONS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	resp := utils.Response{
+		Status:  true,
+		Message: dto.MESSAGE_SUCCESS_GET_NOTIFICATIONS,
+		Data:    result.Data,
+		Meta:    result.Meta,
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (c *notificationController) GetUnreadCount(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.notificationService.GetUnreadCount(ctx.Request.Context(), userId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_NOTIFICATIONS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_NOTIFICATIONS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *notificationController) MarkRead(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.notificationService.MarkRead(ctx.Request.Context(), userId, ctx.Param("id"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_MARK_NOTIFICATION_READ, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_MARK_NOTIFICATION_READ, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *notificationController) MarkAllRead(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.notificationService.MarkAllRead(ctx.Request.Context(), userId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_MARK_NOTIFICATION_READ, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_MARK_NOTIFICATION_READ, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Stream sends new notifications as Server-Sent Events. Every message is a
+// "notification" event followed by an "unread" event with the new count.
+// Notifications from this instance arrive at once, those from other
+// instances on the next poll.
+func (c *notificationController) Stream(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	reqCtx := ctx.Request.Context()
+
+	wake, cancel := c.notificationService.Subscribe(userId)
+	defer cancel()
+
+	// start from the newest stored notification so nothing already listed is
+	// sent again and clock differences between instances do not matter
+	var since time.Time
+	latest, err := c.notificationService.GetNotifications(reqCtx, userId, dto.NotificationListRequest{PaginationRequest: dto.PaginationRequest{Page: 1, PerPage: 1}})
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_NOTIFICATIONS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	if len(latest.Data) > 0 {
+		since = latest.Data[0].CreatedAt
+	}
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// disables response buffering in nginx
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.SSEvent("unread", dto.NotificationUnreadResponse{UnreadCount: latest.Meta.UnreadCount})
+	ctx.Writer.Flush()
+
+	poll := time.NewTicker(notificationPollInterval)
+	defer poll.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		woken := false
+		select {
+		case <-reqCtx.Done():
+			return false
+		case <-wake:
+			woken = true
+		case <-poll.C:
+		}
+
+		notifications, err := c.notificationService.GetSince(reqCtx, userId, since)
+		if err != nil {
+			return false
+		}
+		for _, notification := range notifications {
+			ctx.SSEvent("notification", notification)
+			since = notification.CreatedAt
+		}
+
+		if len(notifications) == 0 && !woken {
+			// comment line, keeps proxies from closing an idle connection
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return false
+			}
+			return true
+		}
+
+		unread, err := c.notificationService.GetUnreadCount(reqCtx, userId)
+		if err != nil {
+			return false
+		}
+		ctx.SSEvent("unread", unread)
+		return true
+	})
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/miraicantsleep/myits-event-be/utils"
+)
+
+type (
+	OIDCController interface {
+		Login(ctx *gin.Context)
+		Authorize(ctx *gin.Context)
+		Callback(ctx *gin.Context)
+	}
+
+	oidcController struct {
+		oidcService service.OIDCService
+	}
+)
+
+func NewOIDCController(os service.OIDCService) OIDCController {
+	return &oidcController{
+		oidcService: os,
+	}
+}
+
+// Login sends the browser straight to the provider
+func (c *oidcController) Login(ctx *gin.Context) {
+	authorizationURL, err := c.oidcService.AuthorizationURL(ctx.Request.Context())
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_OIDC_AUTHORIZE, err.Error(), nil)
+		ctx.JSON(oidcErrorStatus(err), res)
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, authorizationURL)
+}
+
+// Authorize returns the provider URL for frontends that navigate themselves
+func (c *oidcController) Authorize(ctx *gin.Context) {
+	authorizationURL, err := c.oidcService.AuthorizationURL(ctx.Request.Context())
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_OIDC_AUTHORIZE, err.Error(), nil)
+		ctx.JSON(oidcErrorStatus(err), res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_OIDC_AUTHORIZE, dto.OIDCAuthorizeResponse{AuthorizationURL: authorizationURL})
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *oidcController) Callback(ctx *gin.Context) {
+	var req dto.OIDCCallbackRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.oidcService.Callback(ctx.Request.Context(), req, dto.ClientInfo{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_OIDC_CALLBACK, err.Error(), nil)
+		ctx.JSON(oidcErrorStatus(err), res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_OIDC_CALLBACK, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func oidcErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, dto.ErrOIDCDisabled):
+		return http.StatusNotFound
+	case errors.Is(err, dto.ErrOIDCProvider):
+		return http.StatusBadGateway
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/miraicantsleep/myits-event-be/utils"
+)
+
+type (
+	OrganizationController interface {
+		Create(ctx *gin.Context)
+		GetAllOrganizations(ctx *gin.Context)
+		GetOrganizationByID(ctx *gin.Context)
+		Update(ctx *gin.Context)
+		GetMyOrganizations(ctx *gin.Context)
+		SwitchOrganization(ctx *gin.Context)
+		GetMembers(ctx *gin.Context)
+		AddMember(ctx *gin.Context)
+		UpdateMember(ctx *gin.Context)
+		RemoveMember(ctx *gin.Context)
+	}
+
+	organizationController struct {
+		organizationService service.OrganizationService
+	}
+)
+
+func NewOrganizationController(os service.OrganizationService) OrganizationController {
+	return &organizationController{
+		organizationService: os,
+	}
+}
+
+func (c *organizationController) Create(ctx *gin.Context) {
+	var req dto.OrganizationCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.organizationService.Create(ctx.Request.Context(), req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_ORGANIZATION, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_ORGANIZATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *organizationController) GetAllOrganizations(ctx *gin.Context) {
+	result, err := c.organizationService.GetAllOrganizations(ctx.Request.Context())
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_ORGANIZATION, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_ORGANIZATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *organizationController) GetOrganizationByID(ctx *gin.Context) {
+	result, err := c.organizationService.GetOrganizationByID(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_ORGANIZATION, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_ORGANIZATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *organizationController) Update(ctx *gin.Context) {
+	var req dto.OrganizationUpdateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	result, err := c.organizationService.Update(ctx.Request.Context(), ctx.Param("id"), req, userId, role)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_ORGANIZATION, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_ORGANIZATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *organizationController) GetMyOrganizations(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.organizationService.GetMyOrganizations(ctx.Request.Context(), userId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_MY_ORGANIZATIONS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_MY_ORGANIZATIONS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *organizationController) SwitchOrganization(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.organizationService.SwitchOrganization(ctx.Request.Context(), ctx.Param("id"), userId, ctx.GetString("session_id"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_SWITCH_ORGANIZATION, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_SWITCH_ORGANIZATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *organizationController) GetMembers(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	result, err := c.organizationService.GetMembers(ctx.Request.Context(), ctx.Param("id"), userId, role)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_ORGANIZATION_MEMBER, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_ORGANIZATION_MEMBER, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *organizationController) AddMember(ctx *gin.Context) {
+	var req dto.OrganizationMemberAddRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	result, err := c.organizationService.AddMember(ctx.Request.Context(), ctx.Param("id"), req, userId, role)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_ADD_MEMBER, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_ADD_MEMBER, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *organizationController) UpdateMember(ctx *gin.Context) {
+	var req dto.OrganizationMemberUpdateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	result, err := c.organizationService.UpdateMember(ctx.Request.Context(), ctx.Param("id"), ctx.Param("user_id"), req, userId, role)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_MEMBER, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_MEMBER, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *organizationController) RemoveMember(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	if err := c.organizationService.RemoveMember(ctx.Request.Context(), ctx.Param("id"), ctx.Param("user_id"), userId, role); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REMOVE_MEMBER, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REMOVE_MEMBER, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// actingOrganization returns the organization an ormawa caller acts for, or
+// an empty string for roles that are not limited to one organization.
+func actingOrganization(ctx *gin.Context) string {
+	if ctx.GetString("role") != "ormawa" {
+		return ""
+	}
+	return ctx.GetString("organization_id")
+}
@@ -16,16 +16,29 @@ type (
 		GetAllRoom(ctx *gin.Context)
 		Update(ctx *gin.Context)
 		Delete(ctx *gin.Context)
+		GetAvailableRooms(ctx *gin.Context)
+		GetOperatingHours(ctx *gin.Context)
+		SetOperatingHours(ctx *gin.Context)
+		GetBlackouts(ctx *gin.Context)
+		CreateBlackout(ctx *gin.Context)
+		DeleteBlackout(ctx *gin.Context)
+		UploadImages(ctx *gin.Context)
+		GetImages(ctx *gin.Context)
+		DeleteImage(ctx *gin.Context)
 	}
 
 	roomController struct {
-		roomService service.RoomService
+		roomService     service.RoomService
+		scheduleService service.RoomScheduleService
+		imageService    service.RoomImageService
 	}
 )
 
-func NewRoomController(rs service.RoomService) RoomController {
+func NewRoomController(rs service.RoomService, ss service.RoomScheduleService, is service.RoomImageService) RoomController {
 	return &roomController{
-		roomService: rs,
+		roomService:     rs,
+		scheduleService: ss,
+		imageService:    is,
 	}
 }
 
@@ -69,7 +82,14 @@ func (c *roomController) GetRoomByID(ctx *gin.Context) {
 }
 
 func (c *roomController) GetAllRoom(ctx *gin.Context) {
-	result, err := c.roomService.GetAllRoom(ctx.Request.Context())
+	var filter dto.RoomFilterRequest
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.roomService.GetAllRoom(ctx.Request.Context(), filter)
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_ALL_ROOM, err.Error(), nil)
 		ctx.JSON(http.StatusBadRequest, res)
@@ -123,3 +143,163 @@ func (c *roomController) Delete(ctx *gin.Context) {
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_ROOM, nil)
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (c *roomController) GetAvailableRooms(ctx *gin.Context) {
+	var req dto.RoomAvailabilityRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.scheduleService.GetAvailableRooms(ctx.Request.Context(), req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_AVAILABLE_ROOMS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_AVAILABLE_ROOMS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *roomController) GetOperatingHours(ctx *gin.Context) {
+	roomID := ctx.Param("id")
+	result, err := c.scheduleService.GetRoomOperatingHours(ctx.Request.Context(), roomID)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_OPERATING_HOURS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_OPERATING_HOURS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *roomController) SetOperatingHours(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	var req dto.OperatingHoursUpdateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	roomID := ctx.Param("id")
+	result, err := c.scheduleService.SetRoomOperatingHours(ctx.Request.Context(), roomID, req, userId, role)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_SET_OPERATING_HOURS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_SET_OPERATING_HOURS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *roomController) GetBlackouts(ctx *gin.Context) {
+	roomID := ctx.Param("id")
+	result, err := c.scheduleService.GetRoomBlackouts(ctx.Request.Context(), roomID)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_BLACKOUTS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_BLACKOUTS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *roomController) CreateBlackout(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	var req dto.BlackoutCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	roomID := ctx.Param("id")
+	result, err := c.scheduleService.CreateRoomBlackout(ctx.Request.Context(), roomID, req, userId, role)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_BLACKOUT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_BLACKOUT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *roomController) DeleteBlackout(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	blackoutID := ctx.Param("blackout_id")
+	if err := c.scheduleService.DeleteBlackout(ctx.Request.Context(), blackoutID, userId, role); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_BLACKOUT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_BLACKOUT, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *roomController) UploadImages(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	var req dto.RoomImageUploadRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	roomID := ctx.Param("id")
+	result, err := c.imageService.Upload(ctx.Request.Context(), roomID, req.Kind, form.File["files"], userId, role)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPLOAD_ROOM_IMAGE, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPLOAD_ROOM_IMAGE, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *roomController) GetImages(ctx *gin.Context) {
+	roomID := ctx.Param("id")
+	result, err := c.imageService.GetRoomImages(ctx.Request.Context(), roomID)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_ROOM_IMAGES, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_ROOM_IMAGES, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *roomController) DeleteImage(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	role := ctx.MustGet("role").(string)
+	roomID := ctx.Param("id")
+	imageID := ctx.Param("image_id")
+	if err := c.imageService.Delete(ctx.Request.Context(), roomID, imageID, userId, role); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_ROOM_IMAGE, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_ROOM_IMAGE, nil)
+	ctx.JSON(http.StatusOK, res)
+}
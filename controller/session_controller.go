@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/miraicantsleep/myits-event-be/utils"
+)
+
+type (
+	SessionController interface {
+		Refresh(ctx *gin.Context)
+		Logout(ctx *gin.Context)
+		GetMySessions(ctx *gin.Context)
+		RevokeMySession(ctx *gin.Context)
+		GetUserSessions(ctx *gin.Context)
+		RevokeUserSessions(ctx *gin.Context)
+	}
+
+	sessionController struct {
+		sessionService service.SessionService
+	}
+)
+
+func NewSessionController(ss service.SessionService) SessionController {
+	return &sessionController{
+		sessionService: ss,
+	}
+}
+
+func (c *sessionController) Refresh(ctx *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.sessionService.Refresh(ctx.Request.Context(), req, dto.ClientInfo{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REFRESH_TOKEN, err.Error(), nil)
+		ctx.JSON(http.StatusUnauthorized, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REFRESH_TOKEN, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *sessionController) Logout(ctx *gin.Context) {
+	sessionId := ctx.MustGet("session_id").(string)
+
+	if err := c.sessionService.Logout(ctx.Request.Context(), sessionId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_LOGOUT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_LOGOUT, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *sessionController) GetMySessions(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	sessionId := ctx.MustGet("session_id").(string)
+
+	result, err := c.sessionService.GetSessions(ctx.Request.Context(), userId, sessionId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_SESSIONS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_SESSIONS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *sessionController) RevokeMySession(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+
+	if err := c.sessionService.RevokeSession(ctx.Request.Context(), ctx.Param("id"), userId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REVOKE_SESSION, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REVOKE_SESSION, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *sessionController) GetUserSessions(ctx *gin.Context) {
+	result, err := c.sessionService.GetSessions(ctx.Request.Context(), ctx.Param("id"), "")
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_SESSIONS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_SESSIONS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *sessionController) RevokeUserSessions(ctx *gin.Context) {
+	if err := c.sessionService.RevokeAllSessions(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REVOKE_SESSION, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REVOKE_SESSION, nil)
+	ctx.JSON(http.StatusOK, res)
+}
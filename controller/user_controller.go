@@ -1,7 +1,10 @@
 package controller
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -18,6 +21,17 @@ type (
 		GetAllUser(ctx *gin.Context)
 		Update(ctx *gin.Context)
 		Delete(ctx *gin.Context)
+		SendVerificationEmail(ctx *gin.Context)
+		VerifyEmail(ctx *gin.Context)
+		ForgotPassword(ctx *gin.Context)
+		ResetPassword(ctx *gin.Context)
+		CreateAccount(ctx *gin.Context)
+		ChangeRole(ctx *gin.Context)
+		DisableUser(ctx *gin.Context)
+		EnableUser(ctx *gin.Context)
+		GetRoleChanges(ctx *gin.Context)
+		UnlockLogin(ctx *gin.Context)
+		UnlockIP(ctx *gin.Context)
 	}
 
 	userController struct {
@@ -105,7 +119,23 @@ func (c *userController) Login(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.userService.Verify(ctx.Request.Context(), req)
+	result, err := c.userService.Verify(ctx.Request.Context(), req, dto.ClientInfo{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	var throttled *dto.LoginThrottleError
+	if errors.As(err, &throttled) {
+		// 423 while locked out, 429 while waiting out a delay
+		status := http.StatusTooManyRequests
+		if throttled.Locked {
+			status = http.StatusLocked
+		}
+		retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+		ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_LOGIN, err.Error(), dto.LoginThrottleResponse{RetryAfter: retryAfter})
+		ctx.JSON(status, res)
+		return
+	}
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_LOGIN, err.Error(), nil)
 		ctx.JSON(http.StatusBadRequest, res)
@@ -148,3 +178,176 @@ func (c *userController) Delete(ctx *gin.Context) {
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_USER, nil)
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (c *userController) SendVerificationEmail(ctx *gin.Context) {
+	var req dto.SendVerificationEmailRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := c.userService.SendVerificationEmail(ctx.Request.Context(), req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_SEND_VERIFICATION, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SEND_VERIFICATION_EMAIL_SUCCESS, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *userController) VerifyEmail(ctx *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.userService.VerifyEmail(ctx.Request.Context(), req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_VERIFY_EMAIL, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_VERIFY_EMAIL, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *userController) ForgotPassword(ctx *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := c.userService.ForgotPassword(ctx.Request.Context(), req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_FORGOT_PASSWORD, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_FORGOT_PASSWORD, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *userController) ResetPassword(ctx *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := c.userService.ResetPassword(ctx.Request.Context(), req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_RESET_PASSWORD, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_RESET_PASSWORD, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *userController) CreateAccount(ctx *gin.Context) {
+	var req dto.AdminUserCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	adminId := ctx.MustGet("user_id").(string)
+	result, err := c.userService.CreateAccount(ctx.Request.Context(), req, adminId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REGISTER_USER, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REGISTER_USER, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *userController) ChangeRole(ctx *gin.Context) {
+	var req dto.UserRoleUpdateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	adminId := ctx.MustGet("user_id").(string)
+	result, err := c.userService.ChangeRole(ctx.Request.Context(), ctx.Param("id"), req, adminId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CHANGE_ROLE, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CHANGE_ROLE, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *userController) DisableUser(ctx *gin.Context) {
+	adminId := ctx.MustGet("user_id").(string)
+	result, err := c.userService.DisableUser(ctx.Request.Context(), ctx.Param("id"), adminId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DISABLE_USER, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DISABLE_USER, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *userController) EnableUser(ctx *gin.Context) {
+	adminId := ctx.MustGet("user_id").(string)
+	result, err := c.userService.EnableUser(ctx.Request.Context(), ctx.Param("id"), adminId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_ENABLE_USER, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_ENABLE_USER, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *userController) GetRoleChanges(ctx *gin.Context) {
+	result, err := c.userService.GetRoleChanges(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_ROLE_CHANGES, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_ROLE_CHANGES, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *userController) UnlockLogin(ctx *gin.Context) {
+	if err := c.userService.UnlockLogin(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UNLOCK_LOGIN, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UNLOCK_LOGIN, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *userController) UnlockIP(ctx *gin.Context) {
+	if err := c.userService.UnlockIP(ctx.Request.Context(), ctx.Param("ip")); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UNLOCK_LOGIN, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UNLOCK_LOGIN, nil)
+	ctx.JSON(http.StatusOK, res)
+}
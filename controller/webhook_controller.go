@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/miraicantsleep/myits-event-be/utils"
+)
+
+type (
+	WebhookController interface {
+		Create(ctx *gin.Context)
+		GetAll(ctx *gin.Context)
+		GetByID(ctx *gin.Context)
+		Update(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+		GetDeliveries(ctx *gin.Context)
+		Redeliver(ctx *gin.Context)
+		Ping(ctx *gin.Context)
+	}
+
+	webhookController struct {
+		webhookService service.WebhookService
+	}
+)
+
+func NewWebhookController(ws service.WebhookService) WebhookController {
+	return &webhookController{
+		webhookService: ws,
+	}
+}
+
+func (c *webhookController) Create(ctx *gin.Context) {
+	var req dto.WebhookCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.webhookService.Create(ctx.Request.Context(), req, userId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_WEBHOOK, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_WEBHOOK, result)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *webhookController) GetAll(ctx *gin.Context) {
+	result, err := c.webhookService.GetAll(ctx.Request.Context())
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WEBHOOKS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_WEBHOOKS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *webhookController) GetByID(ctx *gin.Context) {
+	result, err := c.webhookService.GetByID(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WEBHOOK, err.Error(), nil)
+		ctx.JSON(http.StatusNotFound, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_WEBHOOK, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *webhookController) Update(ctx *gin.Context) {
+	var req dto.WebhookUpdateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.webhookService.Update(ctx.Request.Context(), ctx.Param("id"), req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_WEBHOOK, err.Error(), nil)
+		ctx.JSON(webhookErrorStatus(err), res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_WEBHOOK, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *webhookController) Delete(ctx *gin.Context) {
+	if err := c.webhookService.Delete(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_WEBHOOK, err.Error(), nil)
+		ctx.JSON(webhookErrorStatus(err), res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_WEBHOOK, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *webhookController) GetDeliveries(ctx *gin.Context) {
+	var req dto.PaginationRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.webhookService.GetDeliveries(ctx.Request.Context(), ctx.Param("id"), req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WEBHOOK_DELIVERIES, err.Error(), nil)
+		ctx.JSON(webhookErrorStatus(err), res)
+		return
+	}
+
+	resp := utils.Response{
+		Status:  true,
+		Message: dto.MESSAGE_SUCCESS_GET_WEBHOOK_DELIVERIES,
+		Data:    result.Data,
+		Meta:    result.PaginationResponse,
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (c *webhookController) Redeliver(ctx *gin.Context) {
+	result, err := c.webhookService.Redeliver(ctx.Request.Context(), ctx.Param("id"), ctx.Param("delivery_id"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REDELIVER_WEBHOOK, err.Error(), nil)
+		ctx.JSON(webhookErrorStatus(err), res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REDELIVER_WEBHOOK, result)
+	ctx.JSON(http.StatusAccepted, res)
+}
+
+func (c *webhookController) Ping(ctx *gin.Context) {
+	result, err := c.webhookService.Ping(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PING_WEBHOOK, err.Error(), nil)
+		ctx.JSON(webhookErrorStatus(err), res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_PING_WEBHOOK, result)
+	ctx.JSON(http.StatusAccepted, res)
+}
+
+func webhookErrorStatus(err error) int {
+	if errors.Is(err, dto.ErrWebhookNotFound) || errors.Is(err, dto.ErrWebhookDeliveryNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_CREATE_API_KEY = "failed create api key"
+	MESSAGE_FAILED_GET_API_KEYS   = "failed get api keys"
+	MESSAGE_FAILED_GET_API_KEY    = "failed get api key"
+	MESSAGE_FAILED_REVOKE_API_KEY = "failed revoke api key"
+
+	// Success
+	MESSAGE_SUCCESS_CREATE_API_KEY = "success create api key"
+	MESSAGE_SUCCESS_GET_API_KEYS   = "success get api keys"
+	MESSAGE_SUCCESS_GET_API_KEY    = "success get api key"
+	MESSAGE_SUCCESS_REVOKE_API_KEY = "success revoke api key"
+)
+
+var (
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+	ErrAPIKeyInvalid      = errors.New("invalid api key")
+	ErrAPIKeyRevoked      = errors.New("api key has been revoked")
+	ErrAPIKeyExpired      = errors.New("api key has expired")
+	ErrAPIKeyScope        = errors.New("api key is not allowed to call this route")
+	ErrAPIKeyScopeInvalid = errors.New("scopes must look like read:/api/..., write:/api/... or *:/api/...")
+	ErrAPIKeyExpiresAt    = errors.New("expires_at must be in the future")
+)
+
+type (
+	APIKeyCreateRequest struct {
+		Name string `json:"name" binding:"required,min=2,max=100"`
+		// UserID is the account the key acts as
+		UserID         string     `json:"user_id" binding:"required,uuid"`
+		OrganizationID string     `json:"organization_id" binding:"omitempty,uuid"`
+		Scopes         []string   `json:"scopes" binding:"required,min=1"`
+		ExpiresAt      *time.Time `json:"expires_at"`
+	}
+
+	APIKeyResponse struct {
+		ID             string     `json:"id"`
+		Name           string     `json:"name"`
+		Prefix         string     `json:"prefix"`
+		UserID         string     `json:"user_id"`
+		OrganizationID string     `json:"organization_id,omitempty"`
+		Scopes         []string   `json:"scopes"`
+		ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+		LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+		LastUsedIP     string     `json:"last_used_ip,omitempty"`
+		RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+		CreatedBy      string     `json:"created_by"`
+		CreatedAt      time.Time  `json:"created_at"`
+	}
+
+	// APIKeyCreateResponse is the only time the key itself is returned
+	APIKeyCreateResponse struct {
+		APIKeyResponse
+		Key string `json:"key"`
+	}
+)
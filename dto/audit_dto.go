@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_GET_AUDIT_LOGS    = "failed get audit logs"
+	MESSAGE_FAILED_EXPORT_AUDIT_LOGS = "failed export audit logs"
+
+	// Success
+	MESSAGE_SUCCESS_GET_AUDIT_LOGS = "success get audit logs"
+)
+
+var (
+	ErrAuditTimeRange = errors.New("from and to must be RFC3339 times, from before to")
+)
+
+type (
+	// AuditLogFilter narrows the audit log, every field is optional. From and
+	// To are RFC3339 times; From is inclusive and To exclusive.
+	AuditLogFilter struct {
+		ActorID    string `form:"actor_id" binding:"omitempty,uuid"`
+		ActorRole  string `form:"actor_role"`
+		Action     string `form:"action" binding:"omitempty,oneof=create update delete"`
+		EntityType string `form:"entity_type"`
+		EntityID   string `form:"entity_id"`
+		RequestID  string `form:"request_id"`
+		From       string `form:"from"`
+		To         string `form:"to"`
+	}
+
+	AuditLogListRequest struct {
+		PaginationRequest
+		AuditLogFilter
+	}
+
+	AuditLogResponse struct {
+		ID         string    `json:"id"`
+		ActorID    string    `json:"actor_id,omitempty"`
+		ActorRole  string    `json:"actor_role"`
+		Action     string    `json:"action"`
+		EntityType string    `json:"entity_type"`
+		EntityID   string    `json:"entity_id"`
+		Before     any       `json:"before,omitempty"`
+		After      any       `json:"after,omitempty"`
+		IP         string    `json:"ip"`
+		RequestID  string    `json:"request_id"`
+		Route      string    `json:"route"`
+		CreatedAt  time.Time `json:"created_at"`
+	}
+
+	AuditLogPaginationResponse struct {
+		Data []AuditLogResponse `json:"data"`
+		PaginationResponse
+	}
+)
+
+// TimeRange parses From and To, leaving a bound zero when it is not set
+func (f AuditLogFilter) TimeRange() (from time.Time, to time.Time, err error) {
+	if f.From != "" {
+		if from, err = time.Parse(time.RFC3339, f.From); err != nil {
+			return time.Time{}, time.Time{}, ErrAuditTimeRange
+		}
+	}
+	if f.To != "" {
+		if to, err = time.Parse(time.RFC3339, f.To); err != nil {
+			return time.Time{}, time.Time{}, ErrAuditTimeRange
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return time.Time{}, time.Time{}, ErrAuditTimeRange
+	}
+	return from, to, nil
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
 
 const (
 	// Success
@@ -22,23 +26,42 @@ const (
 	MESSAGE_FAILED_REJECT_BOOKING_REQUEST   = "Failed reject booking request"
 )
 
+var (
+	ErrBookingStatusChange = errors.New("the booking status can only be changed by approving or rejecting the request")
+	ErrBookingNotPending   = errors.New("the booking request has already been approved or rejected")
+)
+
 type BookingRequestCreateRequest struct {
-	EventID uuid.UUID   `json:"event_id" binding:"required"`
-	RoomIDs []uuid.UUID `json:"room_ids" binding:"required,min=1"`
+	EventID    uuid.UUID                 `json:"event_id" binding:"required"`
+	RoomIDs    []uuid.UUID               `json:"room_ids" binding:"required,min=1"`
+	Equipments []BookingEquipmentRequest `json:"equipments" binding:"omitempty,dive"`
 }
 
 type BookingRequestUpdateRequest struct {
-	RoomIDs []uuid.UUID `json:"room_ids" binding:"omitempty,min=1"`
-	Status  string      `json:"status" binding:"omitempty,oneof=pending approved rejected"`
+	RoomIDs    []uuid.UUID               `json:"room_ids" binding:"omitempty,min=1"`
+	Equipments []BookingEquipmentRequest `json:"equipments" binding:"omitempty,dive"`
+	Status     string                    `json:"status" binding:"omitempty,oneof=pending approved rejected"`
+}
+
+type BookingEquipmentRequest struct {
+	EquipmentID uuid.UUID `json:"equipment_id" binding:"required"`
+	Quantity    int       `json:"quantity" binding:"required,gt=0"`
 }
 
 type BookingRequestResponse struct {
-	ID          uuid.UUID      `json:"id"`
-	EventID     uuid.UUID      `json:"event_id"`
-	EventName   string         `json:"event_name"`
-	RequestedAt string         `json:"requested_at"`
-	Status      string         `json:"status"`
-	Rooms       []RoomResponse `json:"rooms"`
+	ID          uuid.UUID                  `json:"id"`
+	EventID     uuid.UUID                  `json:"event_id"`
+	EventName   string                     `json:"event_name"`
+	RequestedAt string                     `json:"requested_at"`
+	Status      string                     `json:"status"`
+	Rooms       []RoomResponse             `json:"rooms"`
+	Equipments  []BookingEquipmentResponse `json:"equipments,omitempty"`
+}
+
+type BookingEquipmentResponse struct {
+	EquipmentID uuid.UUID `json:"equipment_id"`
+	Name        string    `json:"name"`
+	Quantity    int       `json:"quantity"`
 }
 
 // Add/Replace these structs in the file
@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Topics of the domain events published on the event bus
+const (
+	TopicInvitationCreated = "invitation.created"
+	TopicRSVPChanged       = "invitation.rsvp_changed"
+	TopicAttendanceMarked  = "invitation.attendance_marked"
+	TopicBookingApproved   = "booking.approved"
+	TopicBookingRejected   = "booking.rejected"
+	TopicEventUpdated      = "event.updated"
+	TopicEventCancelled    = "event.cancelled"
+)
+
+// DomainEvent is something that happened in the domain. Events are stored as
+// JSON until delivered, so they carry ids and the few values subscribers need
+// rather than whole entities.
+type DomainEvent interface {
+	Topic() string
+}
+
+type (
+	InvitationCreated struct {
+		InvitationID uuid.UUID   `json:"invitation_id"`
+		EventID      uuid.UUID   `json:"event_id"`
+		EventName    string      `json:"event_name"`
+		UserIDs      []uuid.UUID `json:"user_ids"`
+	}
+
+	RSVPChanged struct {
+		InvitationID   uuid.UUID `json:"invitation_id"`
+		EventID        uuid.UUID `json:"event_id"`
+		UserID         uuid.UUID `json:"user_id"`
+		RSVPStatus     string    `json:"rsvp_status"`
+		AttendanceMode string    `json:"attendance_mode,omitempty"`
+		RsvpAt         time.Time `json:"rsvp_at"`
+	}
+
+	AttendanceMarked struct {
+		InvitationID uuid.UUID `json:"invitation_id"`
+		EventID      uuid.UUID `json:"event_id"`
+		UserID       uuid.UUID `json:"user_id"`
+		AttendedAt   time.Time `json:"attended_at"`
+	}
+
+	BookingApproved struct {
+		BookingRequestID uuid.UUID `json:"booking_request_id"`
+		EventID          uuid.UUID `json:"event_id"`
+		EventName        string    `json:"event_name"`
+		// EventCreatedBy is the user who asked for the booking
+		EventCreatedBy uuid.UUID `json:"event_created_by"`
+	}
+
+	BookingRejected struct {
+		BookingRequestID uuid.UUID `json:"booking_request_id"`
+		EventID          uuid.UUID `json:"event_id"`
+		EventName        string    `json:"event_name"`
+		EventCreatedBy   uuid.UUID `json:"event_created_by"`
+	}
+
+	EventUpdated struct {
+		EventID   uuid.UUID `json:"event_id"`
+		EventName string    `json:"event_name"`
+		StartTime time.Time `json:"start_time"`
+		EndTime   time.Time `json:"end_time"`
+		// Rescheduled is set when the start or end time changed
+		Rescheduled bool `json:"rescheduled"`
+	}
+
+	EventCancelled struct {
+		EventID   uuid.UUID `json:"event_id"`
+		EventName string    `json:"event_name"`
+	}
+)
+
+func (InvitationCreated) Topic() string { return TopicInvitationCreated }
+func (RSVPChanged) Topic() string       { return TopicRSVPChanged }
+func (AttendanceMarked) Topic() string  { return TopicAttendanceMarked }
+func (BookingApproved) Topic() string   { return TopicBookingApproved }
+func (BookingRejected) Topic() string   { return TopicBookingRejected }
+func (EventUpdated) Topic() string      { return TopicEventUpdated }
+func (EventCancelled) Topic() string    { return TopicEventCancelled }
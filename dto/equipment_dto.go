@@ -0,0 +1,72 @@
+package dto
+
+import "errors"
+
+const (
+	// Success
+	MESSAGE_SUCCESS_CREATE_EQUIPMENT           = "Success create equipment"
+	MESSAGE_SUCCESS_GET_EQUIPMENT_BY_ID        = "Success get equipment by id"
+	MESSAGE_SUCCESS_GET_ALL_EQUIPMENT          = "Success get all equipment"
+	MESSAGE_SUCCESS_UPDATE_EQUIPMENT           = "Success update equipment"
+	MESSAGE_SUCCESS_DELETE_EQUIPMENT           = "Success delete equipment"
+	MESSAGE_SUCCESS_GET_EQUIPMENT_AVAILABILITY = "Success get equipment availability"
+
+	// Failed
+	MESSAGE_FAILED_CREATE_EQUIPMENT           = "Failed create equipment"
+	MESSAGE_FAILED_GET_EQUIPMENT_BY_ID        = "Failed get equipment by id"
+	MESSAGE_FAILED_GET_ALL_EQUIPMENT          = "Failed get all equipment"
+	MESSAGE_FAILED_UPDATE_EQUIPMENT           = "Failed update equipment"
+	MESSAGE_FAILED_DELETE_EQUIPMENT           = "Failed delete equipment"
+	MESSAGE_FAILED_GET_EQUIPMENT_AVAILABILITY = "Failed get equipment availability"
+)
+
+var (
+	ErrEquipmentNotFound       = errors.New("equipment not found")
+	ErrEquipmentAlreadyExists  = errors.New("equipment already exists")
+	ErrEquipmentNotAvailable   = errors.New("requested equipment quantity is not available for the event time")
+	ErrEquipmentInvalidRange   = errors.New("end time must be after start time")
+	ErrEquipmentDuplicateInReq = errors.New("equipment listed more than once in the booking request")
+	ErrEquipmentDepartment     = errors.New("equipment belongs to a department that owns none of the booked rooms")
+)
+
+type (
+	EquipmentCreateRequest struct {
+		Name         string `json:"name" binding:"required,min=2,max=100"`
+		Description  string `json:"description" binding:"omitempty"`
+		Quantity     int    `json:"quantity" binding:"required,gt=0"`
+		DepartmentID string `json:"department_id" binding:"omitempty"` // required for admin, optional for department role
+	}
+
+	EquipmentUpdateRequest struct {
+		Name        string `json:"name" binding:"omitempty,min=2,max=100"`
+		Description string `json:"description" binding:"omitempty"`
+		Quantity    int    `json:"quantity" binding:"omitempty,gt=0"`
+	}
+
+	EquipmentFilterRequest struct {
+		DepartmentID string `form:"department_id" binding:"omitempty,uuid"`
+		Search       string `form:"search"`
+	}
+
+	EquipmentAvailabilityRequest struct {
+		StartTime string `form:"start_time" binding:"required"`
+		EndTime   string `form:"end_time" binding:"required"`
+	}
+
+	EquipmentResponse struct {
+		ID           string `json:"id"`
+		Name         string `json:"name"`
+		Description  string `json:"description"`
+		Quantity     int    `json:"quantity"`
+		Department   string `json:"department"`
+		DepartmentID string `json:"department_id"`
+	}
+
+	EquipmentAvailabilityResponse struct {
+		EquipmentID string `json:"equipment_id"`
+		Name        string `json:"name"`
+		Quantity    int    `json:"quantity"`
+		Booked      int    `json:"booked"`
+		Available   int    `json:"available"`
+	}
+)
@@ -8,20 +8,32 @@ import (
 const (
 	EVENT_TYPE_ONLINE  = "online"
 	EVENT_TYPE_OFFLINE = "offline"
+	EVENT_TYPE_HYBRID  = "hybrid"
 
 	// FAILED
-	MESSAGE_FAILED_CREATE_EVENT   = "failed create event"
-	MESSAGE_FAILED_GET_EVENT      = "failed get event"
-	MESSAGE_FAILED_DELETE_EVENT   = "failed delete event"
-	MESSAGE_FAILED_UPDATE_EVENT   = "failed update event"
-	MESSAGE_FAILED_GET_LIST_EVENT = "failed get list event"
+	MESSAGE_FAILED_CREATE_EVENT         = "failed create event"
+	MESSAGE_FAILED_GET_EVENT            = "failed get event"
+	MESSAGE_FAILED_DELETE_EVENT         = "failed delete event"
+	MESSAGE_FAILED_UPDATE_EVENT         = "failed update event"
+	MESSAGE_FAILED_GET_LIST_EVENT       = "failed get list event"
+	MESSAGE_FAILED_GET_ATTENDANCE_STATS = "failed get attendance stats"
+	MESSAGE_FAILED_UPLOAD_POSTER        = "failed upload event poster"
+	MESSAGE_FAILED_DELETE_POSTER        = "failed delete event poster"
+	MESSAGE_FAILED_UPLOAD_ATTACHMENT    = "failed upload event attachment"
+	MESSAGE_FAILED_DELETE_ATTACHMENT    = "failed delete event attachment"
+	MESSAGE_FAILED_DOWNLOAD_ATTACHMENT  = "failed download event attachment"
 
 	// SUCCESS
-	MESSAGE_SUCCESS_CREATE_EVENT   = "success create event"
-	MESSAGE_SUCCESS_GET_EVENT      = "success get event"
-	MESSAGE_SUCCESS_DELETE_EVENT   = "success delete event"
-	MESSAGE_SUCCESS_UPDATE_EVENT   = "success update event"
-	MESSAGE_SUCCESS_GET_LIST_EVENT = "success get list event"
+	MESSAGE_SUCCESS_CREATE_EVENT         = "success create event"
+	MESSAGE_SUCCESS_GET_EVENT            = "success get event"
+	MESSAGE_SUCCESS_DELETE_EVENT         = "success delete event"
+	MESSAGE_SUCCESS_UPDATE_EVENT         = "success update event"
+	MESSAGE_SUCCESS_GET_LIST_EVENT       = "success get list event"
+	MESSAGE_SUCCESS_GET_ATTENDANCE_STATS = "success get attendance stats"
+	MESSAGE_SUCCESS_UPLOAD_POSTER        = "success upload event poster"
+	MESSAGE_SUCCESS_DELETE_POSTER        = "success delete event poster"
+	MESSAGE_SUCCESS_UPLOAD_ATTACHMENT    = "success upload event attachment"
+	MESSAGE_SUCCESS_DELETE_ATTACHMENT    = "success delete event attachment"
 )
 
 var (
@@ -31,23 +43,40 @@ var (
 	ErrDeleteEvent   = errors.New("failed to delete event")
 	ErrGetAllEvent   = errors.New("failed to get all events")
 	ErrEventNotFound = errors.New("event not found")
+
+	ErrEventPosterInvalidType = errors.New("poster must be a JPEG, PNG or GIF image")
+	ErrAttachmentInvalidType  = errors.New("attachment must be a PDF, image or office document")
+	ErrAttachmentNotFound     = errors.New("attachment not found")
+	ErrMeetingURLRequired     = errors.New("meeting url is required for online events")
+	ErrEventPosterNotFound    = errors.New("event has no poster")
 )
 
 type (
 	EventCreateRequest struct {
 		Name        string `json:"name" form:"name" binding:"required,min=2,max=100"`
-		Description string `json:"description" form:"description" binding:"required,min=10,max=500"`
+		Description string `json:"description" form:"description" binding:"required,min=10,max=10000"`
 		Start_Time  string `json:"start_time" form:"start_time" binding:"required"`
 		End_Time    string `json:"end_time" form:"end_time" binding:"required"`
-		Event_Type  string `json:"event_type" form:"event_type" binding:"required,oneof=online offline"`
+		Event_Type  string `json:"event_type" form:"event_type" binding:"required,oneof=online offline hybrid"`
+
+		MeetingURL      string `json:"meeting_url" form:"meeting_url" binding:"omitempty,http_url,max=500"`
+		MeetingPasscode string `json:"meeting_passcode" form:"meeting_passcode" binding:"omitempty,max=100"`
+		MeetingPlatform string `json:"meeting_platform" form:"meeting_platform" binding:"omitempty,oneof=zoom google_meet microsoft_teams other"`
+		// hybrid events only, 0 means no limit on online attendees
+		OnlineQuota int `json:"online_quota" form:"online_quota" binding:"omitempty,min=0"`
 	}
 
 	EventUpdateRequest struct {
 		Name        string `json:"name" form:"name" binding:"omitempty,min=2,max=100"`
-		Description string `json:"description" form:"description" binding:"omitempty,min=10,max=500"`
+		Description string `json:"description" form:"description" binding:"omitempty,min=10,max=10000"`
 		Start_Time  string `json:"start_time" form:"start_time" binding:"omitempty"`
 		End_Time    string `json:"end_time" form:"end_time" binding:"omitempty"`
-		Event_Type  string `json:"event_type" form:"event_type" binding:"omitempty,oneof=online offline"`
+		Event_Type  string `json:"event_type" form:"event_type" binding:"omitempty,oneof=online offline hybrid"`
+
+		MeetingURL      string `json:"meeting_url" form:"meeting_url" binding:"omitempty,http_url,max=500"`
+		MeetingPasscode string `json:"meeting_passcode" form:"meeting_passcode" binding:"omitempty,max=100"`
+		MeetingPlatform string `json:"meeting_platform" form:"meeting_platform" binding:"omitempty,oneof=zoom google_meet microsoft_teams other"`
+		OnlineQuota     *int   `json:"online_quota" form:"online_quota" binding:"omitempty,min=0"`
 	}
 
 	GetAllEventRepositoryResponse struct {
@@ -56,14 +85,31 @@ type (
 	}
 
 	EventResponse struct {
+		ID              string                    `json:"id"`
+		Name            string                    `json:"name"`
+		Description     string                    `json:"description"`
+		DescriptionHTML string                    `json:"description_html"`
+		Start_Time      string                    `json:"start_time"`
+		End_Time        string                    `json:"end_time"`
+		Created_By      string                    `json:"created_by"`
+		OrganizationID  string                    `json:"organization_id,omitempty"`
+		Event_Type      string                    `json:"event_type"`
+		Duration        int                       `json:"duration" gorm:"column:duration_in_minutes"`
+		OnlineQuota     int                       `json:"online_quota,omitempty"`
+		MeetingURL      string                    `json:"meeting_url,omitempty"`
+		MeetingPasscode string                    `json:"meeting_passcode,omitempty"`
+		MeetingPlatform string                    `json:"meeting_platform,omitempty"`
+		PosterPath      string                    `json:"-"`
+		PosterURL       string                    `json:"poster_url,omitempty"`
+		Attachments     []EventAttachmentResponse `json:"attachments,omitempty"`
+	}
+
+	EventAttachmentResponse struct {
 		ID          string `json:"id"`
 		Name        string `json:"name"`
-		Description string `json:"description"`
-		Start_Time  string `json:"start_time"`
-		End_Time    string `json:"end_time"`
-		Created_By  string `json:"created_by"`
-		Event_Type  string `json:"event_type"`
-		Duration    int    `json:"duration" gorm:"column:duration_in_minutes"`
+		MimeType    string `json:"mime_type"`
+		Size        int64  `json:"size"`
+		DownloadURL string `json:"download_url"`
 	}
 
 	EventPaginationResponse struct {
@@ -71,6 +117,28 @@ type (
 		PaginationResponse
 	}
 
+	// AttendanceModeStats counts invitees of one attendance mode. Capacity 0
+	// means there is no limit.
+	AttendanceModeStats struct {
+		Capacity int   `json:"capacity"`
+		Accepted int64 `json:"accepted"`
+		Attended int64 `json:"attended"`
+	}
+
+	EventAttendanceStatsResponse struct {
+		EventID   string              `json:"event_id"`
+		EventType string              `json:"event_type"`
+		InPerson  AttendanceModeStats `json:"in_person"`
+		Online    AttendanceModeStats `json:"online"`
+	}
+
+	// AttendanceModeCount is a row of the per-mode attendance query
+	AttendanceModeCount struct {
+		Mode     string `gorm:"column:mode"`
+		Accepted int64  `gorm:"column:accepted"`
+		Attended int64  `gorm:"column:attended"`
+	}
+
 	UserAttendanceResponse struct {
 		UserID     string    `json:"user_id" gorm:"column:user_id"`
 		UserName   string    `json:"user_name" gorm:"column:user_name"`
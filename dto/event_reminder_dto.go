@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_GET_EVENT_REMINDERS    = "failed get event reminders"
+	MESSAGE_FAILED_UPDATE_EVENT_REMINDERS = "failed update event reminders"
+
+	// Success
+	MESSAGE_SUCCESS_GET_EVENT_REMINDERS    = "success get event reminders"
+	MESSAGE_SUCCESS_UPDATE_EVENT_REMINDERS = "success update event reminders"
+)
+
+var (
+	ErrReminderOffsetDuplicate = errors.New("reminder offsets must be unique")
+)
+
+type (
+	EventReminderRequest struct {
+		// OffsetsMinutes are minutes before the start time, e.g. [1440, 60]
+		// for a day and an hour before; an empty list turns reminders off
+		OffsetsMinutes []int `json:"offsets_minutes" binding:"required,max=5,dive,min=5,max=43200"`
+		IncludePending bool  `json:"include_pending"`
+	}
+
+	EventReminderResponse struct {
+		EventID        string                  `json:"event_id"`
+		IncludePending bool                    `json:"include_pending"`
+		Reminders      []EventReminderSchedule `json:"reminders"`
+	}
+
+	EventReminderSchedule struct {
+		OffsetMinutes int       `json:"offset_minutes"`
+		SendAt        time.Time `json:"send_at"`
+		Sent          int64     `json:"sent"`
+	}
+
+	EventReminderRecipient struct {
+		UserID         uuid.UUID `json:"user_id"`
+		UserName       string    `json:"user_name"`
+		UserEmail      string    `json:"user_email"`
+		QRCode         string    `json:"qr_code"`
+		RSVPStatus     string    `json:"rsvp_status"`
+		AttendanceMode string    `json:"attendance_mode"`
+	}
+
+	EventReminderDeliveryCount struct {
+		OffsetMinutes int   `json:"offset_minutes"`
+		Sent          int64 `json:"sent"`
+	}
+)
@@ -0,0 +1,9 @@
+package dto
+
+import "errors"
+
+// shared by every endpoint that accepts file uploads
+var (
+	ErrFileRequired = errors.New("file is required")
+	ErrFileTooLarge = errors.New("file exceeds the maximum upload size")
+)
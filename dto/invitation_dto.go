@@ -35,9 +35,21 @@ var (
 	ErrGetAllInvitations           = errors.New("failed to get all invitations")
 	ErrUpdateInvitation            = errors.New("failed to update invitation")
 	ErrInvitationNotFound          = errors.New("invitation not found")
+	ErrInvitationForbidden         = errors.New("you are not allowed to view this invitation")
 	ErrDeleteInvitation            = errors.New("failed to delete invitation")
 	ErrInvitationAlreadyExists     = errors.New("invitation already exists")
 	ErrInvitationInvalidRSVPStatus = errors.New("invalid RSVP status, must be one of accepted, declined, pending")
+	ErrJoinLinkInvalid             = errors.New("this join link is invalid")
+	ErrJoinNotAccepted             = errors.New("please accept the invitation before joining the event")
+	ErrEventNotOnline              = errors.New("this event is not held online")
+	ErrJoinTooEarly                = errors.New("the meeting room is not open yet, please come back closer to the start time")
+	ErrJoinEventEnded              = errors.New("this event has already ended")
+	ErrAttendanceModeRequired      = errors.New("please choose whether you will attend in person or online")
+	ErrInvalidAttendanceMode       = errors.New("invalid attendance mode, must be one of in-person, online")
+	ErrInPersonQuotaFull           = errors.New("sorry, all in-person seats for this event have been taken")
+	ErrOnlineQuotaFull             = errors.New("sorry, all online seats for this event have been taken")
+	ErrInPersonNotOpen             = errors.New("sorry, no room has been approved for this event yet, so in-person seats are not open")
+	ErrAttendingOnline             = errors.New("this invitee chose to attend online and cannot check in on site")
 )
 
 type CreateInvitationRequest struct {
@@ -69,13 +81,23 @@ type ScanQRCodeResponse struct {
 
 type InvitationResponse struct {
 	ID         string `json:"id"`
+	EventID    string `json:"event_id,omitempty"`
 	EventName  string `json:"event_name"`
+	EventType  string `json:"event_type,omitempty"`
 	Name       string `json:"name,omitempty"`
 	InvitedAt  string `json:"invited_at"`
 	RSVPStatus string `json:"rsvp_status"`
 	RsvpAt     string `json:"rsvp_at,omitempty"`
 	AttendedAt string `json:"attended_at,omitempty"`
 	QRCode     string `json:"qr_code,omitempty"`
+
+	AttendanceMode string `json:"attendance_mode,omitempty"`
+
+	// Only filled once the invitee has accepted to attend online
+	MeetingURL      string `json:"meeting_url,omitempty"`
+	MeetingPasscode string `json:"meeting_passcode,omitempty"`
+	MeetingPlatform string `json:"meeting_platform,omitempty"`
+	JoinURL         string `json:"join_url,omitempty" gorm:"-"`
 }
 
 type InvitationDetailResponse struct {
@@ -91,4 +113,13 @@ type InvitationDetailResponse struct {
 	AttendedAt   *time.Time `json:"attended_at"`
 	QRCode       string     `json:"qr_code"`
 	CreatorName  string     `json:"creator_name"`
+
+	AttendanceMode string `json:"attendance_mode,omitempty"`
+
+	EventType       string    `json:"event_type"`
+	StartTime       time.Time `json:"start_time"`
+	EndTime         time.Time `json:"end_time"`
+	MeetingURL      string    `json:"meeting_url,omitempty"`
+	MeetingPasscode string    `json:"meeting_passcode,omitempty"`
+	MeetingPlatform string    `json:"meeting_platform,omitempty"`
 }
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_GET_JOBS     = "failed get jobs"
+	MESSAGE_FAILED_GET_JOB_RUNS = "failed get job runs"
+	MESSAGE_FAILED_TRIGGER_JOB  = "failed trigger job"
+
+	// Success
+	MESSAGE_SUCCESS_GET_JOBS     = "success get jobs"
+	MESSAGE_SUCCESS_GET_JOB_RUNS = "success get job runs"
+	MESSAGE_SUCCESS_TRIGGER_JOB  = "success trigger job"
+)
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobRunning  = errors.New("job is already running")
+)
+
+type (
+	JobResponse struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Schedule    string `json:"schedule"`
+		// NextRunAt is empty when the scheduler is disabled on this instance
+		NextRunAt *time.Time      `json:"next_run_at,omitempty"`
+		LastRun   *JobRunResponse `json:"last_run,omitempty"`
+	}
+
+	JobRunResponse struct {
+		ID          string     `json:"id"`
+		Job         string     `json:"job"`
+		Trigger     string     `json:"trigger"`
+		TriggeredBy string     `json:"triggered_by,omitempty"`
+		ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+		Status      string     `json:"status"`
+		Result      string     `json:"result,omitempty"`
+		Error       string     `json:"error,omitempty"`
+		Instance    string     `json:"instance"`
+		StartedAt   time.Time  `json:"started_at"`
+		FinishedAt  *time.Time `json:"finished_at,omitempty"`
+		DurationMs  *int64     `json:"duration_ms,omitempty"`
+	}
+)
@@ -0,0 +1,20 @@
+package dto
+
+type (
+	// JWK is a public signing key in RFC 7517 form. RSA keys set N and E,
+	// Ed25519 keys set Crv and X.
+	JWK struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		N   string `json:"n,omitempty"`
+		E   string `json:"e,omitempty"`
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+	}
+
+	JWKSResponse struct {
+		Keys []JWK `json:"keys"`
+	}
+)
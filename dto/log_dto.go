@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_GET_LOGS = "failed get logs"
+
+	// Success
+	MESSAGE_SUCCESS_GET_LOGS = "success get logs"
+)
+
+var (
+	ErrLogTimeRange = errors.New("from and to must be RFC3339 times, from before to")
+)
+
+type (
+	// LogListRequest filters the application log. Level is the minimum level,
+	// Search matches the message and SQL, and SlowMs keeps entries with a
+	// latency_ms of at least that many milliseconds. From and To are RFC3339
+	// times and default to the last 24 hours.
+	LogListRequest struct {
+		PaginationRequest
+		Level     string `form:"level" binding:"omitempty,oneof=debug info warn error"`
+		From      string `form:"from"`
+		To        string `form:"to"`
+		Route     string `form:"route"`
+		SlowMs    int64  `form:"slow_ms" binding:"omitempty,min=1"`
+		RequestID string `form:"request_id"`
+		UserID    string `form:"user_id" binding:"omitempty,uuid"`
+	}
+
+	LogEntryResponse struct {
+		Time    time.Time      `json:"time"`
+		Level   string         `json:"level"`
+		Message string         `json:"msg"`
+		Fields  map[string]any `json:"fields"`
+	}
+
+	LogPaginationResponse struct {
+		Data []LogEntryResponse `json:"data"`
+		PaginationResponse
+	}
+)
+
+// TimeRange parses From and To. A missing To is now and a missing From is
+// 24 hours before To.
+func (r LogListRequest) TimeRange(now time.Time) (from time.Time, to time.Time, err error) {
+	to = now
+	if r.To != "" {
+		if to, err = time.Parse(time.RFC3339, r.To); err != nil {
+			return time.Time{}, time.Time{}, ErrLogTimeRange
+		}
+	}
+	from = to.Add(-24 * time.Hour)
+	if r.From != "" {
+		if from, err = time.Parse(time.RFC3339, r.From); err != nil {
+			return time.Time{}, time.Time{}, ErrLogTimeRange
+		}
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, ErrLogTimeRange
+	}
+	return from, to, nil
+}
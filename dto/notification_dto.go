@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_GET_NOTIFICATIONS      = "failed get notifications"
+	MESSAGE_FAILED_MARK_NOTIFICATION_READ = "failed mark notification as read"
+
+	// Success
+	MESSAGE_SUCCESS_GET_NOTIFICATIONS      = "success get notifications"
+	MESSAGE_SUCCESS_MARK_NOTIFICATION_READ = "success mark notification as read"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+type (
+	NotificationListRequest struct {
+		PaginationRequest
+		UnreadOnly bool `form:"unread_only"`
+	}
+
+	NotificationResponse struct {
+		ID        string     `json:"id"`
+		Type      string     `json:"type"`
+		Title     string     `json:"title"`
+		Body      string     `json:"body"`
+		EventID   string     `json:"event_id,omitempty"`
+		ReadAt    *time.Time `json:"read_at,omitempty"`
+		CreatedAt time.Time  `json:"created_at"`
+	}
+
+	NotificationPaginationResponse struct {
+		Data []NotificationResponse `json:"data"`
+		Meta NotificationMeta       `json:"meta"`
+	}
+
+	NotificationMeta struct {
+		PaginationResponse
+		UnreadCount int64 `json:"unread_count"`
+	}
+
+	NotificationUnreadResponse struct {
+		UnreadCount int64 `json:"unread_count"`
+	}
+
+	MarkNotificationsReadResponse struct {
+		Marked      int64 `json:"marked"`
+		UnreadCount int64 `json:"unread_count"`
+	}
+)
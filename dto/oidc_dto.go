@@ -0,0 +1,34 @@
+package dto
+
+import "errors"
+
+const (
+	// Failed
+	MESSAGE_FAILED_OIDC_AUTHORIZE = "failed start sso login"
+	MESSAGE_FAILED_OIDC_CALLBACK  = "failed sso login"
+
+	// Success
+	MESSAGE_SUCCESS_OIDC_AUTHORIZE = "success start sso login"
+	MESSAGE_SUCCESS_OIDC_CALLBACK  = "success sso login"
+)
+
+var (
+	ErrOIDCDisabled        = errors.New("sso login is not configured")
+	ErrOIDCProvider        = errors.New("sso provider is unavailable")
+	ErrOIDCStateInvalid    = errors.New("sso login has expired or was already used")
+	ErrOIDCCodeExchange    = errors.New("failed to exchange authorization code")
+	ErrOIDCIDTokenInvalid  = errors.New("invalid id token")
+	ErrOIDCEmailMissing    = errors.New("sso account has no email address")
+	ErrOIDCEmailUnverified = errors.New("sso account email address is not verified")
+)
+
+type (
+	OIDCCallbackRequest struct {
+		Code  string `json:"code" form:"code" binding:"required"`
+		State string `json:"state" form:"state" binding:"required"`
+	}
+
+	OIDCAuthorizeResponse struct {
+		AuthorizationURL string `json:"authorization_url"`
+	}
+)
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"errors"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_CREATE_ORGANIZATION     = "failed create organization"
+	MESSAGE_FAILED_GET_LIST_ORGANIZATION   = "failed get list organization"
+	MESSAGE_FAILED_GET_ORGANIZATION        = "failed get organization"
+	MESSAGE_FAILED_UPDATE_ORGANIZATION     = "failed update organization"
+	MESSAGE_FAILED_ADD_MEMBER              = "failed add organization member"
+	MESSAGE_FAILED_UPDATE_MEMBER           = "failed update organization member"
+	MESSAGE_FAILED_REMOVE_MEMBER           = "failed remove organization member"
+	MESSAGE_FAILED_SWITCH_ORGANIZATION     = "failed switch organization"
+	MESSAGE_FAILED_GET_MY_ORGANIZATIONS    = "failed get my organizations"
+	MESSAGE_FAILED_GET_ORGANIZATION_MEMBER = "failed get organization members"
+
+	// Success
+	MESSAGE_SUCCESS_CREATE_ORGANIZATION     = "success create organization"
+	MESSAGE_SUCCESS_GET_LIST_ORGANIZATION   = "success get list organization"
+	MESSAGE_SUCCESS_GET_ORGANIZATION        = "success get organization"
+	MESSAGE_SUCCESS_UPDATE_ORGANIZATION     = "success update organization"
+	MESSAGE_SUCCESS_ADD_MEMBER              = "success add organization member"
+	MESSAGE_SUCCESS_UPDATE_MEMBER           = "success update organization member"
+	MESSAGE_SUCCESS_REMOVE_MEMBER           = "success remove organization member"
+	MESSAGE_SUCCESS_SWITCH_ORGANIZATION     = "success switch organization"
+	MESSAGE_SUCCESS_GET_MY_ORGANIZATIONS    = "success get my organizations"
+	MESSAGE_SUCCESS_GET_ORGANIZATION_MEMBER = "success get organization members"
+)
+
+var (
+	ErrOrganizationNotFound      = errors.New("organization not found")
+	ErrOrganizationNameExists    = errors.New("organization name already exists")
+	ErrOrganizationMemberExists  = errors.New("user is already a member of this organization")
+	ErrOrganizationMemberMissing = errors.New("user is not a member of this organization")
+	ErrOrganizationForbidden     = errors.New("you do not have permission to act for this organization")
+	ErrNoActiveOrganization      = errors.New("no active organization, switch to one of your organizations first")
+	ErrLastChair                 = errors.New("an organization must keep at least one chair")
+	ErrOrganizationMemberRole    = errors.New("only user accounts can join an organization")
+)
+
+type (
+	OrganizationCreateRequest struct {
+		Name        string `json:"name" form:"name" binding:"required,min=2,max=100"`
+		Description string `json:"description" form:"description" binding:"omitempty,max=2000"`
+		// ChairEmail is the account that becomes the first chair
+		ChairEmail string `json:"chair_email" form:"chair_email" binding:"required,email"`
+	}
+
+	OrganizationUpdateRequest struct {
+		Name        string `json:"name" form:"name" binding:"omitempty,min=2,max=100"`
+		Description string `json:"description" form:"description" binding:"omitempty,max=2000"`
+	}
+
+	OrganizationResponse struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		// Role is the caller's role, only filled when listing their own organizations
+		Role string `json:"role,omitempty"`
+	}
+
+	OrganizationMemberAddRequest struct {
+		Email string `json:"email" form:"email" binding:"required,email"`
+		Role  string `json:"role" form:"role" binding:"required,oneof=chair secretary committee scanner"`
+	}
+
+	OrganizationMemberUpdateRequest struct {
+		Role string `json:"role" form:"role" binding:"required,oneof=chair secretary committee scanner"`
+	}
+
+	OrganizationMemberResponse struct {
+		ID        string `json:"id"`
+		UserID    string `json:"user_id"`
+		UserName  string `json:"user_name"`
+		UserEmail string `json:"user_email"`
+		Role      string `json:"role"`
+	}
+)
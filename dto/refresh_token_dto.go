@@ -7,7 +7,14 @@ const (
 	MESSAGE_FAILED_EXPIRED_REFRESH_TOKEN = "Refresh token has expired"
 )
 
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" form:"refresh_token" binding:"required"`
+}
+
 type TokenResponse struct {
-	AccessToken string `json:"access_token"`
-	Role        string `json:"role"`
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token,omitempty"`
+	Role             string `json:"role"`
+	OrganizationID   string `json:"organization_id,omitempty"`
+	OrganizationRole string `json:"organization_role,omitempty"`
 }
@@ -33,22 +33,46 @@ var (
 
 type (
 	RoomCreateRequest struct {
-		Name         string `json:"name" binding:"required"`
-		Capacity     int    `json:"capacity" binding:"required,gt=0"`
-		DepartmentID string `json:"department_id" binding:"omitempty"` // required for admin, optional for department role
+		Name                 string `json:"name" binding:"required"`
+		Capacity             int    `json:"capacity" binding:"required,gt=0"`
+		DepartmentID         string `json:"department_id" binding:"omitempty"` // required for admin, optional for department role
+		HasProjector         bool   `json:"has_projector"`
+		HasSoundSystem       bool   `json:"has_sound_system"`
+		HasAC                bool   `json:"has_ac"`
+		WheelchairAccessible bool   `json:"wheelchair_accessible"`
 	}
 
 	RoomUpdateRequest struct {
 		Name         string `json:"name" binding:"omitempty"`
 		Capacity     int    `json:"capacity" binding:"omitempty"`
 		DepartmentID string `json:"department_id" binding:"omitempty"`
+		// pointers so that an explicit false can be told apart from "not sent"
+		HasProjector         *bool `json:"has_projector" binding:"omitempty"`
+		HasSoundSystem       *bool `json:"has_sound_system" binding:"omitempty"`
+		HasAC                *bool `json:"has_ac" binding:"omitempty"`
+		WheelchairAccessible *bool `json:"wheelchair_accessible" binding:"omitempty"`
+	}
+
+	// RoomFilterRequest is bound from the query string of GET /api/room.
+	// Only amenities set to true are used as filters.
+	RoomFilterRequest struct {
+		Search               string `form:"search"`
+		MinCapacity          int    `form:"min_capacity" binding:"omitempty,gte=0"`
+		HasProjector         bool   `form:"has_projector"`
+		HasSoundSystem       bool   `form:"has_sound_system"`
+		HasAC                bool   `form:"has_ac"`
+		WheelchairAccessible bool   `form:"wheelchair_accessible"`
 	}
 
 	RoomResponse struct {
-		ID           string `json:"id"`
-		Name         string `json:"name"`
-		Department   string `gorm:"column:department_name" json:"department"`
-		DepartmentID string `json:"department_id"`
-		Capacity     int    `json:"capacity"`
+		ID                   string `json:"id"`
+		Name                 string `json:"name"`
+		Department           string `gorm:"column:department_name" json:"department"`
+		DepartmentID         string `json:"department_id"`
+		Capacity             int    `json:"capacity"`
+		HasProjector         bool   `json:"has_projector"`
+		HasSoundSystem       bool   `gorm:"column:has_sound_system" json:"has_sound_system"`
+		HasAC                bool   `gorm:"column:has_ac" json:"has_ac"`
+		WheelchairAccessible bool   `json:"wheelchair_accessible"`
 	}
 )
@@ -0,0 +1,41 @@
+package dto
+
+import "errors"
+
+const (
+	// Success
+	MESSAGE_SUCCESS_UPLOAD_ROOM_IMAGE = "Success upload room image"
+	MESSAGE_SUCCESS_GET_ROOM_IMAGES   = "Success get room images"
+	MESSAGE_SUCCESS_DELETE_ROOM_IMAGE = "Success delete room image"
+
+	// Failed
+	MESSAGE_FAILED_UPLOAD_ROOM_IMAGE = "Failed upload room image"
+	MESSAGE_FAILED_GET_ROOM_IMAGES   = "Failed get room images"
+	MESSAGE_FAILED_DELETE_ROOM_IMAGE = "Failed delete room image"
+)
+
+var (
+	ErrRoomImageNotFound     = errors.New("room image not found")
+	ErrRoomImageInvalidType  = errors.New("file must be a JPEG, PNG or GIF image")
+	ErrFloorPlanSingleFile   = errors.New("only one floor plan can be uploaded per room")
+	ErrRoomImageForbidden    = errors.New("you are not allowed to manage images of this room")
+	ErrRoomImageTooManyFiles = errors.New("too many files in one upload")
+	ErrRoomImageTooLarge     = errors.New("image must not exceed 40 megapixels")
+)
+
+type (
+	// RoomImageUploadRequest is bound from the multipart form; the files
+	// themselves are read from the "files" field.
+	RoomImageUploadRequest struct {
+		Kind string `form:"kind" binding:"required,oneof=photo floor_plan"`
+	}
+
+	RoomImageResponse struct {
+		ID           string `json:"id"`
+		Kind         string `json:"kind"`
+		URL          string `json:"url"`
+		ThumbnailURL string `json:"thumbnail_url"`
+		MimeType     string `json:"mime_type"`
+		Size         int64  `json:"size"`
+	}
+)
@@ -0,0 +1,74 @@
+package dto
+
+import "errors"
+
+const (
+	// Success
+	MESSAGE_SUCCESS_GET_OPERATING_HOURS = "Success get operating hours"
+	MESSAGE_SUCCESS_SET_OPERATING_HOURS = "Success set operating hours"
+	MESSAGE_SUCCESS_GET_BLACKOUTS       = "Success get blackout periods"
+	MESSAGE_SUCCESS_CREATE_BLACKOUT     = "Success create blackout period"
+	MESSAGE_SUCCESS_DELETE_BLACKOUT     = "Success delete blackout period"
+	MESSAGE_SUCCESS_GET_AVAILABLE_ROOMS = "Success get available rooms"
+
+	// Failed
+	MESSAGE_FAILED_GET_OPERATING_HOURS = "Failed get operating hours"
+	MESSAGE_FAILED_SET_OPERATING_HOURS = "Failed set operating hours"
+	MESSAGE_FAILED_GET_BLACKOUTS       = "Failed get blackout periods"
+	MESSAGE_FAILED_CREATE_BLACKOUT     = "Failed create blackout period"
+	MESSAGE_FAILED_DELETE_BLACKOUT     = "Failed delete blackout period"
+	MESSAGE_FAILED_GET_AVAILABLE_ROOMS = "Failed get available rooms"
+)
+
+var (
+	ErrInvalidOperatingHour      = errors.New("operating hours must use HH:MM and close after they open")
+	ErrDuplicateOperatingDay     = errors.New("each day of the week can only be listed once")
+	ErrBlackoutNotFound          = errors.New("blackout period not found")
+	ErrInvalidBlackoutRange      = errors.New("blackout end time must be after start time")
+	ErrRoomOutsideOperatingHours = errors.New("requested time is outside the room's operating hours")
+	ErrRoomBlackedOut            = errors.New("room is closed during the requested time")
+	ErrRoomAlreadyBooked         = errors.New("room is already booked during the requested time")
+	ErrScheduleForbidden         = errors.New("you can only manage the schedule of your own department")
+	ErrInvalidAvailabilityRange  = errors.New("end time must be after start time")
+)
+
+type (
+	OperatingHourRequest struct {
+		DayOfWeek int    `json:"day_of_week" binding:"min=0,max=6"`
+		OpenTime  string `json:"open_time" binding:"required"`
+		CloseTime string `json:"close_time" binding:"required"`
+	}
+
+	// OperatingHoursUpdateRequest replaces the whole weekly schedule. An empty
+	// list removes the restriction.
+	OperatingHoursUpdateRequest struct {
+		Hours []OperatingHourRequest `json:"hours" binding:"omitempty,dive"`
+	}
+
+	OperatingHourResponse struct {
+		DayOfWeek int    `json:"day_of_week"`
+		OpenTime  string `json:"open_time"`
+		CloseTime string `json:"close_time"`
+	}
+
+	BlackoutCreateRequest struct {
+		StartTime string `json:"start_time" binding:"required"`
+		EndTime   string `json:"end_time" binding:"required"`
+		Reason    string `json:"reason" binding:"omitempty,max=255"`
+	}
+
+	BlackoutResponse struct {
+		ID           string `json:"id"`
+		RoomID       string `json:"room_id,omitempty"`
+		DepartmentID string `json:"department_id,omitempty"`
+		StartTime    string `json:"start_time"`
+		EndTime      string `json:"end_time"`
+		Reason       string `json:"reason"`
+	}
+
+	RoomAvailabilityRequest struct {
+		StartTime string `form:"start_time" binding:"required"`
+		EndTime   string `form:"end_time" binding:"required"`
+		RoomFilterRequest
+	}
+)
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_GET_SESSIONS   = "failed get sessions"
+	MESSAGE_FAILED_REVOKE_SESSION = "failed revoke session"
+	MESSAGE_FAILED_LOGOUT         = "failed logout"
+
+	// Success
+	MESSAGE_SUCCESS_GET_SESSIONS   = "success get sessions"
+	MESSAGE_SUCCESS_REVOKE_SESSION = "success revoke session"
+	MESSAGE_SUCCESS_LOGOUT         = "success logout"
+)
+
+var (
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionRevoked      = errors.New("session has been revoked or has expired")
+	ErrCreateSession       = errors.New("failed to create session")
+	ErrRefreshTokenInvalid = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token has expired")
+)
+
+type (
+	// ClientInfo describes where a login comes from, shown in the session list
+	ClientInfo struct {
+		IP        string
+		UserAgent string
+	}
+
+	SessionResponse struct {
+		ID         string    `json:"id"`
+		Device     string    `json:"device"`
+		IP         string    `json:"ip"`
+		CreatedAt  time.Time `json:"created_at"`
+		LastUsedAt time.Time `json:"last_used_at"`
+		ExpiresAt  time.Time `json:"expires_at"`
+		// Current marks the session the request was made with
+		Current bool `json:"current"`
+	}
+)
@@ -2,6 +2,7 @@ package dto
 
 import (
 	"errors"
+	"time"
 
 	"github.com/miraicantsleep/myits-event-be/entity"
 )
@@ -20,6 +21,14 @@ const (
 	MESSAGE_FAILED_PROSES_REQUEST     = "failed proses request"
 	MESSAGE_FAILED_DENIED_ACCESS      = "denied access"
 	MESSAGE_FAILED_VERIFY_EMAIL       = "failed verify email"
+	MESSAGE_FAILED_SEND_VERIFICATION  = "failed send verification email"
+	MESSAGE_FAILED_FORGOT_PASSWORD    = "failed request password reset"
+	MESSAGE_FAILED_RESET_PASSWORD     = "failed reset password"
+	MESSAGE_FAILED_CHANGE_ROLE        = "failed change role"
+	MESSAGE_FAILED_DISABLE_USER       = "failed disable user"
+	MESSAGE_FAILED_ENABLE_USER        = "failed enable user"
+	MESSAGE_FAILED_GET_ROLE_CHANGES   = "failed get role changes"
+	MESSAGE_FAILED_UNLOCK_LOGIN       = "failed unlock login"
 
 	// Success
 	MESSAGE_SUCCESS_REGISTER_USER           = "success create user"
@@ -30,6 +39,13 @@ const (
 	MESSAGE_SUCCESS_DELETE_USER             = "success delete user"
 	MESSAGE_SEND_VERIFICATION_EMAIL_SUCCESS = "success send verification email"
 	MESSAGE_SUCCESS_VERIFY_EMAIL            = "success verify email"
+	MESSAGE_SUCCESS_FORGOT_PASSWORD         = "if the email is registered, a password reset link has been sent"
+	MESSAGE_SUCCESS_RESET_PASSWORD          = "success reset password"
+	MESSAGE_SUCCESS_CHANGE_ROLE             = "success change role"
+	MESSAGE_SUCCESS_DISABLE_USER            = "success disable user"
+	MESSAGE_SUCCESS_ENABLE_USER             = "success enable user"
+	MESSAGE_SUCCESS_GET_ROLE_CHANGES        = "success get role changes"
+	MESSAGE_SUCCESS_UNLOCK_LOGIN            = "success unlock login"
 )
 
 var (
@@ -44,21 +60,89 @@ var (
 	ErrTokenInvalid           = errors.New("token invalid")
 	ErrTokenExpired           = errors.New("token expired")
 	ErrAccountAlreadyVerified = errors.New("account already verified")
+	ErrAccountNotVerified     = errors.New("please verify your email before logging in")
+	ErrSendEmail              = errors.New("failed to send email")
+	ErrAccountDisabled        = errors.New("this account has been disabled")
+	ErrRoleUnchanged          = errors.New("user already has this role")
+	ErrInvalidRole            = errors.New("this role cannot be given to the account")
+	ErrAccountAlreadyDisabled = errors.New("this account is already disabled")
+	ErrAccountNotDisabled     = errors.New("this account is not disabled")
+	ErrChangeOwnAccount       = errors.New("you cannot change the role or status of your own account")
+	ErrDepartmentRoleChange   = errors.New("departemen accounts are tied to a department and cannot be changed to or from another role")
+	ErrFacultyRequired        = errors.New("faculty is required for departemen accounts")
+	ErrLoginThrottled         = errors.New("too many failed login attempts, please wait before trying again")
+	ErrLoginLocked            = errors.New("login is temporarily locked after too many failed attempts")
+	ErrInvalidIP              = errors.New("invalid ip address")
+	ErrTooManyRequests        = errors.New("too many requests, please slow down")
 )
 
+// LoginThrottleError is returned by login while an email address or client IP
+// has to wait, either out a progressive delay or a lockout. It unwraps to
+// ErrLoginThrottled or ErrLoginLocked.
+type LoginThrottleError struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottleError) Error() string {
+	return e.Unwrap().Error()
+}
+
+func (e *LoginThrottleError) Unwrap() error {
+	if e.Locked {
+		return ErrLoginLocked
+	}
+	return ErrLoginThrottled
+}
+
 type (
+	// UserCreateRequest is the public sign-up form, which always creates a
+	// "user" account.
 	UserCreateRequest struct {
 		Name     string `json:"name" form:"name" binding:"required,min=2,max=100"`
 		Email    string `json:"email" form:"email" binding:"required,email"`
 		Password string `json:"password" form:"password" binding:"required,min=8"`
-		Role     string `json:"role" form:"role" binding:"required,oneof=user departemen ormawa admin"`
+	}
+
+	// AdminUserCreateRequest provisions an organisation account. Departemen
+	// accounts also get a department, so they need a faculty.
+	AdminUserCreateRequest struct {
+		Name     string `json:"name" form:"name" binding:"required,min=2,max=100"`
+		Email    string `json:"email" form:"email" binding:"required,email"`
+		Password string `json:"password" form:"password" binding:"required,min=8"`
+		Role     string `json:"role" form:"role" binding:"required,oneof=departemen ormawa"`
+		Faculty  string `json:"faculty" form:"faculty" binding:"omitempty,min=2,max=100"`
+		Reason   string `json:"reason" form:"reason" binding:"omitempty,max=255"`
+	}
+
+	UserRoleUpdateRequest struct {
+		Role   string `json:"role" form:"role" binding:"required,oneof=user ormawa admin"`
+		Reason string `json:"reason" form:"reason" binding:"omitempty,max=255"`
 	}
 
 	UserResponse struct {
-		ID    string `json:"id"`
-		Name  string `json:"name"`
-		Email string `json:"email"`
-		Role  string `json:"role"`
+		ID         string `json:"id"`
+		Name       string `json:"name"`
+		Email      string `json:"email"`
+		Role       string `json:"role"`
+		IsVerified bool   `json:"is_verified"`
+		IsDisabled bool   `json:"is_disabled"`
+	}
+
+	RoleChangeResponse struct {
+		ID            string    `json:"id"`
+		UserID        string    `json:"user_id"`
+		OldRole       string    `json:"old_role"`
+		NewRole       string    `json:"new_role"`
+		Reason        string    `json:"reason"`
+		ChangedBy     string    `json:"changed_by"`
+		ChangedByName string    `json:"changed_by_name"`
+		CreatedAt     time.Time `json:"created_at"`
+	}
+
+	// LoginThrottleResponse tells a throttled client how many seconds to wait
+	LoginThrottleResponse struct {
+		RetryAfter int `json:"retry_after"`
 	}
 
 	UserPaginationResponse struct {
@@ -90,4 +174,26 @@ type (
 		Email    string `json:"email" form:"email" binding:"required"`
 		Password string `json:"password" form:"password" binding:"required"`
 	}
+
+	SendVerificationEmailRequest struct {
+		Email string `json:"email" form:"email" binding:"required,email"`
+	}
+
+	VerifyEmailRequest struct {
+		Token string `json:"token" form:"token" binding:"required"`
+	}
+
+	VerifyEmailResponse struct {
+		Email      string `json:"email"`
+		IsVerified bool   `json:"is_verified"`
+	}
+
+	ForgotPasswordRequest struct {
+		Email string `json:"email" form:"email" binding:"required,email"`
+	}
+
+	ResetPasswordRequest struct {
+		Token    string `json:"token" form:"token" binding:"required"`
+		Password string `json:"password" form:"password" binding:"required,min=8"`
+	}
 )
@@ -0,0 +1,113 @@
+package dto
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_CREATE_WEBHOOK         = "failed create webhook"
+	MESSAGE_FAILED_GET_WEBHOOKS           = "failed get webhooks"
+	MESSAGE_FAILED_GET_WEBHOOK            = "failed get webhook"
+	MESSAGE_FAILED_UPDATE_WEBHOOK         = "failed update webhook"
+	MESSAGE_FAILED_DELETE_WEBHOOK         = "failed delete webhook"
+	MESSAGE_FAILED_GET_WEBHOOK_DELIVERIES = "failed get webhook deliveries"
+	MESSAGE_FAILED_REDELIVER_WEBHOOK      = "failed redeliver webhook"
+	MESSAGE_FAILED_PING_WEBHOOK           = "failed ping webhook"
+
+	// Success
+	MESSAGE_SUCCESS_CREATE_WEBHOOK         = "success create webhook"
+	MESSAGE_SUCCESS_GET_WEBHOOKS           = "success get webhooks"
+	MESSAGE_SUCCESS_GET_WEBHOOK            = "success get webhook"
+	MESSAGE_SUCCESS_UPDATE_WEBHOOK         = "success update webhook"
+	MESSAGE_SUCCESS_DELETE_WEBHOOK         = "success delete webhook"
+	MESSAGE_SUCCESS_GET_WEBHOOK_DELIVERIES = "success get webhook deliveries"
+	MESSAGE_SUCCESS_REDELIVER_WEBHOOK      = "success redeliver webhook"
+	MESSAGE_SUCCESS_PING_WEBHOOK           = "success ping webhook"
+
+	// WebhookEventPing is only sent by the ping endpoint
+	WebhookEventPing = "ping"
+)
+
+// WebhookEventTypes are the domain event topics a webhook can receive
+var WebhookEventTypes = []string{
+	TopicInvitationCreated,
+	TopicRSVPChanged,
+	TopicAttendanceMarked,
+	TopicBookingApproved,
+	TopicBookingRejected,
+	TopicEventUpdated,
+	TopicEventCancelled,
+}
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrWebhookURL              = errors.New("url must be an absolute http or https url")
+	ErrWebhookEventType        = errors.New("unknown webhook event type")
+)
+
+type (
+	WebhookCreateRequest struct {
+		URL         string   `json:"url" binding:"required,max=2048"`
+		Description string   `json:"description" binding:"max=255"`
+		EventTypes  []string `json:"event_types" binding:"required,min=1"`
+	}
+
+	// WebhookUpdateRequest only changes the fields that are set
+	WebhookUpdateRequest struct {
+		URL         string   `json:"url" binding:"max=2048"`
+		Description *string  `json:"description" binding:"omitempty,max=255"`
+		EventTypes  []string `json:"event_types"`
+		Active      *bool    `json:"active"`
+	}
+
+	WebhookResponse struct {
+		ID          string    `json:"id"`
+		URL         string    `json:"url"`
+		Description string    `json:"description"`
+		EventTypes  []string  `json:"event_types"`
+		Active      bool      `json:"active"`
+		CreatedBy   string    `json:"created_by"`
+		CreatedAt   time.Time `json:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at"`
+	}
+
+	// WebhookCreateResponse is the only time the signing secret is returned
+	WebhookCreateResponse struct {
+		WebhookResponse
+		Secret string `json:"secret"`
+	}
+
+	WebhookDeliveryResponse struct {
+		ID             string     `json:"id"`
+		WebhookID      string     `json:"webhook_id"`
+		EventType      string     `json:"event_type"`
+		Payload        any        `json:"payload"`
+		Status         string     `json:"status"`
+		Attempts       int        `json:"attempts"`
+		NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+		RedeliveryOf   string     `json:"redelivery_of,omitempty"`
+		ResponseStatus *int       `json:"response_status,omitempty"`
+		ResponseBody   string     `json:"response_body,omitempty"`
+		Error          string     `json:"error,omitempty"`
+		DurationMs     *int64     `json:"duration_ms,omitempty"`
+		CreatedAt      time.Time  `json:"created_at"`
+		DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	}
+
+	WebhookDeliveryPaginationResponse struct {
+		Data []WebhookDeliveryResponse `json:"data"`
+		PaginationResponse
+	}
+
+	// WebhookPayload is the body POSTed to a webhook. ID stays the same when
+	// a delivery is redelivered, so receivers can drop duplicates.
+	WebhookPayload struct {
+		ID        string    `json:"id"`
+		Type      string    `json:"type"`
+		CreatedAt time.Time `json:"created_at"`
+		Data      any       `json:"data"`
+	}
+)
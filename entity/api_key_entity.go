@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix starts every API key, so keys are recognisable in headers and
+// in secret scanners
+const APIKeyPrefix = "mek_"
+
+// APIKey lets an external system call the API as its owner. Only the SHA-256
+// hash of the key is stored; Prefix keeps the first characters so admins can
+// tell keys apart.
+//
+// Scopes is a space separated list of "action:route" entries, where action is
+// read, write or * and route is a route pattern such as /api/event/:id, or a
+// prefix ending in /*.
+type APIKey struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name           string     `gorm:"type:varchar(100);not null" json:"name"`
+	Prefix         string     `gorm:"type:varchar(16);not null" json:"prefix"`
+	KeyHash        string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	User           User       `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid" json:"organization_id,omitempty"`
+	Scopes         string     `gorm:"type:text;not null" json:"scopes"`
+	ExpiresAt      *time.Time `gorm:"type:timestamp with time zone;default:null" json:"expires_at,omitempty"`
+	LastUsedAt     *time.Time `gorm:"type:timestamp with time zone;default:null" json:"last_used_at,omitempty"`
+	LastUsedIP     string     `gorm:"type:varchar(45)" json:"last_used_ip"`
+	RevokedAt      *time.Time `gorm:"type:timestamp with time zone;default:null" json:"revoked_at,omitempty"`
+	CreatedBy      uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+
+	Timestamp
+}
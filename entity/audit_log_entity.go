@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+
+	// AuditRoleSystem is recorded for writes made outside a request, such as
+	// jobs and event subscribers
+	AuditRoleSystem = "system"
+	// AuditRoleAnonymous is recorded for writes of unauthenticated requests,
+	// such as registration
+	AuditRoleAnonymous = "anonymous"
+)
+
+// AuditLog records one row written to an audited table. Before and After hold
+// the changed columns only: After alone for a create, Before alone for a
+// delete. The table is append-only, a trigger rejects updates and deletes.
+type AuditLog struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ActorID    *uuid.UUID `gorm:"type:uuid;default:null" json:"actor_id,omitempty"`
+	ActorRole  string     `gorm:"type:varchar(20);not null" json:"actor_role"`
+	Action     string     `gorm:"type:varchar(10);not null" json:"action"`
+	EntityType string     `gorm:"type:varchar(100);not null" json:"entity_type"`
+	EntityID   string     `gorm:"type:varchar(100);not null" json:"entity_id"`
+	Before     *string    `gorm:"type:jsonb;default:null" json:"before,omitempty"`
+	After      *string    `gorm:"type:jsonb;default:null" json:"after,omitempty"`
+	IP         string     `gorm:"type:varchar(45);not null;default:''" json:"ip"`
+	RequestID  string     `gorm:"type:varchar(64);not null;default:''" json:"request_id"`
+	// Route is the method and route pattern of the request, like
+	// "PATCH /api/event/:id"
+	Route     string    `gorm:"type:varchar(255);not null;default:''" json:"route"`
+	CreatedAt time.Time `gorm:"type:timestamp with time zone;not null" json:"created_at"`
+}
@@ -13,5 +13,7 @@ type BookingRequest struct {
 	RequestedAt time.Time `gorm:"type:timestamp;not null;default:current_timestamp" json:"requested_at"`
 	Status      string    `gorm:"type:booking_status;not null;default:'pending'" json:"status" validate:"required,oneof=pending approved rejected"`
 	Rooms       []Room    `gorm:"many2many:booking_request_room" json:"rooms"`
+
+	Equipments []BookingRequestEquipment `gorm:"foreignKey:BookingRequestID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"equipments,omitempty"`
 	Timestamp
 }
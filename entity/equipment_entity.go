@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+// Equipment is a department-owned item (microphone, extra projector, ...) that
+// can be booked alongside rooms.
+type Equipment struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	DepartmentID uuid.UUID  `gorm:"type:uuid;not null" json:"department_id"`
+	Department   Department `gorm:"foreignKey:DepartmentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"department"`
+	Name         string     `gorm:"type:varchar(100);not null" json:"name"`
+	Description  string     `gorm:"type:text" json:"description"`
+	Quantity     int        `gorm:"not null" json:"quantity"`
+	Timestamp
+}
+
+type BookingRequestEquipment struct {
+	BookingRequestID uuid.UUID `gorm:"type:uuid;primaryKey" json:"booking_request_id"`
+	EquipmentID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"equipment_id"`
+	Quantity         int       `gorm:"not null" json:"quantity"`
+	Equipment        Equipment `gorm:"foreignKey:EquipmentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"equipment"`
+}
+
+func (BookingRequestEquipment) TableName() string { return "booking_request_equipment" }
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+// EventAttachment is a downloadable document (TOR, rundown, ...) attached to
+// an event. Path is a storage key; Name is the original file name used for
+// downloads.
+type EventAttachment struct {
+	ID       uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	EventID  uuid.UUID `gorm:"type:uuid;not null;index" json:"event_id"`
+	Name     string    `gorm:"type:varchar(255);not null" json:"name"`
+	Path     string    `gorm:"type:varchar(255);not null" json:"path"`
+	MimeType string    `gorm:"type:varchar(100);not null" json:"mime_type"`
+	Size     int64     `gorm:"not null" json:"size"`
+	Timestamp
+}
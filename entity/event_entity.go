@@ -9,20 +9,46 @@ import (
 const (
 	EventTypeOnline  = "online"
 	EventTypeOffline = "offline"
+	EventTypeHybrid  = "hybrid"
+
+	MeetingPlatformZoom       = "zoom"
+	MeetingPlatformGoogleMeet = "google_meet"
+	MeetingPlatformTeams      = "microsoft_teams"
+	MeetingPlatformOther      = "other"
 )
 
 type Event struct {
 	ID                uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
 	Name              string    `gorm:"type:varchar(100);not null" json:"name" validate:"required,min=2,max=100"`
-	Description       string    `gorm:"type:text;not null" json:"description" validate:"required,min=10,max=500"`
+	Description       string    `gorm:"type:text;not null" json:"description" validate:"required,min=10,max=10000"`
 	Start_Time        time.Time `gorm:"type:timestamp;not null" json:"start_time" validate:"required"`
 	End_Time          time.Time `gorm:"type:timestamp;not null" json:"end_time" validate:"required"`
 	Created_By        uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
-	Event_Type        string    `gorm:"type:event_type;not null;default:'offline'" json:"event_type" validate:"required,oneof=online offline"`
+	Event_Type        string    `gorm:"type:event_type;not null;default:'offline'" json:"event_type" validate:"required,oneof=online offline hybrid"`
 	DurationInMinutes int       `gorm:"type:integer;" json:"duration_in_minutes"`
+	PosterPath        string    `gorm:"type:varchar(255)" json:"poster_path"`
+
+	// OrganizationID is the organization owning the event; admin-created events have none
+	OrganizationID *uuid.UUID `gorm:"type:uuid;index" json:"organization_id,omitempty"`
+
+	// OnlineQuota caps online attendees of a hybrid event; 0 means unlimited.
+	// In-person attendance is capped by the capacity of the booked rooms.
+	OnlineQuota int `gorm:"not null;default:0" json:"online_quota"`
+
+	// Online and hybrid events only; never exposed to invitees who have not accepted
+	MeetingURL      string `gorm:"type:varchar(500)" json:"meeting_url,omitempty"`
+	MeetingPasscode string `gorm:"type:varchar(100)" json:"meeting_passcode,omitempty"`
+	MeetingPlatform string `gorm:"type:varchar(50)" json:"meeting_platform,omitempty"`
+
+	// ReminderOffsets lists the minutes before Start_Time at which invitees
+	// are reminded, comma separated; empty disables reminders. RemindPending
+	// includes invitees who have not answered yet.
+	ReminderOffsets string `gorm:"type:varchar(100);not null;default:'1440,60'" json:"-"`
+	RemindPending   bool   `gorm:"not null;default:false" json:"-"`
 
 	// Relationships
-	Invitations []Invitation `gorm:"foreignKey:EventID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"invitations,omitempty"`
+	Invitations []Invitation      `gorm:"foreignKey:EventID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"invitations,omitempty"`
+	Attachments []EventAttachment `gorm:"foreignKey:EventID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"attachments,omitempty"`
 	// temp
 	Creator_Name string `gorm:"->;column:creator_name" json:"creator_name,omitempty"`
 	Timestamp
@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventReminderDelivery records a reminder sent to an invitee. The primary
+// key makes each reminder of an event go out once per user.
+type EventReminderDelivery struct {
+	EventID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"event_id"`
+	UserID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	OffsetMinutes int       `gorm:"primaryKey;autoIncrement:false" json:"offset_minutes"`
+	SentAt        time.Time `gorm:"type:timestamp with time zone;not null" json:"sent_at"`
+}
@@ -10,6 +10,10 @@ const (
 	RSVPStatusAccepted = "accepted"
 	RSVPStatusDeclined = "declined"
 	RSVPStatusPending  = "pending"
+
+	// AttendanceMode is chosen by the invitee when accepting a hybrid event
+	AttendanceModeInPerson = "in_person"
+	AttendanceModeOnline   = "online"
 )
 
 type Invitation struct {
@@ -28,6 +32,12 @@ type UserInvitation struct {
 	RSVPStatus   string     `gorm:"type:rsvp_status;not null;default:'pending'" json:"rsvp_status" validate:"required,oneof=accepted declined pending"`
 	RsvpAt       *time.Time `gorm:"type:timestamp;default:null" json:"rsvp_at,omitempty"`
 	AttendedAt   *time.Time `gorm:"type:timestamp;default:null" json:"attended_at,omitempty"`
+	// AttendanceMode is only set for hybrid events
+	AttendanceMode *string `gorm:"type:varchar(10);default:null" json:"attendance_mode,omitempty"`
+	// EmailError holds the last send failure and is cleared once the
+	// invitation email goes out
+	EmailSentAt *time.Time `gorm:"type:timestamp;default:null" json:"email_sent_at,omitempty"`
+	EmailError  *string    `gorm:"type:text;default:null" json:"-"`
 }
 
 func (UserInvitation) TableName() string { return "user_invitation" }
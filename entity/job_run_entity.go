@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+
+	JobRunRunning   = "running"
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed"
+)
+
+// JobRun is one execution of a scheduled job. Scheduled runs carry the tick
+// they belong to, and (job, scheduled_at) is unique so a tick runs once even
+// when several instances fire it.
+type JobRun struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Job         string     `gorm:"type:varchar(100);not null" json:"job"`
+	Trigger     string     `gorm:"type:varchar(20);not null" json:"trigger"`
+	TriggeredBy *uuid.UUID `gorm:"type:uuid;default:null" json:"triggered_by,omitempty"`
+	ScheduledAt *time.Time `gorm:"type:timestamp with time zone;default:null" json:"scheduled_at,omitempty"`
+	Status      string     `gorm:"type:varchar(20);not null" json:"status"`
+	Result      string     `gorm:"type:text;not null;default:''" json:"result"`
+	Error       *string    `gorm:"type:text;default:null" json:"error,omitempty"`
+	// Instance is the host and pid that ran the job
+	Instance   string     `gorm:"type:varchar(255);not null" json:"instance"`
+	StartedAt  time.Time  `gorm:"type:timestamp with time zone;not null" json:"started_at"`
+	FinishedAt *time.Time `gorm:"type:timestamp with time zone;default:null" json:"finished_at,omitempty"`
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	LoginThrottleKeyEmail = "email:"
+	LoginThrottleKeyIP    = "ip:"
+)
+
+// LoginThrottle counts consecutive failed logins for one key, either an email
+// address or a client IP. It lives in the database so limits survive restarts
+// and are shared by every instance.
+type LoginThrottle struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Key           string     `gorm:"type:varchar(300);not null;uniqueIndex" json:"key"`
+	Failures      int        `gorm:"not null;default:0" json:"failures"`
+	LastFailureAt time.Time  `gorm:"type:timestamp with time zone;not null" json:"last_failure_at"`
+	LockedUntil   *time.Time `gorm:"type:timestamp with time zone;default:null" json:"locked_until,omitempty"`
+
+	Timestamp
+}
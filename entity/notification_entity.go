@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	NotificationInvitationReceived = "invitation_received"
+	NotificationBookingApproved    = "booking_approved"
+	NotificationBookingRejected    = "booking_rejected"
+	NotificationEventCancelled     = "event_cancelled"
+	NotificationEventRescheduled   = "event_rescheduled"
+)
+
+// Notification is an in-app message for one user. Rows are only ever marked
+// read, never edited.
+type Notification struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	Type   string    `gorm:"type:varchar(50);not null" json:"type"`
+	Title  string    `gorm:"type:varchar(200);not null" json:"title"`
+	Body   string    `gorm:"type:text;not null;default:''" json:"body"`
+	// EventID is the event the notification is about, if any
+	EventID   *uuid.UUID `gorm:"type:uuid;default:null" json:"event_id,omitempty"`
+	ReadAt    *time.Time `gorm:"type:timestamp with time zone;default:null" json:"read_at,omitempty"`
+	CreatedAt time.Time  `gorm:"type:timestamp with time zone;not null" json:"created_at"`
+}
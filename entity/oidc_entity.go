@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to an account at an OpenID Connect provider. The
+// subject is stable at the provider, so later logins find the user even when
+// their email address changes there.
+type UserIdentity struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	User        User      `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Issuer      string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identity_subject" json:"issuer"`
+	Subject     string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identity_subject" json:"subject"`
+	Email       string    `gorm:"type:varchar(255)" json:"email"`
+	LastLoginAt time.Time `gorm:"type:timestamp with time zone;not null" json:"last_login_at"`
+
+	Timestamp
+}
+
+// OIDCLoginState is an authorization request waiting for the provider to send
+// the user back. It is consumed by the callback, which makes the state single
+// use, and keeps the PKCE verifier and nonce off the client.
+type OIDCLoginState struct {
+	State        string    `gorm:"type:varchar(64);primary_key" json:"-"`
+	CodeVerifier string    `gorm:"type:varchar(128);not null" json:"-"`
+	Nonce        string    `gorm:"type:varchar(64);not null" json:"-"`
+	ExpiresAt    time.Time `gorm:"type:timestamp with time zone;not null;index" json:"expires_at"`
+
+	Timestamp
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	OrganizationRoleChair     = "chair"
+	OrganizationRoleSecretary = "secretary"
+	OrganizationRoleCommittee = "committee"
+	OrganizationRoleScanner   = "scanner"
+)
+
+// Organization is a student organisation (ormawa). Events are owned by the
+// organisation and its members act on its behalf with their own accounts.
+type Organization struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name        string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"name" validate:"required,min=2,max=100"`
+	Description string    `gorm:"type:text" json:"description"`
+
+	// Relationships
+	Members []OrganizationMember `gorm:"foreignKey:OrganizationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"members,omitempty"`
+	Events  []Event              `gorm:"foreignKey:OrganizationID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"events,omitempty"`
+	Timestamp
+}
+
+type OrganizationMember struct {
+	ID             uuid.UUID    `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrganizationID uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_organization_member" json:"organization_id"`
+	Organization   Organization `gorm:"foreignKey:OrganizationID" json:"-"`
+	UserID         uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_organization_member;index" json:"user_id"`
+	User           User         `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Role           string       `gorm:"type:varchar(20);not null" json:"role" validate:"required,oneof=chair secretary committee scanner"`
+	Timestamp
+}
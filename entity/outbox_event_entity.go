@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// OutboxEvent is a domain event waiting to be delivered to its subscribers.
+// It is inserted in the same transaction as the change it describes, so it
+// only exists once that change is committed.
+type OutboxEvent struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Topic     string    `gorm:"type:varchar(100);not null" json:"topic"`
+	Payload   string    `gorm:"type:jsonb;not null" json:"payload"`
+	CreatedAt time.Time `gorm:"type:timestamp with time zone;not null" json:"created_at"`
+	Attempts  int       `gorm:"not null;default:0" json:"attempts"`
+	// PendingSubscribers lists, comma separated, the subscribers that still
+	// have to handle the event after a failed attempt; empty means all
+	PendingSubscribers string     `gorm:"type:text;not null;default:''" json:"pending_subscribers,omitempty"`
+	NextAttemptAt      time.Time  `gorm:"type:timestamp with time zone;not null" json:"next_attempt_at"`
+	LastError          *string    `gorm:"type:text;default:null" json:"last_error,omitempty"`
+	DeliveredAt        *time.Time `gorm:"type:timestamp with time zone;default:null" json:"delivered_at,omitempty"`
+	// FailedAt is set when the event ran out of attempts
+	FailedAt *time.Time `gorm:"type:timestamp with time zone;default:null" json:"failed_at,omitempty"`
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// RateLimitBucket is the state of one token bucket of the Postgres-backed rate
+// limiter. Tokens are refilled lazily from RefilledAt on the next request.
+type RateLimitBucket struct {
+	Key        string    `gorm:"type:varchar(300);primary_key" json:"key"`
+	Tokens     float64   `gorm:"type:double precision;not null" json:"tokens"`
+	RefilledAt time.Time `gorm:"type:timestamp with time zone;not null;index" json:"refilled_at"`
+}
@@ -6,12 +6,21 @@ import (
 	"github.com/google/uuid"
 )
 
+// RefreshToken is a login session. Only the SHA-256 hash of the refresh token
+// is stored. Access tokens carry the session id and stop working as soon as
+// the session is revoked.
 type RefreshToken struct {
 	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
-	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
-	Token     string    `gorm:"type:varchar(255);not null;uniqueIndex" json:"-"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
 	ExpiresAt time.Time `gorm:"type:timestamp with time zone;not null" json:"expires_at"`
 	User      User      `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
 
+	// OrganizationID is the organization the session acts for, kept across refreshes
+	OrganizationID *uuid.UUID `gorm:"type:uuid" json:"organization_id,omitempty"`
+	Device         string     `gorm:"type:varchar(255)" json:"device"`
+	IP             string     `gorm:"type:varchar(45)" json:"ip"`
+	LastUsedAt     time.Time  `gorm:"type:timestamp with time zone;not null" json:"last_used_at"`
+
 	Timestamp
 }
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoleChange records every role assigned to an account by an admin, including
+// the initial role of accounts the admin provisions. OldRole is empty for a
+// newly created account.
+type RoleChange struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	ChangedBy uuid.UUID `gorm:"type:uuid;not null" json:"changed_by"`
+	Changer   User      `gorm:"foreignKey:ChangedBy;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	OldRole   string    `gorm:"type:varchar(20)" json:"old_role"`
+	NewRole   string    `gorm:"type:varchar(20);not null" json:"new_role"`
+	Reason    string    `gorm:"type:varchar(255)" json:"reason"`
+	CreatedAt time.Time `gorm:"type:timestamp with time zone" json:"created_at"`
+}
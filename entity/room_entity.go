@@ -11,5 +11,11 @@ type Room struct {
 	Name         string           `gorm:"type:varchar(255);not null" json:"name"`
 	Capacity     int              `gorm:"not null" json:"capacity"`
 	Bookings     []BookingRequest `gorm:"many2many:booking_request_room" json:"bookings,omitempty"`
+
+	// Amenities
+	HasProjector         bool `gorm:"not null;default:false" json:"has_projector"`
+	HasSoundSystem       bool `gorm:"not null;default:false" json:"has_sound_system"`
+	HasAC                bool `gorm:"column:has_ac;not null;default:false" json:"has_ac"`
+	WheelchairAccessible bool `gorm:"not null;default:false" json:"wheelchair_accessible"`
 	Timestamp
 }
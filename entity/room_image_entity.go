@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	RoomImageKindPhoto     = "photo"
+	RoomImageKindFloorPlan = "floor_plan"
+)
+
+// RoomImage is an uploaded room photo or floor plan. Path and ThumbnailPath
+// are storage keys, not URLs.
+type RoomImage struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RoomID        uuid.UUID `gorm:"type:uuid;not null;index" json:"room_id"`
+	Room          Room      `gorm:"foreignKey:RoomID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Kind          string    `gorm:"type:varchar(20);not null" json:"kind"`
+	Path          string    `gorm:"type:varchar(255);not null" json:"path"`
+	ThumbnailPath string    `gorm:"type:varchar(255);not null" json:"thumbnail_path"`
+	MimeType      string    `gorm:"type:varchar(50);not null" json:"mime_type"`
+	Size          int64     `gorm:"not null" json:"size"`
+	Timestamp
+}
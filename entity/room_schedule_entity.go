@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OperatingHour is the opening window of a room or of every room in a
+// department for one day of the week. Exactly one of RoomID and DepartmentID
+// is set; room-level hours take precedence over department-level hours.
+type OperatingHour struct {
+	ID           uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RoomID       *uuid.UUID  `gorm:"type:uuid;index" json:"room_id,omitempty"`
+	Room         *Room       `gorm:"foreignKey:RoomID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	DepartmentID *uuid.UUID  `gorm:"type:uuid;index" json:"department_id,omitempty"`
+	Department   *Department `gorm:"foreignKey:DepartmentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	DayOfWeek    int         `gorm:"type:smallint;not null" json:"day_of_week"` // 0 = Sunday, follows time.Weekday
+	OpenTime     string      `gorm:"type:varchar(5);not null" json:"open_time"` // HH:MM
+	CloseTime    string      `gorm:"type:varchar(5);not null" json:"close_time"`
+	Timestamp
+}
+
+// BlackoutPeriod closes a room, or every room in a department, for exams,
+// maintenance or holidays.
+type BlackoutPeriod struct {
+	ID           uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RoomID       *uuid.UUID  `gorm:"type:uuid;index" json:"room_id,omitempty"`
+	Room         *Room       `gorm:"foreignKey:RoomID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	DepartmentID *uuid.UUID  `gorm:"type:uuid;index" json:"department_id,omitempty"`
+	Department   *Department `gorm:"foreignKey:DepartmentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	StartTime    time.Time   `gorm:"type:timestamp;not null" json:"start_time"`
+	EndTime      time.Time   `gorm:"type:timestamp;not null" json:"end_time"`
+	Reason       string      `gorm:"type:varchar(255)" json:"reason"`
+	Timestamp
+}
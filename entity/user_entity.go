@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/miraicantsleep/myits-event-be/helpers"
 	"gorm.io/gorm"
@@ -22,6 +24,9 @@ type User struct {
 	Password string    `gorm:"type:varchar(255);not null" json:"password" validate:"required,min=8"`
 	Role     UserRole  `gorm:"type:user_role;not null;default:'user'" json:"role" validate:"required,oneof=user departemen ormawa admin"`
 
+	IsVerified bool       `gorm:"not null;default:false" json:"is_verified"`
+	DisabledAt *time.Time `gorm:"type:timestamp with time zone;default:null" json:"disabled_at,omitempty"`
+
 	// relationships
 	Events      []Event      `gorm:"foreignKey:Created_By;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"events,omitempty"`
 	Department  *Department  `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"department,omitempty"`
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	UserTokenPurposeVerifyEmail   = "verify_email"
+	UserTokenPurposeResetPassword = "reset_password"
+)
+
+// UserToken is a single-use token mailed to a user. Only the SHA-256 hash of
+// the token is stored, so a leaked table cannot be used to take over accounts.
+type UserToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	User      User       `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Purpose   string     `gorm:"type:varchar(20);not null" json:"purpose"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"type:timestamp with time zone;not null" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"type:timestamp with time zone;default:null" json:"used_at,omitempty"`
+
+	Timestamp
+}
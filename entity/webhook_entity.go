@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookSecretPrefix starts every webhook signing secret
+const WebhookSecretPrefix = "whsec_"
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook is an integration endpoint that receives the selected event types.
+// The secret is kept in plain text because every payload is signed with it.
+type Webhook struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	URL         string    `gorm:"type:varchar(2048);not null" json:"url"`
+	Description string    `gorm:"type:varchar(255);not null;default:''" json:"description"`
+	Secret      string    `gorm:"type:varchar(100);not null" json:"-"`
+	// EventTypes are the comma separated topics the webhook receives
+	EventTypes string    `gorm:"type:text;not null" json:"event_types"`
+	Active     bool      `gorm:"not null;default:true" json:"active"`
+	CreatedBy  uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+
+	Timestamp
+}
+
+// WebhookDelivery is one payload sent to a webhook, together with the outcome
+// of its latest attempt. Redelivering copies the payload into a new delivery.
+type WebhookDelivery struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	WebhookID     uuid.UUID  `gorm:"type:uuid;not null" json:"webhook_id"`
+	EventType     string     `gorm:"type:varchar(100);not null" json:"event_type"`
+	Payload       string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status        string     `gorm:"type:varchar(20);not null" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"type:timestamp with time zone;not null" json:"next_attempt_at"`
+	RedeliveryOf  *uuid.UUID `gorm:"type:uuid;default:null" json:"redelivery_of,omitempty"`
+	// ResponseStatus and ResponseBody describe the latest attempt; the body
+	// is cut to a few kilobytes
+	ResponseStatus *int       `gorm:"default:null" json:"response_status,omitempty"`
+	ResponseBody   string     `gorm:"type:text;not null;default:''" json:"response_body"`
+	Error          *string    `gorm:"type:text;default:null" json:"error,omitempty"`
+	DurationMs     *int64     `gorm:"default:null" json:"duration_ms,omitempty"`
+	CreatedAt      time.Time  `gorm:"type:timestamp with time zone;not null" json:"created_at"`
+	DeliveredAt    *time.Time `gorm:"type:timestamp with time zone;default:null" json:"delivered_at,omitempty"`
+}
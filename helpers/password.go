@@ -4,15 +4,15 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-func HashPassword(password string)(string, error){
+func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 4)
 	return string(bytes), err
 }
 
-func CheckPassword(hashPassword string, plainPassword []byte)(bool, error){
+func CheckPassword(hashPassword string, plainPassword []byte) (bool, error) {
 	hashPW := []byte(hashPassword)
-	if err := bcrypt.CompareHashAndPassword(hashPW, plainPassword); err != nil{
+	if err := bcrypt.CompareHashAndPassword(hashPW, plainPassword); err != nil {
 		return false, err
 	}
 	return true, nil
-}
\ No newline at end of file
+}
@@ -1,13 +1,17 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"os"
 
 	"github.com/miraicantsleep/myits-event-be/command"
+	"github.com/miraicantsleep/myits-event-be/config"
+	"github.com/miraicantsleep/myits-event-be/constants"
 	"github.com/miraicantsleep/myits-event-be/middleware"
 	"github.com/miraicantsleep/myits-event-be/provider"
 	"github.com/miraicantsleep/myits-event-be/routes"
+	"github.com/miraicantsleep/myits-event-be/service"
 	"github.com/samber/do"
 
 	"github.com/common-nighthawk/go-figure"
@@ -23,11 +27,24 @@ func args(injector *do.Injector) bool {
 	return true
 }
 
-func run(server *gin.Engine) {
+func run(server *gin.Engine, injector *do.Injector) {
 	server.Static("/assets", "./assets")
 
-	if os.Getenv("IS_LOGGER") == "true" {
-		routes.LoggerRoute(server)
+	// SCHEDULER_ENABLED=false keeps this instance out of scheduled runs, jobs
+	// can still be triggered from the admin endpoint
+	if os.Getenv("SCHEDULER_ENABLED") != "false" {
+		scheduler := do.MustInvokeNamed[service.SchedulerService](injector, constants.SchedulerService)
+		scheduler.Start(context.Background())
+	}
+
+	// domain events wait in the outbox, and webhook deliveries in their table,
+	// until an instance with the dispatcher enabled sends them
+	if os.Getenv("EVENT_DISPATCHER_ENABLED") != "false" {
+		dispatcher := do.MustInvokeNamed[service.EventDispatcher](injector, constants.EventDispatcher)
+		dispatcher.Start(context.Background())
+
+		webhookService := do.MustInvokeNamed[service.WebhookService](injector, constants.WebhookService)
+		webhookService.Start(context.Background())
 	}
 
 	port := os.Getenv("GOLANG_PORT")
@@ -46,7 +63,8 @@ func run(server *gin.Engine) {
 	myFigure.Print()
 
 	if err := server.Run(serve); err != nil {
-		log.Fatalf("error running server: %v", err)
+		slog.Error("error running server", "error", err)
+		os.Exit(1)
 	}
 }
 
@@ -55,14 +73,30 @@ func main() {
 		injector = do.New()
 	)
 
+	if err := config.SetUpLogger(config.NewLogConfig()); err != nil {
+		slog.Error("error setting up logger", "error", err)
+		os.Exit(1)
+	}
+
 	provider.RegisterDependencies(injector)
 
 	if !args(injector) {
 		return
 	}
 
-	server := gin.Default()
-	server.Use(middleware.CORSMiddleware())
+	// requests are logged through slog by RequestLogger instead of gin
+	server := gin.New()
+	if err := server.SetTrustedProxies(config.TrustedProxies()); err != nil {
+		slog.Error("invalid TRUSTED_PROXIES", "error", err)
+		os.Exit(1)
+	}
+	server.Use(
+		middleware.RequestID(),
+		middleware.RequestLogger(),
+		middleware.Recovery(),
+		middleware.CORSMiddleware(),
+		middleware.Audit(),
+	)
 
 	// add ping
 	server.GET("/api/ping", func(c *gin.Context) {
@@ -77,5 +111,5 @@ func main() {
 	// routes
 	routes.RegisterRoutes(server, injector)
 
-	run(server)
+	run(server, injector)
 }
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/service"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// RequestID tags every request with the caller's X-Request-ID, or a new one
+// when it is missing or unreasonable, and echoes it in the response
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestId := ctx.GetHeader(requestIDHeader)
+		if !validRequestID(requestId) {
+			requestId = uuid.NewString()
+		}
+
+		ctx.Set("request_id", requestId)
+		ctx.Header(requestIDHeader, requestId)
+		ctx.Next()
+	}
+}
+
+// Audit attributes the database writes of a request to its caller, see
+// service.RegisterAuditCallbacks. It must run after RequestID and before
+// Authenticate, which fills in the user.
+func Audit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		actor := &service.AuditActor{
+			IP:        ctx.ClientIP(),
+			RequestID: ctx.GetString("request_id"),
+			Route:     ctx.Request.Method + " " + ctx.FullPath(),
+		}
+		ctx.Request = ctx.Request.WithContext(service.WithAuditActor(ctx.Request.Context(), actor))
+		ctx.Next()
+	}
+}
+
+func validRequestID(requestId string) bool {
+	if requestId == "" || len(requestId) > 64 {
+		return false
+	}
+	for _, r := range requestId {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RequestID(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestId string
+		wantKept  bool
+	}{
+		{"kept", "trace-1234", true},
+		{"missing", "", false},
+		{"too long", strings.Repeat("a", 65), false},
+		{"spaces", "trace 1234", false},
+		{"control characters", "trace\x001234", false},
+		{"non ascii", "tracé", false},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			r := gin.New()
+			r.GET("/events", RequestID(), func(ctx *gin.Context) {
+				got = ctx.GetString("request_id")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/events", nil)
+			req.Header.Set(requestIDHeader, tt.requestId)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, got, w.Header().Get(requestIDHeader))
+			if tt.wantKept {
+				assert.Equal(t, tt.requestId, got)
+				return
+			}
+			assert.NoError(t, uuid.Validate(got))
+		})
+	}
+}
+
+func Test_Audit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var actor *service.AuditActor
+	r := gin.New()
+	r.POST("/events/:id", RequestID(), Audit(), func(ctx *gin.Context) {
+		actor = service.AuditActorFromContext(ctx.Request.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/events/42", nil)
+	req.Header.Set(requestIDHeader, "trace-1234")
+	req.RemoteAddr = "10.0.0.1:5000"
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if assert.NotNil(t, actor) {
+		assert.Equal(t, "10.0.0.1", actor.IP)
+		assert.Equal(t, "trace-1234", actor.RequestID)
+		assert.Equal(t, "POST /events/:id", actor.Route)
+	}
+}
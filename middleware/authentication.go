@@ -1,19 +1,34 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/config"
 	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
 	"github.com/miraicantsleep/myits-event-be/service"
 	"github.com/miraicantsleep/myits-event-be/utils"
 )
 
-func Authenticate(jwtService service.JWTService) gin.HandlerFunc {
+// Authenticate accepts a Bearer access token or an API key, sent either as
+// X-API-Key or as a Bearer token starting with the key prefix. Requests made
+// with a key act as the key's owner.
+func Authenticate(jwtService service.JWTService, apiKeyService service.APIKeyService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 
+		apiKey := ctx.GetHeader("X-API-Key")
+		if apiKey == "" && strings.HasPrefix(authHeader, "Bearer "+entity.APIKeyPrefix) {
+			apiKey = strings.TrimPrefix(authHeader, "Bearer ")
+		}
+		if apiKey != "" {
+			authenticateAPIKey(ctx, apiKeyService, apiKey)
+			return
+		}
+
 		if authHeader == "" {
 			response := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_TOKEN_NOT_FOUND, nil)
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
@@ -54,12 +69,80 @@ func Authenticate(jwtService service.JWTService) gin.HandlerFunc {
 			return
 		}
 
+		organization, err := jwtService.GetOrganizationByToken(authHeader)
+		if err != nil {
+			response := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			return
+		}
+
+		// access tokens die with their session, see jwtService.ValidateSession
+		sessionId, err := jwtService.GetSessionIDByToken(authHeader)
+		if err == nil {
+			err = jwtService.ValidateSession(ctx.Request.Context(), sessionId)
+		}
+		if err != nil {
+			response := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			return
+		}
+
 		ctx.Set("token", authHeader)
+		ctx.Set("api_key_id", "")
+		ctx.Set("session_id", sessionId)
 		ctx.Set("user_id", userId)
 		ctx.Set("role", role)
+		identifyCaller(ctx, userId, role)
+		// empty when the user is not acting for an organization
+		ctx.Set("organization_id", "")
+		ctx.Set("organization_role", "")
+		if organization != nil {
+			ctx.Set("organization_id", organization.ID)
+			ctx.Set("organization_role", organization.Role)
+		}
 		ctx.Next()
 	}
 }
+func authenticateAPIKey(ctx *gin.Context, apiKeyService service.APIKeyService, key string) {
+	principal, err := apiKeyService.Authenticate(ctx.Request.Context(), key, ctx.Request.Method, ctx.FullPath(), ctx.ClientIP())
+	if err != nil {
+		// the key is fine but does not cover this route
+		status := http.StatusUnauthorized
+		if errors.Is(err, dto.ErrAPIKeyScope) {
+			status = http.StatusForbidden
+		}
+		response := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, err.Error(), nil)
+		ctx.AbortWithStatusJSON(status, response)
+		return
+	}
+
+	ctx.Set("token", "")
+	ctx.Set("api_key_id", principal.KeyID)
+	ctx.Set("session_id", "")
+	ctx.Set("user_id", principal.UserID)
+	ctx.Set("role", principal.Role)
+	identifyCaller(ctx, principal.UserID, principal.Role)
+	ctx.Set("organization_id", "")
+	ctx.Set("organization_role", "")
+	if principal.Organization != nil {
+		ctx.Set("organization_id", principal.Organization.ID)
+		ctx.Set("organization_role", principal.Organization.Role)
+	}
+	ctx.Next()
+}
+
+// identifyCaller attributes the audit log and the log entries of the request
+// to the authenticated user
+func identifyCaller(ctx *gin.Context, userId string, role string) {
+	if actor := service.AuditActorFromContext(ctx.Request.Context()); actor != nil {
+		actor.UserID = userId
+		actor.Role = role
+	}
+	if fields := config.LogFieldsFromContext(ctx.Request.Context()); fields != nil {
+		fields.UserID = userId
+	}
+}
+
 func RoleMiddleware(requiredRoles ...string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		userRole, exists := ctx.Get("role")
@@ -87,3 +170,44 @@ func RoleMiddleware(requiredRoles ...string) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// OrganizationRoleMiddleware limits an ormawa route to members holding one of
+// the given organization roles. Other roles pass through, so it is meant to be
+// combined with RoleMiddleware.
+func OrganizationRoleMiddleware(requiredRoles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetString("role") != "ormawa" {
+			ctx.Next()
+			return
+		}
+
+		if ctx.GetString("organization_id") == "" {
+			response := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrNoActiveOrganization.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+			return
+		}
+
+		organizationRole := ctx.GetString("organization_role")
+		for _, role := range requiredRoles {
+			if organizationRole == role {
+				ctx.Next()
+				return
+			}
+		}
+
+		response := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, "insufficient organization permission", nil)
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+	}
+}
+
+// TokenFromQuery lets a client that cannot set headers, like the browser's
+// EventSource, pass its access token as a query parameter. It must run
+// before Authenticate.
+func TokenFromQuery(param string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if token := ctx.Query(param); token != "" && ctx.GetHeader("Authorization") == "" {
+			ctx.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TokenFromQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		authorization string
+		want          string
+	}{
+		{"token in the query", "/stream?token=abc", "", "Bearer abc"},
+		{"header wins over the query", "/stream?token=abc", "Bearer header", "Bearer header"},
+		{"no token", "/stream", "", ""},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			r := gin.New()
+			r.GET("/stream", TokenFromQuery("token"), func(ctx *gin.Context) {
+				got = ctx.GetHeader("Authorization")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
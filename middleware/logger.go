@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/config"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/utils"
+)
+
+// RequestLogger logs one entry per request with its status and latency, and
+// makes every entry logged with the request context carry the request and
+// user id. It must run right after RequestID. Only the route template is
+// logged, as paths and query strings carry RSVP, join and verification
+// tokens.
+func RequestLogger() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		fields := &config.LogFields{RequestID: ctx.GetString("request_id")}
+		ctx.Request = ctx.Request.WithContext(config.WithLogFields(ctx.Request.Context(), fields))
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("route", ctx.FullPath()),
+			slog.Int("status", status),
+			slog.Int64("latency_ms", time.Since(start).Milliseconds()),
+			slog.String("ip", ctx.ClientIP()),
+			slog.Int("bytes", ctx.Writer.Size()),
+		}
+		if len(ctx.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", ctx.Errors.String()))
+		}
+		slog.LogAttrs(ctx.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// Recovery turns a panic into a 500 response and logs it with its stack
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(ctx *gin.Context, err any) {
+		slog.ErrorContext(ctx.Request.Context(), "panic recovered", "error", fmt.Sprint(err), "stack", string(debug.Stack()))
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, "internal server error", nil)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+	})
+}
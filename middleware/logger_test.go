@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RequestLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantLevel string
+	}{
+		{"success", http.StatusOK, "INFO"},
+		{"client error", http.StatusNotFound, "WARN"},
+		{"server error", http.StatusInternalServerError, "ERROR"},
+	}
+
+	gin.SetMode(gin.TestMode)
+	previous := slog.Default()
+	defer slog.SetDefault(previous)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+			var fields *config.LogFields
+			r := gin.New()
+			r.GET("/events/:id", RequestID(), RequestLogger(), func(ctx *gin.Context) {
+				fields = config.LogFieldsFromContext(ctx.Request.Context())
+				ctx.Status(tt.status)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/events/42?token=secret", nil)
+			req.Header.Set(requestIDHeader, "trace-1234")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if assert.NotNil(t, fields) {
+				assert.Equal(t, "trace-1234", fields.RequestID)
+			}
+
+			var entry map[string]any
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+			assert.Equal(t, tt.wantLevel, entry["level"])
+			assert.Equal(t, "/events/:id", entry["route"])
+			assert.Equal(t, float64(tt.status), entry["status"])
+			// only the route template is logged, never the token in the query
+			assert.NotContains(t, buf.String(), "secret")
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/utils"
+)
+
+type (
+	// RateLimitConfig describes one token bucket per key: it holds up to Limit
+	// tokens and refills completely over Period, so bursts of Limit requests
+	// are allowed and the sustained rate is Limit per Period.
+	RateLimitConfig struct {
+		// Name separates the buckets of different limits sharing a store
+		Name   string
+		Limit  int
+		Period time.Duration
+		Key    RateLimitKeyFunc
+		Store  RateLimitStore
+	}
+
+	// RateLimitKeyFunc picks the bucket a request is counted against
+	RateLimitKeyFunc func(ctx *gin.Context) string
+
+	RateLimitResult struct {
+		Allowed   bool
+		Remaining int
+		// Reset is how long until the bucket is full again
+		Reset time.Duration
+		// RetryAfter is how long until the next token, set when not allowed
+		RetryAfter time.Duration
+	}
+)
+
+// RateLimitByIP counts requests per client IP
+func RateLimitByIP(ctx *gin.Context) string {
+	return "ip:" + ctx.ClientIP()
+}
+
+// RateLimitByUser counts requests per authenticated user and falls back to the
+// client IP, so it must run after Authenticate to tell users apart
+func RateLimitByUser(ctx *gin.Context) string {
+	if userId := ctx.GetString("user_id"); userId != "" {
+		return "user:" + userId
+	}
+	return RateLimitByIP(ctx)
+}
+
+// RateLimitByEmail counts requests per email field of the JSON or form body,
+// so mails to one address are limited whichever IP asks for them. It falls
+// back to the client IP when the body has no email.
+func RateLimitByEmail(ctx *gin.Context) string {
+	var email string
+	if ctx.ContentType() == binding.MIMEJSON {
+		data, err := ctx.GetRawData()
+		if err == nil {
+			// the handler binds the body again
+			ctx.Request.Body = io.NopCloser(bytes.NewReader(data))
+			var body struct {
+				Email string `json:"email"`
+			}
+			_ = json.Unmarshal(data, &body)
+			email = body.Email
+		}
+	} else {
+		// the parsed form is kept on the request for the handler
+		email = ctx.PostForm("email")
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return RateLimitByIP(ctx)
+	}
+	return "email:" + email
+}
+
+// RateLimitByRoute shares one bucket between every caller of a route
+func RateLimitByRoute(ctx *gin.Context) string {
+	return "route:" + ctx.Request.Method + " " + ctx.FullPath()
+}
+
+// RateLimit rejects requests with 429 once their bucket is empty. Every
+// response carries RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset,
+// and rejected ones Retry-After as well. If the store fails the request is let
+// through, since an outage of the limiter should not take the API down.
+func RateLimit(config RateLimitConfig) gin.HandlerFunc {
+	if config.Key == nil {
+		config.Key = RateLimitByIP
+	}
+
+	return func(ctx *gin.Context) {
+		key := config.Name + ":" + config.Key(ctx)
+		result, err := config.Store.Take(ctx.Request.Context(), key, config.Limit, config.Period, time.Now())
+		if err != nil {
+			slog.ErrorContext(ctx.Request.Context(), "rate limiter failed", "key", key, "error", err)
+			ctx.Next()
+			return
+		}
+
+		ctx.Header("RateLimit-Limit", strconv.Itoa(config.Limit))
+		ctx.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			ctx.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			response := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrTooManyRequests.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, response)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	RateLimitStoreMemory   = "memory"
+	RateLimitStorePostgres = "postgres"
+
+	// buckets untouched for this long are full again and can be dropped
+	rateLimitIdleTTL = 24 * time.Hour
+	rateLimitSweep   = 10 * time.Minute
+)
+
+type (
+	// RateLimitStore takes a token from the bucket of key, creating a full
+	// bucket on first use
+	RateLimitStore interface {
+		Take(ctx context.Context, key string, limit int, period time.Duration, now time.Time) (RateLimitResult, error)
+	}
+
+	// tokenBucket refills lazily: elapsed time since refilledAt is turned into
+	// tokens whenever the bucket is touched
+	tokenBucket struct {
+		tokens     float64
+		refilledAt time.Time
+	}
+
+	memoryRateLimitStore struct {
+		mu        sync.Mutex
+		buckets   map[string]*tokenBucket
+		lastSweep time.Time
+	}
+
+	postgresRateLimitStore struct {
+		db        *gorm.DB
+		mu        sync.Mutex
+		lastSweep time.Time
+	}
+)
+
+func (b *tokenBucket) take(limit int, period time.Duration, now time.Time) RateLimitResult {
+	rate := float64(limit) / period.Seconds()
+	if elapsed := now.Sub(b.refilledAt).Seconds(); elapsed > 0 {
+		b.tokens = min(float64(limit), b.tokens+elapsed*rate)
+	}
+	b.refilledAt = now
+
+	var result RateLimitResult
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = secondsToDuration((float64(limit) - b.tokens) / rate)
+	return result
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// NewMemoryRateLimitStore keeps buckets in process. Limits are per instance
+// and reset on restart.
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &memoryRateLimitStore{
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+func (s *memoryRateLimitStore) Take(ctx context.Context, key string, limit int, period time.Duration, now time.Time) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) > rateLimitSweep {
+		for k, bucket := range s.buckets {
+			if now.Sub(bucket.refilledAt) > rateLimitIdleTTL {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit), refilledAt: now}
+		s.buckets[key] = bucket
+	}
+	return bucket.take(limit, period, now), nil
+}
+
+// NewPostgresRateLimitStore keeps buckets in the rate_limit_buckets table so
+// every instance shares the same limits. Each take locks the bucket row.
+func NewPostgresRateLimitStore(db *gorm.DB) RateLimitStore {
+	return &postgresRateLimitStore{
+		db: db,
+	}
+}
+
+func (s *postgresRateLimitStore) Take(ctx context.Context, key string, limit int, period time.Duration, now time.Time) (RateLimitResult, error) {
+	s.sweep(ctx, now)
+
+	var result RateLimitResult
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		bucket := entity.RateLimitBucket{Key: key, Tokens: float64(limit), RefilledAt: now}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&bucket).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).Take(&bucket).Error; err != nil {
+			return err
+		}
+
+		state := tokenBucket{tokens: bucket.Tokens, refilledAt: bucket.RefilledAt}
+		result = state.take(limit, period, now)
+
+		return tx.Model(&entity.RateLimitBucket{}).
+			Where("key = ?", key).
+			Updates(map[string]any{"tokens": state.tokens, "refilled_at": state.refilledAt}).Error
+	})
+	return result, err
+}
+
+// sweep drops idle buckets at most once per rateLimitSweep per instance
+func (s *postgresRateLimitStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < rateLimitSweep {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	s.db.WithContext(ctx).Where("refilled_at < ?", now.Add(-rateLimitIdleTTL)).Delete(&entity.RateLimitBucket{})
+}
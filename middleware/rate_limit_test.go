@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TokenBucketTake(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		bucket tokenBucket
+		at     time.Time
+		want   RateLimitResult
+	}{
+		{
+			name:   "full bucket",
+			bucket: tokenBucket{tokens: 10, refilledAt: now},
+			at:     now,
+			want:   RateLimitResult{Allowed: true, Remaining: 9, Reset: 6 * time.Second},
+		},
+		{
+			name:   "last token",
+			bucket: tokenBucket{tokens: 1, refilledAt: now},
+			at:     now,
+			want:   RateLimitResult{Allowed: true, Remaining: 0, Reset: time.Minute},
+		},
+		{
+			name:   "empty bucket",
+			bucket: tokenBucket{tokens: 0, refilledAt: now},
+			at:     now,
+			want:   RateLimitResult{Remaining: 0, Reset: time.Minute, RetryAfter: 6 * time.Second},
+		},
+		{
+			name:   "refilled while idle",
+			bucket: tokenBucket{tokens: 0, refilledAt: now},
+			at:     now.Add(12 * time.Second),
+			want:   RateLimitResult{Allowed: true, Remaining: 1, Reset: 54 * time.Second},
+		},
+		{
+			name:   "refill stops at the limit",
+			bucket: tokenBucket{tokens: 5, refilledAt: now},
+			at:     now.Add(time.Hour),
+			want:   RateLimitResult{Allowed: true, Remaining: 9, Reset: 6 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := tt.bucket
+			// 10 tokens per minute, one every 6 seconds
+			got := bucket.take(10, time.Minute, tt.at)
+			assert.Equal(t, tt.want.Allowed, got.Allowed)
+			assert.Equal(t, tt.want.Remaining, got.Remaining)
+			assert.InDelta(t, tt.want.Reset, got.Reset, float64(time.Millisecond))
+			assert.InDelta(t, tt.want.RetryAfter, got.RetryAfter, float64(time.Millisecond))
+		})
+	}
+}
+
+func Test_RateLimitByEmail(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"json body", "application/json", `{"email":"Student@ITS.ac.id "}`, "email:student@its.ac.id"},
+		{"form body", "application/x-www-form-urlencoded", "email=student%40its.ac.id", "email:student@its.ac.id"},
+		{"json without email", "application/json", `{"name":"x"}`, "ip:192.0.2.1"},
+		{"malformed json", "application/json", `{"email":`, "ip:192.0.2.1"},
+		{"empty form", "application/x-www-form-urlencoded", "", "ip:192.0.2.1"},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/forgot-password", strings.NewReader(tt.body))
+			ctx.Request.Header.Set("Content-Type", tt.contentType)
+			ctx.Request.RemoteAddr = "192.0.2.1:1234"
+
+			assert.Equal(t, tt.want, RateLimitByEmail(ctx))
+
+			// the handler still has to be able to bind the body
+			if tt.contentType == "application/json" {
+				rest, err := io.ReadAll(ctx.Request.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.body, string(rest))
+			}
+		})
+	}
+}
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(ctx context.Context, key string, limit int, period time.Duration, now time.Time) (RateLimitResult, error) {
+	return RateLimitResult{}, context.DeadlineExceeded
+}
+
+func Test_RateLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		store      RateLimitStore
+		requests   int
+		wantStatus []int
+	}{
+		{"within the limit", NewMemoryRateLimitStore(), 2, []int{http.StatusOK, http.StatusOK}},
+		{"over the limit", NewMemoryRateLimitStore(), 3, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}},
+		{"store failure lets requests through", failingRateLimitStore{}, 3, []int{http.StatusOK, http.StatusOK, http.StatusOK}},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/limited", RateLimit(RateLimitConfig{Name: "test", Limit: 2, Period: time.Minute, Store: tt.store}), func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			for i := range tt.requests {
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+				req.RemoteAddr = "192.0.2.1:1234"
+				r.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.wantStatus[i], w.Code, "request %d", i+1)
+				if w.Code == http.StatusTooManyRequests {
+					assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+					assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+					assert.Equal(t, "30", w.Header().Get("Retry-After"))
+				}
+			}
+		})
+	}
+}
+
+func Test_RateLimitByIP_TrustedProxies(t *testing.T) {
+	tests := []struct {
+		name       string
+		proxies    []string
+		remoteAddr string
+		wantStatus []int
+	}{
+		{
+			name:       "spoofed header from a client",
+			remoteAddr: "192.0.2.1:1234",
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:       "spoofed header from an untrusted proxy",
+			proxies:    []string{"10.0.0.2"},
+			remoteAddr: "192.0.2.1:1234",
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:       "clients behind a trusted proxy",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:1234",
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			assert.NoError(t, r.SetTrustedProxies(tt.proxies))
+			r.GET("/limited", RateLimit(RateLimitConfig{Name: "test", Limit: 2, Period: time.Minute, Key: RateLimitByIP, Store: NewMemoryRateLimitStore()}), func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			for i, forwardedFor := range []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"} {
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+				req.RemoteAddr = tt.remoteAddr
+				req.Header.Set("X-Forwarded-For", forwardedFor)
+				r.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.wantStatus[i], w.Code, "request %d", i+1)
+			}
+		})
+	}
+}
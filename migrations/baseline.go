@@ -0,0 +1,108 @@
+package migrations
+
+import (
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+)
+
+// baseline is the schema as it was before migrations were versioned: the enum
+// types, the tables of the entities and the organization backfill. Tables are
+// still created with AutoMigrate from the entities, so later migrations must
+// tolerate a fresh database already having their columns (IF NOT EXISTS).
+//
+// It runs in the migration's transaction, which needs PostgreSQL 12 or newer
+// since older servers refuse ALTER TYPE ... ADD VALUE inside a transaction.
+func baseline(tx *gorm.DB) error {
+	// Ensure enum type exists before AutoMigrate
+	err := tx.Exec(`
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'user_role') THEN
+				CREATE TYPE user_role AS ENUM ('user', 'departemen', 'ormawa', 'admin');
+			END IF;
+			IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'event_type') THEN
+				CREATE TYPE event_type AS ENUM ('online', 'offline', 'hybrid');
+			END IF;
+			IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'rsvp_status') THEN
+				CREATE TYPE rsvp_status AS ENUM ('accepted', 'declined', 'pending');
+			END IF;
+			IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'booking_status') THEN
+				CREATE TYPE booking_status AS ENUM ('pending', 'approved', 'rejected');
+			END IF;
+		END
+		$$;
+	`).Error
+	if err != nil {
+		return err
+	}
+
+	// databases created before hybrid events existed only have online/offline
+	if err := tx.Exec(`ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'hybrid';`).Error; err != nil {
+		return err
+	}
+
+	// Setup Join Table and AutoMigrate
+	if err := tx.SetupJoinTable(&entity.Invitation{}, "Users", &entity.UserInvitation{}); err != nil {
+		return err
+	}
+
+	// accounts created before email verification existed are treated as verified
+	grandfatherUsers := tx.Migrator().HasTable(&entity.User{}) && !tx.Migrator().HasColumn(&entity.User{}, "is_verified")
+
+	if err := tx.AutoMigrate(
+		&entity.User{}, &entity.Department{}, &entity.Event{}, &entity.Room{}, &entity.Invitation{}, &entity.BookingRequest{}, &entity.UserInvitation{},
+		&entity.Equipment{}, &entity.BookingRequestEquipment{},
+		&entity.OperatingHour{}, &entity.BlackoutPeriod{},
+		&entity.RoomImage{}, &entity.EventAttachment{},
+		&entity.UserToken{}, &entity.RoleChange{},
+		&entity.Organization{}, &entity.OrganizationMember{},
+		&entity.LoginThrottle{}, &entity.RateLimitBucket{},
+		&entity.RefreshToken{},
+		&entity.UserIdentity{}, &entity.OIDCLoginState{},
+		&entity.APIKey{},
+	); err != nil {
+		return err
+	}
+
+	// every ormawa account without an organisation becomes the chair of its
+	// own, and takes over ownership of the events it created
+	backfillOrganizations := `
+	INSERT INTO organizations (id, name, description, created_at, updated_at)
+	SELECT uuid_generate_v4(), u.name, '', NOW(), NOW()
+	FROM users u
+	WHERE u.role = 'ormawa' AND u.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM organization_members m WHERE m.user_id = u.id AND m.deleted_at IS NULL)
+		AND NOT EXISTS (SELECT 1 FROM organizations o WHERE o.name = u.name)
+	ON CONFLICT DO NOTHING;
+
+	INSERT INTO organization_members (id, organization_id, user_id, role, created_at, updated_at)
+	SELECT uuid_generate_v4(), o.id, u.id, 'chair', NOW(), NOW()
+	FROM users u
+	JOIN organizations o ON o.name = u.name
+	WHERE u.role = 'ormawa' AND u.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM organization_members m WHERE m.user_id = u.id AND m.deleted_at IS NULL)
+	ON CONFLICT DO NOTHING;
+
+	UPDATE events e
+	SET organization_id = m.organization_id
+	FROM organization_members m
+	WHERE e.organization_id IS NULL AND m.user_id = e.created_by AND m.role = 'chair' AND m.deleted_at IS NULL;
+	`
+	if err := tx.Exec(backfillOrganizations).Error; err != nil {
+		return err
+	}
+
+	if grandfatherUsers {
+		if err := tx.Exec("UPDATE users SET is_verified = true").Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// baselineDown refuses to run: undoing the baseline would drop every table,
+// which is not something --migrate:down should do by accident
+func baselineDown(tx *gorm.DB) error {
+	return ErrBaselineDown
+}
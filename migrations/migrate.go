@@ -1,568 +1,320 @@
 package migrations
 
 import (
-	"github.com/miraicantsleep/myits-event-be/entity"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
 	"gorm.io/gorm"
 )
 
-func Migrate(db *gorm.DB) error {
-	// Ensure enum type exists before AutoMigrate
-	err := db.Exec(`
-		DO $$
-		BEGIN
-			IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'user_role') THEN
-				CREATE TYPE user_role AS ENUM ('user', 'departemen', 'ormawa', 'admin');
-			END IF;
-			IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'event_type') THEN
-				CREATE TYPE event_type AS ENUM ('online', 'offline');
-			END IF;
-			IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'rsvp_status') THEN
-				CREATE TYPE rsvp_status AS ENUM ('accepted', 'declined', 'pending');
-			END IF;
-			IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'booking_status') THEN
-				CREATE TYPE booking_status AS ENUM ('pending', 'approved', 'rejected');
-			END IF;
-		END
-		$$;
-	`).Error
-	if err != nil {
-		return err
-	}
+// SQL migrations live in sql/ as NNNN_name.up.sql and NNNN_name.down.sql and
+// are embedded in the binary; Go migrations are listed in goMigrations.
+//
+//go:embed sql/*.sql
+var sqlFiles embed.FS
 
-	qrCodeFunctionSQL := `
-	CREATE OR REPLACE FUNCTION generate_user_invitation_qr_code()
-	RETURNS TRIGGER AS $$
-	BEGIN
-	    IF NEW.qr_code IS NULL OR NEW.qr_code = '' THEN
-	        NEW.qr_code := uuid_generate_v4();
-	    END IF;
-	    RETURN NEW;
-	END;
-	$$ LANGUAGE plpgsql;
-	`
-	if err := db.Exec(qrCodeFunctionSQL).Error; err != nil {
-		return err
-	}
+const (
+	// SQLDir is where --migrate:create writes new files, relative to the
+	// repository root
+	SQLDir = "migrations/sql"
 
-	// Setup Join Table and AutoMigrate
-	if err := db.SetupJoinTable(&entity.Invitation{}, "Users", &entity.UserInvitation{}); err != nil {
-		return err
-	}
+	// migrationLockKey is the advisory lock held while migrating, so instances
+	// started together do not apply the same migration twice
+	migrationLockKey = 7_302_114_950_001
+)
 
-	if err := db.AutoMigrate(
-		&entity.User{}, &entity.Department{}, &entity.Event{}, &entity.Room{}, &entity.Invitation{}, &entity.BookingRequest{}, &entity.UserInvitation{},
-	); err != nil {
-		return err
-	}
+var (
+	ErrIrreversible     = errors.New("migration cannot be rolled back")
+	ErrInvalidName      = errors.New("migration name must be lowercase letters, digits and underscores")
+	ErrMigrationUnknown = errors.New("applied migration is missing from this binary")
+	ErrBaselineDown     = errors.New("the baseline cannot be rolled back, drop the database to start over")
 
-	// SQL for QR Code trigger - MOVED TO AFTER AUTOMIGRATE
-	qrCodeTriggerSQL := `
-	DROP TRIGGER IF EXISTS trg_generate_qr_code_before_insert_on_user_invitation ON user_invitation;
-	CREATE TRIGGER trg_generate_qr_code_before_insert_on_user_invitation
-	    BEFORE INSERT ON user_invitation
-	    FOR EACH ROW
-	    EXECUTE FUNCTION generate_user_invitation_qr_code();
-	`
-	if err := db.Exec(qrCodeTriggerSQL).Error; err != nil {
-		return err
-	}
+	sqlFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+	namePattern    = regexp.MustCompile(`^[a-z0-9_]+$`)
+)
 
-	db.Exec("DROP VIEW IF EXISTS event_details;")
-	createEventDetailsView := `
-	CREATE OR REPLACE VIEW event_details AS
-		SELECT
-			e.id,
-			e.name,
-			e.description,
-			e.start_time,
-			e.end_time,
-			e.event_type,
-			e.created_by AS creator_id,
-			u.name AS creator_name,
-			e.created_at,
-			e.updated_at,
-			e.deleted_at,
-			e.created_by,
-			e.duration_in_minutes
-		FROM
-			events e
-		LEFT JOIN
-			users u ON e.created_by = u.id;
-	`
-	if err := db.Exec(createEventDetailsView).Error; err != nil {
-		return err
+type (
+	migration struct {
+		Version int64
+		Name    string
+		Up      func(tx *gorm.DB) error
+		// Down is nil for migrations that cannot be rolled back
+		Down func(tx *gorm.DB) error
 	}
 
-	db.Exec("DROP VIEW IF EXISTS full_invitation_details;")
-	fullInvitationDetailsView := `
-	CREATE OR REPLACE VIEW full_invitation_details AS
-	SELECT
-		i.id AS id, 
-		e.id AS event_id,
-		e.name AS event_name,
-		e.description AS event_description,
-		e.start_time,
-		e.end_time,
-		e.deleted_at,
-		u.id AS user_id,
-		u.name AS user_name,
-		u.email AS user_email,
-		ui.invited_at,
-		ui.rsvp_status,
-		ui.rsvp_at,
-		ui.attended_at,
-		ui.qr_code
-	FROM
-		invitations i
-	JOIN
-		user_invitation ui ON i.id = ui.invitation_id
-	JOIN
-		users u ON ui.user_id = u.id
-	JOIN
-		events e ON i.event_id = e.id;
-	`
-	if err := db.Exec(fullInvitationDetailsView).Error; err != nil {
-		return err
+	// SchemaMigration is a row of schema_migrations, one per applied migration
+	SchemaMigration struct {
+		Version   int64     `gorm:"primary_key;autoIncrement:false"`
+		Name      string    `gorm:"type:varchar(255);not null"`
+		AppliedAt time.Time `gorm:"type:timestamp with time zone;not null"`
 	}
+)
 
-	db.Exec("DROP VIEW IF EXISTS ormawa_events_view;")
-	ormawaEventsView := `
-	-- Events created by "Ormawa" View
-	CREATE OR REPLACE VIEW ormawa_events_view AS
-	SELECT
-		e.id AS event_id,
-		e.name AS event_name,
-		e.description,
-		e.start_time,
-		e.end_time,
-		u.id AS creator_id,
-		u.name AS creator_name
-	FROM
-		events e
-	JOIN
-		users u ON e.created_by = u.id
-	WHERE
-		u.role = 'ormawa' AND e.deleted_at IS NULL;
-	`
-	if err := db.Exec(ormawaEventsView).Error; err != nil {
-		return err
-	}
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
 
-	if err := db.Exec(ormawaEventsView).Error; err != nil {
-		return err
-	}
+// goMigrations are migrations that need Go rather than plain SQL
+var goMigrations = []migration{
+	{Version: 1, Name: "baseline", Up: baseline, Down: baselineDown},
+}
 
-	db.Exec("DROP VIEW IF EXISTS user_attendance_view;")
-	userAttendanceView := `
-	-- User Attendance View
-	CREATE OR REPLACE VIEW user_attendance_view AS
-	SELECT
-		u.id as user_id,
-		u.name as user_name,
-		e.id as event_id,
-		e.name as event_name,
-		ui.attended_at
-	FROM
-		users u
-	JOIN
-		user_invitation ui ON u.id = ui.user_id
-	JOIN
-		invitations i ON ui.invitation_id = i.id
-	JOIN
-		events e ON i.event_id = e.id
-	WHERE 
-		ui.attended_at IS NOT NULL
-	AND
-		u.deleted_at IS NULL;
-	`
-	if err := db.Exec(userAttendanceView).Error; err != nil {
+// Migrate applies every pending migration in version order
+func Migrate(db *gorm.DB) error {
+	migrations, err := loadMigrations()
+	if err != nil {
 		return err
 	}
 
-	// Trigger to prevent modification of approved/rejected bookings
-	preventBookingModificationFunc := `
-	CREATE OR REPLACE FUNCTION prevent_booking_modification()
-	RETURNS TRIGGER AS $$
-	BEGIN
-	    IF OLD.status IN ('approved', 'rejected') THEN
-	        RAISE EXCEPTION 'Cannot modify a booking request that has already been approved or rejected.';
-	    END IF;
-	    RETURN NEW;
-	END;
-	$$ LANGUAGE plpgsql;
-	`
-	if err := db.Exec(preventBookingModificationFunc).Error; err != nil {
-		return err
-	}
+	return withLock(db, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+
+			slog.Info("migrating", "version", m.Version, "name", m.Name)
+			if err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := m.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+			}); err != nil {
+				return fmt.Errorf("%04d_%s: %w", m.Version, m.Name, err)
+			}
+		}
+		return nil
+	})
+}
 
-	preventBookingModificationTrigger := `
-	DROP TRIGGER IF EXISTS trg_prevent_booking_modification ON booking_requests;
-	CREATE TRIGGER trg_prevent_booking_modification
-	BEFORE UPDATE ON booking_requests
-	FOR EACH ROW EXECUTE FUNCTION prevent_booking_modification();
-	`
-	if err := db.Exec(preventBookingModificationTrigger).Error; err != nil {
+// Rollback reverts the last steps applied migrations, newest first
+func Rollback(db *gorm.DB, steps int) error {
+	migrations, err := loadMigrations()
+	if err != nil {
 		return err
 	}
-
-	// Trigger to prevent deletion of events with active bookings
-	preventEventDeletionFunc := `
-	CREATE OR REPLACE FUNCTION prevent_event_deletion_with_bookings()
-	RETURNS TRIGGER AS $$
-	BEGIN
-	    IF EXISTS (SELECT 1 FROM booking_requests WHERE event_id = OLD.id AND status IN ('pending', 'approved') AND deleted_at IS NULL) THEN
-	        RAISE EXCEPTION 'Cannot delete event: It has active booking requests.';
-	    END IF;
-	    RETURN OLD;
-	END;
-	$$ LANGUAGE plpgsql;
-	`
-	if err := db.Exec(preventEventDeletionFunc).Error; err != nil {
-		return err
+	byVersion := make(map[int64]migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
 	}
 
-	preventEventDeletionTrigger := `
-	DROP TRIGGER IF EXISTS trg_prevent_event_deletion_with_bookings ON events;
-	CREATE TRIGGER trg_prevent_event_deletion_with_bookings
-	BEFORE DELETE ON events
-	FOR EACH ROW EXECUTE FUNCTION prevent_event_deletion_with_bookings();
-	`
-	if err := db.Exec(preventEventDeletionTrigger).Error; err != nil {
-		return err
-	}
+	return withLock(db, func(conn *gorm.DB) error {
+		var applied []SchemaMigration
+		if err := conn.Order("version DESC").Limit(steps).Find(&applied).Error; err != nil {
+			return err
+		}
+
+		for _, row := range applied {
+			m, ok := byVersion[row.Version]
+			if !ok {
+				return fmt.Errorf("%04d_%s: %w", row.Version, row.Name, ErrMigrationUnknown)
+			}
+			if m.Down == nil {
+				return fmt.Errorf("%04d_%s: %w", m.Version, m.Name, ErrIrreversible)
+			}
+
+			slog.Info("rolling back", "version", m.Version, "name", m.Name)
+			if err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := m.Down(tx); err != nil {
+					return err
+				}
+				return tx.Delete(&SchemaMigration{}, "version = ?", m.Version).Error
+			}); err != nil {
+				return fmt.Errorf("%04d_%s: %w", m.Version, m.Name, err)
+			}
+		}
+		return nil
+	})
+}
 
-	getEventAttendeesFunc := `
-	CREATE OR REPLACE FUNCTION get_event_attendees(p_event_id uuid)
-	RETURNS TABLE (
-		user_id uuid,
-		user_name character varying,
-		user_email character varying,
-		attended_at timestamp
-	) AS $$
-	BEGIN
-		RETURN QUERY
-		SELECT
-			u.id,
-			u.name,
-			u.email,
-			ui.attended_at
-		FROM
-			user_invitation ui
-		JOIN
-			users u ON ui.user_id = u.id
-		JOIN
-			invitations i ON ui.invitation_id = i.id
-		WHERE
-			i.event_id = p_event_id
-			AND ui.attended_at IS NOT NULL
-			AND u.deleted_at IS NULL;
-	END;
-	$$ LANGUAGE plpgsql;
-	`
-	if err := db.Exec(getEventAttendeesFunc).Error; err != nil {
+// Status prints every known migration with when it was applied, followed by
+// applied versions this binary does not know about
+func Status(db *gorm.DB) error {
+	migrations, err := loadMigrations()
+	if err != nil {
 		return err
 	}
 
-	getPendingBookingsFunc := `
-	CREATE OR REPLACE FUNCTION get_pending_booking_requests_for_department(p_department_id uuid)
-	RETURNS TABLE (
-		booking_request_id uuid,
-		event_name character varying,
-		event_start_time timestamp,
-		event_end_time timestamp,
-		room_name character varying,
-		requesting_ormawa character varying
-	) AS $$
-	BEGIN
-		RETURN QUERY
-		SELECT
-			br.id,
-			e.name,
-			e.start_time,
-			e.end_time,
-			r.name,
-			u.name
-		FROM
-			booking_requests br
-		JOIN
-			events e ON br.event_id = e.id
-		JOIN
-			users u ON e.created_by = u.id
-		JOIN
-			booking_request_room brr ON br.id = brr.booking_request_id
-		JOIN
-			rooms r ON brr.room_id = r.id
-		WHERE
-			r.department_id = p_department_id
-			AND br.status = 'pending'
-			AND br.deleted_at IS NULL;
-	END;
-	$$ LANGUAGE plpgsql;
-	`
-	if err := db.Exec(getPendingBookingsFunc).Error; err != nil {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
 		return err
 	}
-
-	db.Exec("DROP VIEW IF EXISTS vw_booking_with_rooms;")
-	createBookingWithRoomsView := `
-	CREATE OR REPLACE VIEW vw_booking_with_rooms AS
-	SELECT
-		br.id AS booking_id,
-		br.status AS booking_status,
-		e.id AS event_id,
-		e.name AS event_name,
-		r.id AS room_id,
-		r.name AS room_name,
-		u.name AS requested_by
-	FROM
-		booking_requests br
-	JOIN
-		events e ON br.event_id = e.id
-	JOIN
-		users u ON e.created_by = u.id
-	JOIN
-		booking_request_room brr ON br.id = brr.booking_request_id
-	JOIN
-		rooms r ON brr.room_id = r.id;
-	`
-	if err := db.Exec(createBookingWithRoomsView).Error; err != nil {
+	applied, err := appliedMigrations(db)
+	if err != nil {
 		return err
 	}
 
-	db.Exec("DROP VIEW IF EXISTS vw_room_details;")
-	roomDetailsView := `
-	CREATE OR REPLACE VIEW vw_room_details AS
-	SELECT
-		r.id,
-		r.name,
-		r.capacity,
-		r.department_id,
-		d.name AS department_name,
-		r.created_at,
-		r.updated_at,
-		r.deleted_at
-	FROM
-		rooms r
-	LEFT JOIN
-		departments d ON r.department_id = d.id
-	WHERE
-		r.deleted_at IS NULL;
-	`
-	if err := db.Exec(roomDetailsView).Error; err != nil {
-		return err
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, m := range migrations {
+		appliedAt := "pending"
+		if row, ok := applied[m.Version]; ok {
+			appliedAt = row.AppliedAt.Format(time.RFC3339)
+			delete(applied, m.Version)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", m.Version, m.Name, appliedAt)
 	}
 
-	setInvitedAtFunction := `
-	CREATE OR REPLACE FUNCTION fn_set_invited_at_timestamp()
-	RETURNS TRIGGER AS $$
-	BEGIN
-		-- Set kolom invited_at dengan waktu transaksi saat ini
-		NEW.invited_at := NOW();
-		RETURN NEW;
-	END;
-	$$ LANGUAGE plpgsql;
-	`
-	if err := db.Exec(setInvitedAtFunction).Error; err != nil {
-		return err
+	unknown := make([]SchemaMigration, 0, len(applied))
+	for _, row := range applied {
+		unknown = append(unknown, row)
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i].Version < unknown[j].Version })
+	for _, row := range unknown {
+		fmt.Fprintf(w, "%04d\t%s\t%s (missing from this binary)\n", row.Version, row.Name, row.AppliedAt.Format(time.RFC3339))
 	}
+	return w.Flush()
+}
 
-	setInvitedAtTrigger := `
-	DROP TRIGGER IF EXISTS trg_auto_set_invited_at ON user_invitation;
-	CREATE TRIGGER trg_auto_set_invited_at
-	BEFORE INSERT ON user_invitation
-	FOR EACH ROW
-	EXECUTE FUNCTION fn_set_invited_at_timestamp();
-	`
+// Create writes an empty up/down pair numbered after the newest migration
+// and returns their paths. The binary has to be rebuilt to embed them.
+func Create(name string) ([]string, error) {
+	if !namePattern.MatchString(name) {
+		return nil, ErrInvalidName
+	}
 
-	if err := db.Exec(setInvitedAtTrigger).Error; err != nil {
-		return err
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	var version int64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
 	}
 
-	getEventByStatusFunc := `
-	CREATE OR REPLACE FUNCTION get_event_by_status(p_timeline_status TEXT)
-	RETURNS TABLE (
-		id uuid,
-		name character varying,
-		description text,
-		start_time timestamp,
-		end_time timestamp,
-		event_type event_type,
-		creator_name character varying
-	) AS $$
-	BEGIN
-		IF p_timeline_status = 'ongoing' THEN
-			RETURN QUERY
-			SELECT e.id, e.name, e.description, e.start_time, e.end_time, e.event_type, u.name
-			FROM events e
-			JOIN users u ON e.created_by = u.id
-			WHERE e.deleted_at IS NULL AND NOW() BETWEEN e.start_time AND e.end_time;
-
-
-		ELSIF p_timeline_status = 'upcoming' THEN
-			RETURN QUERY
-			SELECT e.id, e.name, e.description, e.start_time, e.end_time, e.event_type, u.name
-			FROM events e
-			JOIN users u ON e.created_by = u.id
-			WHERE e.deleted_at IS NULL AND e.start_time > NOW();
-
-
-		ELSIF p_timeline_status = 'finished' THEN
-			RETURN QUERY
-			SELECT e.id, e.name, e.description, e.start_time, e.end_time, e.event_type, u.name
-			FROM events e
-			JOIN users u ON e.created_by = u.id
-			WHERE e.deleted_at IS NULL AND e.end_time < NOW();
-
-
-		ELSE
-			RAISE EXCEPTION 'Invalid timeline status';
-		END IF;
-	END;
-	$$ LANGUAGE plpgsql;
-	`
-
-	if err := db.Exec(getEventByStatusFunc).Error; err != nil {
-		return err
+	// files created since the last build are not embedded yet
+	entries, err := os.ReadDir(SQLDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if match := sqlFilePattern.FindStringSubmatch(entry.Name()); match != nil {
+			if v, _ := strconv.ParseInt(match[1], 10, 64); v >= version {
+				version = v + 1
+			}
+		}
 	}
 
-	isRoomAvailableFunc := `
-	CREATE OR REPLACE FUNCTION is_room_available(
-		p_room_id UUID,
-		p_start_time TIMESTAMP,
-		p_end_time TIMESTAMP
-	)
-	RETURNS BOOLEAN AS $$
-	DECLARE
-		is_available BOOLEAN;
-	BEGIN
-		SELECT NOT EXISTS (
-			SELECT 1
-			FROM booking_requests br
-			JOIN booking_request_room brr ON br.id = brr.booking_request_id
-			JOIN events e ON br.event_id = e.id
-			WHERE brr.room_id = p_room_id
-				AND br.status = 'approved'
-				AND br.deleted_at IS NULL
-				AND (p_start_time < e.end_time AND p_end_time > e.start_time)
-		) INTO is_available;
-
-
-		RETURN is_available;
-	END;
-	$$ LANGUAGE plpgsql;
-	`
-
-	if err := db.Exec(isRoomAvailableFunc).Error; err != nil {
-		return err
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(SQLDir, fmt.Sprintf("%04d_%s.%s.sql", version, name, direction))
+		content := fmt.Sprintf("-- %04d_%s (%s)\n", version, name, direction)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
 	}
+	return paths, nil
+}
 
-	validateEventTimeFunc := `
-	CREATE OR REPLACE FUNCTION validate_event_time()
-	RETURNS TRIGGER AS $$
-	BEGIN
-		IF NEW.end_time <= NEW.start_time THEN
-			RAISE EXCEPTION 'end time harus lebih besar dari start time';
-		END IF;
-		-- Jika valid, lanjutkan operasi INSERT atau UPDATE
-		RETURN NEW;
-	END;
-	$$ LANGUAGE plpgsql;
-	`
-	if err := db.Exec(validateEventTimeFunc).Error; err != nil {
-		return err
+// loadMigrations merges the Go migrations with the embedded SQL files, sorted
+// by version
+func loadMigrations() ([]migration, error) {
+	return readMigrations(sqlFiles, goMigrations)
+}
+
+// readMigrations merges goMigrations with the SQL files in the sql directory
+// of files, sorted by version
+func readMigrations(files fs.FS, goMigrations []migration) ([]migration, error) {
+	byVersion := make(map[int64]*migration)
+	goVersions := make(map[int64]bool)
+	for i := range goMigrations {
+		m := goMigrations[i]
+		byVersion[m.Version] = &m
+		goVersions[m.Version] = true
 	}
 
-	validateEventTimeTrigger := `
-	DROP TRIGGER IF EXISTS trg_validate_event_time ON events;
-	CREATE TRIGGER trg_validate_event_time
-	BEFORE INSERT OR UPDATE ON events
-	FOR EACH ROW
-	EXECUTE FUNCTION validate_event_time();
-	`
-	if err := db.Exec(validateEventTimeTrigger).Error; err != nil {
-		return err
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		match := sqlFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", entry.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+
+		m, ok := byVersion[version]
+		switch {
+		case !ok:
+			m = &migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		case m.Name != match[2] || goVersions[version]:
+			return nil, fmt.Errorf("migration version %d is used twice", version)
+		}
+
+		content, err := fs.ReadFile(files, "sql/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		run := execSQL(string(content))
+		if match[3] == "up" {
+			m.Up = run
+		} else {
+			m.Down = run
+		}
 	}
 
-	calculateEventDurationFunc := `
-	CREATE OR REPLACE FUNCTION calculate_event_duration()
-	RETURNS TRIGGER AS $$
-	BEGIN
-		NEW.duration_in_minutes := EXTRACT(EPOCH FROM (NEW.end_time - NEW.start_time)) / 60;
-		RETURN NEW;
-	END;
-	$$ LANGUAGE plpgsql;
-	`
-	if err := db.Exec(calculateEventDurationFunc).Error; err != nil {
-		return err
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == nil {
+			return nil, fmt.Errorf("migration %04d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
 	}
-	calculateEventDurationTrigger := `
-	DROP TRIGGER IF EXISTS trg_set_event_duration ON events;
-	CREATE TRIGGER trg_set_event_duration
-	BEFORE INSERT OR UPDATE ON events
-	FOR EACH ROW
-	EXECUTE FUNCTION calculate_event_duration();
-	`
-	if err := db.Exec(calculateEventDurationTrigger).Error; err != nil {
-		return err
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func execSQL(content string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		if strings.TrimSpace(content) == "" {
+			return nil
+		}
+		return tx.Exec(content).Error
 	}
+}
 
-	getUserUpcomingEventsFunc := `
-	CREATE OR REPLACE FUNCTION get_user_upcoming_events(p_user_id UUID)
-	RETURNS TABLE (
-		event_id uuid,
-		event_name character varying,
-		event_description text,
-		event_start_time timestamp,
-		event_end_time timestamp,
-		rsvp_status rsvp_status
-	) AS $$
-	BEGIN
-		RETURN QUERY
-		SELECT
-			f.event_id,
-			f.event_name,
-			f.event_description,
-			f.start_time,
-			f.end_time,
-			f.rsvp_status
-		FROM
-			full_invitation_details f
-		WHERE
-			f.user_id = p_user_id
-			AND f.start_time > NOW()
-			AND f.deleted_at IS NULL
-		ORDER BY
-			f.start_time ASC;
-	END;
-	$$ LANGUAGE plpgsql;
-	`
-	if err := db.Exec(getUserUpcomingEventsFunc).Error; err != nil {
-		return err
+func appliedMigrations(db *gorm.DB) (map[int64]SchemaMigration, error) {
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
 	}
 
-	getCreatedEventCountFunc := `
-	CREATE OR REPLACE FUNCTION get_created_event_count(p_user_id UUID)
-	RETURNS INT AS $$
-	DECLARE
-		event_count INT;
-	BEGIN
-		SELECT
-			COUNT(*)
-		INTO
-			event_count
-		FROM
-			events
-		WHERE
-			created_by = p_user_id
-			AND deleted_at IS NULL;
-			
-		RETURN event_count;
-	END;
-	$$ LANGUAGE plpgsql;
-	`
-	if err := db.Exec(getCreatedEventCountFunc).Error; err != nil {
-		return err
+	applied := make(map[int64]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
 	}
+	return applied, nil
+}
 
-	return nil
+// withLock runs fn on a single connection holding the migration advisory
+// lock; session locks belong to a connection, so the pool cannot be used
+func withLock(db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey)
+
+		if err := conn.AutoMigrate(&SchemaMigration{}); err != nil {
+			return err
+		}
+		return fn(conn)
+	})
 }
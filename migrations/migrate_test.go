@@ -0,0 +1,127 @@
+package migrations
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func Test_ReadMigrations(t *testing.T) {
+	noop := func(tx *gorm.DB) error { return nil }
+	baselineOnly := []migration{{Version: 1, Name: "baseline", Up: noop, Down: noop}}
+	file := func(content string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(content)}
+	}
+
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		goMigrations []migration
+		wantVersions []int64
+		wantNames    []string
+		wantDown     []bool
+		wantErr      bool
+	}{
+		{
+			name: "sorted by version",
+			files: fstest.MapFS{
+				"sql/0010_later.up.sql":   file("SELECT 10"),
+				"sql/0010_later.down.sql": file("SELECT -10"),
+				"sql/0002_first.up.sql":   file("SELECT 2"),
+				"sql/0002_first.down.sql": file("SELECT -2"),
+				"sql/0003_next.up.sql":    file("SELECT 3"),
+			},
+			goMigrations: baselineOnly,
+			wantVersions: []int64{1, 2, 3, 10},
+			wantNames:    []string{"baseline", "first", "next", "later"},
+			wantDown:     []bool{true, true, false, true},
+		},
+		{
+			name:         "no sql files",
+			files:        fstest.MapFS{"sql": &fstest.MapFile{Mode: fs.ModeDir}},
+			goMigrations: baselineOnly,
+			wantVersions: []int64{1},
+			wantNames:    []string{"baseline"},
+			wantDown:     []bool{true},
+		},
+		{
+			name:         "down file without up file",
+			files:        fstest.MapFS{"sql/0002_first.down.sql": file("SELECT -2")},
+			goMigrations: baselineOnly,
+			wantErr:      true,
+		},
+		{
+			name: "version used by two names",
+			files: fstest.MapFS{
+				"sql/0002_first.up.sql":  file("SELECT 2"),
+				"sql/0002_second.up.sql": file("SELECT 2"),
+			},
+			goMigrations: baselineOnly,
+			wantErr:      true,
+		},
+		{
+			name:         "version used by a go migration",
+			files:        fstest.MapFS{"sql/0001_baseline.up.sql": file("SELECT 1")},
+			goMigrations: baselineOnly,
+			wantErr:      true,
+		},
+		{
+			name:         "unexpected file name",
+			files:        fstest.MapFS{"sql/2_First.sql": file("SELECT 2")},
+			goMigrations: baselineOnly,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := readMigrations(tt.files, tt.goMigrations)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var versions []int64
+			var names []string
+			var down []bool
+			for _, m := range migrations {
+				versions = append(versions, m.Version)
+				names = append(names, m.Name)
+				down = append(down, m.Down != nil)
+				assert.NotNil(t, m.Up, m.Name)
+			}
+			assert.Equal(t, tt.wantVersions, versions)
+			assert.Equal(t, tt.wantNames, names)
+			assert.Equal(t, tt.wantDown, down)
+		})
+	}
+}
+
+// the embedded migrations must load, be numbered without gaps and all be
+// reversible, the baseline by refusing with a clear error
+func Test_LoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	assert.NoError(t, err)
+
+	for i, m := range migrations {
+		assert.Equal(t, int64(i+1), m.Version, m.Name)
+		assert.NotNil(t, m.Down, m.Name)
+	}
+	if assert.NotEmpty(t, migrations) {
+		assert.Equal(t, "baseline", migrations[0].Name)
+		assert.ErrorIs(t, migrations[0].Down(nil), ErrBaselineDown)
+	}
+}
+
+func Test_ExecSQL_Empty(t *testing.T) {
+	tests := []string{"", "  \n\t"}
+
+	for _, content := range tests {
+		// an empty file never reaches the database
+		assert.NoError(t, execSQL(content)(nil))
+	}
+}
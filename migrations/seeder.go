@@ -5,14 +5,13 @@ import (
 	"gorm.io/gorm"
 )
 
+// Seeder seeds the minimal set: the accounts and departments every
+// environment needs
 func Seeder(db *gorm.DB) error {
-	if err := seeds.ListUserSeeder(db); err != nil {
-		return err
-	}
-
-	if err := seeds.DepartmentAndUserSeeder(db); err != nil {
-		return err
-	}
+	return seeds.Run(db, "minimal", seeds.Options{})
+}
 
-	return nil
+// SeedSet seeds a named set from seeds.Sets; scale 0 uses the set default
+func SeedSet(db *gorm.DB, name string, scale int) error {
+	return seeds.Run(db, name, seeds.Options{Scale: scale})
 }
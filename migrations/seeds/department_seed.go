@@ -3,10 +3,11 @@ package seeds
 
 import (
 	"encoding/json"
-	"io"
 	"os"
 
+	"github.com/google/uuid"
 	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/helpers"
 	"gorm.io/gorm"
 )
 
@@ -18,12 +19,15 @@ type deptSeed struct {
 }
 
 func DepartmentAndUserSeeder(db *gorm.DB) error {
-	f, err := os.Open("./migrations/json/departments.json")
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-	data, err := io.ReadAll(f)
+	return db.Transaction(func(tx *gorm.DB) error {
+		return seedDepartments(&Session{DB: tx})
+	})
+}
+
+// seedDepartments upserts the department accounts by email and their
+// departments by account, since a department account owns one department
+func seedDepartments(s *Session) error {
+	data, err := os.ReadFile("./migrations/json/departments.json")
 	if err != nil {
 		return err
 	}
@@ -31,21 +35,81 @@ func DepartmentAndUserSeeder(db *gorm.DB) error {
 	if err := json.Unmarshal(data, &seeds); err != nil {
 		return err
 	}
-	for _, s := range seeds {
-		tx := db.Begin()
-		user := entity.User{Name: s.Name, Email: s.Email, Password: s.Password, Role: entity.RoleDepartemen}
-		if err := tx.Create(&user).Error; err != nil {
-			tx.Rollback()
-			return err
-		}
-		dept := entity.Department{Name: s.Name, Faculty: s.Faculty, UserID: user.ID}
-		if err := tx.Create(&dept).Error; err != nil {
-			tx.Rollback()
+
+	users := make([]entity.User, 0, len(seeds))
+	emails := make([]string, 0, len(seeds))
+	for _, seed := range seeds {
+		password, err := helpers.HashPassword(seed.Password)
+		if err != nil {
 			return err
 		}
-		if err := tx.Commit().Error; err != nil {
-			return err
+		users = append(users, entity.User{
+			ID:         seedID("user", seed.Email),
+			Name:       seed.Name,
+			Email:      seed.Email,
+			Password:   password,
+			Role:       entity.RoleDepartemen,
+			IsVerified: true,
+		})
+		emails = append(emails, seed.Email)
+	}
+	if err := upsert(s.DB, &users, []string{"email"}, []string{"name", "role", "is_verified", "updated_at"}); err != nil {
+		return err
+	}
+
+	userIds, err := userIDsByEmail(s.DB, emails)
+	if err != nil {
+		return err
+	}
+
+	// departments seeded before seeding was idempotent keep their ids
+	var existing []entity.Department
+	if err := s.DB.Where("user_id IN ?", mapValues(userIds)).Find(&existing).Error; err != nil {
+		return err
+	}
+	departmentIds := make(map[uuid.UUID]uuid.UUID, len(existing))
+	for _, department := range existing {
+		departmentIds[department.UserID] = department.ID
+	}
+
+	departments := make([]entity.Department, 0, len(seeds))
+	for _, seed := range seeds {
+		userId := userIds[seed.Email]
+		id, ok := departmentIds[userId]
+		if !ok {
+			id = seedID("department", seed.Email)
 		}
+		departments = append(departments, entity.Department{ID: id, Name: seed.Name, Faculty: seed.Faculty, UserID: userId})
+	}
+	if err := upsert(s.DB, &departments, []string{"id"}, []string{"name", "faculty", "user_id", "updated_at"}); err != nil {
+		return err
 	}
+	logProgress("departments", len(departments))
 	return nil
 }
+
+// userIDsByEmail maps emails to the ids stored for them, which differ from
+// seedID for accounts created before seeding was idempotent
+func userIDsByEmail(tx *gorm.DB, emails []string) (map[string]uuid.UUID, error) {
+	ids := make(map[string]uuid.UUID, len(emails))
+	for start := 0; start < len(emails); start += batchSize {
+		end := min(start+batchSize, len(emails))
+
+		var users []entity.User
+		if err := tx.Select("id", "email").Where("email IN ?", emails[start:end]).Find(&users).Error; err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			ids[user.Email] = user.ID
+		}
+	}
+	return ids, nil
+}
+
+func mapValues[K comparable, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, value := range m {
+		values = append(values, value)
+	}
+	return values
+}
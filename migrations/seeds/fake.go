@@ -0,0 +1,74 @@
+package seeds
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// Word lists for the fake data generator. Picks come from Session.Rand, so
+// the same scale always produces the same data.
+var (
+	firstNames = []string{
+		"Adi", "Ayu", "Bagus", "Bima", "Citra", "Dewi", "Dimas", "Eka", "Fajar", "Fitri",
+		"Gilang", "Hana", "Indra", "Intan", "Joko", "Kartika", "Lestari", "Made", "Nadia", "Nanda",
+		"Oka", "Putri", "Rafi", "Rani", "Rizky", "Sari", "Surya", "Tari", "Utami", "Wahyu",
+		"Wulan", "Yoga", "Yusuf", "Zahra", "Arief", "Bunga", "Dinda", "Galih", "Kevin", "Laras",
+	}
+	lastNames = []string{
+		"Pratama", "Saputra", "Wijaya", "Kusuma", "Hidayat", "Santoso", "Nugroho", "Purnomo", "Setiawan", "Lestari",
+		"Rahmawati", "Permana", "Siregar", "Nasution", "Wibowo", "Hakim", "Firmansyah", "Maharani", "Anggraini", "Utomo",
+	}
+	organizationKinds = []string{
+		"Himpunan Mahasiswa", "UKM", "Badan Eksekutif Mahasiswa", "Lembaga Minat Bakat", "Komunitas",
+	}
+	organizationFields = []string{
+		"Robotika", "Fotografi", "Paduan Suara", "Kewirausahaan", "Riset", "Bahasa Inggris", "Sepak Bola",
+		"Teater", "Pecinta Alam", "Karawitan", "Catur", "Debat", "Jurnalistik", "Desain", "Keamanan Siber",
+		"Teknik Informatika", "Teknik Elektro", "Teknik Sipil", "Arsitektur", "Statistika",
+	}
+	eventKinds = []string{
+		"Seminar", "Workshop", "Webinar", "Talkshow", "Pelatihan", "Lomba", "Kuliah Tamu", "Bootcamp", "Festival", "Sarasehan",
+	}
+	eventTopics = []string{
+		"Kecerdasan Buatan", "Kewirausahaan Digital", "Energi Terbarukan", "Karier di Industri", "Desain Produk",
+		"Keamanan Siber", "Smart City", "Data Science", "Public Speaking", "Pengembangan Diri", "Robotika",
+		"Maritim Berkelanjutan", "Startup Kampus", "Inovasi Teknologi", "Kepemimpinan",
+	}
+	roomPrefixes = []string{"Ruang Kelas", "Laboratorium", "Ruang Sidang", "Auditorium", "Ruang Seminar"}
+)
+
+func pick(r *rand.Rand, words []string) string {
+	return words[r.Intn(len(words))]
+}
+
+func fakePersonName(r *rand.Rand) string {
+	return pick(r, firstNames) + " " + pick(r, lastNames)
+}
+
+// fakeOrganizationName walks through the combinations in order, since
+// organization names are unique, and numbers them once they run out
+func fakeOrganizationName(index int) string {
+	combinations := len(organizationKinds) * len(organizationFields)
+	name := organizationKinds[index%len(organizationKinds)] + " " + organizationFields[(index/len(organizationKinds))%len(organizationFields)]
+	if round := index / combinations; round > 0 {
+		name = fmt.Sprintf("%s %d", name, round+1)
+	}
+	return name
+}
+
+func fakeEventName(r *rand.Rand, year int) string {
+	return fmt.Sprintf("%s %s %d", pick(r, eventKinds), pick(r, eventTopics), year)
+}
+
+func fakeDescription(r *rand.Rand, name string) string {
+	sentences := []string{
+		name + " mengajak mahasiswa ITS untuk belajar langsung dari praktisi dan akademisi.",
+		"Peserta akan mendapatkan materi, sesi tanya jawab, dan sertifikat kehadiran.",
+		"Acara terbuka untuk seluruh mahasiswa dari berbagai departemen.",
+		"Jangan lupa membawa kartu mahasiswa dan menunjukkan kode QR saat registrasi ulang.",
+		"Kuota terbatas, segera konfirmasi kehadiran melalui undangan yang dikirimkan.",
+	}
+	r.Shuffle(len(sentences)-1, func(i, j int) { sentences[i+1], sentences[j+1] = sentences[j+1], sentences[i+1] })
+	return strings.Join(sentences[:2+r.Intn(3)], " ")
+}
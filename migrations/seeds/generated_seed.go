@@ -0,0 +1,346 @@
+package seeds
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/entity"
+)
+
+// seedGenerated fills the database with fake but consistent data for the
+// departments seeded before it. For a scale of N it creates N students,
+// N/40 organizations, N/5 events and their bookings, invitations and
+// attendance, each within sensible bounds.
+func seedGenerated(s *Session) error {
+	var departments []entity.Department
+	if err := s.DB.Order("name").Find(&departments).Error; err != nil {
+		return err
+	}
+	if len(departments) == 0 {
+		return ErrNoDepartments
+	}
+
+	rooms, err := s.generateRooms(departments)
+	if err != nil {
+		return err
+	}
+	students, err := s.generateStudents()
+	if err != nil {
+		return err
+	}
+	organizations, err := s.generateOrganizations(students)
+	if err != nil {
+		return err
+	}
+	events, err := s.generateEvents(organizations)
+	if err != nil {
+		return err
+	}
+	if err := s.generateBookings(events, rooms); err != nil {
+		return err
+	}
+	return s.generateInvitations(events, students)
+}
+
+// generatedOrganization is an organization with the account its events are
+// created by
+type generatedOrganization struct {
+	ID      uuid.UUID
+	ChairID uuid.UUID
+}
+
+func (s *Session) generateRooms(departments []entity.Department) ([]entity.Room, error) {
+	perDepartment := min(2+s.Scale/1000, 20)
+
+	rooms := make([]entity.Room, 0, len(departments)*perDepartment)
+	for _, department := range departments {
+		for i := 0; i < perDepartment; i++ {
+			name := fmt.Sprintf("%s %s %d", roomPrefixes[i%len(roomPrefixes)], department.Name, 101+i)
+			rooms = append(rooms, entity.Room{
+				ID:                   seedID("room", department.ID.String()+":"+name),
+				DepartmentID:         department.ID,
+				Name:                 name,
+				Capacity:             20 + 10*s.Rand.Intn(15),
+				HasProjector:         s.Rand.Intn(10) < 8,
+				HasSoundSystem:       s.Rand.Intn(10) < 5,
+				HasAC:                s.Rand.Intn(10) < 9,
+				WheelchairAccessible: s.Rand.Intn(10) < 6,
+			})
+		}
+	}
+
+	if err := upsert(s.DB, &rooms, []string{"id"}, []string{"name", "capacity", "has_projector", "has_sound_system", "has_ac", "wheelchair_accessible", "updated_at"}); err != nil {
+		return nil, err
+	}
+	logProgress("rooms", len(rooms))
+	return rooms, nil
+}
+
+func (s *Session) generateStudents() ([]uuid.UUID, error) {
+	users := make([]entity.User, 0, s.Scale)
+	emails := make([]string, 0, s.Scale)
+	for i := 0; i < s.Scale; i++ {
+		email := fmt.Sprintf("student%05d@%s", i+1, generatedEmailHost)
+		users = append(users, entity.User{
+			ID:         seedID("user", email),
+			Name:       fakePersonName(s.Rand),
+			Email:      email,
+			Password:   s.hashedPassword,
+			Role:       entity.RoleUser,
+			IsVerified: true,
+		})
+		emails = append(emails, email)
+	}
+
+	if err := upsert(s.DB, &users, []string{"email"}, []string{"name", "is_verified", "updated_at"}); err != nil {
+		return nil, err
+	}
+	ids, err := userIDsByEmail(s.DB, emails)
+	if err != nil {
+		return nil, err
+	}
+
+	students := make([]uuid.UUID, 0, len(emails))
+	for _, email := range emails {
+		students = append(students, ids[email])
+	}
+	logProgress("students", len(students))
+	return students, nil
+}
+
+// generateOrganizations gives every organization a chair, a secretary, two
+// committee members and a scanner, taken from the students in turn
+func (s *Session) generateOrganizations(students []uuid.UUID) ([]generatedOrganization, error) {
+	count := min(max(s.Scale/40, 3), len(students)/5, 500)
+	if count == 0 {
+		return nil, nil
+	}
+
+	organizations := make([]entity.Organization, 0, count)
+	names := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		name := fakeOrganizationName(i)
+		organizations = append(organizations, entity.Organization{
+			ID:          seedID("organization", name),
+			Name:        name,
+			Description: "Organisasi mahasiswa " + name + " Institut Teknologi Sepuluh Nopember.",
+		})
+		names = append(names, name)
+	}
+	if err := upsert(s.DB, &organizations, []string{"name"}, []string{"description", "updated_at"}); err != nil {
+		return nil, err
+	}
+
+	// organizations created by hand before seeding keep their ids
+	var stored []entity.Organization
+	if err := s.DB.Select("id", "name").Where("name IN ?", names).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+	ids := make(map[string]uuid.UUID, len(stored))
+	for _, organization := range stored {
+		ids[organization.Name] = organization.ID
+	}
+
+	roles := []string{
+		entity.OrganizationRoleChair, entity.OrganizationRoleSecretary,
+		entity.OrganizationRoleCommittee, entity.OrganizationRoleCommittee,
+		entity.OrganizationRoleScanner,
+	}
+	generated := make([]generatedOrganization, 0, count)
+	members := make([]entity.OrganizationMember, 0, count*len(roles))
+	for i, name := range names {
+		for j, role := range roles {
+			userId := students[(i*len(roles)+j)%len(students)]
+			members = append(members, entity.OrganizationMember{
+				ID:             seedID("organization_member", ids[name].String()+":"+userId.String()),
+				OrganizationID: ids[name],
+				UserID:         userId,
+				Role:           role,
+			})
+		}
+		generated = append(generated, generatedOrganization{ID: ids[name], ChairID: members[len(members)-len(roles)].UserID})
+	}
+	if err := upsert(s.DB, &members, []string{"organization_id", "user_id"}, []string{"role", "updated_at"}); err != nil {
+		return nil, err
+	}
+
+	logProgress("organizations", len(generated))
+	return generated, nil
+}
+
+// generateEvents spreads events from 45 days ago to 60 days ahead of today
+func (s *Session) generateEvents(organizations []generatedOrganization) ([]entity.Event, error) {
+	if len(organizations) == 0 {
+		return nil, nil
+	}
+
+	count := min(max(s.Scale/5, 10), 5000)
+	platforms := []string{entity.MeetingPlatformZoom, entity.MeetingPlatformGoogleMeet, entity.MeetingPlatformTeams}
+
+	events := make([]entity.Event, 0, count)
+	for i := 0; i < count; i++ {
+		organization := organizations[i%len(organizations)]
+		start := s.Today.
+			AddDate(0, 0, s.Rand.Intn(106)-45).
+			Add(time.Duration(8+s.Rand.Intn(10)) * time.Hour)
+		name := fakeEventName(s.Rand, start.Year())
+		organizationId := organization.ID
+
+		event := entity.Event{
+			ID:             seedID("event", fmt.Sprint(i)),
+			Name:           name,
+			Description:    fakeDescription(s.Rand, name),
+			Start_Time:     start,
+			End_Time:       start.Add(time.Duration(1+s.Rand.Intn(4)) * time.Hour),
+			Created_By:     organization.ChairID,
+			Event_Type:     entity.EventTypeOffline,
+			OrganizationID: &organizationId,
+		}
+		switch kind := s.Rand.Intn(10); {
+		case kind < 2:
+			event.Event_Type = entity.EventTypeOnline
+		case kind < 4:
+			event.Event_Type = entity.EventTypeHybrid
+			event.OnlineQuota = 50 * (1 + s.Rand.Intn(4))
+		}
+		if event.Event_Type != entity.EventTypeOffline {
+			event.MeetingPlatform = platforms[s.Rand.Intn(len(platforms))]
+			event.MeetingURL = fmt.Sprintf("https://meet.example.com/myits-demo-%d", i)
+			event.MeetingPasscode = fmt.Sprintf("%06d", s.Rand.Intn(1000000))
+		}
+		events = append(events, event)
+	}
+
+	if err := upsert(s.DB, &events, []string{"id"}, []string{
+		"name", "description", "start_time", "end_time", "created_by", "event_type", "organization_id",
+		"online_quota", "meeting_url", "meeting_passcode", "meeting_platform", "updated_at",
+	}); err != nil {
+		return nil, err
+	}
+	logProgress("events", len(events))
+	return events, nil
+}
+
+// generateBookings books rooms for most in-person events. Decided bookings
+// cannot be modified, so existing bookings are left alone.
+func (s *Session) generateBookings(events []entity.Event, rooms []entity.Room) error {
+	var bookings []entity.BookingRequest
+	var bookingRooms []map[string]any
+	for _, event := range events {
+		if event.Event_Type == entity.EventTypeOnline || s.Rand.Intn(10) < 3 {
+			continue
+		}
+
+		status := "pending"
+		roll := s.Rand.Intn(100)
+		switch {
+		case event.End_Time.Before(s.Today) && roll < 85, !event.End_Time.Before(s.Today) && roll < 40:
+			status = "approved"
+		case event.End_Time.Before(s.Today), roll < 50:
+			status = "rejected"
+		}
+
+		booking := entity.BookingRequest{
+			ID:          seedID("booking_request", event.ID.String()),
+			EventID:     event.ID,
+			RequestedAt: event.Start_Time.AddDate(0, 0, -7-s.Rand.Intn(14)),
+			Status:      status,
+		}
+		bookings = append(bookings, booking)
+
+		for j := 0; j < 1+s.Rand.Intn(2); j++ {
+			bookingRooms = append(bookingRooms, map[string]any{
+				"booking_request_id": booking.ID,
+				"room_id":            rooms[s.Rand.Intn(len(rooms))].ID,
+			})
+		}
+	}
+
+	if err := upsert(s.DB, &bookings, []string{"id"}, nil); err != nil {
+		return err
+	}
+	if len(bookingRooms) > 0 {
+		if err := upsert(s.DB.Table("booking_request_room"), &bookingRooms, []string{"booking_request_id", "room_id"}, nil); err != nil {
+			return err
+		}
+	}
+	logProgress("booking requests", len(bookings))
+	return nil
+}
+
+// generateInvitations invites a sample of students to every event. Past
+// events get attendance for most accepted invitations.
+func (s *Session) generateInvitations(events []entity.Event, students []uuid.UUID) error {
+	maxInvitees := min(s.Set.InviteesPerEvent, len(students))
+	if maxInvitees == 0 {
+		return nil
+	}
+
+	invitations := make([]entity.Invitation, 0, len(events))
+	var invitees []entity.UserInvitation
+	attended := 0
+	for _, event := range events {
+		invitation := entity.Invitation{ID: seedID("invitation", event.ID.String()), EventID: event.ID}
+		invitations = append(invitations, invitation)
+
+		finished := event.End_Time.Before(s.Today)
+		picked := make(map[int]bool)
+		count := min(5+s.Rand.Intn(maxInvitees), maxInvitees)
+		for len(picked) < count {
+			picked[s.Rand.Intn(len(students))] = true
+		}
+
+		for index := range students {
+			if !picked[index] {
+				continue
+			}
+			userId := students[index]
+			invitee := entity.UserInvitation{
+				UserID:       userId,
+				InvitationID: invitation.ID,
+				QRCode:       seedID("qr_code", invitation.ID.String()+":"+userId.String()).String(),
+				InvitedAt:    event.Start_Time.AddDate(0, 0, -14),
+				RSVPStatus:   entity.RSVPStatusPending,
+			}
+
+			roll := s.Rand.Intn(100)
+			switch {
+			case finished && roll < 70, !finished && roll < 40:
+				invitee.RSVPStatus = entity.RSVPStatusAccepted
+			case finished && roll < 85, !finished && roll < 50:
+				invitee.RSVPStatus = entity.RSVPStatusDeclined
+			}
+			if invitee.RSVPStatus != entity.RSVPStatusPending {
+				rsvpAt := invitee.InvitedAt.Add(time.Duration(1+s.Rand.Intn(72)) * time.Hour)
+				invitee.RsvpAt = &rsvpAt
+			}
+
+			if invitee.RSVPStatus == entity.RSVPStatusAccepted {
+				if event.Event_Type == entity.EventTypeHybrid {
+					mode := entity.AttendanceModeInPerson
+					if s.Rand.Intn(2) == 0 {
+						mode = entity.AttendanceModeOnline
+					}
+					invitee.AttendanceMode = &mode
+				}
+				if finished && s.Rand.Intn(10) < 8 {
+					attendedAt := event.Start_Time.Add(time.Duration(s.Rand.Intn(30)-10) * time.Minute)
+					invitee.AttendedAt = &attendedAt
+					attended++
+				}
+			}
+			invitees = append(invitees, invitee)
+		}
+	}
+
+	if err := upsert(s.DB, &invitations, []string{"id"}, nil); err != nil {
+		return err
+	}
+	if err := upsert(s.DB, &invitees, []string{"user_id", "invitation_id"}, []string{"rsvp_status", "rsvp_at", "attended_at", "attendance_mode"}); err != nil {
+		return err
+	}
+	logProgress("invitations", len(invitees))
+	logProgress("attendances", attended)
+	return nil
+}
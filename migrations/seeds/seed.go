@@ -0,0 +1,176 @@
+package seeds
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/helpers"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DemoPassword is the password of every generated account
+	DemoPassword = "password123"
+
+	// randomSeed keeps generated data the same on every run, so rerunning a
+	// set updates the rows it created instead of adding new ones
+	randomSeed = 20240601
+	batchSize  = 500
+)
+
+var (
+	ErrUnknownSet      = errors.New("unknown seed set")
+	ErrProductionSeed  = errors.New("this seed set generates fake accounts and cannot run in production")
+	ErrNoDepartments   = errors.New("no departments to generate rooms for, seed the minimal set first")
+	seedNamespace      = uuid.MustParse("6f1c1a5e-3f0a-4c55-9d53-0b8f2d8a7e21")
+	generatedEmailHost = "demo.myits.test"
+)
+
+type (
+	// Set is a named list of seeders run in one transaction
+	Set struct {
+		Name        string
+		Description string
+		// DefaultScale is the number of generated students when --scale is not given
+		DefaultScale int
+		// InviteesPerEvent caps the generated invitations of an event
+		InviteesPerEvent int
+		// AllowProduction is false for sets that create fake accounts
+		AllowProduction bool
+		Seeders         []func(s *Session) error
+	}
+
+	Options struct {
+		// Scale is the number of generated students; the other generated
+		// records are derived from it
+		Scale int
+	}
+
+	// Session is the state shared by the seeders of one run
+	Session struct {
+		DB   *gorm.DB
+		Rand *rand.Rand
+		Set  Set
+		// Scale is Options.Scale, or Set.DefaultScale when unset
+		Scale int
+		// Today anchors generated event times, so demo data always has
+		// finished, ongoing and upcoming events
+		Today time.Time
+
+		hashedPassword string
+	}
+)
+
+var sets = []Set{
+	{
+		Name:            "minimal",
+		Description:     "admin, sample and department accounts from migrations/json",
+		AllowProduction: true,
+		Seeders:         []func(s *Session) error{seedUsers, seedDepartments},
+	},
+	{
+		Name:             "demo",
+		Description:      "minimal plus rooms, organizations, events, bookings, invitations and attendance",
+		DefaultScale:     200,
+		InviteesPerEvent: 60,
+		Seeders:          []func(s *Session) error{seedUsers, seedDepartments, seedGenerated},
+	},
+	{
+		Name:             "load-test",
+		Description:      "the demo data at a size meant for load tests",
+		DefaultScale:     10000,
+		InviteesPerEvent: 300,
+		Seeders:          []func(s *Session) error{seedUsers, seedDepartments, seedGenerated},
+	},
+}
+
+// Sets lists the available seed sets
+func Sets() []Set {
+	return sets
+}
+
+// Run seeds the named set. Every seeder upserts by natural key, so running a
+// set twice leaves the database as after the first run.
+func Run(db *gorm.DB, name string, opts Options) error {
+	var set *Set
+	for i := range sets {
+		if sets[i].Name == name {
+			set = &sets[i]
+		}
+	}
+	if set == nil {
+		return fmt.Errorf("%w %q", ErrUnknownSet, name)
+	}
+	if !set.AllowProduction && os.Getenv("APP_ENV") == constants.ENUM_RUN_PRODUCTION {
+		return ErrProductionSeed
+	}
+
+	scale := opts.Scale
+	if scale <= 0 {
+		scale = set.DefaultScale
+	}
+	hashed, err := helpers.HashPassword(DemoPassword)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return db.Transaction(func(tx *gorm.DB) error {
+		s := &Session{
+			DB:             tx,
+			Rand:           rand.New(rand.NewSource(randomSeed)),
+			Set:            *set,
+			Scale:          scale,
+			Today:          time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local),
+			hashedPassword: hashed,
+		}
+		for _, seeder := range set.Seeders {
+			if err := seeder(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// seedID derives a stable primary key for rows without a unique natural key
+func seedID(kind string, key string) uuid.UUID {
+	return uuid.NewSHA1(seedNamespace, []byte(kind+":"+key))
+}
+
+// upsert inserts rows in batches. On a conflict on the given columns the
+// update columns are overwritten; without update columns the existing row is
+// kept as it is.
+func upsert(tx *gorm.DB, rows any, conflict []string, updates []string) error {
+	if reflect.ValueOf(rows).Elem().Len() == 0 {
+		return nil
+	}
+
+	onConflict := clause.OnConflict{}
+	for _, column := range conflict {
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: column})
+	}
+	if len(updates) == 0 {
+		onConflict.DoNothing = true
+	} else {
+		onConflict.DoUpdates = clause.AssignmentColumns(updates)
+	}
+
+	// hooks would hash the already hashed passwords again
+	return tx.Session(&gorm.Session{SkipHooks: true}).
+		Omit(clause.Associations).
+		Clauses(onConflict).
+		CreateInBatches(rows, batchSize).Error
+}
+
+func logProgress(kind string, count int) {
+	slog.Info("seeded", "kind", kind, "count", count)
+}
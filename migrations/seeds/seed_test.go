@@ -0,0 +1,89 @@
+package seeds
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Run_Refuses(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     string
+		appEnv  string
+		wantErr error
+	}{
+		{"unknown set", "everything", "", ErrUnknownSet},
+		{"demo in production", "demo", constants.ENUM_RUN_PRODUCTION, ErrProductionSeed},
+		{"load test in production", "load-test", constants.ENUM_RUN_PRODUCTION, ErrProductionSeed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_ENV", tt.appEnv)
+			// both checks run before the database is touched
+			assert.ErrorIs(t, Run(nil, tt.set, Options{}), tt.wantErr)
+		})
+	}
+}
+
+func Test_SeedID(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind, key string
+		otherKind string
+		otherKey  string
+	}{
+		{"other key", "user", "a@its.ac.id", "user", "b@its.ac.id"},
+		{"other kind", "user", "a@its.ac.id", "room", "a@its.ac.id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the same record gets the same id on every run
+			assert.Equal(t, seedID(tt.kind, tt.key), seedID(tt.kind, tt.key))
+			assert.NotEqual(t, seedID(tt.kind, tt.key), seedID(tt.otherKind, tt.otherKey))
+		})
+	}
+}
+
+func Test_FakeOrganizationName(t *testing.T) {
+	combinations := len(organizationKinds) * len(organizationFields)
+
+	tests := []struct {
+		name  string
+		count int
+	}{
+		{"within the combinations", combinations},
+		{"numbered after running out", 3 * combinations},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make(map[string]int, tt.count)
+			for i := range tt.count {
+				name := fakeOrganizationName(i)
+				if previous, ok := seen[name]; ok {
+					t.Fatalf("organizations %d and %d are both named %q", previous, i, name)
+				}
+				seen[name] = i
+			}
+		})
+	}
+}
+
+func Test_FakeData_Deterministic(t *testing.T) {
+	generate := func() []string {
+		r := rand.New(rand.NewSource(randomSeed))
+		var out []string
+		for range 20 {
+			name := fakeEventName(r, 2026)
+			out = append(out, fakePersonName(r), name, fakeDescription(r, name))
+		}
+		return out
+	}
+
+	assert.Equal(t, generate(), generate())
+}
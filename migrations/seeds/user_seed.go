@@ -2,51 +2,44 @@ package seeds
 
 import (
 	"encoding/json"
-	"errors"
-	"io"
 	"os"
 
 	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/helpers"
 	"gorm.io/gorm"
 )
 
+// ListUserSeeder seeds the accounts of migrations/json/users.json
 func ListUserSeeder(db *gorm.DB) error {
-	jsonFile, err := os.Open("./migrations/json/users.json")
-	if err != nil {
-		return err
-	}
+	return seedUsers(&Session{DB: db})
+}
 
-	jsonData, err := io.ReadAll(jsonFile)
+// seedUsers upserts users.json by email. Passwords are only set for new
+// accounts, so a password changed after seeding is kept.
+func seedUsers(s *Session) error {
+	data, err := os.ReadFile("./migrations/json/users.json")
 	if err != nil {
 		return err
 	}
 
 	var listUser []entity.User
-	if err := json.Unmarshal(jsonData, &listUser); err != nil {
+	if err := json.Unmarshal(data, &listUser); err != nil {
 		return err
 	}
 
-	hasTable := db.Migrator().HasTable(&entity.User{})
-	if !hasTable {
-		if err := db.Migrator().CreateTable(&entity.User{}); err != nil {
-			return err
+	for i := range listUser {
+		listUser[i].ID = seedID("user", listUser[i].Email)
+		if listUser[i].Role == "" {
+			listUser[i].Role = entity.RoleUser
 		}
-	}
-
-	for _, data := range listUser {
-		var user entity.User
-		err := db.Where(&entity.User{Email: data.Email}).First(&user).Error
-		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		if listUser[i].Password, err = helpers.HashPassword(listUser[i].Password); err != nil {
 			return err
 		}
-
-		isData := db.Find(&user, "email = ?", data.Email).RowsAffected
-		if isData == 0 {
-			if err := db.Create(&data).Error; err != nil {
-				return err
-			}
-		}
 	}
 
+	if err := upsert(s.DB, &listUser, []string{"email"}, []string{"name", "role", "is_verified", "updated_at"}); err != nil {
+		return err
+	}
+	logProgress("users", len(listUser))
 	return nil
 }
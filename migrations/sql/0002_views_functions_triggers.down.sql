@@ -0,0 +1,26 @@
+DROP TRIGGER IF EXISTS trg_set_event_duration ON events;
+DROP TRIGGER IF EXISTS trg_validate_event_time ON events;
+DROP TRIGGER IF EXISTS trg_auto_set_invited_at ON user_invitation;
+DROP TRIGGER IF EXISTS trg_prevent_event_deletion_with_bookings ON events;
+DROP TRIGGER IF EXISTS trg_prevent_booking_modification ON booking_requests;
+DROP TRIGGER IF EXISTS trg_generate_qr_code_before_insert_on_user_invitation ON user_invitation;
+
+DROP FUNCTION IF EXISTS get_created_event_count(UUID);
+DROP FUNCTION IF EXISTS get_user_upcoming_events(UUID);
+DROP FUNCTION IF EXISTS calculate_event_duration();
+DROP FUNCTION IF EXISTS validate_event_time();
+DROP FUNCTION IF EXISTS is_room_available(UUID, TIMESTAMP, TIMESTAMP);
+DROP FUNCTION IF EXISTS get_event_by_status(TEXT);
+DROP FUNCTION IF EXISTS fn_set_invited_at_timestamp();
+DROP FUNCTION IF EXISTS get_pending_booking_requests_for_department(UUID);
+DROP FUNCTION IF EXISTS get_event_attendees(UUID);
+DROP FUNCTION IF EXISTS prevent_event_deletion_with_bookings();
+DROP FUNCTION IF EXISTS prevent_booking_modification();
+DROP FUNCTION IF EXISTS generate_user_invitation_qr_code();
+
+DROP VIEW IF EXISTS vw_room_details;
+DROP VIEW IF EXISTS vw_booking_with_rooms;
+DROP VIEW IF EXISTS user_attendance_view;
+DROP VIEW IF EXISTS ormawa_events_view;
+DROP VIEW IF EXISTS full_invitation_details;
+DROP VIEW IF EXISTS event_details;
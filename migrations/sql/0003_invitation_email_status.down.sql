@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_user_invitation_email_failed;
+
+ALTER TABLE user_invitation DROP COLUMN IF EXISTS email_error;
+ALTER TABLE user_invitation DROP COLUMN IF EXISTS email_sent_at;
@@ -0,0 +1,5 @@
+-- tracks delivery of the invitation email so failed sends can be retried
+ALTER TABLE user_invitation ADD COLUMN IF NOT EXISTS email_sent_at TIMESTAMP;
+ALTER TABLE user_invitation ADD COLUMN IF NOT EXISTS email_error TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_user_invitation_email_failed ON user_invitation (invitation_id) WHERE email_error IS NOT NULL;
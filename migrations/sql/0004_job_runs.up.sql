@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS job_runs (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	job VARCHAR(100) NOT NULL,
+	trigger VARCHAR(20) NOT NULL,
+	triggered_by UUID REFERENCES users (id) ON DELETE SET NULL,
+	scheduled_at TIMESTAMP WITH TIME ZONE,
+	status VARCHAR(20) NOT NULL,
+	result TEXT NOT NULL DEFAULT '',
+	error TEXT,
+	instance VARCHAR(255) NOT NULL,
+	started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_started_at ON job_runs (job, started_at DESC);
+
+-- one row per scheduled tick is what keeps replicas from running it twice
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_runs_job_scheduled_at ON job_runs (job, scheduled_at) WHERE scheduled_at IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS event_reminder_deliveries;
+
+ALTER TABLE events DROP COLUMN IF EXISTS remind_pending;
+ALTER TABLE events DROP COLUMN IF EXISTS reminder_offsets;
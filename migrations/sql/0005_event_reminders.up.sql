@@ -0,0 +1,13 @@
+-- minutes before start_time at which reminders go out, comma separated;
+-- an empty list disables reminders for the event
+ALTER TABLE events ADD COLUMN IF NOT EXISTS reminder_offsets VARCHAR(100) NOT NULL DEFAULT '1440,60';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS remind_pending BOOLEAN NOT NULL DEFAULT false;
+
+-- one row per reminder sent, so each reminder reaches an invitee once
+CREATE TABLE IF NOT EXISTS event_reminder_deliveries (
+	event_id UUID NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	offset_minutes INTEGER NOT NULL,
+	sent_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	PRIMARY KEY (event_id, user_id, offset_minutes)
+);
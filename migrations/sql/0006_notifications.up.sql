@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS notifications (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	type VARCHAR(50) NOT NULL,
+	title VARCHAR(200) NOT NULL,
+	body TEXT NOT NULL DEFAULT '',
+	event_id UUID REFERENCES events (id) ON DELETE SET NULL,
+	read_at TIMESTAMP WITH TIME ZONE,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created_at ON notifications (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications (user_id) WHERE read_at IS NULL;
@@ -0,0 +1,16 @@
+-- events are written in the transaction that caused them and delivered to
+-- subscribers once it commits; the serial id keeps delivery in commit order
+CREATE TABLE IF NOT EXISTS outbox_events (
+	id BIGSERIAL PRIMARY KEY,
+	topic VARCHAR(100) NOT NULL,
+	payload JSONB NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	pending_subscribers TEXT NOT NULL DEFAULT '',
+	next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	last_error TEXT,
+	delivered_at TIMESTAMP WITH TIME ZONE,
+	failed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_due ON outbox_events (next_attempt_at, id) WHERE delivered_at IS NULL AND failed_at IS NULL;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	url VARCHAR(2048) NOT NULL,
+	description VARCHAR(255) NOT NULL DEFAULT '',
+	secret VARCHAR(100) NOT NULL,
+	event_types TEXT NOT NULL,
+	active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_by UUID NOT NULL REFERENCES users (id),
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_deleted_at ON webhooks (deleted_at);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	webhook_id UUID NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+	event_type VARCHAR(100) NOT NULL,
+	payload JSONB NOT NULL,
+	status VARCHAR(20) NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	redelivery_of UUID REFERENCES webhook_deliveries (id) ON DELETE SET NULL,
+	response_status INTEGER,
+	response_body TEXT NOT NULL DEFAULT '',
+	error TEXT,
+	duration_ms BIGINT,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	delivered_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_created_at ON webhook_deliveries (webhook_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS audit_logs (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	actor_id UUID,
+	actor_role VARCHAR(20) NOT NULL,
+	action VARCHAR(10) NOT NULL,
+	entity_type VARCHAR(100) NOT NULL,
+	entity_id VARCHAR(100) NOT NULL,
+	before JSONB,
+	after JSONB,
+	ip VARCHAR(45) NOT NULL DEFAULT '',
+	request_id VARCHAR(64) NOT NULL DEFAULT '',
+	route VARCHAR(255) NOT NULL DEFAULT '',
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs (entity_type, entity_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON audit_logs (actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_request_id ON audit_logs (request_id);
+
+-- actor_id has no foreign key so the log outlives deleted users
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_logs_append_only ON audit_logs;
+CREATE TRIGGER trg_audit_logs_append_only
+	BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_logs
+	FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only();
@@ -0,0 +1,22 @@
+package provider
+
+import (
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+func ProvideAuditDependencies(injector *do.Injector, db *gorm.DB, jwtService service.JWTService) {
+	// Repository
+	auditRepository := repository.NewAuditRepository(db)
+
+	// Service
+	auditService := service.NewAuditService(auditRepository, db)
+
+	// Controller
+	do.Provide(injector, func(i *do.Injector) (controller.AuditController, error) {
+		return controller.NewAuditController(auditService), nil
+	})
+}
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"github.com/miraicantsleep/myits-event-be/constants"
 	"github.com/miraicantsleep/myits-event-be/controller"
 	"github.com/miraicantsleep/myits-event-be/repository"
 	"github.com/miraicantsleep/myits-event-be/service"
@@ -13,9 +14,16 @@ func ProvideBookingRequestDependencies(injector *do.Injector, db *gorm.DB, jwtSe
 	bookingRequestRepository := repository.NewBookingRequestRepository(db)
 	eventRepository := repository.NewEventRepository(db)
 	roomRepository := repository.NewRoomRepository(db)
+	equipmentRepository := repository.NewEquipmentRepository(db)
+	departmentRepository := repository.NewDepartmentRepository(db)
+	roomScheduleRepository := repository.NewRoomScheduleRepository(db)
 
 	// Service
-	bookingRequestService := service.NewBookingRequestService(bookingRequestRepository, roomRepository, eventRepository, jwtService, db)
+	roomScheduleService := service.NewRoomScheduleService(roomScheduleRepository, roomRepository, departmentRepository, db)
+	eventBus := do.MustInvokeNamed[service.EventBus](injector, constants.EventBus)
+	bookingRequestService := service.NewBookingRequestService(bookingRequestRepository, roomRepository, eventRepository, equipmentRepository, roomScheduleService, jwtService, eventBus, db)
+	// webhook payloads reuse the booking request response
+	do.ProvideNamedValue(injector, constants.BookingRequestService, bookingRequestService)
 
 	// Controller
 	do.Provide(
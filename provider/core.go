@@ -1,16 +1,23 @@
 package provider
 
 import (
+	"os"
+
 	"github.com/miraicantsleep/myits-event-be/config"
 	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/middleware"
+	"github.com/miraicantsleep/myits-event-be/repository"
 	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/miraicantsleep/myits-event-be/utils"
 	"github.com/samber/do"
 	"gorm.io/gorm"
 )
 
 func InitDatabase(injector *do.Injector) {
 	do.ProvideNamed(injector, constants.DB, func(i *do.Injector) (*gorm.DB, error) {
-		return config.SetUpDatabaseConnection(), nil
+		db := config.SetUpDatabaseConnection()
+		// writes to audited tables append to audit_logs from here on
+		return db, service.RegisterAuditCallbacks(db, repository.NewAuditRepository(db))
 	})
 }
 
@@ -18,7 +25,41 @@ func RegisterDependencies(injector *do.Injector) {
 	InitDatabase(injector)
 
 	do.ProvideNamed(injector, constants.JWTService, func(i *do.Injector) (service.JWTService, error) {
-		return service.NewJWTService(), nil
+		// fails startup in production when no signing key is configured
+		db := do.MustInvokeNamed[*gorm.DB](i, constants.DB)
+		return service.NewJWTService(repository.NewRefreshTokenRepository(db))
+	})
+
+	do.ProvideNamed(injector, constants.APIKeyService, func(i *do.Injector) (service.APIKeyService, error) {
+		db := do.MustInvokeNamed[*gorm.DB](i, constants.DB)
+		return service.NewAPIKeyService(
+			repository.NewAPIKeyRepository(db),
+			repository.NewUserRepository(db),
+			repository.NewOrganizationRepository(db),
+			db,
+		), nil
+	})
+
+	do.ProvideNamed(injector, constants.FileStorage, func(i *do.Injector) (utils.Storage, error) {
+		return utils.NewLocalStorage(utils.PATH, "/"+utils.PATH), nil
+	})
+
+	// RATE_LIMIT_STORE=postgres shares limits between instances
+	do.ProvideNamed(injector, constants.RateLimitStore, func(i *do.Injector) (middleware.RateLimitStore, error) {
+		if os.Getenv("RATE_LIMIT_STORE") == middleware.RateLimitStorePostgres {
+			return middleware.NewPostgresRateLimitStore(do.MustInvokeNamed[*gorm.DB](i, constants.DB)), nil
+		}
+		return middleware.NewMemoryRateLimitStore(), nil
+	})
+
+	do.ProvideNamed(injector, constants.EventBus, func(i *do.Injector) (service.EventBus, error) {
+		db := do.MustInvokeNamed[*gorm.DB](i, constants.DB)
+		return service.NewEventBus(repository.NewOutboxRepository(db)), nil
+	})
+
+	do.ProvideNamed(injector, constants.NotificationService, func(i *do.Injector) (service.NotificationService, error) {
+		db := do.MustInvokeNamed[*gorm.DB](i, constants.DB)
+		return service.NewNotificationService(repository.NewNotificationRepository(db), db), nil
 	})
 
 	// Initialize
@@ -28,8 +69,16 @@ func RegisterDependencies(injector *do.Injector) {
 	// Provide Dependencies
 	ProvideUserDependencies(injector, db, jwtService)
 	ProvideDepartmentDependencies(injector, db, jwtService)
+	ProvideOrganizationDependencies(injector, db, jwtService)
 	ProvideEventDependencies(injector, db, jwtService)
 	ProvideRoomDependencies(injector, db, jwtService)
+	ProvideEquipmentDependencies(injector, db, jwtService)
 	ProvideInvitationDependencies(injector, db, jwtService)
 	ProvideBookingRequestDependencies(injector, db, jwtService)
+	ProvideSchedulerDependencies(injector, db, jwtService)
+	ProvideNotificationDependencies(injector, db, jwtService)
+	ProvideWebhookDependencies(injector, db, jwtService)
+	ProvideAuditDependencies(injector, db, jwtService)
+	ProvideLogDependencies(injector)
+	ProvideEventBusDependencies(injector, db, jwtService)
 }
@@ -12,15 +12,23 @@ func ProvideDepartmentDependencies(injector *do.Injector, db *gorm.DB, jwtServic
 	// Repository
 	departmentRepository := repository.NewDepartmentRepository(db)
 	userRepository := repository.NewUserRepository(db)
+	userTokenRepository := repository.NewUserTokenRepository(db)
+	refreshTokenRepository := repository.NewRefreshTokenRepository(db)
+	roleChangeRepository := repository.NewRoleChangeRepository(db)
+	organizationRepository := repository.NewOrganizationRepository(db)
+	loginThrottleRepository := repository.NewLoginThrottleRepository(db)
+	roomRepository := repository.NewRoomRepository(db)
+	roomScheduleRepository := repository.NewRoomScheduleRepository(db)
 
 	// Service
 	departmentService := service.NewDepartmentService(departmentRepository, userRepository, jwtService, db)
-	userService := service.NewUserService(userRepository, jwtService, db)
+	userService := service.NewUserService(userRepository, userTokenRepository, refreshTokenRepository, departmentRepository, roleChangeRepository, organizationRepository, loginThrottleRepository, jwtService, db)
+	roomScheduleService := service.NewRoomScheduleService(roomScheduleRepository, roomRepository, departmentRepository, db)
 
 	// Controller
 	do.Provide(
 		injector, func(i *do.Injector) (controller.DepartmentController, error) {
-			return controller.NewDepartmentController(departmentService, userService), nil
+			return controller.NewDepartmentController(departmentService, userService, roomScheduleService), nil
 		},
 	)
 }
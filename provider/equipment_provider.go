@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+func ProvideEquipmentDependencies(injector *do.Injector, db *gorm.DB, jwtService service.JWTService) {
+	// Repository
+	equipmentRepository := repository.NewEquipmentRepository(db)
+	departmentRepository := repository.NewDepartmentRepository(db)
+
+	// Service
+	equipmentService := service.NewEquipmentService(equipmentRepository, departmentRepository, db)
+
+	// Controller
+	do.Provide(
+		injector, func(i *do.Injector) (controller.EquipmentController, error) {
+			return controller.NewEquipmentController(equipmentService), nil
+		},
+	)
+}
@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+func ProvideEventBusDependencies(injector *do.Injector, db *gorm.DB, jwtService service.JWTService) {
+	// Repository
+	outboxRepository := repository.NewOutboxRepository(db)
+
+	// Jobs
+	provideJob(injector, service.PruneOutboxEventsJob(outboxRepository, outboxRetention()))
+
+	// Service
+	do.ProvideNamed(injector, constants.EventDispatcher, func(i *do.Injector) (service.EventDispatcher, error) {
+		var subscribers []service.Subscriber
+		for _, name := range i.ListProvidedServices() {
+			if strings.HasPrefix(name, constants.SubscriberPrefix) {
+				subscribers = append(subscribers, do.MustInvokeNamed[service.Subscriber](i, name))
+			}
+		}
+		return service.NewEventDispatcher(subscribers, outboxRepository)
+	})
+}
+
+func provideSubscribers(injector *do.Injector, subscribers ...service.Subscriber) {
+	for _, subscriber := range subscribers {
+		do.ProvideNamedValue(injector, constants.SubscriberPrefix+subscriber.Topic+":"+subscriber.Name, subscriber)
+	}
+}
+
+// outboxRetention reads OUTBOX_RETENTION_DAYS, 7 days by default
+func outboxRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("OUTBOX_RETENTION_DAYS"))
+	if err != nil || days < 1 {
+		days = 7
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
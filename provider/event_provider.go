@@ -5,6 +5,7 @@ import (
 	"github.com/miraicantsleep/myits-event-be/controller"
 	"github.com/miraicantsleep/myits-event-be/repository"
 	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/miraicantsleep/myits-event-be/utils"
 	"github.com/samber/do"
 	"gorm.io/gorm"
 )
@@ -18,13 +19,26 @@ func ProvideEventDependencies(injector *do.Injector, db *gorm.DB, jwtService ser
 	// Service
 	do.ProvideNamed(injector, constants.EventService, func(i *do.Injector) (service.EventService, error) {
 		eventRepo := do.MustInvokeNamed[repository.EventRepository](i, constants.EventRepository)
+		attachmentRepo := repository.NewEventAttachmentRepository(db)
+		bookingRequestRepo := repository.NewBookingRequestRepository(db)
+		scheduleService := service.NewRoomScheduleService(repository.NewRoomScheduleRepository(db), repository.NewRoomRepository(db), repository.NewDepartmentRepository(db), db)
+		storage := do.MustInvokeNamed[utils.Storage](i, constants.FileStorage)
+		eventBus := do.MustInvokeNamed[service.EventBus](i, constants.EventBus)
 		// jwtService is available in the ProvideEventDependencies function's scope
-		return service.NewEventService(eventRepo, jwtService, db), nil
+		return service.NewEventService(eventRepo, attachmentRepo, bookingRequestRepo, scheduleService, storage, jwtService, eventBus, db), nil
 	})
 
+	reminderService := service.NewEventReminderService(repository.NewEventReminderRepository(db), repository.NewEventRepository(db), db)
+
+	// Jobs
+	provideJob(injector, service.SendEventRemindersJob(reminderService))
+
 	// Controller
 	do.Provide(injector, func(i *do.Injector) (controller.EventController, error) {
 		eventSvc := do.MustInvokeNamed[service.EventService](i, constants.EventService)
 		return controller.NewEventController(eventSvc), nil
 	})
+	do.Provide(injector, func(i *do.Injector) (controller.EventReminderController, error) {
+		return controller.NewEventReminderController(reminderService), nil
+	})
 }
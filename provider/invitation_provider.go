@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"github.com/miraicantsleep/myits-event-be/constants"
 	"github.com/miraicantsleep/myits-event-be/controller"
 	"github.com/miraicantsleep/myits-event-be/repository"
 	"github.com/miraicantsleep/myits-event-be/service"
@@ -11,9 +12,14 @@ import (
 func ProvideInvitationDependencies(injector *do.Injector, db *gorm.DB, jwtService service.JWTService) {
 	// Repository
 	invitationRepository := repository.NewInvitationRepository(db)
+	eventRepository := repository.NewEventRepository(db)
 
 	// Service
-	invitationService := service.NewInvitationService(invitationRepository, jwtService, db)
+	eventBus := do.MustInvokeNamed[service.EventBus](injector, constants.EventBus)
+	invitationService := service.NewInvitationService(invitationRepository, eventRepository, jwtService, eventBus, db)
+
+	// Subscribers
+	provideSubscribers(injector, service.InvitationSubscribers(invitationService)...)
 
 	// Controller
 	do.Provide(
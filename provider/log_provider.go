@@ -0,0 +1,18 @@
+package provider
+
+import (
+	"github.com/miraicantsleep/myits-event-be/config"
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+)
+
+func ProvideLogDependencies(injector *do.Injector) {
+	// Service
+	logService := service.NewLogService(config.NewLogConfig().Dir)
+
+	// Controller
+	do.Provide(injector, func(i *do.Injector) (controller.LogController, error) {
+		return controller.NewLogController(logService), nil
+	})
+}
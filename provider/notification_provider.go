@@ -0,0 +1,20 @@
+package provider
+
+import (
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+func ProvideNotificationDependencies(injector *do.Injector, db *gorm.DB, jwtService service.JWTService) {
+	// Subscribers
+	notificationService := do.MustInvokeNamed[service.NotificationService](injector, constants.NotificationService)
+	provideSubscribers(injector, service.NotificationSubscribers(notificationService)...)
+
+	// Controller
+	do.Provide(injector, func(i *do.Injector) (controller.NotificationController, error) {
+		return controller.NewNotificationController(notificationService), nil
+	})
+}
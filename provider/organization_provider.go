@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+func ProvideOrganizationDependencies(injector *do.Injector, db *gorm.DB, jwtService service.JWTService) {
+	// Repository
+	organizationRepository := repository.NewOrganizationRepository(db)
+	userRepository := repository.NewUserRepository(db)
+	refreshTokenRepository := repository.NewRefreshTokenRepository(db)
+
+	// Service
+	organizationService := service.NewOrganizationService(organizationRepository, userRepository, refreshTokenRepository, jwtService, db)
+
+	// Controller
+	do.Provide(
+		injector, func(i *do.Injector) (controller.OrganizationController, error) {
+			return controller.NewOrganizationController(organizationService), nil
+		},
+	)
+}
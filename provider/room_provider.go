@@ -1,9 +1,11 @@
 package provider
 
 import (
+	"github.com/miraicantsleep/myits-event-be/constants"
 	"github.com/miraicantsleep/myits-event-be/controller"
 	"github.com/miraicantsleep/myits-event-be/repository"
 	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/miraicantsleep/myits-event-be/utils"
 	"github.com/samber/do"
 	"gorm.io/gorm"
 )
@@ -12,14 +14,19 @@ func ProvideRoomDependencies(injector *do.Injector, db *gorm.DB, jwtService serv
 	// Repository
 	roomRepository := repository.NewRoomRepository(db)
 	departmentRepository := repository.NewDepartmentRepository(db)
+	roomScheduleRepository := repository.NewRoomScheduleRepository(db)
+	roomImageRepository := repository.NewRoomImageRepository(db)
+	storage := do.MustInvokeNamed[utils.Storage](injector, constants.FileStorage)
 
 	// Service
 	roomService := service.NewRoomService(roomRepository, jwtService, db, departmentRepository)
+	roomScheduleService := service.NewRoomScheduleService(roomScheduleRepository, roomRepository, departmentRepository, db)
+	roomImageService := service.NewRoomImageService(roomImageRepository, roomRepository, departmentRepository, storage, db)
 
 	// Controller
 	do.Provide(
 		injector, func(i *do.Injector) (controller.RoomController, error) {
-			return controller.NewRoomController(roomService), nil
+			return controller.NewRoomController(roomService, roomScheduleService, roomImageService), nil
 		},
 	)
 }
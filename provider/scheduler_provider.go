@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+func ProvideSchedulerDependencies(injector *do.Injector, db *gorm.DB, jwtService service.JWTService) {
+	// Repository
+	jobRunRepository := repository.NewJobRunRepository(db)
+
+	// Jobs
+	provideJob(injector, service.PurgeRefreshTokensJob(repository.NewRefreshTokenRepository(db)))
+	provideJob(injector, service.RejectStaleBookingsJob(repository.NewBookingRequestRepository(db)))
+	provideJob(injector, service.PruneJobRunsJob(jobRunRepository, jobRunRetention()))
+
+	// Service
+	do.ProvideNamed(injector, constants.SchedulerService, func(i *do.Injector) (service.SchedulerService, error) {
+		var jobs []service.Job
+		for _, name := range i.ListProvidedServices() {
+			if strings.HasPrefix(name, constants.JobPrefix) {
+				jobs = append(jobs, do.MustInvokeNamed[service.Job](i, name))
+			}
+		}
+		// a malformed schedule fails startup
+		return service.NewSchedulerService(jobs, jobRunRepository, db)
+	})
+
+	// Controller
+	do.Provide(
+		injector, func(i *do.Injector) (controller.JobController, error) {
+			schedulerService := do.MustInvokeNamed[service.SchedulerService](i, constants.SchedulerService)
+			return controller.NewJobController(schedulerService), nil
+		},
+	)
+}
+
+func provideJob(injector *do.Injector, job service.Job) {
+	do.ProvideNamedValue(injector, constants.JobPrefix+job.Name, job)
+}
+
+// jobRunRetention reads JOB_RUN_RETENTION_DAYS, 30 days by default
+func jobRunRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("JOB_RUN_RETENTION_DAYS"))
+	if err != nil || days < 1 {
+		days = 30
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
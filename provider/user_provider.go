@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"github.com/miraicantsleep/myits-event-be/constants"
 	"github.com/miraicantsleep/myits-event-be/controller"
 	"github.com/miraicantsleep/myits-event-be/repository"
 	"github.com/miraicantsleep/myits-event-be/service"
@@ -11,9 +12,17 @@ import (
 func ProvideUserDependencies(injector *do.Injector, db *gorm.DB, jwtService service.JWTService) {
 	// Repository
 	userRepository := repository.NewUserRepository(db)
+	departmentRepository := repository.NewDepartmentRepository(db)
+	userTokenRepository := repository.NewUserTokenRepository(db)
+	refreshTokenRepository := repository.NewRefreshTokenRepository(db)
+	roleChangeRepository := repository.NewRoleChangeRepository(db)
+	organizationRepository := repository.NewOrganizationRepository(db)
+	loginThrottleRepository := repository.NewLoginThrottleRepository(db)
+	oidcRepository := repository.NewOIDCRepository(db)
 
 	// Service
-	userService := service.NewUserService(userRepository, jwtService, db)
+	userService := service.NewUserService(userRepository, userTokenRepository, refreshTokenRepository, departmentRepository, roleChangeRepository, organizationRepository, loginThrottleRepository, jwtService, db)
+	sessionService := service.NewSessionService(refreshTokenRepository, userRepository, organizationRepository, jwtService, db)
 
 	// Controller
 	do.Provide(
@@ -21,4 +30,31 @@ func ProvideUserDependencies(injector *do.Injector, db *gorm.DB, jwtService serv
 			return controller.NewUserController(userService), nil
 		},
 	)
+	do.Provide(
+		injector, func(i *do.Injector) (controller.SessionController, error) {
+			return controller.NewSessionController(sessionService), nil
+		},
+	)
+	do.Provide(
+		injector, func(i *do.Injector) (controller.OIDCController, error) {
+			// a malformed OIDC_ROLE_MAPPING fails startup
+			config, err := service.OIDCConfigFromEnv()
+			if err != nil {
+				return nil, err
+			}
+			oidcService := service.NewOIDCService(config, oidcRepository, userRepository, organizationRepository, refreshTokenRepository, jwtService, db)
+			return controller.NewOIDCController(oidcService), nil
+		},
+	)
+	do.Provide(
+		injector, func(i *do.Injector) (controller.APIKeyController, error) {
+			apiKeyService := do.MustInvokeNamed[service.APIKeyService](i, constants.APIKeyService)
+			return controller.NewAPIKeyController(apiKeyService), nil
+		},
+	)
+	do.Provide(
+		injector, func(i *do.Injector) (controller.JWKSController, error) {
+			return controller.NewJWKSController(jwtService), nil
+		},
+	)
 }
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+func ProvideWebhookDependencies(injector *do.Injector, db *gorm.DB, jwtService service.JWTService) {
+	// Repository
+	webhookRepository := repository.NewWebhookRepository(db)
+	invitationRepository := repository.NewInvitationRepository(db)
+
+	// Service
+	webhookService := service.NewWebhookService(webhookRepository, nil, db)
+	do.ProvideNamedValue(injector, constants.WebhookService, webhookService)
+
+	// Jobs
+	provideJob(injector, service.PruneWebhookDeliveriesJob(webhookRepository, webhookDeliveryRetention()))
+
+	// Subscribers
+	bookingRequestService := do.MustInvokeNamed[service.BookingRequestService](injector, constants.BookingRequestService)
+	provideSubscribers(injector, service.WebhookSubscribers(webhookService, bookingRequestService, invitationRepository)...)
+
+	// Controller
+	do.Provide(injector, func(i *do.Injector) (controller.WebhookController, error) {
+		return controller.NewWebhookController(webhookService), nil
+	})
+}
+
+// webhookDeliveryRetention reads WEBHOOK_DELIVERY_RETENTION_DAYS, 30 days by
+// default
+func webhookDeliveryRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("WEBHOOK_DELIVERY_RETENTION_DAYS"))
+	if err != nil || days < 1 {
+		days = 30
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	APIKeyRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, key entity.APIKey) (entity.APIKey, error)
+		GetAll(ctx context.Context, tx *gorm.DB) ([]entity.APIKey, error)
+		GetByID(ctx context.Context, tx *gorm.DB, keyId string) (entity.APIKey, error)
+		// FindByHash preloads the owner, whose account state decides whether
+		// the key may be used
+		FindByHash(ctx context.Context, tx *gorm.DB, keyHash string) (entity.APIKey, error)
+		Revoke(ctx context.Context, tx *gorm.DB, keyId string, revokedAt time.Time) error
+		Touch(ctx context.Context, tx *gorm.DB, keyId string, usedAt time.Time, ip string) error
+	}
+
+	apiKeyRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, tx *gorm.DB, key entity.APIKey) (entity.APIKey, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Omit("User").Create(&key).Error; err != nil {
+		return entity.APIKey{}, err
+	}
+	return key, nil
+}
+
+func (r *apiKeyRepository) GetAll(ctx context.Context, tx *gorm.DB) ([]entity.APIKey, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var keys []entity.APIKey
+	if err := tx.WithContext(ctx).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, tx *gorm.DB, keyId string) (entity.APIKey, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var key entity.APIKey
+	if err := tx.WithContext(ctx).Where("id = ?", keyId).Take(&key).Error; err != nil {
+		return entity.APIKey{}, err
+	}
+	return key, nil
+}
+
+func (r *apiKeyRepository) FindByHash(ctx context.Context, tx *gorm.DB, keyHash string) (entity.APIKey, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var key entity.APIKey
+	if err := tx.WithContext(ctx).Preload("User").Where("key_hash = ?", keyHash).Take(&key).Error; err != nil {
+		return entity.APIKey{}, err
+	}
+	return key, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, tx *gorm.DB, keyId string, revokedAt time.Time) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", keyId).
+		Update("revoked_at", revokedAt).Error
+}
+
+func (r *apiKeyRepository) Touch(ctx context.Context, tx *gorm.DB, keyId string, usedAt time.Time, ip string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.APIKey{}).
+		Where("id = ?", keyId).
+		UpdateColumns(map[string]any{"last_used_at": usedAt, "last_used_ip": ip}).Error
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	AuditRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, logs []entity.AuditLog) error
+		GetAll(ctx context.Context, tx *gorm.DB, filter dto.AuditLogFilter, req dto.PaginationRequest) ([]entity.AuditLog, dto.PaginationResponse, error)
+		// Each calls fn for every matching log, oldest first, streaming the
+		// rows instead of loading them all
+		Each(ctx context.Context, tx *gorm.DB, filter dto.AuditLogFilter, fn func(entity.AuditLog) error) error
+	}
+
+	auditRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+func (r *auditRepository) Create(ctx context.Context, tx *gorm.DB, logs []entity.AuditLog) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).CreateInBatches(&logs, 500).Error
+}
+
+func (r *auditRepository) GetAll(ctx context.Context, tx *gorm.DB, filter dto.AuditLogFilter, req dto.PaginationRequest) ([]entity.AuditLog, dto.PaginationResponse, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	req.Default()
+
+	query, err := filterAuditLogs(tx.WithContext(ctx).Model(&entity.AuditLog{}), filter)
+	if err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+	if req.Search != "" {
+		search := "%" + req.Search + "%"
+		query = query.Where("entity_id ILIKE ? OR route ILIKE ? OR ip ILIKE ?", search, search, search)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	var logs []entity.AuditLog
+	if err := query.Order("created_at DESC, id").Scopes(Paginate(req)).Find(&logs).Error; err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	return logs, dto.PaginationResponse{
+		Page:    req.Page,
+		PerPage: req.PerPage,
+		Count:   count,
+		MaxPage: TotalPage(count, int64(req.PerPage)),
+	}, nil
+}
+
+func (r *auditRepository) Each(ctx context.Context, tx *gorm.DB, filter dto.AuditLogFilter, fn func(entity.AuditLog) error) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	query, err := filterAuditLogs(tx.WithContext(ctx).Model(&entity.AuditLog{}), filter)
+	if err != nil {
+		return err
+	}
+
+	rows, err := query.Order("created_at, id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var log entity.AuditLog
+		if err := tx.ScanRows(rows, &log); err != nil {
+			return err
+		}
+		if err := fn(log); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func filterAuditLogs(query *gorm.DB, filter dto.AuditLogFilter) (*gorm.DB, error) {
+	from, to, err := filter.TimeRange()
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.ActorRole != "" {
+		query = query.Where("actor_role = ?", filter.ActorRole)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if !from.IsZero() {
+		query = query.Where("created_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("created_at < ?", to)
+	}
+	return query, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/miraicantsleep/myits-event-be/dto"
@@ -18,6 +19,9 @@ type (
 		UpdateBookingRequestStatus(ctx context.Context, tx *gorm.DB, id uuid.UUID, status string) error
 		DeleteBookingRequest(ctx context.Context, tx *gorm.DB, id uuid.UUID) error
 		GetAllBookingRequestsWithCapacity(ctx context.Context, tx *gorm.DB) ([]dto.BookingRequestWithCapacityResponse, error)
+		ReplaceEquipments(ctx context.Context, tx *gorm.DB, id uuid.UUID, equipments []entity.BookingRequestEquipment) error
+		RejectPendingForPastEvents(ctx context.Context, tx *gorm.DB, now time.Time) (int64, error)
+		GetActiveBookingRequestsByEvent(ctx context.Context, tx *gorm.DB, eventID uuid.UUID) ([]entity.BookingRequest, error)
 	}
 
 	bookingRequestRepository struct {
@@ -49,6 +53,8 @@ func (r *bookingRequestRepository) GetBookingRequestByID(ctx context.Context, tx
 	}
 	err := db.WithContext(ctx).
 		Joins("Event").
+		Preload("Rooms").
+		Preload("Equipments.Equipment").
 		Joins("left join booking_request_room on booking_request_room.booking_request_id = booking_requests.id").
 		Joins("left join rooms on rooms.id = booking_request_room.room_id").
 		Where("booking_requests.id = ?", id).
@@ -82,12 +88,25 @@ func (r *bookingRequestRepository) UpdateBookingRequest(ctx context.Context, tx
 	return db.WithContext(ctx).Session(&gorm.Session{FullSaveAssociations: true}).Save(br).Error
 }
 
+// UpdateBookingRequestStatus decides a pending request. It fails with
+// ErrBookingNotPending when the request was approved or rejected already,
+// also by a concurrent call.
 func (r *bookingRequestRepository) UpdateBookingRequestStatus(ctx context.Context, tx *gorm.DB, id uuid.UUID, status string) error {
 	db := r.db
 	if tx != nil {
 		db = tx
 	}
-	return db.WithContext(ctx).Model(&entity.BookingRequest{}).Where("id = ?", id).Update("status", status).Error
+	result := db.WithContext(ctx).
+		Model(&entity.BookingRequest{}).
+		Where("id = ? AND status = ?", id, "pending").
+		Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return dto.ErrBookingNotPending
+	}
+	return nil
 }
 
 func (r *bookingRequestRepository) DeleteBookingRequest(ctx context.Context, tx *gorm.DB, id uuid.UUID) error {
@@ -137,3 +156,54 @@ func (r *bookingRequestRepository) GetAllBookingRequestsWithCapacity(ctx context
 	}
 	return bookings, nil
 }
+
+func (r *bookingRequestRepository) ReplaceEquipments(ctx context.Context, tx *gorm.DB, id uuid.UUID, equipments []entity.BookingRequestEquipment) error {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+
+	if err := db.WithContext(ctx).Where("booking_request_id = ?", id).Delete(&entity.BookingRequestEquipment{}).Error; err != nil {
+		return err
+	}
+
+	if len(equipments) == 0 {
+		return nil
+	}
+
+	for i := range equipments {
+		equipments[i].BookingRequestID = id
+		equipments[i].Equipment = entity.Equipment{}
+	}
+	return db.WithContext(ctx).Omit("Equipment").Create(&equipments).Error
+}
+
+// RejectPendingForPastEvents rejects pending requests whose event has already
+// started, since a room can no longer be booked for them
+func (r *bookingRequestRepository) RejectPendingForPastEvents(ctx context.Context, tx *gorm.DB, now time.Time) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).
+		Model(&entity.BookingRequest{}).
+		Where("status = ?", "pending").
+		Where("event_id IN (SELECT id FROM events WHERE start_time < ?)", now).
+		Update("status", "rejected")
+	return result.RowsAffected, result.Error
+}
+
+// GetActiveBookingRequestsByEvent returns the pending and approved booking
+// requests of an event with their rooms
+func (r *bookingRequestRepository) GetActiveBookingRequestsByEvent(ctx context.Context, tx *gorm.DB, eventID uuid.UUID) ([]entity.BookingRequest, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var bookingRequests []entity.BookingRequest
+	err := tx.WithContext(ctx).
+		Preload("Rooms").
+		Where("event_id = ? AND status IN ?", eventID, []string{"pending", "approved"}).
+		Find(&bookingRequests).Error
+	return bookingRequests, err
+}
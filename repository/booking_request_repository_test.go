@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BookingRequestRepository_UpdateBookingRequestStatus(t *testing.T) {
+	var sql string
+	var vars []any
+	db := dryRunDB(t, func(s string, v []any) { sql, vars = s, v })
+	id := uuid.New()
+
+	// a dry run matches no rows, like a request decided in the meantime
+	err := NewBookingRequestRepository(db).UpdateBookingRequestStatus(context.Background(), nil, id, "approved")
+	assert.ErrorIs(t, err, dto.ErrBookingNotPending)
+	assert.Contains(t, sql, `SET "status"=$1`)
+	assert.Contains(t, sql, `WHERE (id = $3 AND status = $4)`)
+	if assert.Len(t, vars, 4) {
+		assert.Equal(t, "approved", vars[0])
+		assert.Equal(t, []any{id, "pending"}, vars[2:])
+	}
+}
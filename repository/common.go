@@ -16,6 +16,6 @@ func Paginate(req dto.PaginationRequest) func(db *gorm.DB) *gorm.DB {
 
 func TotalPage(count, perPage int64) int64 {
 	totalPage := int64(math.Ceil(float64(count) / float64(perPage)))
-	
+
 	return totalPage
 }
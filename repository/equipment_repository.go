@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	EquipmentRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, equipment entity.Equipment) (entity.Equipment, error)
+		GetEquipmentByID(ctx context.Context, tx *gorm.DB, id string) (entity.Equipment, error)
+		// LockEquipment loads an equipment with a row lock held for the rest
+		// of tx, used to serialise stock checks.
+		LockEquipment(ctx context.Context, tx *gorm.DB, id uuid.UUID) (entity.Equipment, error)
+		GetAllEquipment(ctx context.Context, tx *gorm.DB, filter dto.EquipmentFilterRequest) ([]entity.Equipment, error)
+		Update(ctx context.Context, tx *gorm.DB, equipment entity.Equipment) (entity.Equipment, error)
+		Delete(ctx context.Context, tx *gorm.DB, id string) error
+		// GetBookedQuantity sums the quantity of an equipment held by approved
+		// booking requests whose event overlaps [start, end). excludeBookingID is
+		// ignored when it is uuid.Nil.
+		GetBookedQuantity(ctx context.Context, tx *gorm.DB, equipmentID uuid.UUID, start time.Time, end time.Time, excludeBookingID uuid.UUID) (int, error)
+	}
+
+	equipmentRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewEquipmentRepository(db *gorm.DB) EquipmentRepository {
+	return &equipmentRepository{
+		db: db,
+	}
+}
+
+func (r *equipmentRepository) Create(ctx context.Context, tx *gorm.DB, equipment entity.Equipment) (entity.Equipment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var existing entity.Equipment
+	if err := tx.WithContext(ctx).Where("name = ? AND department_id = ?", equipment.Name, equipment.DepartmentID).First(&existing).Error; err == nil {
+		return entity.Equipment{}, dto.ErrEquipmentAlreadyExists
+	}
+
+	if err := tx.WithContext(ctx).Create(&equipment).Error; err != nil {
+		return entity.Equipment{}, err
+	}
+
+	return r.GetEquipmentByID(ctx, tx, equipment.ID.String())
+}
+
+func (r *equipmentRepository) GetEquipmentByID(ctx context.Context, tx *gorm.DB, id string) (entity.Equipment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var equipment entity.Equipment
+	if err := tx.WithContext(ctx).Preload("Department").First(&equipment, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Equipment{}, dto.ErrEquipmentNotFound
+		}
+		return entity.Equipment{}, err
+	}
+
+	return equipment, nil
+}
+
+func (r *equipmentRepository) LockEquipment(ctx context.Context, tx *gorm.DB, id uuid.UUID) (entity.Equipment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var equipment entity.Equipment
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&equipment, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Equipment{}, dto.ErrEquipmentNotFound
+		}
+		return entity.Equipment{}, err
+	}
+
+	return equipment, nil
+}
+
+func (r *equipmentRepository) GetAllEquipment(ctx context.Context, tx *gorm.DB, filter dto.EquipmentFilterRequest) ([]entity.Equipment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	query := tx.WithContext(ctx).Preload("Department")
+	if filter.DepartmentID != "" {
+		query = query.Where("department_id = ?", filter.DepartmentID)
+	}
+	if filter.Search != "" {
+		query = query.Where("name ILIKE ?", "%"+filter.Search+"%")
+	}
+
+	var equipments []entity.Equipment
+	if err := query.Order("name ASC").Find(&equipments).Error; err != nil {
+		return nil, err
+	}
+
+	return equipments, nil
+}
+
+func (r *equipmentRepository) Update(ctx context.Context, tx *gorm.DB, equipment entity.Equipment) (entity.Equipment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Model(&entity.Equipment{ID: equipment.ID}).
+		Select("name", "description", "quantity").
+		Updates(equipment).Error; err != nil {
+		return entity.Equipment{}, err
+	}
+
+	return r.GetEquipmentByID(ctx, tx, equipment.ID.String())
+}
+
+func (r *equipmentRepository) Delete(ctx context.Context, tx *gorm.DB, id string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Delete(&entity.Equipment{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return dto.ErrEquipmentNotFound
+	}
+
+	return nil
+}
+
+func (r *equipmentRepository) GetBookedQuantity(
+	ctx context.Context,
+	tx *gorm.DB,
+	equipmentID uuid.UUID,
+	start time.Time,
+	end time.Time,
+	excludeBookingID uuid.UUID,
+) (int, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var booked int
+	query := tx.WithContext(ctx).
+		Table("booking_request_equipment bre").
+		Select("COALESCE(SUM(bre.quantity), 0)").
+		Joins("JOIN booking_requests br ON br.id = bre.booking_request_id").
+		Joins("JOIN events e ON e.id = br.event_id").
+		Where("bre.equipment_id = ?", equipmentID).
+		Where("br.status = ? AND br.deleted_at IS NULL AND e.deleted_at IS NULL", "approved").
+		Where("? < e.end_time AND ? > e.start_time", start, end)
+	if excludeBookingID != uuid.Nil {
+		query = query.Where("br.id <> ?", excludeBookingID)
+	}
+
+	if err := query.Scan(&booked).Error; err != nil {
+		return 0, err
+	}
+
+	return booked, nil
+}
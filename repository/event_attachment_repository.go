@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	EventAttachmentRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, attachment entity.EventAttachment) (entity.EventAttachment, error)
+		GetAttachmentByID(ctx context.Context, tx *gorm.DB, id string) (entity.EventAttachment, error)
+		GetEventAttachments(ctx context.Context, tx *gorm.DB, eventID uuid.UUID) ([]entity.EventAttachment, error)
+		Delete(ctx context.Context, tx *gorm.DB, id uuid.UUID) error
+	}
+
+	eventAttachmentRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewEventAttachmentRepository(db *gorm.DB) EventAttachmentRepository {
+	return &eventAttachmentRepository{
+		db: db,
+	}
+}
+
+func (r *eventAttachmentRepository) Create(ctx context.Context, tx *gorm.DB, attachment entity.EventAttachment) (entity.EventAttachment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&attachment).Error; err != nil {
+		return entity.EventAttachment{}, err
+	}
+	return attachment, nil
+}
+
+func (r *eventAttachmentRepository) GetAttachmentByID(ctx context.Context, tx *gorm.DB, id string) (entity.EventAttachment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var attachment entity.EventAttachment
+	if err := tx.WithContext(ctx).First(&attachment, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.EventAttachment{}, dto.ErrAttachmentNotFound
+		}
+		return entity.EventAttachment{}, err
+	}
+	return attachment, nil
+}
+
+func (r *eventAttachmentRepository) GetEventAttachments(ctx context.Context, tx *gorm.DB, eventID uuid.UUID) ([]entity.EventAttachment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var attachments []entity.EventAttachment
+	if err := tx.WithContext(ctx).Where("event_id = ?", eventID).Order("created_at ASC").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *eventAttachmentRepository) Delete(ctx context.Context, tx *gorm.DB, id uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Unscoped().Delete(&entity.EventAttachment{}, "id = ?", id).Error
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	EventReminderRepository interface {
+		// GetSettings reads the reminder columns from events, which the
+		// event_details view used by GetEventById does not expose
+		GetSettings(ctx context.Context, tx *gorm.DB, eventId string) (entity.Event, error)
+		// GetUpcomingEvents returns events with reminders that start between
+		// now and until
+		GetUpcomingEvents(ctx context.Context, tx *gorm.DB, now time.Time, until time.Time) ([]entity.Event, error)
+		// GetRecipients returns the invitees with one of rsvpStatuses that
+		// have not received the reminder at offsetMinutes yet
+		GetRecipients(ctx context.Context, tx *gorm.DB, eventId string, offsetMinutes int, rsvpStatuses []string) ([]dto.EventReminderRecipient, error)
+		GetRooms(ctx context.Context, tx *gorm.DB, eventId string) ([]string, error)
+		GetDeliveryCounts(ctx context.Context, tx *gorm.DB, eventId string) ([]dto.EventReminderDeliveryCount, error)
+		// ClaimDelivery records a delivery before it is sent and returns false
+		// when it was already recorded
+		ClaimDelivery(ctx context.Context, tx *gorm.DB, delivery entity.EventReminderDelivery) (bool, error)
+		ReleaseDelivery(ctx context.Context, tx *gorm.DB, delivery entity.EventReminderDelivery) error
+	}
+
+	eventReminderRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewEventReminderRepository(db *gorm.DB) EventReminderRepository {
+	return &eventReminderRepository{
+		db: db,
+	}
+}
+
+func (r *eventReminderRepository) GetSettings(ctx context.Context, tx *gorm.DB, eventId string) (entity.Event, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var event entity.Event
+	err := tx.WithContext(ctx).
+		Select("id", "name", "start_time", "organization_id", "reminder_offsets", "remind_pending").
+		Where("id = ?", eventId).
+		Take(&event).Error
+	return event, err
+}
+
+func (r *eventReminderRepository) GetUpcomingEvents(ctx context.Context, tx *gorm.DB, now time.Time, until time.Time) ([]entity.Event, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var events []entity.Event
+	err := tx.WithContext(ctx).
+		Where("start_time > ? AND start_time <= ? AND reminder_offsets <> ''", now, until).
+		Order("start_time").
+		Find(&events).Error
+	return events, err
+}
+
+func (r *eventReminderRepository) GetRecipients(ctx context.Context, tx *gorm.DB, eventId string, offsetMinutes int, rsvpStatuses []string) ([]dto.EventReminderRecipient, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var recipients []dto.EventReminderRecipient
+	err := tx.WithContext(ctx).Raw(`
+		SELECT DISTINCT ON (u.id)
+			u.id AS user_id,
+			u.name AS user_name,
+			u.email AS user_email,
+			ui.qr_code,
+			ui.rsvp_status,
+			COALESCE(ui.attendance_mode, '') AS attendance_mode
+		FROM user_invitation ui
+		JOIN invitations i ON i.id = ui.invitation_id
+		JOIN users u ON u.id = ui.user_id AND u.deleted_at IS NULL AND u.disabled_at IS NULL
+		WHERE i.event_id = ? AND ui.rsvp_status IN ?
+			AND NOT EXISTS (
+				SELECT 1 FROM event_reminder_deliveries d
+				WHERE d.event_id = i.event_id AND d.user_id = u.id AND d.offset_minutes = ?)
+		ORDER BY u.id, ui.invited_at
+	`, eventId, rsvpStatuses, offsetMinutes).Scan(&recipients).Error
+	return recipients, err
+}
+
+func (r *eventReminderRepository) GetRooms(ctx context.Context, tx *gorm.DB, eventId string) ([]string, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var rooms []string
+	err := tx.WithContext(ctx).Raw(`
+		SELECT DISTINCT rooms.name || ' (' || departments.name || ')'
+		FROM booking_requests br
+		JOIN booking_request_room brr ON brr.booking_request_id = br.id
+		JOIN rooms ON rooms.id = brr.room_id
+		JOIN departments ON departments.id = rooms.department_id
+		WHERE br.event_id = ? AND br.status = 'approved' AND br.deleted_at IS NULL
+	`, eventId).Scan(&rooms).Error
+	return rooms, err
+}
+
+func (r *eventReminderRepository) GetDeliveryCounts(ctx context.Context, tx *gorm.DB, eventId string) ([]dto.EventReminderDeliveryCount, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var counts []dto.EventReminderDeliveryCount
+	err := tx.WithContext(ctx).
+		Model(&entity.EventReminderDelivery{}).
+		Select("offset_minutes, COUNT(*) AS sent").
+		Where("event_id = ?", eventId).
+		Group("offset_minutes").
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *eventReminderRepository) ClaimDelivery(ctx context.Context, tx *gorm.DB, delivery entity.EventReminderDelivery) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&delivery)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *eventReminderRepository) ReleaseDelivery(ctx context.Context, tx *gorm.DB, delivery entity.EventReminderDelivery) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).
+		Where("event_id = ? AND user_id = ? AND offset_minutes = ?", delivery.EventID, delivery.UserID, delivery.OffsetMinutes).
+		Delete(&entity.EventReminderDelivery{}).Error
+}
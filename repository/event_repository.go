@@ -14,9 +14,20 @@ type (
 		GetAllEventWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.GetAllEventRepositoryResponse, error)
 		GetEventById(ctx context.Context, tx *gorm.DB, eventId string) (entity.Event, error)
 		Update(ctx context.Context, tx *gorm.DB, event entity.Event) (entity.Event, error)
+		UpdatePoster(ctx context.Context, tx *gorm.DB, eventId string, posterPath string) error
+		UpdateOnlineQuota(ctx context.Context, tx *gorm.DB, eventId string, quota int) error
+		UpdateReminders(ctx context.Context, tx *gorm.DB, eventId string, offsets string, remindPending bool) error
+		// LockEvent takes a row lock on the event for the rest of tx, used to
+		// serialise quota checks.
+		LockEvent(ctx context.Context, tx *gorm.DB, eventId string) error
+		// GetApprovedRoomCapacity sums the capacity of the rooms held by the
+		// event's approved booking requests.
+		GetApprovedRoomCapacity(ctx context.Context, tx *gorm.DB, eventId string) (int, error)
+		GetAttendanceModeCounts(ctx context.Context, tx *gorm.DB, eventId string) ([]dto.AttendanceModeCount, error)
 		Delete(ctx context.Context, tx *gorm.DB, eventId string) error
 		CheckEventExist(ctx context.Context, tx *gorm.DB, name string) (bool, error)
 		GetEventByUserId(ctx context.Context, tx *gorm.DB, userId string) ([]entity.Event, error)
+		GetEventByOrganizationId(ctx context.Context, tx *gorm.DB, organizationId string) ([]entity.Event, error)
 		GetEventAttendees(ctx context.Context, tx *gorm.DB, eventId string) ([]dto.UserAttendanceResponse, error)
 		GetAllUserAttendances(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.GetAllUserAttendanceRepositoryResponse, error)
 	}
@@ -84,6 +95,15 @@ func (r *eventRepository) GetAllEventWithPagination(ctx context.Context, tx *gor
 			Created_By:  event.Creator_Name,
 			Event_Type:  event.Event_Type,
 			Duration:    event.DurationInMinutes,
+			PosterPath:  event.PosterPath,
+
+			OnlineQuota:     event.OnlineQuota,
+			MeetingURL:      event.MeetingURL,
+			MeetingPasscode: event.MeetingPasscode,
+			MeetingPlatform: event.MeetingPlatform,
+		}
+		if event.OrganizationID != nil {
+			eventResponses[i].OrganizationID = event.OrganizationID.String()
 		}
 	}
 
@@ -116,6 +136,24 @@ func (r *eventRepository) GetEventByUserId(ctx context.Context, tx *gorm.DB, use
 	return events, nil
 }
 
+// get event by organization id
+
+func (r *eventRepository) GetEventByOrganizationId(ctx context.Context, tx *gorm.DB, organizationId string) ([]entity.Event, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var events []entity.Event
+
+	if err := tx.WithContext(ctx).
+		Table("event_details").
+		Where("organization_id = ?", organizationId).
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
 func (r *eventRepository) GetEventById(ctx context.Context, tx *gorm.DB, eventId string) (entity.Event, error) {
 	if tx == nil {
 		tx = r.db
@@ -138,6 +176,86 @@ func (r *eventRepository) Update(ctx context.Context, tx *gorm.DB, event entity.
 	return r.GetEventById(ctx, tx, event.ID.String())
 }
 
+// UpdatePoster sets poster_path explicitly, since Updates skips empty strings
+// and removing a poster has to clear the column.
+func (r *eventRepository) UpdatePoster(ctx context.Context, tx *gorm.DB, eventId string, posterPath string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Event{}).Where("id = ?", eventId).Update("poster_path", posterPath).Error
+}
+
+// UpdateOnlineQuota is separate from Update because a quota of 0 (unlimited)
+// would be skipped by Updates.
+func (r *eventRepository) UpdateOnlineQuota(ctx context.Context, tx *gorm.DB, eventId string, quota int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Event{}).Where("id = ?", eventId).Update("online_quota", quota).Error
+}
+
+// UpdateReminders is separate from Update because an empty offset list
+// (reminders off) and remindPending false would be skipped by Updates.
+func (r *eventRepository) UpdateReminders(ctx context.Context, tx *gorm.DB, eventId string, offsets string, remindPending bool) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Event{}).Where("id = ?", eventId).Updates(map[string]any{
+		"reminder_offsets": offsets,
+		"remind_pending":   remindPending,
+	}).Error
+}
+
+func (r *eventRepository) LockEvent(ctx context.Context, tx *gorm.DB, eventId string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Exec("SELECT id FROM events WHERE id = ? FOR UPDATE", eventId).Error
+}
+
+func (r *eventRepository) GetApprovedRoomCapacity(ctx context.Context, tx *gorm.DB, eventId string) (int, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var capacity int
+	err := tx.WithContext(ctx).Raw(`
+		SELECT COALESCE(SUM(rooms.capacity), 0)
+		FROM booking_requests br
+		JOIN booking_request_room brr ON brr.booking_request_id = br.id
+		JOIN rooms ON rooms.id = brr.room_id
+		WHERE br.event_id = ? AND br.status = 'approved' AND br.deleted_at IS NULL
+	`, eventId).Scan(&capacity).Error
+	return capacity, err
+}
+
+// GetAttendanceModeCounts groups the event's invitees by attendance mode.
+// Invitees of non-hybrid events have no mode of their own and are counted
+// under the event type.
+func (r *eventRepository) GetAttendanceModeCounts(ctx context.Context, tx *gorm.DB, eventId string) ([]dto.AttendanceModeCount, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var counts []dto.AttendanceModeCount
+	err := tx.WithContext(ctx).Raw(`
+		SELECT
+			COALESCE(ui.attendance_mode, CASE WHEN e.event_type = 'online' THEN 'online' ELSE 'in_person' END) AS mode,
+			COUNT(*) FILTER (WHERE ui.rsvp_status = 'accepted') AS accepted,
+			COUNT(*) FILTER (WHERE ui.attended_at IS NOT NULL) AS attended
+		FROM user_invitation ui
+		JOIN invitations i ON i.id = ui.invitation_id
+		JOIN events e ON e.id = i.event_id
+		WHERE i.event_id = ?
+		GROUP BY 1
+	`, eventId).Scan(&counts).Error
+	return counts, err
+}
+
 func (r *eventRepository) Delete(ctx context.Context, tx *gorm.DB, eventId string) error {
 	if tx == nil {
 		tx = r.db
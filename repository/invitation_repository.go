@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/miraicantsleep/myits-event-be/dto"
@@ -12,6 +13,7 @@ import (
 type (
 	InvitationRepository interface {
 		Create(ctx context.Context, tx *gorm.DB, invitation entity.Invitation) (entity.Invitation, error)
+		GetInvitation(ctx context.Context, tx *gorm.DB, invitationID uuid.UUID) (entity.Invitation, error)
 		GetInvitationByID(ctx context.Context, tx *gorm.DB, invitationID uuid.UUID) ([]dto.InvitationDetailResponse, error)
 		GetInvitationByEvent(ctx context.Context, tx *gorm.DB, eventID uuid.UUID) ([]dto.InvitationResponse, error)
 		GetInvitationByUserId(ctx context.Context, tx *gorm.DB, userID uuid.UUID) ([]dto.InvitationResponse, error)
@@ -23,6 +25,10 @@ type (
 		GetUserInvitationByQRCode(ctx context.Context, tx *gorm.DB, qrCode string) (entity.UserInvitation, error)
 		UpdateUserInvitation(ctx context.Context, tx *gorm.DB, userInvitation entity.UserInvitation) (entity.UserInvitation, error)
 		GetUserInvitation(ctx context.Context, tx *gorm.DB, invitationID uuid.UUID, userID uuid.UUID) (entity.UserInvitation, error)
+		GetInvitationDetailByQRCode(ctx context.Context, tx *gorm.DB, qrCode string) (dto.InvitationDetailResponse, error)
+		CountAcceptedByMode(ctx context.Context, tx *gorm.DB, eventID uuid.UUID, mode string) (int64, error)
+		SetEmailStatus(ctx context.Context, tx *gorm.DB, invitationID uuid.UUID, userID uuid.UUID, sendErr error) error
+		GetFailedEmails(ctx context.Context, tx *gorm.DB, limit int) ([]entity.UserInvitation, error)
 	}
 
 	invitationRepository struct {
@@ -55,7 +61,14 @@ func (r *invitationRepository) GetInvitationByID(ctx context.Context, tx *gorm.D
 			ui.rsvp_at,
 			ui.attended_at,
 			ui.qr_code,
-			creator.name AS creator_name
+			ui.attendance_mode,
+			creator.name AS creator_name,
+			e.event_type,
+			e.start_time,
+			e.end_time,
+			e.meeting_url,
+			e.meeting_passcode,
+			e.meeting_platform
 		FROM
 			invitations i
 		JOIN
@@ -161,6 +174,18 @@ func (r *invitationRepository) Update(ctx context.Context, tx *gorm.DB, invitati
 	return invitation, nil
 }
 
+func (r *invitationRepository) GetInvitation(ctx context.Context, tx *gorm.DB, invitationID uuid.UUID) (entity.Invitation, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var invitation entity.Invitation
+	if err := tx.WithContext(ctx).First(&invitation, "id = ?", invitationID).Error; err != nil {
+		return entity.Invitation{}, err
+	}
+	return invitation, nil
+}
+
 func (r *invitationRepository) Delete(ctx context.Context, tx *gorm.DB, invitationID uuid.UUID) error {
 	if tx == nil {
 		tx = r.db
@@ -252,3 +277,72 @@ func (r *invitationRepository) GetInvitationByUserId(ctx context.Context, tx *go
 
 	return resp, err
 }
+
+// GetInvitationDetailByQRCode returns the invitation of a single user together
+// with the event data needed to let them join an online event.
+func (r *invitationRepository) GetInvitationDetailByQRCode(ctx context.Context, tx *gorm.DB, qrCode string) (dto.InvitationDetailResponse, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var detail dto.InvitationDetailResponse
+	if err := tx.WithContext(ctx).
+		Table("full_invitation_details").
+		Select("full_invitation_details.*, id AS invitation_id").
+		Where("qr_code = ? AND deleted_at IS NULL", qrCode).
+		First(&detail).Error; err != nil {
+		return dto.InvitationDetailResponse{}, err
+	}
+	return detail, nil
+}
+
+// CountAcceptedByMode counts the accepted invitees of an event who chose the
+// given attendance mode.
+func (r *invitationRepository) CountAcceptedByMode(ctx context.Context, tx *gorm.DB, eventID uuid.UUID, mode string) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var count int64
+	err := tx.WithContext(ctx).
+		Table("user_invitation").
+		Joins("JOIN invitations ON invitations.id = user_invitation.invitation_id").
+		Where("invitations.event_id = ? AND user_invitation.rsvp_status = ? AND user_invitation.attendance_mode = ?", eventID, entity.RSVPStatusAccepted, mode).
+		Count(&count).Error
+	return count, err
+}
+
+// SetEmailStatus records the outcome of sending the invitation email. A nil
+// sendErr marks it sent and clears any earlier failure.
+func (r *invitationRepository) SetEmailStatus(ctx context.Context, tx *gorm.DB, invitationID uuid.UUID, userID uuid.UUID, sendErr error) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	updates := map[string]any{"email_sent_at": time.Now(), "email_error": nil}
+	if sendErr != nil {
+		updates = map[string]any{"email_error": sendErr.Error()}
+	}
+	return tx.WithContext(ctx).
+		Model(&entity.UserInvitation{}).
+		Where("invitation_id = ? AND user_id = ?", invitationID, userID).
+		UpdateColumns(updates).Error
+}
+
+// GetFailedEmails returns invitations whose email failed to send, limited to
+// events that have not ended yet
+func (r *invitationRepository) GetFailedEmails(ctx context.Context, tx *gorm.DB, limit int) ([]entity.UserInvitation, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var userInvitations []entity.UserInvitation
+	err := tx.WithContext(ctx).
+		Joins("JOIN invitations ON invitations.id = user_invitation.invitation_id").
+		Joins("JOIN events ON events.id = invitations.event_id AND events.deleted_at IS NULL").
+		Where("user_invitation.email_error IS NOT NULL AND events.end_time > ?", time.Now()).
+		Order("user_invitation.invited_at").
+		Limit(limit).
+		Find(&userInvitations).Error
+	return userInvitations, err
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	JobRunRepository interface {
+		// Create returns false without inserting when the scheduled tick of
+		// the run was already recorded by another instance
+		Create(ctx context.Context, tx *gorm.DB, run entity.JobRun) (entity.JobRun, bool, error)
+		Finish(ctx context.Context, tx *gorm.DB, run entity.JobRun) error
+		// FailRunning closes runs of a job left running by an instance that
+		// stopped mid-run
+		FailRunning(ctx context.Context, tx *gorm.DB, job string, reason string) error
+		GetLastRuns(ctx context.Context, tx *gorm.DB) ([]entity.JobRun, error)
+		GetByJob(ctx context.Context, tx *gorm.DB, job string, limit int) ([]entity.JobRun, error)
+		DeleteBefore(ctx context.Context, tx *gorm.DB, before time.Time) (int64, error)
+	}
+
+	jobRunRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewJobRunRepository(db *gorm.DB) JobRunRepository {
+	return &jobRunRepository{
+		db: db,
+	}
+}
+
+func (r *jobRunRepository) Create(ctx context.Context, tx *gorm.DB, run entity.JobRun) (entity.JobRun, bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&run)
+	if result.Error != nil {
+		return entity.JobRun{}, false, result.Error
+	}
+	return run, result.RowsAffected == 1, nil
+}
+
+func (r *jobRunRepository) Finish(ctx context.Context, tx *gorm.DB, run entity.JobRun) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.JobRun{}).Where("id = ?", run.ID).Updates(map[string]any{
+		"status":      run.Status,
+		"result":      run.Result,
+		"error":       run.Error,
+		"finished_at": run.FinishedAt,
+	}).Error
+}
+
+func (r *jobRunRepository) FailRunning(ctx context.Context, tx *gorm.DB, job string, reason string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.JobRun{}).
+		Where("job = ? AND status = ?", job, entity.JobRunRunning).
+		Updates(map[string]any{"status": entity.JobRunFailed, "error": reason, "finished_at": time.Now()}).Error
+}
+
+func (r *jobRunRepository) GetLastRuns(ctx context.Context, tx *gorm.DB) ([]entity.JobRun, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var runs []entity.JobRun
+	err := tx.WithContext(ctx).
+		Raw("SELECT DISTINCT ON (job) * FROM job_runs ORDER BY job, started_at DESC").
+		Scan(&runs).Error
+	return runs, err
+}
+
+func (r *jobRunRepository) GetByJob(ctx context.Context, tx *gorm.DB, job string, limit int) ([]entity.JobRun, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var runs []entity.JobRun
+	err := tx.WithContext(ctx).Where("job = ?", job).Order("started_at DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
+
+func (r *jobRunRepository) DeleteBefore(ctx context.Context, tx *gorm.DB, before time.Time) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Where("started_at < ? AND status <> ?", before, entity.JobRunRunning).Delete(&entity.JobRun{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	LoginThrottleRepository interface {
+		GetByKeys(ctx context.Context, tx *gorm.DB, keys ...string) ([]entity.LoginThrottle, error)
+		// RecordFailure bumps the failure count of key in a single statement,
+		// so concurrent attempts from several instances are all counted.
+		// Failures older than window start a fresh count.
+		RecordFailure(ctx context.Context, tx *gorm.DB, key string, now time.Time, window time.Duration) (entity.LoginThrottle, error)
+		Lock(ctx context.Context, tx *gorm.DB, key string, until time.Time) error
+		Reset(ctx context.Context, tx *gorm.DB, key string) error
+	}
+
+	loginThrottleRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewLoginThrottleRepository(db *gorm.DB) LoginThrottleRepository {
+	return &loginThrottleRepository{
+		db: db,
+	}
+}
+
+func (r *loginThrottleRepository) GetByKeys(ctx context.Context, tx *gorm.DB, keys ...string) ([]entity.LoginThrottle, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var throttles []entity.LoginThrottle
+	if err := tx.WithContext(ctx).Where("key IN ?", keys).Find(&throttles).Error; err != nil {
+		return nil, err
+	}
+	return throttles, nil
+}
+
+func (r *loginThrottleRepository) RecordFailure(ctx context.Context, tx *gorm.DB, key string, now time.Time, window time.Duration) (entity.LoginThrottle, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var throttle entity.LoginThrottle
+	err := tx.WithContext(ctx).Raw(`
+		INSERT INTO login_throttles (id, key, failures, last_failure_at, created_at, updated_at)
+		VALUES (uuid_generate_v4(), ?, 1, ?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END,
+			last_failure_at = EXCLUDED.last_failure_at,
+			updated_at = EXCLUDED.updated_at
+		RETURNING *`,
+		key, now, now, now, now.Add(-window),
+	).Scan(&throttle).Error
+	return throttle, err
+}
+
+func (r *loginThrottleRepository) Lock(ctx context.Context, tx *gorm.DB, key string, until time.Time) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.LoginThrottle{}).
+		Where("key = ?", key).
+		Update("locked_until", until).Error
+}
+
+func (r *loginThrottleRepository) Reset(ctx context.Context, tx *gorm.DB, key string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Unscoped().Where("key = ?", key).Delete(&entity.LoginThrottle{}).Error
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	NotificationRepository interface {
+		CreateMany(ctx context.Context, tx *gorm.DB, notifications []entity.Notification) error
+		GetAllWithPagination(ctx context.Context, tx *gorm.DB, userId string, req dto.PaginationRequest, unreadOnly bool) ([]entity.Notification, dto.PaginationResponse, error)
+		// GetSince returns notifications created after since, oldest first
+		GetSince(ctx context.Context, tx *gorm.DB, userId string, since time.Time) ([]entity.Notification, error)
+		CountUnread(ctx context.Context, tx *gorm.DB, userId string) (int64, error)
+		MarkRead(ctx context.Context, tx *gorm.DB, userId string, notificationId string, readAt time.Time) (int64, error)
+		MarkAllRead(ctx context.Context, tx *gorm.DB, userId string, readAt time.Time) (int64, error)
+		// GetEventInviteeIDs returns the users invited to an event who have
+		// not declined
+		GetEventInviteeIDs(ctx context.Context, tx *gorm.DB, eventId string) ([]string, error)
+	}
+
+	notificationRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+func (r *notificationRepository) CreateMany(ctx context.Context, tx *gorm.DB, notifications []entity.Notification) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).CreateInBatches(&notifications, 500).Error
+}
+
+func (r *notificationRepository) GetAllWithPagination(ctx context.Context, tx *gorm.DB, userId string, req dto.PaginationRequest, unreadOnly bool) ([]entity.Notification, dto.PaginationResponse, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	req.Default()
+
+	query := tx.WithContext(ctx).Model(&entity.Notification{}).Where("user_id = ?", userId)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	var notifications []entity.Notification
+	if err := query.Order("created_at DESC").Scopes(Paginate(req)).Find(&notifications).Error; err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	return notifications, dto.PaginationResponse{
+		Page:    req.Page,
+		PerPage: req.PerPage,
+		Count:   count,
+		MaxPage: TotalPage(count, int64(req.PerPage)),
+	}, nil
+}
+
+func (r *notificationRepository) GetSince(ctx context.Context, tx *gorm.DB, userId string, since time.Time) ([]entity.Notification, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var notifications []entity.Notification
+	err := tx.WithContext(ctx).
+		Where("user_id = ? AND created_at > ?", userId, since).
+		Order("created_at").
+		Limit(100).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, tx *gorm.DB, userId string) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var count int64
+	err := tx.WithContext(ctx).Model(&entity.Notification{}).Where("user_id = ? AND read_at IS NULL", userId).Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, tx *gorm.DB, userId string, notificationId string, readAt time.Time) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Model(&entity.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", notificationId, userId).
+		Update("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, tx *gorm.DB, userId string, readAt time.Time) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Model(&entity.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId).
+		Update("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
+
+func (r *notificationRepository) GetEventInviteeIDs(ctx context.Context, tx *gorm.DB, eventId string) ([]string, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var userIDs []string
+	err := tx.WithContext(ctx).
+		Table("user_invitation").
+		Joins("JOIN invitations ON invitations.id = user_invitation.invitation_id").
+		Where("invitations.event_id = ? AND user_invitation.rsvp_status <> ?", eventId, entity.RSVPStatusDeclined).
+		Distinct().
+		Pluck("user_invitation.user_id", &userIDs).Error
+	return userIDs, err
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	OIDCRepository interface {
+		CreateState(ctx context.Context, tx *gorm.DB, state entity.OIDCLoginState) error
+		// ConsumeState deletes the state and returns it, so a state can only
+		// be redeemed once even by concurrent callbacks
+		ConsumeState(ctx context.Context, tx *gorm.DB, state string) (entity.OIDCLoginState, error)
+		DeleteExpiredStates(ctx context.Context, tx *gorm.DB, now time.Time) error
+		GetIdentity(ctx context.Context, tx *gorm.DB, issuer string, subject string) (entity.UserIdentity, error)
+		CreateIdentity(ctx context.Context, tx *gorm.DB, identity entity.UserIdentity) (entity.UserIdentity, error)
+		TouchIdentity(ctx context.Context, tx *gorm.DB, identity entity.UserIdentity) error
+	}
+
+	oidcRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewOIDCRepository(db *gorm.DB) OIDCRepository {
+	return &oidcRepository{
+		db: db,
+	}
+}
+
+func (r *oidcRepository) CreateState(ctx context.Context, tx *gorm.DB, state entity.OIDCLoginState) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Create(&state).Error
+}
+
+func (r *oidcRepository) ConsumeState(ctx context.Context, tx *gorm.DB, state string) (entity.OIDCLoginState, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var consumed []entity.OIDCLoginState
+	result := tx.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("state = ?", state).
+		Delete(&consumed)
+	if result.Error != nil {
+		return entity.OIDCLoginState{}, result.Error
+	}
+	if len(consumed) == 0 {
+		return entity.OIDCLoginState{}, gorm.ErrRecordNotFound
+	}
+	return consumed[0], nil
+}
+
+func (r *oidcRepository) DeleteExpiredStates(ctx context.Context, tx *gorm.DB, now time.Time) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Where("expires_at < ?", now).Delete(&entity.OIDCLoginState{}).Error
+}
+
+func (r *oidcRepository) GetIdentity(ctx context.Context, tx *gorm.DB, issuer string, subject string) (entity.UserIdentity, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var identity entity.UserIdentity
+	if err := tx.WithContext(ctx).
+		Preload("User").
+		Where("issuer = ? AND subject = ?", issuer, subject).
+		Take(&identity).Error; err != nil {
+		return entity.UserIdentity{}, err
+	}
+	return identity, nil
+}
+
+func (r *oidcRepository) CreateIdentity(ctx context.Context, tx *gorm.DB, identity entity.UserIdentity) (entity.UserIdentity, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Omit("User").Create(&identity).Error; err != nil {
+		return entity.UserIdentity{}, err
+	}
+	return identity, nil
+}
+
+func (r *oidcRepository) TouchIdentity(ctx context.Context, tx *gorm.DB, identity entity.UserIdentity) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.UserIdentity{}).
+		Where("id = ?", identity.ID).
+		Updates(map[string]any{"email": identity.Email, "last_login_at": identity.LastLoginAt}).Error
+}
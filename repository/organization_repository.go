@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	OrganizationRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, organization entity.Organization) (entity.Organization, error)
+		GetAllOrganizations(ctx context.Context, tx *gorm.DB) ([]entity.Organization, error)
+		GetOrganizationByID(ctx context.Context, tx *gorm.DB, id string) (entity.Organization, error)
+		CheckOrganizationName(ctx context.Context, tx *gorm.DB, name string) (bool, error)
+		Update(ctx context.Context, tx *gorm.DB, organization entity.Organization) (entity.Organization, error)
+
+		AddMember(ctx context.Context, tx *gorm.DB, member entity.OrganizationMember) (entity.OrganizationMember, error)
+		GetMember(ctx context.Context, tx *gorm.DB, organizationID uuid.UUID, userID uuid.UUID) (entity.OrganizationMember, error)
+		GetMembers(ctx context.Context, tx *gorm.DB, organizationID uuid.UUID) ([]dto.OrganizationMemberResponse, error)
+		CountMembersByRole(ctx context.Context, tx *gorm.DB, organizationID uuid.UUID, role string) (int64, error)
+		UpdateMemberRole(ctx context.Context, tx *gorm.DB, memberID uuid.UUID, role string) error
+		RemoveMember(ctx context.Context, tx *gorm.DB, memberID uuid.UUID) error
+		// GetMembershipsByUserID lists the user's organizations, oldest
+		// membership first, with Role set to the user's role in each.
+		GetMembershipsByUserID(ctx context.Context, tx *gorm.DB, userID uuid.UUID) ([]dto.OrganizationResponse, error)
+	}
+
+	organizationRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepository{
+		db: db,
+	}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, tx *gorm.DB, organization entity.Organization) (entity.Organization, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&organization).Error; err != nil {
+		return entity.Organization{}, err
+	}
+	return organization, nil
+}
+
+func (r *organizationRepository) GetAllOrganizations(ctx context.Context, tx *gorm.DB) ([]entity.Organization, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var organizations []entity.Organization
+	if err := tx.WithContext(ctx).Order("name").Find(&organizations).Error; err != nil {
+		return nil, err
+	}
+	return organizations, nil
+}
+
+func (r *organizationRepository) GetOrganizationByID(ctx context.Context, tx *gorm.DB, id string) (entity.Organization, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var organization entity.Organization
+	if err := tx.WithContext(ctx).Where("id = ?", id).Take(&organization).Error; err != nil {
+		return entity.Organization{}, err
+	}
+	return organization, nil
+}
+
+func (r *organizationRepository) CheckOrganizationName(ctx context.Context, tx *gorm.DB, name string) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var count int64
+	if err := tx.WithContext(ctx).Model(&entity.Organization{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *organizationRepository) Update(ctx context.Context, tx *gorm.DB, organization entity.Organization) (entity.Organization, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Updates(&organization).Error; err != nil {
+		return entity.Organization{}, err
+	}
+	return r.GetOrganizationByID(ctx, tx, organization.ID.String())
+}
+
+func (r *organizationRepository) AddMember(ctx context.Context, tx *gorm.DB, member entity.OrganizationMember) (entity.OrganizationMember, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&member).Error; err != nil {
+		return entity.OrganizationMember{}, err
+	}
+	return member, nil
+}
+
+func (r *organizationRepository) GetMember(ctx context.Context, tx *gorm.DB, organizationID uuid.UUID, userID uuid.UUID) (entity.OrganizationMember, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var member entity.OrganizationMember
+	if err := tx.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Take(&member).Error; err != nil {
+		return entity.OrganizationMember{}, err
+	}
+	return member, nil
+}
+
+func (r *organizationRepository) GetMembers(ctx context.Context, tx *gorm.DB, organizationID uuid.UUID) ([]dto.OrganizationMemberResponse, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var members []dto.OrganizationMemberResponse
+	err := tx.WithContext(ctx).
+		Table("organization_members").
+		Select("organization_members.id, organization_members.user_id, users.name AS user_name, users.email AS user_email, organization_members.role").
+		Joins("JOIN users ON users.id = organization_members.user_id").
+		Where("organization_members.organization_id = ? AND organization_members.deleted_at IS NULL", organizationID).
+		Order("organization_members.created_at").
+		Scan(&members).Error
+	return members, err
+}
+
+func (r *organizationRepository) CountMembersByRole(ctx context.Context, tx *gorm.DB, organizationID uuid.UUID, role string) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var count int64
+	err := tx.WithContext(ctx).
+		Model(&entity.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", organizationID, role).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *organizationRepository) UpdateMemberRole(ctx context.Context, tx *gorm.DB, memberID uuid.UUID, role string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.OrganizationMember{}).Where("id = ?", memberID).Update("role", role).Error
+}
+
+// RemoveMember hard deletes the membership so the user can be added again.
+func (r *organizationRepository) RemoveMember(ctx context.Context, tx *gorm.DB, memberID uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Unscoped().Delete(&entity.OrganizationMember{}, "id = ?", memberID).Error
+}
+
+func (r *organizationRepository) GetMembershipsByUserID(ctx context.Context, tx *gorm.DB, userID uuid.UUID) ([]dto.OrganizationResponse, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var organizations []dto.OrganizationResponse
+	err := tx.WithContext(ctx).
+		Table("organization_members").
+		Select("organizations.id, organizations.name, organizations.description, organization_members.role").
+		Joins("JOIN organizations ON organizations.id = organization_members.organization_id").
+		Where("organization_members.user_id = ? AND organizations.deleted_at IS NULL", userID).
+		Order("organization_members.created_at").
+		Scan(&organizations).Error
+	return organizations, err
+}
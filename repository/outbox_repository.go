@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	OutboxRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, events []entity.OutboxEvent) error
+		// ClaimDue leases up to limit events that are due for delivery by
+		// pushing their next attempt to leaseUntil, so no other instance picks
+		// them up until the outcome is recorded or the lease runs out
+		ClaimDue(ctx context.Context, tx *gorm.DB, now time.Time, leaseUntil time.Time, limit int) ([]entity.OutboxEvent, error)
+		MarkDelivered(ctx context.Context, tx *gorm.DB, id int64, deliveredAt time.Time) error
+		// MarkRetry records a failed attempt; failedAt is set when the event
+		// will not be retried
+		MarkRetry(ctx context.Context, tx *gorm.DB, event entity.OutboxEvent) error
+		DeleteDeliveredBefore(ctx context.Context, tx *gorm.DB, before time.Time) (int64, error)
+	}
+
+	outboxRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+func (r *outboxRepository) Create(ctx context.Context, tx *gorm.DB, events []entity.OutboxEvent) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Create(&events).Error
+}
+
+func (r *outboxRepository) ClaimDue(ctx context.Context, tx *gorm.DB, now time.Time, leaseUntil time.Time, limit int) ([]entity.OutboxEvent, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var events []entity.OutboxEvent
+	err := tx.WithContext(ctx).Raw(`
+		UPDATE outbox_events SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= ?
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, leaseUntil, now, limit).
+		Scan(&events).Error
+	return events, err
+}
+
+func (r *outboxRepository) MarkDelivered(ctx context.Context, tx *gorm.DB, id int64, deliveredAt time.Time) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.OutboxEvent{}).Where("id = ?", id).Updates(map[string]any{
+		"attempts":            gorm.Expr("attempts + 1"),
+		"pending_subscribers": "",
+		"delivered_at":        deliveredAt,
+	}).Error
+}
+
+func (r *outboxRepository) MarkRetry(ctx context.Context, tx *gorm.DB, event entity.OutboxEvent) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.OutboxEvent{}).Where("id = ?", event.ID).Updates(map[string]any{
+		"attempts":            event.Attempts,
+		"pending_subscribers": event.PendingSubscribers,
+		"next_attempt_at":     event.NextAttemptAt,
+		"last_error":          event.LastError,
+		"failed_at":           event.FailedAt,
+	}).Error
+}
+
+func (r *outboxRepository) DeleteDeliveredBefore(ctx context.Context, tx *gorm.DB, before time.Time) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Where("delivered_at < ?", before).Delete(&entity.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
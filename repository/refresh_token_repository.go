@@ -4,15 +4,23 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/miraicantsleep/myits-event-be/entity"
 	"gorm.io/gorm"
 )
 
 type RefreshTokenRepository interface {
 	Create(ctx context.Context, tx *gorm.DB, token entity.RefreshToken) (entity.RefreshToken, error)
-	FindByToken(ctx context.Context, tx *gorm.DB, token string) (entity.RefreshToken, error)
+	FindByTokenHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.RefreshToken, error)
+	GetByID(ctx context.Context, tx *gorm.DB, id string) (entity.RefreshToken, error)
+	GetActiveByUserID(ctx context.Context, tx *gorm.DB, userID string) ([]entity.RefreshToken, error)
+	IsActive(ctx context.Context, tx *gorm.DB, id string) (bool, error)
+	// Rotate stores the new token hash and expiry of a refreshed session
+	// along with who used it last and the organization it acts for
+	Rotate(ctx context.Context, tx *gorm.DB, token entity.RefreshToken) error
+	SetOrganization(ctx context.Context, tx *gorm.DB, id string, organizationID *uuid.UUID) error
+	DeleteByID(ctx context.Context, tx *gorm.DB, id string) error
 	DeleteByUserID(ctx context.Context, tx *gorm.DB, userID string) error
-	DeleteByToken(ctx context.Context, tx *gorm.DB, token string) error
 	DeleteExpired(ctx context.Context, tx *gorm.DB) error
 }
 
@@ -42,7 +50,7 @@ func (r *refreshTokenRepository) Create(
 	return token, nil
 }
 
-func (r *refreshTokenRepository) FindByToken(ctx context.Context, tx *gorm.DB, token string) (
+func (r *refreshTokenRepository) FindByTokenHash(ctx context.Context, tx *gorm.DB, tokenHash string) (
 	entity.RefreshToken,
 	error,
 ) {
@@ -51,31 +59,94 @@ func (r *refreshTokenRepository) FindByToken(ctx context.Context, tx *gorm.DB, t
 	}
 
 	var refreshToken entity.RefreshToken
-	if err := tx.WithContext(ctx).Where("token = ?", token).Preload("User").Take(&refreshToken).Error; err != nil {
+	if err := tx.WithContext(ctx).Where("token_hash = ?", tokenHash).Preload("User").Take(&refreshToken).Error; err != nil {
 		return entity.RefreshToken{}, err
 	}
 
 	return refreshToken, nil
 }
 
-func (r *refreshTokenRepository) DeleteByUserID(ctx context.Context, tx *gorm.DB, userID string) error {
+func (r *refreshTokenRepository) GetByID(ctx context.Context, tx *gorm.DB, id string) (entity.RefreshToken, error) {
 	if tx == nil {
 		tx = r.db
 	}
 
-	if err := tx.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.RefreshToken{}).Error; err != nil {
-		return err
+	var refreshToken entity.RefreshToken
+	if err := tx.WithContext(ctx).Where("id = ?", id).Take(&refreshToken).Error; err != nil {
+		return entity.RefreshToken{}, err
 	}
 
-	return nil
+	return refreshToken, nil
+}
+
+func (r *refreshTokenRepository) GetActiveByUserID(ctx context.Context, tx *gorm.DB, userID string) ([]entity.RefreshToken, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var refreshTokens []entity.RefreshToken
+	if err := tx.WithContext(ctx).
+		Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&refreshTokens).Error; err != nil {
+		return nil, err
+	}
+
+	return refreshTokens, nil
 }
 
-func (r *refreshTokenRepository) DeleteByToken(ctx context.Context, tx *gorm.DB, token string) error {
+func (r *refreshTokenRepository) IsActive(ctx context.Context, tx *gorm.DB, id string) (bool, error) {
 	if tx == nil {
 		tx = r.db
 	}
 
-	if err := tx.WithContext(ctx).Where("token = ?", token).Delete(&entity.RefreshToken{}).Error; err != nil {
+	var count int64
+	if err := tx.WithContext(ctx).
+		Model(&entity.RefreshToken{}).
+		Where("id = ? AND expires_at > ?", id, time.Now()).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *refreshTokenRepository) Rotate(ctx context.Context, tx *gorm.DB, token entity.RefreshToken) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&token).
+		Select("token_hash", "expires_at", "last_used_at", "ip", "organization_id").
+		Updates(&token).Error
+}
+
+func (r *refreshTokenRepository) SetOrganization(ctx context.Context, tx *gorm.DB, id string, organizationID *uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.RefreshToken{}).
+		Where("id = ?", id).
+		Update("organization_id", organizationID).Error
+}
+
+func (r *refreshTokenRepository) DeleteByID(ctx context.Context, tx *gorm.DB, id string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Where("id = ?", id).Delete(&entity.RefreshToken{}).Error
+}
+
+func (r *refreshTokenRepository) DeleteByUserID(ctx context.Context, tx *gorm.DB, userID string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.RefreshToken{}).Error; err != nil {
 		return err
 	}
 
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	RoleChangeRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, change entity.RoleChange) (entity.RoleChange, error)
+		GetRoleChangesByUserID(ctx context.Context, tx *gorm.DB, userId string) ([]dto.RoleChangeResponse, error)
+	}
+
+	roleChangeRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewRoleChangeRepository(db *gorm.DB) RoleChangeRepository {
+	return &roleChangeRepository{
+		db: db,
+	}
+}
+
+func (r *roleChangeRepository) Create(ctx context.Context, tx *gorm.DB, change entity.RoleChange) (entity.RoleChange, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&change).Error; err != nil {
+		return entity.RoleChange{}, err
+	}
+	return change, nil
+}
+
+func (r *roleChangeRepository) GetRoleChangesByUserID(ctx context.Context, tx *gorm.DB, userId string) ([]dto.RoleChangeResponse, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var changes []dto.RoleChangeResponse
+	err := tx.WithContext(ctx).
+		Table("role_changes").
+		Select("role_changes.id, role_changes.user_id, role_changes.old_role, role_changes.new_role, role_changes.reason, role_changes.created_at, role_changes.changed_by, users.name AS changed_by_name").
+		Joins("LEFT JOIN users ON users.id = role_changes.changed_by").
+		Where("role_changes.user_id = ?", userId).
+		Order("role_changes.created_at DESC").
+		Scan(&changes).Error
+	return changes, err
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	RoomImageRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, image entity.RoomImage) (entity.RoomImage, error)
+		GetRoomImageByID(ctx context.Context, tx *gorm.DB, id string) (entity.RoomImage, error)
+		GetRoomImages(ctx context.Context, tx *gorm.DB, roomID uuid.UUID, kind string) ([]entity.RoomImage, error)
+		Delete(ctx context.Context, tx *gorm.DB, id uuid.UUID) error
+	}
+
+	roomImageRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewRoomImageRepository(db *gorm.DB) RoomImageRepository {
+	return &roomImageRepository{
+		db: db,
+	}
+}
+
+func (r *roomImageRepository) Create(ctx context.Context, tx *gorm.DB, image entity.RoomImage) (entity.RoomImage, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&image).Error; err != nil {
+		return entity.RoomImage{}, err
+	}
+	return image, nil
+}
+
+func (r *roomImageRepository) GetRoomImageByID(ctx context.Context, tx *gorm.DB, id string) (entity.RoomImage, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var image entity.RoomImage
+	if err := tx.WithContext(ctx).First(&image, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.RoomImage{}, dto.ErrRoomImageNotFound
+		}
+		return entity.RoomImage{}, err
+	}
+	return image, nil
+}
+
+// GetRoomImages lists the images of a room, optionally narrowed to one kind.
+func (r *roomImageRepository) GetRoomImages(ctx context.Context, tx *gorm.DB, roomID uuid.UUID, kind string) ([]entity.RoomImage, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	query := tx.WithContext(ctx).Where("room_id = ?", roomID)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+
+	var images []entity.RoomImage
+	if err := query.Order("created_at ASC").Find(&images).Error; err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (r *roomImageRepository) Delete(ctx context.Context, tx *gorm.DB, id uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Unscoped().Delete(&entity.RoomImage{}, "id = ?", id).Error
+}
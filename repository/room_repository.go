@@ -14,7 +14,7 @@ type (
 		Create(ctx context.Context, room entity.Room) (entity.Room, error)
 		GetRoomByID(ctx context.Context, id string) (entity.Room, error)
 		GetRoomByName(ctx context.Context, name string) (entity.Room, error)
-		GetAllRoom(ctx context.Context) ([]dto.RoomResponse, error)
+		GetAllRoom(ctx context.Context, filter dto.RoomFilterRequest) ([]dto.RoomResponse, error)
 		Update(ctx context.Context, id string, room entity.Room) (entity.Room, error)
 		Delete(ctx context.Context, id string) error
 	}
@@ -76,13 +76,34 @@ func (r *roomRepository) GetRoomByName(ctx context.Context, name string) (entity
 	return room, nil
 }
 
-func (r *roomRepository) GetAllRoom(ctx context.Context) ([]dto.RoomResponse, error) {
+func (r *roomRepository) GetAllRoom(ctx context.Context, filter dto.RoomFilterRequest) ([]dto.RoomResponse, error) {
 	tx := r.db
 	if tx == nil {
 		return nil, dto.ErrGetAllRoom
 	}
+	query := tx.WithContext(ctx).Table("vw_room_details")
+	if filter.Search != "" {
+		query = query.Where("name ILIKE ?", "%"+filter.Search+"%")
+	}
+	if filter.MinCapacity > 0 {
+		query = query.Where("capacity >= ?", filter.MinCapacity)
+	}
+	// an unset amenity means any room, not rooms without it
+	if filter.HasProjector {
+		query = query.Where("has_projector = ?", true)
+	}
+	if filter.HasSoundSystem {
+		query = query.Where("has_sound_system = ?", true)
+	}
+	if filter.HasAC {
+		query = query.Where("has_ac = ?", true)
+	}
+	if filter.WheelchairAccessible {
+		query = query.Where("wheelchair_accessible = ?", true)
+	}
+
 	var rooms []dto.RoomResponse
-	if err := tx.WithContext(ctx).Table("vw_room_details").Find(&rooms).Error; err != nil {
+	if err := query.Find(&rooms).Error; err != nil {
 		return nil, err
 	}
 	return rooms, nil
@@ -104,7 +125,10 @@ func (r *roomRepository) Update(ctx context.Context, id string, room entity.Room
 
 	room.ID, _ = uuid.Parse(id)
 
-	if err := tx.WithContext(ctx).Model(&existingRoom).Updates(room).Error; err != nil {
+	// Select the columns explicitly so amenities can be switched back to false
+	if err := tx.WithContext(ctx).Model(&existingRoom).
+		Select("name", "capacity", "department_id", "has_projector", "has_sound_system", "has_ac", "wheelchair_accessible").
+		Updates(room).Error; err != nil {
 		return entity.Room{}, err
 	}
 
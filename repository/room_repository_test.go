@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB builds statements without a database and hands each query to
+// capture
+func dryRunDB(t *testing.T, capture func(sql string, vars []any)) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	assert.NoError(t, err)
+	record := func(db *gorm.DB) {
+		capture(db.Statement.SQL.String(), db.Statement.Vars)
+	}
+	assert.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", record))
+	assert.NoError(t, db.Callback().Update().After("gorm:update").Register("test:capture", record))
+	return db
+}
+
+func Test_RoomRepository_GetAllRoom(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   dto.RoomFilterRequest
+		wantSQL  string
+		wantVars []any
+	}{
+		{
+			name:    "no filter",
+			wantSQL: `SELECT * FROM "vw_room_details"`,
+		},
+		{
+			name:     "search and capacity",
+			filter:   dto.RoomFilterRequest{Search: "lab", MinCapacity: 30},
+			wantSQL:  `SELECT * FROM "vw_room_details" WHERE name ILIKE $1 AND capacity >= $2`,
+			wantVars: []any{"%lab%", 30},
+		},
+		{
+			name:     "amenities",
+			filter:   dto.RoomFilterRequest{HasProjector: true, HasSoundSystem: true, HasAC: true, WheelchairAccessible: true},
+			wantSQL:  `SELECT * FROM "vw_room_details" WHERE has_projector = $1 AND has_sound_system = $2 AND has_ac = $3 AND wheelchair_accessible = $4`,
+			wantVars: []any{true, true, true, true},
+		},
+		{
+			name:     "one amenity",
+			filter:   dto.RoomFilterRequest{HasAC: true},
+			wantSQL:  `SELECT * FROM "vw_room_details" WHERE has_ac = $1`,
+			wantVars: []any{true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sql string
+			var vars []any
+			db := dryRunDB(t, func(s string, v []any) { sql, vars = s, v })
+
+			_, err := NewRoomRepository(db).GetAllRoom(context.Background(), tt.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSQL, sql)
+			assert.Equal(t, tt.wantVars, vars)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	RoomScheduleRepository interface {
+		GetRoomOperatingHours(ctx context.Context, tx *gorm.DB, roomID uuid.UUID) ([]entity.OperatingHour, error)
+		GetDepartmentOperatingHours(ctx context.Context, tx *gorm.DB, departmentID uuid.UUID) ([]entity.OperatingHour, error)
+		ReplaceRoomOperatingHours(ctx context.Context, tx *gorm.DB, roomID uuid.UUID, hours []entity.OperatingHour) error
+		ReplaceDepartmentOperatingHours(ctx context.Context, tx *gorm.DB, departmentID uuid.UUID, hours []entity.OperatingHour) error
+		CreateBlackout(ctx context.Context, tx *gorm.DB, blackout entity.BlackoutPeriod) (entity.BlackoutPeriod, error)
+		GetBlackoutByID(ctx context.Context, tx *gorm.DB, id string) (entity.BlackoutPeriod, error)
+		GetRoomBlackouts(ctx context.Context, tx *gorm.DB, roomID uuid.UUID) ([]entity.BlackoutPeriod, error)
+		GetDepartmentBlackouts(ctx context.Context, tx *gorm.DB, departmentID uuid.UUID) ([]entity.BlackoutPeriod, error)
+		DeleteBlackout(ctx context.Context, tx *gorm.DB, id string) error
+		// HasOverlappingBlackout reports whether a blackout of the room itself or
+		// of its department intersects [start, end).
+		HasOverlappingBlackout(ctx context.Context, tx *gorm.DB, roomID uuid.UUID, departmentID uuid.UUID, start time.Time, end time.Time) (bool, error)
+		IsRoomBooked(ctx context.Context, tx *gorm.DB, roomID uuid.UUID, start time.Time, end time.Time) (bool, error)
+	}
+
+	roomScheduleRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewRoomScheduleRepository(db *gorm.DB) RoomScheduleRepository {
+	return &roomScheduleRepository{
+		db: db,
+	}
+}
+
+func (r *roomScheduleRepository) GetRoomOperatingHours(ctx context.Context, tx *gorm.DB, roomID uuid.UUID) ([]entity.OperatingHour, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var hours []entity.OperatingHour
+	if err := tx.WithContext(ctx).Where("room_id = ?", roomID).Order("day_of_week ASC").Find(&hours).Error; err != nil {
+		return nil, err
+	}
+	return hours, nil
+}
+
+func (r *roomScheduleRepository) GetDepartmentOperatingHours(ctx context.Context, tx *gorm.DB, departmentID uuid.UUID) ([]entity.OperatingHour, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var hours []entity.OperatingHour
+	if err := tx.WithContext(ctx).Where("department_id = ?", departmentID).Order("day_of_week ASC").Find(&hours).Error; err != nil {
+		return nil, err
+	}
+	return hours, nil
+}
+
+func (r *roomScheduleRepository) ReplaceRoomOperatingHours(ctx context.Context, tx *gorm.DB, roomID uuid.UUID, hours []entity.OperatingHour) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("room_id = ?", roomID).Delete(&entity.OperatingHour{}).Error; err != nil {
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		for i := range hours {
+			hours[i].RoomID = &roomID
+			hours[i].DepartmentID = nil
+		}
+		return tx.Create(&hours).Error
+	})
+}
+
+func (r *roomScheduleRepository) ReplaceDepartmentOperatingHours(ctx context.Context, tx *gorm.DB, departmentID uuid.UUID, hours []entity.OperatingHour) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("department_id = ?", departmentID).Delete(&entity.OperatingHour{}).Error; err != nil {
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		for i := range hours {
+			hours[i].DepartmentID = &departmentID
+			hours[i].RoomID = nil
+		}
+		return tx.Create(&hours).Error
+	})
+}
+
+func (r *roomScheduleRepository) CreateBlackout(ctx context.Context, tx *gorm.DB, blackout entity.BlackoutPeriod) (entity.BlackoutPeriod, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&blackout).Error; err != nil {
+		return entity.BlackoutPeriod{}, err
+	}
+	return blackout, nil
+}
+
+func (r *roomScheduleRepository) GetBlackoutByID(ctx context.Context, tx *gorm.DB, id string) (entity.BlackoutPeriod, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var blackout entity.BlackoutPeriod
+	if err := tx.WithContext(ctx).Take(&blackout, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.BlackoutPeriod{}, dto.ErrBlackoutNotFound
+		}
+		return entity.BlackoutPeriod{}, err
+	}
+	return blackout, nil
+}
+
+func (r *roomScheduleRepository) GetRoomBlackouts(ctx context.Context, tx *gorm.DB, roomID uuid.UUID) ([]entity.BlackoutPeriod, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var blackouts []entity.BlackoutPeriod
+	if err := tx.WithContext(ctx).Where("room_id = ?", roomID).Order("start_time ASC").Find(&blackouts).Error; err != nil {
+		return nil, err
+	}
+	return blackouts, nil
+}
+
+func (r *roomScheduleRepository) GetDepartmentBlackouts(ctx context.Context, tx *gorm.DB, departmentID uuid.UUID) ([]entity.BlackoutPeriod, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var blackouts []entity.BlackoutPeriod
+	if err := tx.WithContext(ctx).Where("department_id = ?", departmentID).Order("start_time ASC").Find(&blackouts).Error; err != nil {
+		return nil, err
+	}
+	return blackouts, nil
+}
+
+func (r *roomScheduleRepository) DeleteBlackout(ctx context.Context, tx *gorm.DB, id string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Delete(&entity.BlackoutPeriod{}, "id = ?", id).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *roomScheduleRepository) HasOverlappingBlackout(
+	ctx context.Context,
+	tx *gorm.DB,
+	roomID uuid.UUID,
+	departmentID uuid.UUID,
+	start time.Time,
+	end time.Time,
+) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var count int64
+	if err := tx.WithContext(ctx).
+		Model(&entity.BlackoutPeriod{}).
+		Where("(room_id = ? OR department_id = ?)", roomID, departmentID).
+		Where("? < end_time AND ? > start_time", start, end).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *roomScheduleRepository) IsRoomBooked(ctx context.Context, tx *gorm.DB, roomID uuid.UUID, start time.Time, end time.Time) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var available bool
+	if err := tx.WithContext(ctx).Raw("SELECT is_room_available(?, ?, ?)", roomID, start, end).Scan(&available).Error; err != nil {
+		return false, err
+	}
+	return !available, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/miraicantsleep/myits-event-be/dto"
 	"github.com/miraicantsleep/myits-event-be/entity"
@@ -22,6 +23,12 @@ type (
 		CheckEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, bool, error)
 		Update(ctx context.Context, tx *gorm.DB, user entity.User) (entity.User, error)
 		Delete(ctx context.Context, tx *gorm.DB, userId string) error
+		MarkVerified(ctx context.Context, tx *gorm.DB, userId string) error
+		UpdatePassword(ctx context.Context, tx *gorm.DB, userId string, hashedPassword string) error
+		UpdateRole(ctx context.Context, tx *gorm.DB, userId string, role entity.UserRole) error
+		// SetDisabledAt disables the account, or enables it again when
+		// disabledAt is nil.
+		SetDisabledAt(ctx context.Context, tx *gorm.DB, userId string, disabledAt *time.Time) error
 	}
 
 	userRepository struct {
@@ -153,3 +160,37 @@ func (r *userRepository) Delete(ctx context.Context, tx *gorm.DB, userId string)
 
 	return nil
 }
+
+func (r *userRepository) MarkVerified(ctx context.Context, tx *gorm.DB, userId string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.User{}).Where("id = ?", userId).Update("is_verified", true).Error
+}
+
+// UpdatePassword stores an already hashed password. UpdateColumn skips the
+// BeforeUpdate hook so the hash is not hashed again.
+func (r *userRepository) UpdatePassword(ctx context.Context, tx *gorm.DB, userId string, hashedPassword string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.User{}).Where("id = ?", userId).UpdateColumn("password", hashedPassword).Error
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, tx *gorm.DB, userId string, role entity.UserRole) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.User{}).Where("id = ?", userId).UpdateColumn("role", role).Error
+}
+
+func (r *userRepository) SetDisabledAt(ctx context.Context, tx *gorm.DB, userId string, disabledAt *time.Time) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.User{}).Where("id = ?", userId).UpdateColumn("disabled_at", disabledAt).Error
+}
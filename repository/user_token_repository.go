@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	UserTokenRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, token entity.UserToken) (entity.UserToken, error)
+		FindByHash(ctx context.Context, tx *gorm.DB, tokenHash string, purpose string) (entity.UserToken, error)
+		MarkUsed(ctx context.Context, tx *gorm.DB, id uuid.UUID) (bool, error)
+		InvalidateByUser(ctx context.Context, tx *gorm.DB, userID uuid.UUID, purpose string) error
+	}
+
+	userTokenRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{
+		db: db,
+	}
+}
+
+func (r *userTokenRepository) Create(ctx context.Context, tx *gorm.DB, token entity.UserToken) (entity.UserToken, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&token).Error; err != nil {
+		return entity.UserToken{}, err
+	}
+	return token, nil
+}
+
+func (r *userTokenRepository) FindByHash(ctx context.Context, tx *gorm.DB, tokenHash string, purpose string) (entity.UserToken, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var token entity.UserToken
+	if err := tx.WithContext(ctx).Where("token_hash = ? AND purpose = ?", tokenHash, purpose).Take(&token).Error; err != nil {
+		return entity.UserToken{}, err
+	}
+	return token, nil
+}
+
+// MarkUsed consumes the token and reports false if it had already been used,
+// so two concurrent requests with the same token cannot both succeed.
+func (r *userTokenRepository) MarkUsed(ctx context.Context, tx *gorm.DB, id uuid.UUID) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).
+		Model(&entity.UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// InvalidateByUser consumes every outstanding token of the purpose, used when
+// a new token is issued so only the latest email link works.
+func (r *userTokenRepository) InvalidateByUser(ctx context.Context, tx *gorm.DB, userID uuid.UUID, purpose string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	WebhookRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, webhook entity.Webhook) (entity.Webhook, error)
+		GetAll(ctx context.Context, tx *gorm.DB) ([]entity.Webhook, error)
+		GetByID(ctx context.Context, tx *gorm.DB, webhookId string) (entity.Webhook, error)
+		Update(ctx context.Context, tx *gorm.DB, webhook entity.Webhook) (entity.Webhook, error)
+		Delete(ctx context.Context, tx *gorm.DB, webhookId string) error
+		// GetActiveForEventType returns the active webhooks subscribed to the
+		// event type
+		GetActiveForEventType(ctx context.Context, tx *gorm.DB, eventType string) ([]entity.Webhook, error)
+
+		CreateDeliveries(ctx context.Context, tx *gorm.DB, deliveries []entity.WebhookDelivery) error
+		GetDeliveries(ctx context.Context, tx *gorm.DB, webhookId string, req dto.PaginationRequest) ([]entity.WebhookDelivery, dto.PaginationResponse, error)
+		GetDelivery(ctx context.Context, tx *gorm.DB, webhookId string, deliveryId string) (entity.WebhookDelivery, error)
+		// ClaimDueDeliveries leases up to limit pending deliveries that are due
+		// by pushing their next attempt to leaseUntil, so no other instance
+		// picks them up while they are being sent
+		ClaimDueDeliveries(ctx context.Context, tx *gorm.DB, now time.Time, leaseUntil time.Time, limit int) ([]entity.WebhookDelivery, error)
+		FinishAttempt(ctx context.Context, tx *gorm.DB, delivery entity.WebhookDelivery) error
+		DeleteDeliveriesBefore(ctx context.Context, tx *gorm.DB, before time.Time) (int64, error)
+	}
+
+	webhookRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, tx *gorm.DB, webhook entity.Webhook) (entity.Webhook, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&webhook).Error; err != nil {
+		return entity.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (r *webhookRepository) GetAll(ctx context.Context, tx *gorm.DB) ([]entity.Webhook, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var webhooks []entity.Webhook
+	err := tx.WithContext(ctx).Order("created_at DESC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, tx *gorm.DB, webhookId string) (entity.Webhook, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var webhook entity.Webhook
+	if err := tx.WithContext(ctx).Where("id = ?", webhookId).Take(&webhook).Error; err != nil {
+		return entity.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (r *webhookRepository) Update(ctx context.Context, tx *gorm.DB, webhook entity.Webhook) (entity.Webhook, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Save(&webhook).Error; err != nil {
+		return entity.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, tx *gorm.DB, webhookId string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Delete(&entity.Webhook{}, "id = ?", webhookId).Error
+}
+
+func (r *webhookRepository) GetActiveForEventType(ctx context.Context, tx *gorm.DB, eventType string) ([]entity.Webhook, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var webhooks []entity.Webhook
+	err := tx.WithContext(ctx).
+		Where("active AND ? = ANY(string_to_array(event_types, ','))", eventType).
+		Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, tx *gorm.DB, deliveries []entity.WebhookDelivery) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Create(&deliveries).Error
+}
+
+func (r *webhookRepository) GetDeliveries(ctx context.Context, tx *gorm.DB, webhookId string, req dto.PaginationRequest) ([]entity.WebhookDelivery, dto.PaginationResponse, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	req.Default()
+
+	query := tx.WithContext(ctx).Model(&entity.WebhookDelivery{}).Where("webhook_id = ?", webhookId)
+	if req.Search != "" {
+		query = query.Where("event_type = ? OR status = ?", req.Search, req.Search)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	var deliveries []entity.WebhookDelivery
+	if err := query.Order("created_at DESC").Scopes(Paginate(req)).Find(&deliveries).Error; err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	return deliveries, dto.PaginationResponse{
+		Page:    req.Page,
+		PerPage: req.PerPage,
+		Count:   count,
+		MaxPage: TotalPage(count, int64(req.PerPage)),
+	}, nil
+}
+
+func (r *webhookRepository) GetDelivery(ctx context.Context, tx *gorm.DB, webhookId string, deliveryId string) (entity.WebhookDelivery, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var delivery entity.WebhookDelivery
+	if err := tx.WithContext(ctx).Where("id = ? AND webhook_id = ?", deliveryId, webhookId).Take(&delivery).Error; err != nil {
+		return entity.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, tx *gorm.DB, now time.Time, leaseUntil time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var deliveries []entity.WebhookDelivery
+	err := tx.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, leaseUntil, entity.WebhookDeliveryPending, now, limit).
+		Scan(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *webhookRepository) FinishAttempt(ctx context.Context, tx *gorm.DB, delivery entity.WebhookDelivery) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]any{
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"next_attempt_at": delivery.NextAttemptAt,
+		"response_status": delivery.ResponseStatus,
+		"response_body":   delivery.ResponseBody,
+		"error":           delivery.Error,
+		"duration_ms":     delivery.DurationMs,
+		"delivered_at":    delivery.DeliveredAt,
+	}).Error
+}
+
+func (r *webhookRepository) DeleteDeliveriesBefore(ctx context.Context, tx *gorm.DB, before time.Time) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Where("created_at < ? AND status <> ?", before, entity.WebhookDeliveryPending).Delete(&entity.WebhookDelivery{})
+	return result.RowsAffected, result.Error
+}
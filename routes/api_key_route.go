@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/miraicantsleep/myits-event-be/middleware"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+)
+
+func APIKey(route *gin.Engine, injector *do.Injector) {
+	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	apiKeyService := do.MustInvokeNamed[service.APIKeyService](injector, constants.APIKeyService)
+	apiKeyController := do.MustInvoke[controller.APIKeyController](injector)
+
+	routes := route.Group("/api/admin/api-keys", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin"))
+	{
+		routes.POST("/", apiKeyController.Create)
+		routes.GET("/", apiKeyController.GetAll)
+		routes.GET("/:id", apiKeyController.GetByID)
+		routes.DELETE("/:id", apiKeyController.Revoke)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/miraicantsleep/myits-event-be/middleware"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+)
+
+func Audit(route *gin.Engine, injector *do.Injector) {
+	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	apiKeyService := do.MustInvokeNamed[service.APIKeyService](injector, constants.APIKeyService)
+	auditController := do.MustInvoke[controller.AuditController](injector)
+
+	routes := route.Group("/api/audit", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin"))
+	{
+		routes.GET("/", auditController.GetAll)
+		routes.GET("/export", auditController.Export)
+	}
+}
@@ -11,17 +11,18 @@ import (
 
 func BookingRequest(route *gin.Engine, injector *do.Injector) {
 	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	apiKeyService := do.MustInvokeNamed[service.APIKeyService](injector, constants.APIKeyService)
 	bookingRequestController := do.MustInvoke[controller.BookingRequestController](injector)
 
 	routes := route.Group("/api/booking-request")
 	{
-		routes.POST("/", middleware.Authenticate(jwtService), middleware.RoleMiddleware("ormawa"), bookingRequestController.Create)
-		routes.GET("/:id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("ormawa", "admin", "departemen"), bookingRequestController.GetByID)
-		routes.GET("/", middleware.Authenticate(jwtService), middleware.RoleMiddleware("ormawa", "admin", "departemen"), bookingRequestController.GetAll)
-		routes.PATCH("/:id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("ormawa", "admin"), bookingRequestController.Update)
-		routes.DELETE("/:id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("ormawa", "admin"), bookingRequestController.Delete)
-		routes.PATCH("/:id/approve", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin", "departemen"), bookingRequestController.Approve)
-		routes.PATCH("/:id/reject", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin", "departemen"), bookingRequestController.Reject)
-		routes.GET("/with-capacity", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin", "departemen"), bookingRequestController.GetAllWithCapacity)
+		routes.POST("/", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa"), middleware.OrganizationRoleMiddleware("chair", "secretary"), bookingRequestController.Create)
+		routes.GET("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin", "departemen"), bookingRequestController.GetByID)
+		routes.GET("/", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin", "departemen"), bookingRequestController.GetAll)
+		routes.PATCH("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin"), middleware.OrganizationRoleMiddleware("chair", "secretary"), bookingRequestController.Update)
+		routes.DELETE("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin"), middleware.OrganizationRoleMiddleware("chair", "secretary"), bookingRequestController.Delete)
+		routes.PATCH("/:id/approve", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), bookingRequestController.Approve)
+		routes.PATCH("/:id/reject", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), bookingRequestController.Reject)
+		routes.GET("/with-capacity", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), bookingRequestController.GetAllWithCapacity)
 	}
 }
@@ -11,15 +11,23 @@ import (
 
 func Department(route *gin.Engine, injector *do.Injector) {
 	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	apiKeyService := do.MustInvokeNamed[service.APIKeyService](injector, constants.APIKeyService)
 	departmentController := do.MustInvoke[controller.DepartmentController](injector)
 
 	routes := route.Group("/api/department")
 	{
 		// Department
-		routes.POST("/", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin"), departmentController.Create)
-		routes.GET("/", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin"), departmentController.GetAllDepartment)
-		routes.GET("/:id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin"), departmentController.GetDepartmentByID)
-		routes.PATCH("/:id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin"), departmentController.Update)
-		routes.DELETE("/:id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin"), departmentController.Delete)
+		routes.POST("/", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin"), departmentController.Create)
+		routes.GET("/", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin"), departmentController.GetAllDepartment)
+		routes.GET("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin"), departmentController.GetDepartmentByID)
+		routes.PATCH("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin"), departmentController.Update)
+		routes.DELETE("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin"), departmentController.Delete)
+
+		// Schedule
+		routes.GET("/:id/operating-hours", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen", "ormawa"), departmentController.GetOperatingHours)
+		routes.PUT("/:id/operating-hours", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), departmentController.SetOperatingHours)
+		routes.GET("/:id/blackouts", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen", "ormawa"), departmentController.GetBlackouts)
+		routes.POST("/:id/blackouts", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), departmentController.CreateBlackout)
+		routes.DELETE("/:id/blackouts/:blackout_id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), departmentController.DeleteBlackout)
 	}
 }
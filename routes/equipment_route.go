@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/miraicantsleep/myits-event-be/middleware"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+)
+
+func Equipment(route *gin.Engine, injector *do.Injector) {
+	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	apiKeyService := do.MustInvokeNamed[service.APIKeyService](injector, constants.APIKeyService)
+	equipmentController := do.MustInvoke[controller.EquipmentController](injector)
+
+	routes := route.Group("/api/equipment")
+	{
+		// Equipment
+		routes.POST("/", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), equipmentController.Create)
+		routes.GET("/", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen", "ormawa"), equipmentController.GetAllEquipment)
+		routes.GET("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen", "ormawa"), equipmentController.GetEquipmentByID)
+		routes.GET("/:id/availability", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen", "ormawa"), equipmentController.GetAvailability)
+		routes.PATCH("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), equipmentController.Update)
+		routes.DELETE("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), equipmentController.Delete)
+	}
+}
@@ -11,17 +11,31 @@ import (
 
 func Event(route *gin.Engine, injector *do.Injector) {
 	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	apiKeyService := do.MustInvokeNamed[service.APIKeyService](injector, constants.APIKeyService)
 	eventController := do.MustInvoke[controller.EventController](injector)
+	reminderController := do.MustInvoke[controller.EventReminderController](injector)
 
 	routes := route.Group("/api/event")
 	{
 		// Event
-		routes.GET("/", middleware.Authenticate(jwtService), middleware.RoleMiddleware("ormawa", "admin", "departemen"), eventController.GetAllEvent)
-		routes.GET("/:id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("ormawa", "admin"), eventController.GetEventByID)
-		routes.GET("/:id/attendees", middleware.Authenticate(jwtService), middleware.RoleMiddleware("ormawa", "admin"), eventController.GetEventAttendees)
-		routes.POST("/", middleware.Authenticate(jwtService), middleware.RoleMiddleware("ormawa", "admin"), eventController.Create)
-		routes.PATCH("/:id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("ormawa", "admin"), eventController.Update)
-		routes.DELETE("/:id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("ormawa", "admin"), eventController.Delete)
-		routes.GET("/attendance/all", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin"), eventController.GetAllUserAttendances)
+		routes.GET("/", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin", "departemen"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee", "scanner"), eventController.GetAllEvent)
+		routes.GET("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee", "scanner"), eventController.GetEventByID)
+		routes.GET("/:id/attendees", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee", "scanner"), eventController.GetEventAttendees)
+		routes.GET("/:id/attendance-stats", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee", "scanner"), eventController.GetAttendanceStats)
+		routes.POST("/", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee"), eventController.Create)
+		routes.PATCH("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee"), eventController.Update)
+		routes.DELETE("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee"), eventController.Delete)
+		routes.GET("/attendance/all", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin"), eventController.GetAllUserAttendances)
+
+		// Reminders
+		routes.GET("/:id/reminders", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee"), reminderController.GetReminders)
+		routes.PUT("/:id/reminders", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee"), reminderController.UpdateReminders)
+
+		// Poster & attachments
+		routes.PUT("/:id/poster", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee"), eventController.UploadPoster)
+		routes.DELETE("/:id/poster", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee"), eventController.DeletePoster)
+		routes.POST("/:id/attachments", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee"), eventController.UploadAttachment)
+		routes.GET("/:id/attachments/:attachment_id", middleware.Authenticate(jwtService, apiKeyService), eventController.DownloadAttachment)
+		routes.DELETE("/:id/attachments/:attachment_id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa", "admin"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee"), eventController.DeleteAttachment)
 	}
 }
@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/miraicantsleep/myits-event-be/constants"
 	"github.com/miraicantsleep/myits-event-be/controller"
@@ -11,22 +13,39 @@ import (
 
 func Invitation(route *gin.Engine, injector *do.Injector) {
 	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	apiKeyService := do.MustInvokeNamed[service.APIKeyService](injector, constants.APIKeyService)
 	invitationController := do.MustInvoke[controller.InvitationController](injector)
+	rateLimitStore := do.MustInvokeNamed[middleware.RateLimitStore](injector, constants.RateLimitStore)
+
+	// public endpoints carry guessable tokens, so they are limited per IP
+	scanLimit := middleware.RateLimit(middleware.RateLimitConfig{
+		Name: "invitation-scan", Limit: 60, Period: time.Minute, Key: middleware.RateLimitByIP, Store: rateLimitStore,
+	})
+	rsvpLimit := middleware.RateLimit(middleware.RateLimitConfig{
+		Name: "invitation-rsvp", Limit: 20, Period: time.Minute, Key: middleware.RateLimitByIP, Store: rateLimitStore,
+	})
+	joinLimit := middleware.RateLimit(middleware.RateLimitConfig{
+		Name: "invitation-join", Limit: 20, Period: time.Minute, Key: middleware.RateLimitByIP, Store: rateLimitStore,
+	})
 
 	routes := route.Group("/api/invitation")
 	{
 		// Invitation
-		routes.GET("/:id", middleware.Authenticate(jwtService), invitationController.GetInvitationByID)
-		routes.GET("/event/:event_id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin", "ormawa"), invitationController.GetInvitationByEventID)
-		routes.GET("/user/:userId", middleware.Authenticate(jwtService), invitationController.GetInvitationByUserID)
-		routes.GET("/", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin", "ormawa"), invitationController.GetAllInvitations)
-		routes.POST("/", middleware.Authenticate(jwtService), middleware.RoleMiddleware("ormawa"), invitationController.Create)
-		routes.PATCH("/:id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("ormawa"), invitationController.Update)
-		routes.DELETE("/:id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("ormawa"), invitationController.Delete)
-		routes.POST("/scan/:qr_code", invitationController.ScanQRCode) // Added for QR Code Scan
+		routes.GET("/:id", middleware.Authenticate(jwtService, apiKeyService), invitationController.GetInvitationByID)
+		routes.GET("/event/:event_id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "ormawa"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee", "scanner"), invitationController.GetInvitationByEventID)
+		routes.GET("/user/:userId", middleware.Authenticate(jwtService, apiKeyService), invitationController.GetInvitationByUserID)
+		routes.GET("/", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "ormawa"), invitationController.GetAllInvitations)
+		routes.POST("/", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee"), invitationController.Create)
+		routes.PATCH("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee"), invitationController.Update)
+		routes.DELETE("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("ormawa"), middleware.OrganizationRoleMiddleware("chair", "secretary", "committee"), invitationController.Delete)
+		routes.POST("/scan/:qr_code", scanLimit, invitationController.ScanQRCode) // Added for QR Code Scan
 
 		// New RSVP Routes - No JWT authentication, token in path is used
-		routes.GET("/rsvp/accept/:token", invitationController.AcceptRSVP)
-		routes.GET("/rsvp/decline/:token", invitationController.DeclineRSVP)
+		routes.GET("/rsvp/accept/:token", rsvpLimit, invitationController.AcceptRSVP)
+		routes.GET("/rsvp/accept/:token/:mode", rsvpLimit, invitationController.AcceptRSVP) // hybrid events: in-person or online
+		routes.GET("/rsvp/decline/:token", rsvpLimit, invitationController.DeclineRSVP)
+
+		// Online events - the invitee's token doubles as their join link
+		routes.GET("/join/:token", joinLimit, invitationController.JoinOnlineEvent)
 	}
 }
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/miraicantsleep/myits-event-be/middleware"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+)
+
+func Job(route *gin.Engine, injector *do.Injector) {
+	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	apiKeyService := do.MustInvokeNamed[service.APIKeyService](injector, constants.APIKeyService)
+	jobController := do.MustInvoke[controller.JobController](injector)
+
+	routes := route.Group("/api/admin/jobs", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin"))
+	{
+		routes.GET("/", jobController.GetAll)
+		routes.GET("/:name/runs", jobController.GetRuns)
+		routes.POST("/:name/run", jobController.Trigger)
+	}
+}
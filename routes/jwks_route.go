@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/samber/do"
+)
+
+func JWKS(route *gin.Engine, injector *do.Injector) {
+	jwksController := do.MustInvoke[controller.JWKSController](injector)
+
+	route.GET("/.well-known/jwks.json", jwksController.GetJWKS)
+	route.GET("/api/auth/jwks", jwksController.GetJWKS)
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/miraicantsleep/myits-event-be/middleware"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+)
+
+func Notification(route *gin.Engine, injector *do.Injector) {
+	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	apiKeyService := do.MustInvokeNamed[service.APIKeyService](injector, constants.APIKeyService)
+	notificationController := do.MustInvoke[controller.NotificationController](injector)
+
+	routes := route.Group("/api/notifications")
+	{
+		routes.GET("/", middleware.Authenticate(jwtService, apiKeyService), notificationController.GetAll)
+		routes.GET("/unread-count", middleware.Authenticate(jwtService, apiKeyService), notificationController.GetUnreadCount)
+		routes.PATCH("/read-all", middleware.Authenticate(jwtService, apiKeyService), notificationController.MarkAllRead)
+		routes.PATCH("/:id/read", middleware.Authenticate(jwtService, apiKeyService), notificationController.MarkRead)
+		routes.GET("/stream", middleware.TokenFromQuery("access_token"), middleware.Authenticate(jwtService, apiKeyService), notificationController.Stream)
+	}
+}
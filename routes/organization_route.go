@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/miraicantsleep/myits-event-be/middleware"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+)
+
+func Organization(route *gin.Engine, injector *do.Injector) {
+	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	apiKeyService := do.MustInvokeNamed[service.APIKeyService](injector, constants.APIKeyService)
+	organizationController := do.MustInvoke[controller.OrganizationController](injector)
+
+	routes := route.Group("/api/organization")
+	{
+		// Organization
+		routes.POST("/", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin"), organizationController.Create)
+		routes.GET("/", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin"), organizationController.GetAllOrganizations)
+		routes.GET("/mine", middleware.Authenticate(jwtService, apiKeyService), organizationController.GetMyOrganizations)
+		routes.GET("/:id", middleware.Authenticate(jwtService, apiKeyService), organizationController.GetOrganizationByID)
+		routes.PATCH("/:id", middleware.Authenticate(jwtService, apiKeyService), organizationController.Update)
+		routes.POST("/:id/switch", middleware.Authenticate(jwtService, apiKeyService), organizationController.SwitchOrganization)
+
+		// Members
+		routes.GET("/:id/members", middleware.Authenticate(jwtService, apiKeyService), organizationController.GetMembers)
+		routes.POST("/:id/members", middleware.Authenticate(jwtService, apiKeyService), organizationController.AddMember)
+		routes.PATCH("/:id/members/:user_id", middleware.Authenticate(jwtService, apiKeyService), organizationController.UpdateMember)
+		routes.DELETE("/:id/members/:user_id", middleware.Authenticate(jwtService, apiKeyService), organizationController.RemoveMember)
+	}
+}
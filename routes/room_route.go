@@ -11,15 +11,29 @@ import (
 
 func Room(route *gin.Engine, injector *do.Injector) {
 	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	apiKeyService := do.MustInvokeNamed[service.APIKeyService](injector, constants.APIKeyService)
 	roomController := do.MustInvoke[controller.RoomController](injector)
 
 	routes := route.Group("/api/room")
 	{
 		// Room
-		routes.POST("/", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin", "departemen"), roomController.Create)
-		routes.GET("/", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin", "departemen", "ormawa"), roomController.GetAllRoom)
-		routes.GET("/:id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin", "departemen"), roomController.GetRoomByID)
-		routes.PATCH("/:id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin", "departemen"), roomController.Update)
-		routes.DELETE("/:id", middleware.Authenticate(jwtService), middleware.RoleMiddleware("admin", "departemen"), roomController.Delete)
+		routes.POST("/", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), roomController.Create)
+		routes.GET("/", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen", "ormawa"), roomController.GetAllRoom)
+		routes.GET("/available", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen", "ormawa"), roomController.GetAvailableRooms)
+		routes.GET("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), roomController.GetRoomByID)
+		routes.PATCH("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), roomController.Update)
+		routes.DELETE("/:id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), roomController.Delete)
+
+		// Schedule
+		routes.GET("/:id/operating-hours", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen", "ormawa"), roomController.GetOperatingHours)
+		routes.PUT("/:id/operating-hours", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), roomController.SetOperatingHours)
+		routes.GET("/:id/blackouts", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen", "ormawa"), roomController.GetBlackouts)
+		routes.POST("/:id/blackouts", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), roomController.CreateBlackout)
+		routes.DELETE("/:id/blackouts/:blackout_id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), roomController.DeleteBlackout)
+
+		// Images
+		routes.GET("/:id/images", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen", "ormawa"), roomController.GetImages)
+		routes.POST("/:id/images", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), roomController.UploadImages)
+		routes.DELETE("/:id/images/:image_id", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin", "departemen"), roomController.DeleteImage)
 	}
 }
@@ -7,9 +7,18 @@ import (
 
 func RegisterRoutes(server *gin.Engine, injector *do.Injector) {
 	User(server, injector)
+	JWKS(server, injector)
+	APIKey(server, injector)
+	Job(server, injector)
 	Department(server, injector)
+	Organization(server, injector)
 	Event(server, injector)
 	Room(server, injector)
+	Equipment(server, injector)
 	Invitation(server, injector)
 	BookingRequest(server, injector)
+	Notification(server, injector)
+	Webhook(server, injector)
+	Audit(server, injector)
+	Log(server, injector)
 }
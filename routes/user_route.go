@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/miraicantsleep/myits-event-be/constants"
 	"github.com/miraicantsleep/myits-event-be/controller"
@@ -11,16 +13,70 @@ import (
 
 func User(route *gin.Engine, injector *do.Injector) {
 	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	apiKeyService := do.MustInvokeNamed[service.APIKeyService](injector, constants.APIKeyService)
 	userController := do.MustInvoke[controller.UserController](injector)
+	sessionController := do.MustInvoke[controller.SessionController](injector)
+	oidcController := do.MustInvoke[controller.OIDCController](injector)
+	rateLimitStore := do.MustInvokeNamed[middleware.RateLimitStore](injector, constants.RateLimitStore)
+
+	registerLimit := middleware.RateLimit(middleware.RateLimitConfig{
+		Name: "auth-register", Limit: 20, Period: time.Hour, Key: middleware.RateLimitByIP, Store: rateLimitStore,
+	})
+	// tokens sent by mail are guessable by brute force
+	tokenLimit := middleware.RateLimit(middleware.RateLimitConfig{
+		Name: "auth-token", Limit: 20, Period: time.Minute, Key: middleware.RateLimitByIP, Store: rateLimitStore,
+	})
+	// endpoints that send mail are limited per IP and per recipient, so an
+	// address cannot be flooded from many IPs
+	mailIPLimit := middleware.RateLimit(middleware.RateLimitConfig{
+		Name: "auth-mail-ip", Limit: 10, Period: time.Hour, Key: middleware.RateLimitByIP, Store: rateLimitStore,
+	})
+	mailEmailLimit := middleware.RateLimit(middleware.RateLimitConfig{
+		Name: "auth-mail-email", Limit: 3, Period: time.Hour, Key: middleware.RateLimitByEmail, Store: rateLimitStore,
+	})
 
 	routes := route.Group("/api/auth")
 	{
 		// User
-		routes.POST("/register", userController.Register)
+		routes.POST("/register", registerLimit, userController.Register)
 		routes.POST("/login", userController.Login)
-		routes.DELETE("/delete", middleware.Authenticate(jwtService), userController.Delete)
-		routes.PATCH("/update", middleware.Authenticate(jwtService), userController.Update)
-		routes.GET("/me", middleware.Authenticate(jwtService), userController.Me)
-		routes.GET("/all", middleware.Authenticate(jwtService), userController.GetAllUser)
+		routes.POST("/send-verification-email", mailIPLimit, mailEmailLimit, userController.SendVerificationEmail)
+		routes.POST("/verify-email", tokenLimit, userController.VerifyEmail)
+		routes.POST("/forgot-password", mailIPLimit, mailEmailLimit, userController.ForgotPassword)
+		routes.POST("/reset-password", tokenLimit, userController.ResetPassword)
+		routes.DELETE("/delete", middleware.Authenticate(jwtService, apiKeyService), userController.Delete)
+		routes.PATCH("/update", middleware.Authenticate(jwtService, apiKeyService), userController.Update)
+		routes.GET("/me", middleware.Authenticate(jwtService, apiKeyService), userController.Me)
+		routes.GET("/all", middleware.Authenticate(jwtService, apiKeyService), userController.GetAllUser)
+
+		// Sessions
+		routes.POST("/refresh", sessionController.Refresh)
+		routes.POST("/logout", middleware.Authenticate(jwtService, apiKeyService), sessionController.Logout)
+		routes.GET("/sessions", middleware.Authenticate(jwtService, apiKeyService), sessionController.GetMySessions)
+		routes.DELETE("/sessions/:id", middleware.Authenticate(jwtService, apiKeyService), sessionController.RevokeMySession)
+
+		// SSO (OpenID Connect)
+		routes.GET("/oidc/login", oidcController.Login)
+		routes.GET("/oidc/authorize", oidcController.Authorize)
+		routes.POST("/oidc/callback", oidcController.Callback)
+	}
+
+	// Account management
+	admin := route.Group("/api/admin/users", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin"))
+	{
+		admin.POST("/", userController.CreateAccount)
+		admin.PATCH("/:id/role", userController.ChangeRole)
+		admin.PATCH("/:id/disable", userController.DisableUser)
+		admin.PATCH("/:id/enable", userController.EnableUser)
+		admin.GET("/:id/role-changes", userController.GetRoleChanges)
+		admin.PATCH("/:id/unlock", userController.UnlockLogin)
+		admin.GET("/:id/sessions", sessionController.GetUserSessions)
+		admin.DELETE("/:id/sessions", sessionController.RevokeUserSessions)
+	}
+
+	// Login lockouts of a client IP; per-account lockouts are lifted above
+	loginLocks := route.Group("/api/admin/login-locks", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin"))
+	{
+		loginLocks.DELETE("/ip/:ip", userController.UnlockIP)
 	}
 }
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/controller"
+	"github.com/miraicantsleep/myits-event-be/middleware"
+	"github.com/miraicantsleep/myits-event-be/service"
+	"github.com/samber/do"
+)
+
+func Webhook(route *gin.Engine, injector *do.Injector) {
+	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	apiKeyService := do.MustInvokeNamed[service.APIKeyService](injector, constants.APIKeyService)
+	webhookController := do.MustInvoke[controller.WebhookController](injector)
+
+	routes := route.Group("/api/admin/webhooks", middleware.Authenticate(jwtService, apiKeyService), middleware.RoleMiddleware("admin"))
+	{
+		routes.POST("/", webhookController.Create)
+		routes.GET("/", webhookController.GetAll)
+		routes.GET("/:id", webhookController.GetByID)
+		routes.PATCH("/:id", webhookController.Update)
+		routes.DELETE("/:id", webhookController.Delete)
+		routes.GET("/:id/deliveries", webhookController.GetDeliveries)
+		routes.POST("/:id/deliveries/:delivery_id/redeliver", webhookController.Redeliver)
+		routes.POST("/:id/ping", webhookController.Ping)
+	}
+}
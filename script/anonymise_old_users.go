@@ -0,0 +1,94 @@
+package script
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"gorm.io/gorm"
+)
+
+// anonymisedEmailHost marks accounts that were already anonymised
+const anonymisedEmailHost = "anonymised.invalid"
+
+func init() {
+	Register(Script{
+		Name:        "anonymise_old_users",
+		Description: "strip personal data from student accounts inactive for a long time, keeping their attendance history",
+		Args: []Arg{
+			{Name: "inactive_days", Description: "days without a login, profile change or attended event", Default: "730"},
+			{Name: "batch", Description: "accounts anonymised per statement", Default: "500"},
+		},
+		Run: runAnonymiseOldUsers,
+	})
+}
+
+func runAnonymiseOldUsers(ctx *Context) error {
+	inactiveDays, err := ctx.Int("inactive_days")
+	if err != nil {
+		return err
+	}
+	batch, err := ctx.Int("batch")
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().AddDate(0, 0, -inactiveDays)
+
+	// soft-deleted accounts are included. Organization members are left
+	// alone, their names appear on the organization's history.
+	var userIDs []uuid.UUID
+	err = ctx.DB.WithContext(ctx).
+		Unscoped().
+		Model(&entity.User{}).
+		Where("role = ? AND updated_at < ? AND email NOT LIKE ?", entity.RoleUser, cutoff, "%@"+anonymisedEmailHost).
+		Where("NOT EXISTS (SELECT 1 FROM refresh_tokens r WHERE r.user_id = users.id AND r.last_used_at >= ?)", cutoff).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM user_invitation ui
+			JOIN invitations i ON i.id = ui.invitation_id
+			JOIN events e ON e.id = i.event_id
+			WHERE ui.user_id = users.id AND e.end_time >= ?)`, cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM organization_members m WHERE m.user_id = users.id)").
+		Order("updated_at").
+		Pluck("id", &userIDs).Error
+	if err != nil {
+		return err
+	}
+	ctx.Logf("%d accounts inactive since %s", len(userIDs), cutoff.Format(time.DateOnly))
+
+	for start := 0; start < len(userIDs); start += batch {
+		ids := userIDs[start:min(start+batch, len(userIDs))]
+		if err := anonymiseUsers(ctx, ids); err != nil {
+			return err
+		}
+		ctx.Progress(start+len(ids), len(userIDs))
+	}
+
+	return nil
+}
+
+// anonymiseUsers replaces the personal data of the users and removes every
+// way to sign in as them, deleting rather than soft-deleting the rows that
+// hold personal data. Invitations stay so event statistics are unchanged.
+func anonymiseUsers(ctx *Context, ids []uuid.UUID) error {
+	tx := ctx.DB.WithContext(ctx).Unscoped()
+
+	// "!" is never a valid bcrypt hash, so no password matches it
+	err := tx.Model(&entity.User{}).Where("id IN ?", ids).UpdateColumns(map[string]any{
+		"name":        "Anonymised User",
+		"email":       gorm.Expr("'anonymised+' || id || ?", "@"+anonymisedEmailHost),
+		"password":    "!",
+		"is_verified": false,
+		"disabled_at": time.Now(),
+		"updated_at":  time.Now(),
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	for _, model := range []any{&entity.RefreshToken{}, &entity.UserToken{}, &entity.UserIdentity{}} {
+		if err := tx.Where("user_id IN ?", ids).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
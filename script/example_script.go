@@ -2,24 +2,18 @@ package script
 
 import (
 	"fmt"
-
-	"gorm.io/gorm"
-)
-
-type (
-	ExampleScript struct {
-		db *gorm.DB
-	}
 )
 
-func NewExampleScript(db *gorm.DB) *ExampleScript {
-	return &ExampleScript{
-		db: db,
-	}
+func init() {
+	Register(Script{
+		Name:        "example_script",
+		Description: "template for new scripts",
+		Run:         runExampleScript,
+	})
 }
 
-func (s *ExampleScript) Run() error {
+func runExampleScript(ctx *Context) error {
 	// your script here
 	fmt.Println("example script running")
 	return nil
-}
\ No newline at end of file
+}
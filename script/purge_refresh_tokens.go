@@ -0,0 +1,30 @@
+package script
+
+import (
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+)
+
+func init() {
+	Register(Script{
+		Name:        "purge_refresh_tokens",
+		Description: "delete refresh tokens that have expired",
+		Run:         runPurgeRefreshTokens,
+	})
+}
+
+func runPurgeRefreshTokens(ctx *Context) error {
+	var expired int64
+	if err := ctx.DB.WithContext(ctx).Model(&entity.RefreshToken{}).Where("expires_at < ?", time.Now()).Count(&expired).Error; err != nil {
+		return err
+	}
+
+	if err := repository.NewRefreshTokenRepository(ctx.DB).DeleteExpired(ctx, nil); err != nil {
+		return err
+	}
+
+	ctx.Logf("deleted %d expired refresh tokens", expired)
+	return nil
+}
@@ -0,0 +1,24 @@
+package script
+
+func init() {
+	Register(Script{
+		Name:        "recompute_event_durations",
+		Description: "recompute duration_in_minutes of events whose stored value is out of date",
+		Run:         runRecomputeEventDurations,
+	})
+}
+
+func runRecomputeEventDurations(ctx *Context) error {
+	// trg_set_event_duration computes the same value on update, the explicit
+	// assignment keeps the script correct if the trigger is missing
+	result := ctx.DB.WithContext(ctx).Exec(`
+		UPDATE events
+		SET duration_in_minutes = EXTRACT(EPOCH FROM (end_time - start_time)) / 60
+		WHERE duration_in_minutes IS DISTINCT FROM (EXTRACT(EPOCH FROM (end_time - start_time)) / 60)::integer`)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	ctx.Logf("updated the duration of %d events", result.RowsAffected)
+	return nil
+}
@@ -0,0 +1,53 @@
+package script
+
+import (
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/miraicantsleep/myits-event-be/service"
+)
+
+func init() {
+	Register(Script{
+		Name:        "resend_failed_invitations",
+		Description: "resend invitation emails that failed to send, for events that have not ended",
+		Args: []Arg{
+			{Name: "limit", Description: "maximum number of emails to send", Default: "500"},
+		},
+		Run: runResendFailedInvitations,
+	})
+}
+
+func runResendFailedInvitations(ctx *Context) error {
+	limit, err := ctx.Int("limit")
+	if err != nil {
+		return err
+	}
+
+	invitationRepo := repository.NewInvitationRepository(ctx.DB)
+	failed, err := invitationRepo.GetFailedEmails(ctx, nil, limit)
+	if err != nil {
+		return err
+	}
+	ctx.Logf("%d failed invitation emails", len(failed))
+	if ctx.DryRun {
+		return nil
+	}
+
+	// the invitation service does not use its JWTService
+	invitationService := service.NewInvitationService(invitationRepo, repository.NewEventRepository(ctx.DB), nil, service.NewEventBus(repository.NewOutboxRepository(ctx.DB)), ctx.DB)
+
+	sent := 0
+	for i, userInvitation := range failed {
+		// failures are recorded on the invitation and retried on the next run
+		if err := invitationService.ResendInvitationEmail(ctx, userInvitation.InvitationID, userInvitation.UserID); err != nil {
+			ctx.Logf("user %s, invitation %s: %v", userInvitation.UserID, userInvitation.InvitationID, err)
+		} else {
+			sent++
+		}
+		if (i+1)%50 == 0 || i+1 == len(failed) {
+			ctx.Progress(i+1, len(failed))
+		}
+	}
+
+	ctx.Logf("sent %d of %d emails", sent, len(failed))
+	return nil
+}
@@ -1,17 +1,153 @@
 package script
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
 
 	"gorm.io/gorm"
 )
 
-func Script(scriptName string, db *gorm.DB) error {
-	switch scriptName {
-	case "example_script":
-		exampleScript := NewExampleScript(db)
-		return exampleScript.Run()
-	default:
-		return errors.New("script not found")
+var (
+	ErrUnknownScript = errors.New("script not found")
+	ErrUnknownArg    = errors.New("unknown script argument")
+
+	// errDryRun rolls the transaction of a dry run back
+	errDryRun = errors.New("dry run")
+
+	registry = map[string]Script{}
+)
+
+type (
+	// Script is a maintenance task run with --script:<name>. Scripts register
+	// themselves from init, so adding a file is all it takes to add one.
+	Script struct {
+		Name        string
+		Description string
+		Args        []Arg
+		Run         func(ctx *Context) error
+	}
+
+	// Arg is a named argument passed as name=value after --script:<name>
+	Arg struct {
+		Name        string
+		Description string
+		Default     string
+	}
+
+	// Context is the state of one run. DB is a transaction that is committed
+	// when Run succeeds, or rolled back on error and on dry runs. Side effects
+	// outside the database, like sending email, must be skipped when DryRun
+	// is set.
+	Context struct {
+		context.Context
+		DB     *gorm.DB
+		DryRun bool
+
+		script Script
+		args   map[string]string
+	}
+)
+
+// Register adds a script to the registry and panics on duplicate names
+func Register(script Script) {
+	if _, ok := registry[script.Name]; ok {
+		panic("script: duplicate script " + script.Name)
+	}
+	registry[script.Name] = script
+}
+
+// Scripts returns the registered scripts sorted by name
+func Scripts() []Script {
+	scripts := make([]Script, 0, len(registry))
+	for _, script := range registry {
+		scripts = append(scripts, script)
+	}
+	sort.Slice(scripts, func(i, j int) bool { return scripts[i].Name < scripts[j].Name })
+	return scripts
+}
+
+// Run runs the named script in one transaction
+func Run(db *gorm.DB, name string, args map[string]string, dryRun bool) error {
+	script, ok := registry[name]
+	if !ok {
+		return ErrUnknownScript
+	}
+	for key := range args {
+		if _, ok := script.arg(key); !ok {
+			return fmt.Errorf("%w %q for %s", ErrUnknownArg, key, name)
+		}
+	}
+
+	if dryRun {
+		slog.Info("dry run, changes will be rolled back", "script", name)
+	}
+
+	started := time.Now()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		ctx := &Context{Context: context.Background(), DB: tx, DryRun: dryRun, script: script, args: args}
+		if err := script.Run(ctx); err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	slog.Info("script finished", "script", name, "latency_ms", time.Since(started).Milliseconds())
+	return nil
+}
+
+func (s Script) arg(name string) (Arg, bool) {
+	for _, arg := range s.Args {
+		if arg.Name == name {
+			return arg, true
+		}
+	}
+	return Arg{}, false
+}
+
+// String returns the value of an argument, or its default when not given
+func (c *Context) String(name string) string {
+	if value, ok := c.args[name]; ok {
+		return value
+	}
+	arg, ok := c.script.arg(name)
+	if !ok {
+		panic("script: " + c.script.Name + " reads undeclared argument " + name)
+	}
+	return arg.Default
+}
+
+// Int returns the value of a positive integer argument
+func (c *Context) Int(name string) (int, error) {
+	n, err := strconv.Atoi(c.String(name))
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("argument %s must be a positive number", name)
+	}
+	return n, nil
+}
+
+// Logf logs a line tagged with the script name
+func (c *Context) Logf(format string, args ...any) {
+	slog.InfoContext(c, fmt.Sprintf(format, args...), "script", c.script.Name)
+}
+
+// Progress reports how many of total items are done
+func (c *Context) Progress(done, total int) {
+	if total == 0 {
+		return
 	}
-}
\ No newline at end of file
+	c.Logf("%d/%d (%d%%)", done, total, done*100/total)
+}
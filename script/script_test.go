@@ -0,0 +1,119 @@
+package script
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testScript = Script{
+	Name: "test-script",
+	Args: []Arg{
+		{Name: "days", Default: "30"},
+		{Name: "email"},
+	},
+}
+
+func Test_Run_RejectsBeforeRunning(t *testing.T) {
+	Register(Script{Name: "test-run", Args: testScript.Args, Run: func(ctx *Context) error {
+		t.Fatal("the script must not run")
+		return nil
+	}})
+	t.Cleanup(func() { delete(registry, "test-run") })
+
+	tests := []struct {
+		name    string
+		script  string
+		args    map[string]string
+		wantErr error
+	}{
+		{"unknown script", "missing-script", nil, ErrUnknownScript},
+		{"unknown argument", "test-run", map[string]string{"day": "3"}, ErrUnknownArg},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// both checks run before the database is touched
+			assert.ErrorIs(t, Run(nil, tt.script, tt.args, false), tt.wantErr)
+		})
+	}
+}
+
+func Test_Register_Duplicate(t *testing.T) {
+	Register(Script{Name: "test-duplicate"})
+	t.Cleanup(func() { delete(registry, "test-duplicate") })
+
+	assert.Panics(t, func() { Register(Script{Name: "test-duplicate"}) })
+}
+
+func Test_Scripts_Sorted(t *testing.T) {
+	scripts := Scripts()
+	assert.NotEmpty(t, scripts)
+	assert.True(t, sort.SliceIsSorted(scripts, func(i, j int) bool { return scripts[i].Name < scripts[j].Name }))
+}
+
+func Test_Context_String(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]string
+		arg  string
+		want string
+	}{
+		{"given", map[string]string{"days": "7"}, "days", "7"},
+		{"default", nil, "days", "30"},
+		{"no default", nil, "email", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &Context{script: testScript, args: tt.args}
+			assert.Equal(t, tt.want, ctx.String(tt.arg))
+		})
+	}
+
+	t.Run("undeclared", func(t *testing.T) {
+		ctx := &Context{script: testScript}
+		assert.Panics(t, func() { ctx.String("undeclared") })
+	})
+}
+
+func Test_Context_Int(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"positive", "7", 7, false},
+		{"zero", "0", 0, true},
+		{"negative", "-1", 0, true},
+		{"not a number", "seven", 0, true},
+		{"empty", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &Context{script: testScript, args: map[string]string{"days": tt.value}}
+			n, err := ctx.Int("days")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, n)
+		})
+	}
+}
+
+// every registered script declares each argument once and can be run
+func Test_RegisteredScripts_Args(t *testing.T) {
+	for _, script := range Scripts() {
+		seen := make(map[string]bool)
+		for _, arg := range script.Args {
+			assert.False(t, seen[arg.Name], "%s declares %s twice", script.Name, arg.Name)
+			seen[arg.Name] = true
+		}
+		assert.NotNil(t, script.Run, script.Name)
+	}
+}
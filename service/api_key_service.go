@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"gorm.io/gorm"
+)
+
+// apiKeyTouchInterval limits last-used tracking to one write per key per
+// interval instead of one per request
+const apiKeyTouchInterval = time.Minute
+
+type (
+	APIKeyService interface {
+		Create(ctx context.Context, req dto.APIKeyCreateRequest, createdBy string) (dto.APIKeyCreateResponse, error)
+		GetAll(ctx context.Context) ([]dto.APIKeyResponse, error)
+		GetByID(ctx context.Context, keyId string) (dto.APIKeyResponse, error)
+		Revoke(ctx context.Context, keyId string) error
+		// Authenticate resolves a key for a request to route (the gin route
+		// pattern) with the given method, checking its scopes
+		Authenticate(ctx context.Context, key string, method string, route string, ip string) (APIKeyPrincipal, error)
+	}
+
+	// APIKeyPrincipal is who a request made with an API key acts as
+	APIKeyPrincipal struct {
+		KeyID        string
+		UserID       string
+		Role         string
+		Organization *ActiveOrganization
+	}
+
+	apiKeyService struct {
+		apiKeyRepo       repository.APIKeyRepository
+		userRepo         repository.UserRepository
+		organizationRepo repository.OrganizationRepository
+		db               *gorm.DB
+	}
+)
+
+func NewAPIKeyService(
+	apiKeyRepo repository.APIKeyRepository,
+	userRepo repository.UserRepository,
+	organizationRepo repository.OrganizationRepository,
+	db *gorm.DB,
+) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo:       apiKeyRepo,
+		userRepo:         userRepo,
+		organizationRepo: organizationRepo,
+		db:               db,
+	}
+}
+
+func (s *apiKeyService) Create(ctx context.Context, req dto.APIKeyCreateRequest, createdBy string) (dto.APIKeyCreateResponse, error) {
+	for _, scope := range req.Scopes {
+		if !validAPIKeyScope(scope) {
+			return dto.APIKeyCreateResponse{}, dto.ErrAPIKeyScopeInvalid
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return dto.APIKeyCreateResponse{}, dto.ErrAPIKeyExpiresAt
+	}
+	creatorUUID, err := uuid.Parse(createdBy)
+	if err != nil {
+		return dto.APIKeyCreateResponse{}, err
+	}
+
+	owner, err := s.userRepo.GetUserById(ctx, nil, req.UserID)
+	if err != nil {
+		return dto.APIKeyCreateResponse{}, dto.ErrUserNotFound
+	}
+	if owner.DisabledAt != nil {
+		return dto.APIKeyCreateResponse{}, dto.ErrAccountDisabled
+	}
+
+	key := entity.APIKey{
+		Name:      req.Name,
+		UserID:    owner.ID,
+		Scopes:    strings.Join(req.Scopes, " "),
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: creatorUUID,
+	}
+
+	// a key acting for an organization needs its owner to be a member
+	if req.OrganizationID != "" {
+		organizationId, err := uuid.Parse(req.OrganizationID)
+		if err != nil {
+			return dto.APIKeyCreateResponse{}, err
+		}
+		if _, err := s.organizationRepo.GetMember(ctx, nil, organizationId, owner.ID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dto.APIKeyCreateResponse{}, dto.ErrOrganizationMemberMissing
+			}
+			return dto.APIKeyCreateResponse{}, err
+		}
+		key.OrganizationID = &organizationId
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return dto.APIKeyCreateResponse{}, err
+	}
+	plain := entity.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	key.KeyHash = hashToken(plain)
+	key.Prefix = plain[:len(entity.APIKeyPrefix)+8]
+
+	key, err = s.apiKeyRepo.Create(ctx, nil, key)
+	if err != nil {
+		return dto.APIKeyCreateResponse{}, err
+	}
+
+	return dto.APIKeyCreateResponse{
+		APIKeyResponse: toAPIKeyResponse(key),
+		Key:            plain,
+	}, nil
+}
+
+func (s *apiKeyService) GetAll(ctx context.Context) ([]dto.APIKeyResponse, error) {
+	keys, err := s.apiKeyRepo.GetAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		responses = append(responses, toAPIKeyResponse(key))
+	}
+	return responses, nil
+}
+
+func (s *apiKeyService) GetByID(ctx context.Context, keyId string) (dto.APIKeyResponse, error) {
+	if _, err := uuid.Parse(keyId); err != nil {
+		return dto.APIKeyResponse{}, dto.ErrAPIKeyNotFound
+	}
+
+	key, err := s.apiKeyRepo.GetByID(ctx, nil, keyId)
+	if err != nil {
+		return dto.APIKeyResponse{}, dto.ErrAPIKeyNotFound
+	}
+	return toAPIKeyResponse(key), nil
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, keyId string) error {
+	key, err := s.GetByID(ctx, keyId)
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		return dto.ErrAPIKeyRevoked
+	}
+
+	return s.apiKeyRepo.Revoke(ctx, nil, keyId, time.Now())
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, plain string, method string, route string, ip string) (APIKeyPrincipal, error) {
+	key, err := s.apiKeyRepo.FindByHash(ctx, nil, hashToken(plain))
+	if err != nil {
+		return APIKeyPrincipal{}, dto.ErrAPIKeyInvalid
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil {
+		return APIKeyPrincipal{}, dto.ErrAPIKeyRevoked
+	}
+	if key.ExpiresAt != nil && key.ExpiresAt.Before(now) {
+		return APIKeyPrincipal{}, dto.ErrAPIKeyExpired
+	}
+	if key.User.DisabledAt != nil {
+		return APIKeyPrincipal{}, dto.ErrAccountDisabled
+	}
+	if !apiKeyAllows(key.Scopes, method, route) {
+		return APIKeyPrincipal{}, dto.ErrAPIKeyScope
+	}
+
+	principal := APIKeyPrincipal{
+		KeyID:  key.ID.String(),
+		UserID: key.UserID.String(),
+		Role:   string(key.User.Role),
+	}
+
+	// the membership is checked on every request, so removing the owner from
+	// the organization takes the key's access with it
+	if key.OrganizationID != nil {
+		member, err := s.organizationRepo.GetMember(ctx, nil, *key.OrganizationID, key.UserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return APIKeyPrincipal{}, dto.ErrOrganizationMemberMissing
+		}
+		if err != nil {
+			return APIKeyPrincipal{}, err
+		}
+		principal.Role = string(entity.RoleOrmawa)
+		principal.Organization = &ActiveOrganization{ID: member.OrganizationID.String(), Role: member.Role}
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval || key.LastUsedIP != ip {
+		if err := s.apiKeyRepo.Touch(ctx, nil, key.ID.String(), now, ip); err != nil {
+			slog.ErrorContext(ctx, "failed to record api key use", "api_key_id", key.ID, "error", err)
+		}
+	}
+
+	return principal, nil
+}
+
+func validAPIKeyScope(scope string) bool {
+	action, pattern, ok := strings.Cut(scope, ":")
+	if !ok || !strings.HasPrefix(pattern, "/api/") {
+		return false
+	}
+	return action == "read" || action == "write" || action == "*"
+}
+
+// apiKeyAllows reports whether any scope covers the request. read covers
+// GET and HEAD, write every other method.
+func apiKeyAllows(scopes string, method string, route string) bool {
+	read := method == http.MethodGet || method == http.MethodHead
+	for _, scope := range strings.Fields(scopes) {
+		action, pattern, _ := strings.Cut(scope, ":")
+		switch {
+		case action == "*":
+		case action == "read" && read:
+		case action == "write" && !read:
+		default:
+			continue
+		}
+
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+			if route == prefix || strings.HasPrefix(route, prefix+"/") {
+				return true
+			}
+		} else if route == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func toAPIKeyResponse(key entity.APIKey) dto.APIKeyResponse {
+	response := dto.APIKeyResponse{
+		ID:         key.ID.String(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		UserID:     key.UserID.String(),
+		Scopes:     strings.Fields(key.Scopes),
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		LastUsedIP: key.LastUsedIP,
+		RevokedAt:  key.RevokedAt,
+		CreatedBy:  key.CreatedBy.String(),
+		CreatedAt:  key.CreatedAt,
+	}
+	if key.OrganizationID != nil {
+		response.OrganizationID = key.OrganizationID.String()
+	}
+	return response
+}
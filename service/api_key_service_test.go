@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// fakeAPIKeyRepository looks keys up by hash, the other methods are not used
+// by Authenticate before it rejects a key
+type fakeAPIKeyRepository struct {
+	repository.APIKeyRepository
+	keys map[string]entity.APIKey
+}
+
+func (r *fakeAPIKeyRepository) FindByHash(ctx context.Context, tx *gorm.DB, keyHash string) (entity.APIKey, error) {
+	key, ok := r.keys[keyHash]
+	if !ok {
+		return entity.APIKey{}, gorm.ErrRecordNotFound
+	}
+	return key, nil
+}
+
+func Test_ValidAPIKeyScope(t *testing.T) {
+	tests := []struct {
+		scope string
+		want  bool
+	}{
+		{"read:/api/event", true},
+		{"write:/api/event/*", true},
+		{"*:/api/*", true},
+		{"admin:/api/event", false},
+		{"read:/event", false},
+		{"read", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			assert.Equal(t, tt.want, validAPIKeyScope(tt.scope))
+		})
+	}
+}
+
+func Test_APIKeyAllows(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes string
+		method string
+		route  string
+		want   bool
+	}{
+		{"read allows GET", "read:/api/event", http.MethodGet, "/api/event", true},
+		{"read allows HEAD", "read:/api/event", http.MethodHead, "/api/event", true},
+		{"read does not allow POST", "read:/api/event", http.MethodPost, "/api/event", false},
+		{"write allows POST", "write:/api/event", http.MethodPost, "/api/event", true},
+		{"write allows DELETE", "write:/api/event", http.MethodDelete, "/api/event", true},
+		{"write does not allow GET", "write:/api/event", http.MethodGet, "/api/event", false},
+		{"any action", "*:/api/event", http.MethodPatch, "/api/event", true},
+		{"exact route only", "read:/api/event", http.MethodGet, "/api/event/:id", false},
+		{"wildcard covers sub routes", "read:/api/event/*", http.MethodGet, "/api/event/:id", true},
+		{"wildcard covers its own route", "read:/api/event/*", http.MethodGet, "/api/event", true},
+		{"wildcard does not cover a longer name", "read:/api/event/*", http.MethodGet, "/api/events", false},
+		{"wildcard does not cover a sibling", "read:/api/event/*", http.MethodGet, "/api/user", false},
+		{"any scope may match", "read:/api/user write:/api/event/*", http.MethodPut, "/api/event/:id", true},
+		{"no scopes", "", http.MethodGet, "/api/event", false},
+		{"unknown action", "admin:/api/event", http.MethodGet, "/api/event", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, apiKeyAllows(tt.scopes, tt.method, tt.route))
+		})
+	}
+}
+
+func Test_APIKeyService_Authenticate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	repo := &fakeAPIKeyRepository{keys: map[string]entity.APIKey{
+		hashToken("revoked"):  {Scopes: "*:/api/event", RevokedAt: &past},
+		hashToken("expired"):  {Scopes: "*:/api/event", ExpiresAt: &past},
+		hashToken("disabled"): {Scopes: "*:/api/event", ExpiresAt: &future, User: entity.User{DisabledAt: &past}},
+		hashToken("scoped"):   {Scopes: "read:/api/room"},
+	}}
+	s := &apiKeyService{apiKeyRepo: repo}
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr error
+	}{
+		{"unknown key", "unknown", dto.ErrAPIKeyInvalid},
+		{"revoked", "revoked", dto.ErrAPIKeyRevoked},
+		{"expired", "expired", dto.ErrAPIKeyExpired},
+		{"owner disabled", "disabled", dto.ErrAccountDisabled},
+		{"out of scope", "scoped", dto.ErrAPIKeyScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Authenticate(context.Background(), tt.key, http.MethodGet, "/api/event", "192.0.2.1")
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
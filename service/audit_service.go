@@ -0,0 +1,535 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	auditBeforeKey = "audit:before"
+	// auditMaxRows bounds the rows recorded for one statement
+	auditMaxRows = 1000
+)
+
+var (
+	// auditedModels are the tables whose writes are recorded. Tokens, rate
+	// limits, job runs, notifications and delivery queues are left out, they
+	// change on their own and carry no decision worth tracing.
+	auditedModels = []any{
+		&entity.User{},
+		&entity.UserIdentity{},
+		&entity.APIKey{},
+		&entity.Department{},
+		&entity.Organization{},
+		&entity.OrganizationMember{},
+		&entity.Event{},
+		&entity.EventAttachment{},
+		&entity.Invitation{},
+		&entity.UserInvitation{},
+		&entity.Room{},
+		&entity.RoomImage{},
+		&entity.OperatingHour{},
+		&entity.BlackoutPeriod{},
+		&entity.Equipment{},
+		&entity.BookingRequest{},
+		&entity.BookingRequestEquipment{},
+		&entity.Webhook{},
+	}
+
+	// auditRedactedColumns never reach the log, a change only shows as redacted
+	auditRedactedColumns = map[string]bool{"password": true, "key_hash": true, "secret": true}
+
+	// auditIgnoredColumns change with every update and are left out of diffs
+	auditIgnoredColumns = map[string]bool{"updated_at": true}
+
+	auditCSVHeader = []string{"id", "created_at", "actor_id", "actor_role", "action", "entity_type", "entity_id", "before", "after", "ip", "request_id", "route"}
+)
+
+type (
+	AuditService interface {
+		GetAll(ctx context.Context, req dto.AuditLogListRequest) (dto.AuditLogPaginationResponse, error)
+		// Export writes the matching logs to w as CSV, oldest first. Nothing
+		// is written when the filter is invalid.
+		Export(ctx context.Context, filter dto.AuditLogFilter, w io.Writer) error
+	}
+
+	// AuditActor describes the request behind a write. It is put in the
+	// request context by the audit middleware, and UserID and Role are filled
+	// in once the request is authenticated.
+	AuditActor struct {
+		UserID    string
+		Role      string
+		IP        string
+		RequestID string
+		Route     string
+	}
+
+	auditActorKey struct{}
+
+	auditService struct {
+		auditRepo repository.AuditRepository
+		db        *gorm.DB
+	}
+
+	// auditor records writes to the audited tables through GORM callbacks
+	auditor struct {
+		auditRepo repository.AuditRepository
+		tables    map[string]bool
+	}
+)
+
+func NewAuditService(auditRepo repository.AuditRepository, db *gorm.DB) AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+		db:        db,
+	}
+}
+
+// WithAuditActor returns a context whose writes are attributed to actor
+func WithAuditActor(ctx context.Context, actor *AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActorFromContext returns the actor of the request, nil outside one
+func AuditActorFromContext(ctx context.Context) *AuditActor {
+	actor, _ := ctx.Value(auditActorKey{}).(*AuditActor)
+	return actor
+}
+
+// RegisterAuditCallbacks makes every create, update and delete on an audited
+// table append to audit_logs in the same transaction, so a change and its log
+// are committed together. The actor comes from the statement context, so
+// repositories must pass the request context with WithContext. Raw SQL
+// statements are not captured.
+func RegisterAuditCallbacks(db *gorm.DB, auditRepo repository.AuditRepository) error {
+	a := &auditor{
+		auditRepo: auditRepo,
+		tables:    make(map[string]bool),
+	}
+	for _, model := range auditedModels {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		a.tables[stmt.Schema.Table] = true
+	}
+
+	// the logs must be written before gorm commits its default transaction
+	const commit = "gorm:commit_or_rollback_transaction"
+	callback := db.Callback()
+	if err := callback.Create().After("gorm:create").Before(commit).Register("audit:after_create", a.afterCreate); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").Register("audit:before_update", a.before); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:update").Before(commit).Register("audit:after_update", a.afterUpdate); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:delete").Register("audit:before_delete", a.before); err != nil {
+		return err
+	}
+	return callback.Delete().After("gorm:delete").Before(commit).Register("audit:after_delete", a.afterDelete)
+}
+
+func (s *auditService) GetAll(ctx context.Context, req dto.AuditLogListRequest) (dto.AuditLogPaginationResponse, error) {
+	logs, pagination, err := s.auditRepo.GetAll(ctx, nil, req.AuditLogFilter, req.PaginationRequest)
+	if err != nil {
+		return dto.AuditLogPaginationResponse{}, err
+	}
+
+	data := make([]dto.AuditLogResponse, len(logs))
+	for i, log := range logs {
+		data[i] = toAuditLogResponse(log)
+	}
+	return dto.AuditLogPaginationResponse{Data: data, PaginationResponse: pagination}, nil
+}
+
+func (s *auditService) Export(ctx context.Context, filter dto.AuditLogFilter, w io.Writer) error {
+	if _, _, err := filter.TimeRange(); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(auditCSVHeader); err != nil {
+		return err
+	}
+
+	written := 0
+	err := s.auditRepo.Each(ctx, nil, filter, func(log entity.AuditLog) error {
+		record := []string{
+			log.ID.String(),
+			log.CreatedAt.Format(time.RFC3339),
+			"",
+			log.ActorRole,
+			log.Action,
+			log.EntityType,
+			log.EntityID,
+			"",
+			"",
+			log.IP,
+			log.RequestID,
+			log.Route,
+		}
+		if log.ActorID != nil {
+			record[2] = log.ActorID.String()
+		}
+		if log.Before != nil {
+			record[7] = *log.Before
+		}
+		if log.After != nil {
+			record[8] = *log.After
+		}
+		for i := range record {
+			record[i] = csvSafe(record[i])
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+
+		written++
+		if written%500 == 0 {
+			writer.Flush()
+			return writer.Error()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvSafe keeps spreadsheets from evaluating a value as a formula
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (a *auditor) audited(db *gorm.DB) bool {
+	return db.Error == nil && a.tables[db.Statement.Table]
+}
+
+// before loads the rows an update or delete is about to change
+func (a *auditor) before(db *gorm.DB) {
+	if !a.audited(db) {
+		return
+	}
+
+	var conditions []clause.Expression
+	if c, ok := db.Statement.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			conditions = append(conditions, where.Exprs...)
+		}
+	}
+	if condition, ok := statementKeyCondition(db.Statement); ok {
+		conditions = append(conditions, condition)
+	}
+	// gorm refuses updates and deletes without conditions anyway
+	if len(conditions) == 0 {
+		return
+	}
+
+	rows, err := a.load(db, conditions...)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	db.InstanceSet(auditBeforeKey, rows)
+}
+
+func (a *auditor) afterCreate(db *gorm.DB) {
+	if !a.audited(db) || db.RowsAffected == 0 {
+		return
+	}
+
+	condition, ok := statementKeyCondition(db.Statement)
+	if !ok {
+		return
+	}
+	rows, err := a.load(db, condition)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	logs := make([]entity.AuditLog, 0, len(rows))
+	for _, row := range rows {
+		logs = append(logs, a.newLog(db, entity.AuditActionCreate, row, nil, redactRow(row)))
+	}
+	a.write(db, logs)
+}
+
+func (a *auditor) afterUpdate(db *gorm.DB) {
+	before := a.beforeRows(db)
+	if !a.audited(db) || db.RowsAffected == 0 || len(before) == 0 {
+		return
+	}
+
+	// reload by key, the update may have changed the columns it matched on
+	after, err := a.load(db, rowsKeyCondition(db.Statement, before))
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	afterByKey := make(map[string]map[string]any, len(after))
+	for _, row := range after {
+		afterByKey[rowKey(db.Statement, row)] = row
+	}
+
+	var logs []entity.AuditLog
+	for _, old := range before {
+		row, ok := afterByKey[rowKey(db.Statement, old)]
+		if !ok {
+			continue
+		}
+		if changedBefore, changedAfter := diffRows(old, row); changedAfter != nil {
+			logs = append(logs, a.newLog(db, entity.AuditActionUpdate, row, changedBefore, changedAfter))
+		}
+	}
+	a.write(db, logs)
+}
+
+func (a *auditor) afterDelete(db *gorm.DB) {
+	before := a.beforeRows(db)
+	if !a.audited(db) || db.RowsAffected == 0 || len(before) == 0 {
+		return
+	}
+
+	logs := make([]entity.AuditLog, 0, len(before))
+	for _, row := range before {
+		logs = append(logs, a.newLog(db, entity.AuditActionDelete, row, redactRow(row), nil))
+	}
+	a.write(db, logs)
+}
+
+func (a *auditor) beforeRows(db *gorm.DB) []map[string]any {
+	value, ok := db.InstanceGet(auditBeforeKey)
+	if !ok {
+		return nil
+	}
+	rows, _ := value.([]map[string]any)
+	return rows
+}
+
+// load reads rows within the transaction of the write
+func (a *auditor) load(db *gorm.DB, conditions ...clause.Expression) ([]map[string]any, error) {
+	var rows []map[string]any
+	err := auditSession(db).
+		Table(db.Statement.Table).
+		Clauses(clause.Where{Exprs: conditions}).
+		Limit(auditMaxRows).
+		Find(&rows).Error
+	for _, row := range rows {
+		for column, value := range row {
+			if b, ok := value.([]byte); ok {
+				row[column] = string(b)
+			}
+		}
+	}
+	return rows, err
+}
+
+func (a *auditor) newLog(db *gorm.DB, action string, row map[string]any, before, after map[string]any) entity.AuditLog {
+	log := entity.AuditLog{
+		ActorRole:  entity.AuditRoleSystem,
+		Action:     action,
+		EntityType: db.Statement.Table,
+		EntityID:   rowKey(db.Statement, row),
+		Before:     marshalAuditRow(before),
+		After:      marshalAuditRow(after),
+		CreatedAt:  time.Now(),
+	}
+
+	if actor := AuditActorFromContext(db.Statement.Context); actor != nil {
+		log.ActorRole = entity.AuditRoleAnonymous
+		log.IP = actor.IP
+		log.RequestID = actor.RequestID
+		log.Route = actor.Route
+		if id, err := uuid.Parse(actor.UserID); err == nil {
+			log.ActorID = &id
+			log.ActorRole = actor.Role
+		}
+	}
+	return log
+}
+
+// write appends the logs within the statement's transaction. A failure fails
+// the statement, a change must not be committed without its log.
+func (a *auditor) write(db *gorm.DB, logs []entity.AuditLog) {
+	if len(logs) == 0 {
+		return
+	}
+	if err := a.auditRepo.Create(db.Statement.Context, auditSession(db), logs); err != nil {
+		db.AddError(fmt.Errorf("audit log: %w", err))
+	}
+}
+
+// auditSession starts a statement on the connection of db, so it runs in the
+// transaction of the audited write. Model(nil) makes the statement fresh right
+// away; a bare NewDB session only does so on the next chained call, and
+// WithContext would copy the audited statement instead.
+func auditSession(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Model(nil)
+}
+
+// auditKeyColumns are the columns that identify a row, the primary key or id
+func auditKeyColumns(stmt *gorm.Statement) []string {
+	if stmt.Schema == nil || len(stmt.Schema.PrimaryFieldDBNames) == 0 {
+		return []string{"id"}
+	}
+	return stmt.Schema.PrimaryFieldDBNames
+}
+
+// statementKeyCondition matches the rows of the statement's model value by
+// primary key, when the value has one
+func statementKeyCondition(stmt *gorm.Statement) (clause.Expression, bool) {
+	if stmt.Schema == nil || len(stmt.Schema.PrimaryFields) == 0 {
+		return nil, false
+	}
+
+	var values []reflect.Value
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Struct:
+		values = append(values, stmt.ReflectValue)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			values = append(values, reflect.Indirect(stmt.ReflectValue.Index(i)))
+		}
+	}
+
+	var rows []map[string]any
+	for _, value := range values {
+		row := make(map[string]any, len(stmt.Schema.PrimaryFields))
+		for _, field := range stmt.Schema.PrimaryFields {
+			v, zero := field.ValueOf(stmt.Context, value)
+			if zero {
+				row = nil
+				break
+			}
+			row[field.DBName] = v
+		}
+		if row != nil {
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		return nil, false
+	}
+	return rowsKeyCondition(stmt, rows), true
+}
+
+// rowsKeyCondition matches the given rows by their key columns
+func rowsKeyCondition(stmt *gorm.Statement, rows []map[string]any) clause.Expression {
+	columns := auditKeyColumns(stmt)
+	matches := make([]clause.Expression, len(rows))
+	for i, row := range rows {
+		eqs := make([]clause.Expression, len(columns))
+		for j, column := range columns {
+			eqs[j] = clause.Eq{Column: clause.Column{Table: stmt.Table, Name: column}, Value: row[column]}
+		}
+		matches[i] = clause.And(eqs...)
+	}
+	return clause.Or(matches...)
+}
+
+// rowKey is the entity id of a row, key columns joined with ":"
+func rowKey(stmt *gorm.Statement, row map[string]any) string {
+	columns := auditKeyColumns(stmt)
+	parts := make([]string, len(columns))
+	for i, column := range columns {
+		parts[i] = fmt.Sprint(row[column])
+	}
+	return strings.Join(parts, ":")
+}
+
+// diffRows returns the columns that differ between two versions of a row,
+// or nil when only ignored columns changed
+func diffRows(before, after map[string]any) (map[string]any, map[string]any) {
+	changedBefore := make(map[string]any)
+	changedAfter := make(map[string]any)
+	for column, value := range after {
+		if auditIgnoredColumns[column] {
+			continue
+		}
+		oldValue, _ := json.Marshal(before[column])
+		newValue, _ := json.Marshal(value)
+		if string(oldValue) == string(newValue) {
+			continue
+		}
+		changedBefore[column] = redactValue(column, before[column])
+		changedAfter[column] = redactValue(column, value)
+	}
+	if len(changedAfter) == 0 {
+		return nil, nil
+	}
+	return changedBefore, changedAfter
+}
+
+func redactRow(row map[string]any) map[string]any {
+	redacted := make(map[string]any, len(row))
+	for column, value := range row {
+		redacted[column] = redactValue(column, value)
+	}
+	return redacted
+}
+
+func redactValue(column string, value any) any {
+	if auditRedactedColumns[column] && value != nil {
+		return "[redacted]"
+	}
+	return value
+}
+
+func marshalAuditRow(row map[string]any) *string {
+	if row == nil {
+		return nil
+	}
+	b, err := json.Marshal(row)
+	if err != nil {
+		return nil
+	}
+	s := string(b)
+	return &s
+}
+
+func toAuditLogResponse(log entity.AuditLog) dto.AuditLogResponse {
+	res := dto.AuditLogResponse{
+		ID:         log.ID.String(),
+		ActorRole:  log.ActorRole,
+		Action:     log.Action,
+		EntityType: log.EntityType,
+		EntityID:   log.EntityID,
+		IP:         log.IP,
+		RequestID:  log.RequestID,
+		Route:      log.Route,
+		CreatedAt:  log.CreatedAt,
+	}
+	if log.ActorID != nil {
+		res.ActorID = log.ActorID.String()
+	}
+	if log.Before != nil {
+		res.Before = json.RawMessage(*log.Before)
+	}
+	if log.After != nil {
+		res.After = json.RawMessage(*log.After)
+	}
+	return res
+}
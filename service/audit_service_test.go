@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CSVSafe(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"alice@example.com", "alice@example.com"},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+1+1", "'+1+1"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"a=1", "a=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, csvSafe(tt.value))
+		})
+	}
+}
+
+func Test_DiffRows(t *testing.T) {
+	tests := []struct {
+		name       string
+		before     map[string]any
+		after      map[string]any
+		wantBefore map[string]any
+		wantAfter  map[string]any
+	}{
+		{
+			name:       "changed columns only",
+			before:     map[string]any{"name": "Seminar", "capacity": 40},
+			after:      map[string]any{"name": "Workshop", "capacity": 40},
+			wantBefore: map[string]any{"name": "Seminar"},
+			wantAfter:  map[string]any{"name": "Workshop"},
+		},
+		{
+			name:   "only ignored columns changed",
+			before: map[string]any{"name": "Seminar", "updated_at": "2026-10-18"},
+			after:  map[string]any{"name": "Seminar", "updated_at": "2026-10-19"},
+		},
+		{
+			name:       "secrets are redacted",
+			before:     map[string]any{"password": "old-hash"},
+			after:      map[string]any{"password": "new-hash"},
+			wantBefore: map[string]any{"password": "[redacted]"},
+			wantAfter:  map[string]any{"password": "[redacted]"},
+		},
+		{
+			name:       "a secret being set",
+			before:     map[string]any{"secret": nil},
+			after:      map[string]any{"secret": "whsec_test"},
+			wantBefore: map[string]any{"secret": nil},
+			wantAfter:  map[string]any{"secret": "[redacted]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after := diffRows(tt.before, tt.after)
+			assert.Equal(t, tt.wantBefore, before)
+			assert.Equal(t, tt.wantAfter, after)
+		})
+	}
+}
+
+func Test_RedactRow(t *testing.T) {
+	row := map[string]any{"email": "alice@example.com", "password": "hash", "key_hash": "hash"}
+	assert.Equal(t, map[string]any{"email": "alice@example.com", "password": "[redacted]", "key_hash": "[redacted]"}, redactRow(row))
+	// the row itself is left untouched
+	assert.Equal(t, "hash", row["password"])
+}
+
+func Test_AuditActorFromContext(t *testing.T) {
+	assert.Nil(t, AuditActorFromContext(context.Background()))
+
+	actor := &AuditActor{IP: "10.0.0.1"}
+	ctx := WithAuditActor(context.Background(), actor)
+	// filled in later by authentication, through the same pointer
+	AuditActorFromContext(ctx).UserID = "alice"
+	assert.Equal(t, "alice", actor.UserID)
+}
@@ -3,6 +3,9 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,11 +17,11 @@ import (
 
 type (
 	BookingRequestService interface {
-		CreateBookingRequest(ctx context.Context, req dto.BookingRequestCreateRequest) (dto.BookingRequestResponse, error)
+		CreateBookingRequest(ctx context.Context, req dto.BookingRequestCreateRequest, organizationId string) (dto.BookingRequestResponse, error)
 		GetBookingRequestByID(ctx context.Context, id string) (dto.BookingRequestResponse, error)
 		GetAllBookingRequests(ctx context.Context) ([]dto.BookingDetailResponse, error)
-		UpdateBookingRequest(ctx context.Context, id string, req dto.BookingRequestUpdateRequest, role string) (dto.BookingRequestResponse, error)
-		DeleteBookingRequest(ctx context.Context, id string) error
+		UpdateBookingRequest(ctx context.Context, id string, req dto.BookingRequestUpdateRequest, role string, organizationId string) (dto.BookingRequestResponse, error)
+		DeleteBookingRequest(ctx context.Context, id string, organizationId string) error
 		ApproveBookingRequest(ctx context.Context, id string) error
 		RejectBookingRequest(ctx context.Context, id string) error
 		GetAllBookingRequestsWithCapacity(ctx context.Context) ([]dto.BookingRequestWithCapacityResponse, error)
@@ -28,7 +31,10 @@ type (
 		bookingRequestRepo repository.BookingRequestRepository
 		roomRepo           repository.RoomRepository
 		eventRepo          repository.EventRepository
+		equipmentRepo      repository.EquipmentRepository
+		scheduleService    RoomScheduleService
 		jwtService         JWTService
+		eventBus           EventBus
 		db                 *gorm.DB
 	}
 )
@@ -37,19 +43,25 @@ func NewBookingRequestService(
 	bookingRequestRepo repository.BookingRequestRepository,
 	roomRepo repository.RoomRepository,
 	eventRepo repository.EventRepository,
+	equipmentRepo repository.EquipmentRepository,
+	scheduleService RoomScheduleService,
 	jwtService JWTService,
+	eventBus EventBus,
 	db *gorm.DB,
 ) BookingRequestService {
 	return &bookingRequestService{
 		bookingRequestRepo: bookingRequestRepo,
 		roomRepo:           roomRepo,
 		eventRepo:          eventRepo,
+		equipmentRepo:      equipmentRepo,
+		scheduleService:    scheduleService,
 		jwtService:         jwtService,
+		eventBus:           eventBus,
 		db:                 db,
 	}
 }
 
-func (s *bookingRequestService) CreateBookingRequest(ctx context.Context, req dto.BookingRequestCreateRequest) (dto.BookingRequestResponse, error) {
+func (s *bookingRequestService) CreateBookingRequest(ctx context.Context, req dto.BookingRequestCreateRequest, organizationId string) (dto.BookingRequestResponse, error) {
 	var response dto.BookingRequestResponse
 	var roomsForBooking []entity.Room
 	var roomResponses []dto.RoomResponse
@@ -83,11 +95,29 @@ func (s *bookingRequestService) CreateBookingRequest(ctx context.Context, req dt
 		tx.Rollback()
 		return response, err
 	}
+	if err := checkEventOwnership(event, organizationId); err != nil {
+		tx.Rollback()
+		return response, err
+	}
+
+	for _, room := range roomsForBooking {
+		if err := s.checkRoomSchedule(ctx, tx, room, event); err != nil {
+			tx.Rollback()
+			return response, err
+		}
+	}
+
+	equipments, equipmentResponses, err := s.resolveEquipments(ctx, tx, req.Equipments, roomsForBooking, event, uuid.Nil)
+	if err != nil {
+		tx.Rollback()
+		return response, err
+	}
 
 	bookingRequest := entity.BookingRequest{
-		EventID: req.EventID,
-		Rooms:   roomsForBooking,
-		Status:  "pending",
+		EventID:    req.EventID,
+		Rooms:      roomsForBooking,
+		Equipments: equipments,
+		Status:     "pending",
 	}
 
 	err = s.bookingRequestRepo.CreateBookingRequest(ctx, tx, &bookingRequest)
@@ -107,6 +137,7 @@ func (s *bookingRequestService) CreateBookingRequest(ctx context.Context, req dt
 		RequestedAt: bookingRequest.RequestedAt.Format(time.RFC3339),
 		Status:      bookingRequest.Status,
 		Rooms:       roomResponses,
+		Equipments:  equipmentResponses,
 	}
 
 	return response, nil
@@ -140,6 +171,7 @@ func (s *bookingRequestService) GetBookingRequestByID(ctx context.Context, id st
 		RequestedAt: bookingRequest.RequestedAt.Format(time.RFC3339),
 		Status:      bookingRequest.Status,
 		Rooms:       roomResponses,
+		Equipments:  toBookingEquipmentResponses(bookingRequest.Equipments),
 	}
 	return response, nil
 }
@@ -185,15 +217,17 @@ func (s *bookingRequestService) GetAllBookingRequests(ctx context.Context) ([]dt
 	return finalResponse, nil
 }
 
-func (s *bookingRequestService) UpdateBookingRequest(ctx context.Context, id string, req dto.BookingRequestUpdateRequest, role string) (dto.BookingRequestResponse, error) {
+func (s *bookingRequestService) UpdateBookingRequest(ctx context.Context, id string, req dto.BookingRequestUpdateRequest, role string, organizationId string) (dto.BookingRequestResponse, error) {
 	var response dto.BookingRequestResponse
 	bookingRequestID, err := uuid.Parse(id)
 	if err != nil {
 		return response, err
 	}
 
-	if role == "ormawa" && req.Status != "" {
-		return response, errors.New("ormawa users are not permitted to change the booking status")
+	// approving goes through ApproveBookingRequest, which re-checks the
+	// schedule and equipment stock and publishes BookingApproved
+	if req.Status != "" {
+		return response, dto.ErrBookingStatusChange
 	}
 
 	tx := s.db.Begin()
@@ -208,9 +242,15 @@ func (s *bookingRequestService) UpdateBookingRequest(ctx context.Context, id str
 		tx.Rollback()
 		return response, err
 	}
-
-	if req.Status != "" {
-		br.Status = req.Status
+	if err := s.checkBookingOwnership(ctx, tx, br.EventID, organizationId); err != nil {
+		tx.Rollback()
+		return response, err
+	}
+	// a decided request is not reviewed again, so its rooms and equipment
+	// are final
+	if br.Status != "pending" {
+		tx.Rollback()
+		return response, dto.ErrBookingNotPending
 	}
 
 	if len(req.RoomIDs) > 0 {
@@ -221,16 +261,35 @@ func (s *bookingRequestService) UpdateBookingRequest(ctx context.Context, id str
 				tx.Rollback()
 				return response, err
 			}
+			if err := s.checkRoomSchedule(ctx, tx, room, br.Event); err != nil {
+				tx.Rollback()
+				return response, err
+			}
 			newRooms = append(newRooms, room)
 		}
 		br.Rooms = newRooms
 	}
 
+	// equipment rows are managed separately from the FullSaveAssociations save
+	br.Equipments = nil
+
 	if err := s.bookingRequestRepo.UpdateBookingRequest(ctx, tx, br); err != nil {
 		tx.Rollback()
 		return response, err
 	}
 
+	if len(req.Equipments) > 0 {
+		equipments, _, err := s.resolveEquipments(ctx, tx, req.Equipments, br.Rooms, br.Event, br.ID)
+		if err != nil {
+			tx.Rollback()
+			return response, err
+		}
+		if err := s.bookingRequestRepo.ReplaceEquipments(ctx, tx, br.ID, equipments); err != nil {
+			tx.Rollback()
+			return response, err
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return response, err
 	}
@@ -257,21 +316,25 @@ func (s *bookingRequestService) UpdateBookingRequest(ctx context.Context, id str
 		RequestedAt: updatedBr.RequestedAt.Format(time.RFC3339),
 		Status:      updatedBr.Status,
 		Rooms:       roomResponses,
+		Equipments:  toBookingEquipmentResponses(updatedBr.Equipments),
 	}
 
 	return response, nil
 }
 
-func (s *bookingRequestService) DeleteBookingRequest(ctx context.Context, id string) error {
+func (s *bookingRequestService) DeleteBookingRequest(ctx context.Context, id string, organizationId string) error {
 	bookingRequestID, err := uuid.Parse(id)
 	if err != nil {
 		return err
 	}
 
-	_, err = s.bookingRequestRepo.GetBookingRequestByID(ctx, nil, bookingRequestID)
+	br, err := s.bookingRequestRepo.GetBookingRequestByID(ctx, nil, bookingRequestID)
 	if err != nil {
 		return err
 	}
+	if err := s.checkBookingOwnership(ctx, nil, br.EventID, organizationId); err != nil {
+		return err
+	}
 
 	return s.bookingRequestRepo.DeleteBookingRequest(ctx, nil, bookingRequestID)
 }
@@ -281,7 +344,60 @@ func (s *bookingRequestService) ApproveBookingRequest(ctx context.Context, id st
 	if err != nil {
 		return err
 	}
-	return s.bookingRequestRepo.UpdateBookingRequestStatus(ctx, nil, bookingRequestID, "approved")
+
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer SafeRollback(tx)
+
+	br, err := s.bookingRequestRepo.GetBookingRequestByID(ctx, tx, bookingRequestID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if br.Status != "pending" {
+		tx.Rollback()
+		return dto.ErrBookingNotPending
+	}
+
+	// Blackouts or operating hours may have changed since the request was made
+	for _, room := range br.Rooms {
+		if err := s.checkRoomSchedule(ctx, tx, room, br.Event); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// Re-check equipment stock against everything approved in the meantime
+	items := slices.Clone(br.Equipments)
+	slices.SortFunc(items, func(a, b entity.BookingRequestEquipment) int {
+		return strings.Compare(a.EquipmentID.String(), b.EquipmentID.String())
+	})
+	for _, item := range items {
+		equipment, err := s.checkEquipmentAvailability(ctx, tx, item.EquipmentID, item.Quantity, br.Event, br.ID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := checkEquipmentDepartment(equipment, br.Rooms); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := s.bookingRequestRepo.UpdateBookingRequestStatus(ctx, tx, bookingRequestID, "approved"); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	approved := dto.BookingApproved{BookingRequestID: br.ID, EventID: br.Event.ID, EventName: br.Event.Name, EventCreatedBy: br.Event.Created_By}
+	if err := s.eventBus.Publish(ctx, tx, approved); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
 }
 
 func (s *bookingRequestService) RejectBookingRequest(ctx context.Context, id string) error {
@@ -289,9 +405,165 @@ func (s *bookingRequestService) RejectBookingRequest(ctx context.Context, id str
 	if err != nil {
 		return err
 	}
-	return s.bookingRequestRepo.UpdateBookingRequestStatus(ctx, nil, bookingRequestID, "rejected")
+
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer SafeRollback(tx)
+
+	br, err := s.bookingRequestRepo.GetBookingRequestByID(ctx, tx, bookingRequestID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if br.Status != "pending" {
+		tx.Rollback()
+		return dto.ErrBookingNotPending
+	}
+
+	if err := s.bookingRequestRepo.UpdateBookingRequestStatus(ctx, tx, bookingRequestID, "rejected"); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	rejected := dto.BookingRejected{BookingRequestID: br.ID, EventID: br.Event.ID, EventName: br.Event.Name, EventCreatedBy: br.Event.Created_By}
+	if err := s.eventBus.Publish(ctx, tx, rejected); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
 }
 
 func (s *bookingRequestService) GetAllBookingRequestsWithCapacity(ctx context.Context) ([]dto.BookingRequestWithCapacityResponse, error) {
 	return s.bookingRequestRepo.GetAllBookingRequestsWithCapacity(ctx, nil)
 }
+
+// resolveEquipments loads the requested equipment and verifies that it
+// belongs to the department of a booked room and that enough stock is left
+// for the event's time window.
+func (s *bookingRequestService) resolveEquipments(
+	ctx context.Context,
+	tx *gorm.DB,
+	items []dto.BookingEquipmentRequest,
+	rooms []entity.Room,
+	event entity.Event,
+	excludeBookingID uuid.UUID,
+) ([]entity.BookingRequestEquipment, []dto.BookingEquipmentResponse, error) {
+	var equipments []entity.BookingRequestEquipment
+	var responses []dto.BookingEquipmentResponse
+	seen := make(map[uuid.UUID]bool)
+
+	// rows are locked in id order, so two requests for the same equipment
+	// cannot deadlock
+	items = slices.Clone(items)
+	slices.SortFunc(items, func(a, b dto.BookingEquipmentRequest) int {
+		return strings.Compare(a.EquipmentID.String(), b.EquipmentID.String())
+	})
+
+	for _, item := range items {
+		if seen[item.EquipmentID] {
+			return nil, nil, dto.ErrEquipmentDuplicateInReq
+		}
+		seen[item.EquipmentID] = true
+
+		equipment, err := s.checkEquipmentAvailability(ctx, tx, item.EquipmentID, item.Quantity, event, excludeBookingID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := checkEquipmentDepartment(equipment, rooms); err != nil {
+			return nil, nil, err
+		}
+
+		equipments = append(equipments, entity.BookingRequestEquipment{
+			EquipmentID: equipment.ID,
+			Quantity:    item.Quantity,
+		})
+		responses = append(responses, dto.BookingEquipmentResponse{
+			EquipmentID: equipment.ID,
+			Name:        equipment.Name,
+			Quantity:    item.Quantity,
+		})
+	}
+
+	return equipments, responses, nil
+}
+
+func (s *bookingRequestService) checkRoomSchedule(ctx context.Context, tx *gorm.DB, room entity.Room, event entity.Event) error {
+	return checkBookedRoom(ctx, tx, s.scheduleService, room, event)
+}
+
+// checkBookedRoom checks that room is open for the whole event, naming the
+// room in the error
+func checkBookedRoom(ctx context.Context, tx *gorm.DB, scheduleService RoomScheduleService, room entity.Room, event entity.Event) error {
+	if err := scheduleService.CheckRoomSchedule(ctx, tx, room, event.Start_Time, event.End_Time); err != nil {
+		if errors.Is(err, dto.ErrRoomOutsideOperatingHours) || errors.Is(err, dto.ErrRoomBlackedOut) {
+			return fmt.Errorf("%w: %s", err, room.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+// checkEquipmentAvailability locks the equipment row for the rest of tx, so
+// concurrent approvals of the same equipment count the approved quantities
+// one after the other, and returns the equipment when enough stock is left
+// for the event's time window.
+func (s *bookingRequestService) checkEquipmentAvailability(
+	ctx context.Context,
+	tx *gorm.DB,
+	equipmentID uuid.UUID,
+	quantity int,
+	event entity.Event,
+	excludeBookingID uuid.UUID,
+) (entity.Equipment, error) {
+	equipment, err := s.equipmentRepo.LockEquipment(ctx, tx, equipmentID)
+	if err != nil {
+		return entity.Equipment{}, err
+	}
+
+	booked, err := s.equipmentRepo.GetBookedQuantity(ctx, tx, equipment.ID, event.Start_Time, event.End_Time, excludeBookingID)
+	if err != nil {
+		return entity.Equipment{}, err
+	}
+	if booked+quantity > equipment.Quantity {
+		return entity.Equipment{}, fmt.Errorf("%w: %s (requested %d, available %d)", dto.ErrEquipmentNotAvailable, equipment.Name, quantity, equipment.Quantity-booked)
+	}
+	return equipment, nil
+}
+
+// checkEquipmentDepartment rejects equipment lent by a department that owns
+// none of the booked rooms, each department only lends its own inventory
+func checkEquipmentDepartment(equipment entity.Equipment, rooms []entity.Room) error {
+	for _, room := range rooms {
+		if room.DepartmentID == equipment.DepartmentID {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", dto.ErrEquipmentDepartment, equipment.Name)
+}
+
+func toBookingEquipmentResponses(equipments []entity.BookingRequestEquipment) []dto.BookingEquipmentResponse {
+	var responses []dto.BookingEquipmentResponse
+	for _, item := range equipments {
+		responses = append(responses, dto.BookingEquipmentResponse{
+			EquipmentID: item.EquipmentID,
+			Name:        item.Equipment.Name,
+			Quantity:    item.Quantity,
+		})
+	}
+	return responses
+}
+
+// checkBookingOwnership rejects bookings for events owned by another organization
+func (s *bookingRequestService) checkBookingOwnership(ctx context.Context, tx *gorm.DB, eventID uuid.UUID, organizationId string) error {
+	if organizationId == "" {
+		return nil
+	}
+	event, err := s.eventRepo.GetEventById(ctx, tx, eventID.String())
+	if err != nil {
+		return dto.ErrEventNotFound
+	}
+	return checkEventOwnership(event, organizationId)
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// fakeConnPool lets services begin and commit transactions without a
+// database. Statements are only built, see newDryRunDB.
+type fakeConnPool struct {
+	committed  int
+	rolledBack int
+}
+
+func (p *fakeConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (p *fakeConnPool) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (p *fakeConnPool) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func (p *fakeConnPool) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return nil
+}
+
+func (p *fakeConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return p, nil
+}
+
+func (p *fakeConnPool) Commit() error {
+	p.committed++
+	return nil
+}
+
+func (p *fakeConnPool) Rollback() error {
+	p.rolledBack++
+	return nil
+}
+
+func newDryRunDB(t *testing.T) (*gorm.DB, *fakeConnPool) {
+	pool := &fakeConnPool{}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: pool}), &gorm.Config{DryRun: true})
+	assert.NoError(t, err)
+	return db, pool
+}
+
+// fakeEventBus records published events instead of storing them
+type fakeEventBus struct {
+	published []dto.DomainEvent
+}
+
+func (b *fakeEventBus) Publish(ctx context.Context, tx *gorm.DB, events ...dto.DomainEvent) error {
+	b.published = append(b.published, events...)
+	return nil
+}
+
+// fakeBookingRequestRepository serves one booking request and records its
+// status changes
+type fakeBookingRequestRepository struct {
+	repository.BookingRequestRepository
+	bookingRequest entity.BookingRequest
+	statusErr      error
+	statuses       []string
+}
+
+func (r *fakeBookingRequestRepository) GetBookingRequestByID(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.BookingRequest, error) {
+	if id != r.bookingRequest.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	bookingRequest := r.bookingRequest
+	return &bookingRequest, nil
+}
+
+func (r *fakeBookingRequestRepository) UpdateBookingRequestStatus(ctx context.Context, tx *gorm.DB, id uuid.UUID, status string) error {
+	if r.statusErr != nil {
+		return r.statusErr
+	}
+	r.statuses = append(r.statuses, status)
+	return nil
+}
+
+// fakeEquipmentRepository serves the equipment and booked quantities the
+// availability checks ask for
+type fakeEquipmentRepository struct {
+	repository.EquipmentRepository
+	equipments map[uuid.UUID]entity.Equipment
+	booked     map[uuid.UUID]int
+}
+
+func (r *fakeEquipmentRepository) LockEquipment(ctx context.Context, tx *gorm.DB, id uuid.UUID) (entity.Equipment, error) {
+	equipment, ok := r.equipments[id]
+	if !ok {
+		return entity.Equipment{}, dto.ErrEquipmentNotFound
+	}
+	return equipment, nil
+}
+
+func (r *fakeEquipmentRepository) GetBookedQuantity(ctx context.Context, tx *gorm.DB, id uuid.UUID, start, end time.Time, excludeBookingID uuid.UUID) (int, error) {
+	return r.booked[id], nil
+}
+
+func Test_ResolveEquipments(t *testing.T) {
+	informatics, physics := uuid.New(), uuid.New()
+	microphone := entity.Equipment{ID: uuid.New(), DepartmentID: informatics, Name: "Microphone", Quantity: 4}
+	telescope := entity.Equipment{ID: uuid.New(), DepartmentID: physics, Name: "Telescope", Quantity: 1}
+	repo := &fakeEquipmentRepository{
+		equipments: map[uuid.UUID]entity.Equipment{microphone.ID: microphone, telescope.ID: telescope},
+		booked:     map[uuid.UUID]int{microphone.ID: 3},
+	}
+	lab := entity.Room{ID: uuid.New(), DepartmentID: informatics, Name: "Lab 1"}
+	observatory := entity.Room{ID: uuid.New(), DepartmentID: physics, Name: "Observatory"}
+
+	tests := []struct {
+		name    string
+		items   []dto.BookingEquipmentRequest
+		rooms   []entity.Room
+		wantErr error
+	}{
+		{"own department", []dto.BookingEquipmentRequest{{EquipmentID: microphone.ID, Quantity: 1}}, []entity.Room{lab}, nil},
+		{"department of one of the rooms", []dto.BookingEquipmentRequest{{EquipmentID: microphone.ID, Quantity: 1}, {EquipmentID: telescope.ID, Quantity: 1}}, []entity.Room{lab, observatory}, nil},
+		{"other department", []dto.BookingEquipmentRequest{{EquipmentID: telescope.ID, Quantity: 1}}, []entity.Room{lab}, dto.ErrEquipmentDepartment},
+		{"out of stock", []dto.BookingEquipmentRequest{{EquipmentID: microphone.ID, Quantity: 2}}, []entity.Room{lab}, dto.ErrEquipmentNotAvailable},
+		{"listed twice", []dto.BookingEquipmentRequest{{EquipmentID: microphone.ID, Quantity: 1}, {EquipmentID: microphone.ID, Quantity: 1}}, []entity.Room{lab}, dto.ErrEquipmentDuplicateInReq},
+		{"unknown", []dto.BookingEquipmentRequest{{EquipmentID: uuid.New(), Quantity: 1}}, []entity.Room{lab}, dto.ErrEquipmentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &bookingRequestService{equipmentRepo: repo}
+			equipments, responses, err := s.resolveEquipments(context.Background(), nil, tt.items, tt.rooms, entity.Event{}, uuid.Nil)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, equipments, len(tt.items))
+			assert.Len(t, responses, len(tt.items))
+		})
+	}
+}
+
+func Test_BookingRequestService_Decide(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        string
+		reject        bool
+		statusErr     error
+		wantErr       error
+		wantPublished string
+	}{
+		{"approve pending", "pending", false, nil, nil, dto.TopicBookingApproved},
+		{"reject pending", "pending", true, nil, nil, dto.TopicBookingRejected},
+		{"approve twice", "approved", false, nil, dto.ErrBookingNotPending, ""},
+		{"approve rejected", "rejected", false, nil, dto.ErrBookingNotPending, ""},
+		{"reject approved", "approved", true, nil, dto.ErrBookingNotPending, ""},
+		{"decided concurrently", "pending", false, dto.ErrBookingNotPending, dto.ErrBookingNotPending, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, pool := newDryRunDB(t)
+			bus := &fakeEventBus{}
+			repo := &fakeBookingRequestRepository{
+				bookingRequest: entity.BookingRequest{ID: uuid.New(), Status: tt.status, Event: entity.Event{ID: uuid.New(), Name: "Seminar"}},
+				statusErr:      tt.statusErr,
+			}
+			s := NewBookingRequestService(repo, nil, nil, nil, nil, nil, bus, db)
+
+			var err error
+			if tt.reject {
+				err = s.RejectBookingRequest(context.Background(), repo.bookingRequest.ID.String())
+			} else {
+				err = s.ApproveBookingRequest(context.Background(), repo.bookingRequest.ID.String())
+			}
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, bus.published)
+				assert.Zero(t, pool.committed)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1, pool.committed)
+			if assert.Len(t, bus.published, 1) {
+				assert.Equal(t, tt.wantPublished, bus.published[0].Topic())
+			}
+		})
+	}
+}
+
+func Test_BookingRequestService_UpdateDecided(t *testing.T) {
+	for _, status := range []string{"approved", "rejected"} {
+		t.Run(status, func(t *testing.T) {
+			db, _ := newDryRunDB(t)
+			repo := &fakeBookingRequestRepository{bookingRequest: entity.BookingRequest{ID: uuid.New(), Status: status}}
+			s := NewBookingRequestService(repo, nil, nil, nil, nil, nil, nil, db)
+
+			req := dto.BookingRequestUpdateRequest{RoomIDs: []uuid.UUID{uuid.New()}}
+			_, err := s.UpdateBookingRequest(context.Background(), repo.bookingRequest.ID.String(), req, "ormawa", "")
+			assert.ErrorIs(t, err, dto.ErrBookingNotPending)
+		})
+	}
+}
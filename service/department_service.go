@@ -52,6 +52,8 @@ func (s *departmentService) Create(ctx context.Context, req dto.DepartmentCreate
 		Email:    req.Email,
 		Password: req.Password,
 		Role:     "departemen",
+		// accounts created by an admin skip email verification
+		IsVerified: true,
 	}
 
 	// Create user via repository (assuming you have userRepo)
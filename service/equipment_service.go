@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"gorm.io/gorm"
+)
+
+type (
+	EquipmentService interface {
+		Create(ctx context.Context, req dto.EquipmentCreateRequest, userId string, role string) (dto.EquipmentResponse, error)
+		GetEquipmentByID(ctx context.Context, id string) (dto.EquipmentResponse, error)
+		GetAllEquipment(ctx context.Context, filter dto.EquipmentFilterRequest) ([]dto.EquipmentResponse, error)
+		Update(ctx context.Context, id string, req dto.EquipmentUpdateRequest) (dto.EquipmentResponse, error)
+		Delete(ctx context.Context, id string) error
+		GetAvailability(ctx context.Context, id string, req dto.EquipmentAvailabilityRequest) (dto.EquipmentAvailabilityResponse, error)
+	}
+
+	equipmentService struct {
+		equipmentRepository  repository.EquipmentRepository
+		departmentRepository repository.DepartmentRepository
+		db                   *gorm.DB
+	}
+)
+
+func NewEquipmentService(
+	equipmentRepository repository.EquipmentRepository,
+	departmentRepository repository.DepartmentRepository,
+	db *gorm.DB,
+) EquipmentService {
+	return &equipmentService{
+		equipmentRepository:  equipmentRepository,
+		departmentRepository: departmentRepository,
+		db:                   db,
+	}
+}
+
+func (s *equipmentService) Create(ctx context.Context, req dto.EquipmentCreateRequest, userId string, role string) (dto.EquipmentResponse, error) {
+	equipment := entity.Equipment{
+		Name:        req.Name,
+		Description: req.Description,
+		Quantity:    req.Quantity,
+	}
+
+	if role == "departemen" {
+		department, err := s.departmentRepository.GetDepartmentByUserId(ctx, nil, userId)
+		if err != nil {
+			return dto.EquipmentResponse{}, err
+		}
+		equipment.DepartmentID = department.ID
+	} else {
+		departmentID, err := uuid.Parse(req.DepartmentID)
+		if err != nil {
+			return dto.EquipmentResponse{}, err
+		}
+		equipment.DepartmentID = departmentID
+	}
+
+	result, err := s.equipmentRepository.Create(ctx, nil, equipment)
+	if err != nil {
+		return dto.EquipmentResponse{}, err
+	}
+
+	return toEquipmentResponse(result), nil
+}
+
+func (s *equipmentService) GetEquipmentByID(ctx context.Context, id string) (dto.EquipmentResponse, error) {
+	result, err := s.equipmentRepository.GetEquipmentByID(ctx, nil, id)
+	if err != nil {
+		return dto.EquipmentResponse{}, err
+	}
+
+	return toEquipmentResponse(result), nil
+}
+
+func (s *equipmentService) GetAllEquipment(ctx context.Context, filter dto.EquipmentFilterRequest) ([]dto.EquipmentResponse, error) {
+	result, err := s.equipmentRepository.GetAllEquipment(ctx, nil, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.EquipmentResponse, 0, len(result))
+	for _, equipment := range result {
+		response = append(response, toEquipmentResponse(equipment))
+	}
+	return response, nil
+}
+
+func (s *equipmentService) Update(ctx context.Context, id string, req dto.EquipmentUpdateRequest) (dto.EquipmentResponse, error) {
+	equipment, err := s.equipmentRepository.GetEquipmentByID(ctx, nil, id)
+	if err != nil {
+		return dto.EquipmentResponse{}, err
+	}
+
+	if req.Name != "" {
+		equipment.Name = req.Name
+	}
+	if req.Description != "" {
+		equipment.Description = req.Description
+	}
+	if req.Quantity != 0 {
+		equipment.Quantity = req.Quantity
+	}
+
+	result, err := s.equipmentRepository.Update(ctx, nil, equipment)
+	if err != nil {
+		return dto.EquipmentResponse{}, err
+	}
+
+	return toEquipmentResponse(result), nil
+}
+
+func (s *equipmentService) Delete(ctx context.Context, id string) error {
+	return s.equipmentRepository.Delete(ctx, nil, id)
+}
+
+func (s *equipmentService) GetAvailability(ctx context.Context, id string, req dto.EquipmentAvailabilityRequest) (dto.EquipmentAvailabilityResponse, error) {
+	startTime, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		return dto.EquipmentAvailabilityResponse{}, err
+	}
+	endTime, err := time.Parse(time.RFC3339, req.EndTime)
+	if err != nil {
+		return dto.EquipmentAvailabilityResponse{}, err
+	}
+	if !endTime.After(startTime) {
+		return dto.EquipmentAvailabilityResponse{}, dto.ErrEquipmentInvalidRange
+	}
+
+	equipment, err := s.equipmentRepository.GetEquipmentByID(ctx, nil, id)
+	if err != nil {
+		return dto.EquipmentAvailabilityResponse{}, err
+	}
+
+	booked, err := s.equipmentRepository.GetBookedQuantity(ctx, nil, equipment.ID, startTime, endTime, uuid.Nil)
+	if err != nil {
+		return dto.EquipmentAvailabilityResponse{}, err
+	}
+
+	available := equipment.Quantity - booked
+	if available < 0 {
+		available = 0
+	}
+
+	return dto.EquipmentAvailabilityResponse{
+		EquipmentID: equipment.ID.String(),
+		Name:        equipment.Name,
+		Quantity:    equipment.Quantity,
+		Booked:      booked,
+		Available:   available,
+	}, nil
+}
+
+func toEquipmentResponse(equipment entity.Equipment) dto.EquipmentResponse {
+	return dto.EquipmentResponse{
+		ID:           equipment.ID.String(),
+		Name:         equipment.Name,
+		Description:  equipment.Description,
+		Quantity:     equipment.Quantity,
+		Department:   equipment.Department.Name,
+		DepartmentID: equipment.DepartmentID.String(),
+	}
+}
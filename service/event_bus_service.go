@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	outboxPollInterval = time.Second
+	outboxBatchSize    = 100
+	outboxMaxAttempts  = 10
+	outboxMaxBackoff   = time.Hour
+	subscriberTimeout  = time.Minute
+	// outboxLease hides a claimed event from other instances while its
+	// subscribers run one after the other, so it must stay well above
+	// subscriberTimeout times the subscribers of a topic
+	outboxLease = 15 * time.Minute
+)
+
+type (
+	// Subscriber reacts to the events of one topic. Subscribers are provided
+	// to the injector under constants.SubscriberPrefix and picked up by the
+	// dispatcher at startup.
+	//
+	// Delivery is at least once: an event is handed to a subscriber again
+	// when the subscriber failed, or when the instance stopped before the
+	// delivery was recorded, so handlers must tolerate duplicates.
+	Subscriber struct {
+		// Name identifies the subscriber in retries and logs, unique per topic
+		Name   string
+		Topic  string
+		Handle func(ctx context.Context, payload []byte) error
+	}
+
+	EventBus interface {
+		// Publish stores events in the outbox within tx, so they reach
+		// subscribers only if tx commits. A nil tx publishes right away.
+		Publish(ctx context.Context, tx *gorm.DB, events ...dto.DomainEvent) error
+	}
+
+	EventDispatcher interface {
+		// Start delivers outbox events to subscribers until ctx is done
+		Start(ctx context.Context)
+	}
+
+	eventBus struct {
+		outboxRepo repository.OutboxRepository
+	}
+
+	eventDispatcher struct {
+		subscribers map[string][]Subscriber
+		outboxRepo  repository.OutboxRepository
+	}
+)
+
+// Subscribe adapts a handler of one event type into a Subscriber
+func Subscribe[T dto.DomainEvent](name string, handle func(ctx context.Context, event T) error) Subscriber {
+	var zero T
+	return Subscriber{
+		Name:  name,
+		Topic: zero.Topic(),
+		Handle: func(ctx context.Context, payload []byte) error {
+			var event T
+			if err := json.Unmarshal(payload, &event); err != nil {
+				return err
+			}
+			return handle(ctx, event)
+		},
+	}
+}
+
+func NewEventBus(outboxRepo repository.OutboxRepository) EventBus {
+	return &eventBus{
+		outboxRepo: outboxRepo,
+	}
+}
+
+func (b *eventBus) Publish(ctx context.Context, tx *gorm.DB, events ...dto.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([]entity.OutboxEvent, len(events))
+	for i, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		rows[i] = entity.OutboxEvent{
+			Topic:         event.Topic(),
+			Payload:       string(payload),
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		}
+	}
+
+	return b.outboxRepo.Create(ctx, tx, rows)
+}
+
+func NewEventDispatcher(subscribers []Subscriber, outboxRepo repository.OutboxRepository) (EventDispatcher, error) {
+	d := &eventDispatcher{
+		subscribers: make(map[string][]Subscriber),
+		outboxRepo:  outboxRepo,
+	}
+
+	for _, subscriber := range subscribers {
+		for _, existing := range d.subscribers[subscriber.Topic] {
+			if existing.Name == subscriber.Name {
+				return nil, fmt.Errorf("subscriber %s is registered twice on %s", subscriber.Name, subscriber.Topic)
+			}
+		}
+		if strings.Contains(subscriber.Name, ",") {
+			return nil, fmt.Errorf("subscriber name %q must not contain a comma", subscriber.Name)
+		}
+		d.subscribers[subscriber.Topic] = append(d.subscribers[subscriber.Topic], subscriber)
+	}
+
+	return d, nil
+}
+
+func (d *eventDispatcher) Start(ctx context.Context) {
+	go d.loop(ctx)
+	slog.Info("event dispatcher started", "topics", len(d.subscribers))
+}
+
+func (d *eventDispatcher) loop(ctx context.Context) {
+	for {
+		claimed, err := d.dispatch(ctx)
+		if err != nil {
+			slog.Error("event dispatcher failed", "error", err)
+		}
+		// a full batch means more may be waiting
+		if err == nil && claimed == outboxBatchSize {
+			continue
+		}
+
+		timer := time.NewTimer(outboxPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// dispatch delivers up to a batch of due events. Each event is claimed and
+// its outcome recorded on its own, outside any transaction, so a slow
+// subscriber or a failed write only holds back that event and never makes
+// the subscribers of other events run again.
+func (d *eventDispatcher) dispatch(ctx context.Context) (int, error) {
+	for claimed := 0; claimed < outboxBatchSize; claimed++ {
+		if ctx.Err() != nil {
+			return claimed, nil
+		}
+
+		now := time.Now()
+		events, err := d.outboxRepo.ClaimDue(ctx, nil, now, now.Add(outboxLease), 1)
+		if err != nil {
+			return claimed, err
+		}
+		if len(events) == 0 {
+			return claimed, nil
+		}
+
+		// when recording fails the lease runs out and the event is retried
+		if err := d.deliver(ctx, events[0]); err != nil {
+			slog.Error("failed to record event delivery", "event_id", events[0].ID, "topic", events[0].Topic, "error", err)
+		}
+	}
+	return outboxBatchSize, nil
+}
+
+func (d *eventDispatcher) deliver(ctx context.Context, event entity.OutboxEvent) error {
+	var pending map[string]bool
+	if event.PendingSubscribers != "" {
+		pending = make(map[string]bool)
+		for _, name := range strings.Split(event.PendingSubscribers, ",") {
+			pending[name] = true
+		}
+	}
+
+	var failed, errs []string
+	for _, subscriber := range d.subscribers[event.Topic] {
+		if pending != nil && !pending[subscriber.Name] {
+			continue
+		}
+		if err := handleEvent(ctx, subscriber, event); err != nil {
+			slog.Warn("subscriber failed", "event_id", event.ID, "topic", event.Topic, "subscriber", subscriber.Name, "error", err)
+			failed = append(failed, subscriber.Name)
+			errs = append(errs, subscriber.Name+": "+err.Error())
+		}
+	}
+
+	// the outcome is recorded even when the dispatcher is stopping
+	recordCtx := context.WithoutCancel(ctx)
+	now := time.Now()
+	if len(failed) == 0 {
+		return d.outboxRepo.MarkDelivered(recordCtx, nil, event.ID, now)
+	}
+
+	message := strings.Join(errs, "; ")
+	event.Attempts++
+	event.PendingSubscribers = strings.Join(failed, ",")
+	event.LastError = &message
+	event.NextAttemptAt = now.Add(outboxBackoff(event.Attempts))
+	if event.Attempts >= outboxMaxAttempts {
+		event.FailedAt = &now
+		slog.Error("giving up on event", "event_id", event.ID, "topic", event.Topic, "attempts", event.Attempts)
+	}
+	return d.outboxRepo.MarkRetry(recordCtx, nil, event)
+}
+
+// handleEvent runs the subscriber with subscriberTimeout and turns a panic
+// into a failed delivery. Handlers that ignore ctx, such as the SMTP mailer,
+// are left running in the background once the timeout passes, and the
+// delivery counts as failed.
+func handleEvent(ctx context.Context, subscriber Subscriber, event entity.OutboxEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, subscriberTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- subscriber.Handle(ctx, []byte(event.Payload))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("subscriber %s: %w", subscriber.Name, ctx.Err())
+	}
+}
+
+// outboxBackoff doubles the wait after every failed attempt, from 2 seconds
+// up to an hour
+func outboxBackoff(attempts int) time.Duration {
+	backoff := time.Second << uint(attempts)
+	if attempts > 20 || backoff > outboxMaxBackoff {
+		return outboxMaxBackoff
+	}
+	return backoff
+}
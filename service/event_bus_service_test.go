@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// fakeOutboxRepository records how deliveries end, the other methods are not
+// used by deliver
+type fakeOutboxRepository struct {
+	repository.OutboxRepository
+	delivered []int64
+	retried   []entity.OutboxEvent
+}
+
+func (r *fakeOutboxRepository) MarkDelivered(ctx context.Context, tx *gorm.DB, id int64, deliveredAt time.Time) error {
+	r.delivered = append(r.delivered, id)
+	return nil
+}
+
+func (r *fakeOutboxRepository) MarkRetry(ctx context.Context, tx *gorm.DB, event entity.OutboxEvent) error {
+	r.retried = append(r.retried, event)
+	return nil
+}
+
+func Test_OutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{5, 32 * time.Second},
+		{11, 2048 * time.Second},
+		{12, outboxMaxBackoff},
+		{21, outboxMaxBackoff},
+		{64, outboxMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, outboxBackoff(tt.attempts))
+		})
+	}
+}
+
+func Test_HandleEvent(t *testing.T) {
+	handleErr := errors.New("smtp is down")
+	release := make(chan struct{})
+	defer close(release)
+
+	tests := []struct {
+		name      string
+		handle    func(ctx context.Context, payload []byte) error
+		cancelled bool
+		wantErr   string
+	}{
+		{"success", func(ctx context.Context, payload []byte) error { return nil }, false, ""},
+		{"error", func(ctx context.Context, payload []byte) error { return handleErr }, false, handleErr.Error()},
+		{"panic", func(ctx context.Context, payload []byte) error { panic("nil map") }, false, "panic: nil map"},
+		{
+			name: "handler ignoring ctx",
+			handle: func(ctx context.Context, payload []byte) error {
+				<-release
+				return nil
+			},
+			cancelled: true,
+			wantErr:   "subscriber mailer: context canceled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.cancelled {
+				cancel()
+			}
+			defer cancel()
+
+			err := handleEvent(ctx, Subscriber{Name: "mailer", Topic: "test", Handle: tt.handle}, entity.OutboxEvent{Payload: "{}"})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_Subscribe(t *testing.T) {
+	eventId := uuid.New()
+
+	var got dto.EventCancelled
+	subscriber := Subscribe("mailer", func(ctx context.Context, event dto.EventCancelled) error {
+		got = event
+		return nil
+	})
+	assert.Equal(t, dto.EventCancelled{}.Topic(), subscriber.Topic)
+
+	assert.NoError(t, subscriber.Handle(context.Background(), []byte(`{"event_id":"`+eventId.String()+`","event_name":"Seminar"}`)))
+	assert.Equal(t, dto.EventCancelled{EventID: eventId, EventName: "Seminar"}, got)
+	assert.Error(t, subscriber.Handle(context.Background(), []byte(`not json`)))
+}
+
+func Test_NewEventDispatcher(t *testing.T) {
+	noop := func(ctx context.Context, payload []byte) error { return nil }
+
+	tests := []struct {
+		name        string
+		subscribers []Subscriber
+		wantErr     bool
+	}{
+		{"one per topic", []Subscriber{{Name: "mailer", Topic: "a", Handle: noop}, {Name: "mailer", Topic: "b", Handle: noop}}, false},
+		{"several on a topic", []Subscriber{{Name: "mailer", Topic: "a", Handle: noop}, {Name: "webhooks", Topic: "a", Handle: noop}}, false},
+		{"registered twice", []Subscriber{{Name: "mailer", Topic: "a", Handle: noop}, {Name: "mailer", Topic: "a", Handle: noop}}, true},
+		{"comma in the name", []Subscriber{{Name: "mailer,webhooks", Topic: "a", Handle: noop}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEventDispatcher(tt.subscribers, nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_EventDispatcher_Deliver(t *testing.T) {
+	ok := func(ctx context.Context, payload []byte) error { return nil }
+	fail := func(ctx context.Context, payload []byte) error { return errors.New("down") }
+
+	tests := []struct {
+		name          string
+		mailer        func(ctx context.Context, payload []byte) error
+		webhooks      func(ctx context.Context, payload []byte) error
+		event         entity.OutboxEvent
+		wantDelivered bool
+		wantPending   string
+		wantAttempts  int
+		wantFailed    bool
+	}{
+		{
+			name:          "all succeed",
+			mailer:        ok,
+			webhooks:      ok,
+			event:         entity.OutboxEvent{ID: 1, Topic: "a"},
+			wantDelivered: true,
+		},
+		{
+			name:         "only the failed subscriber is retried",
+			mailer:       ok,
+			webhooks:     fail,
+			event:        entity.OutboxEvent{ID: 1, Topic: "a"},
+			wantPending:  "webhooks",
+			wantAttempts: 1,
+		},
+		{
+			name:          "a retry skips subscribers that already handled the event",
+			mailer:        fail,
+			webhooks:      ok,
+			event:         entity.OutboxEvent{ID: 1, Topic: "a", Attempts: 3, PendingSubscribers: "webhooks"},
+			wantDelivered: true,
+		},
+		{
+			name:         "gives up after the last attempt",
+			mailer:       fail,
+			webhooks:     fail,
+			event:        entity.OutboxEvent{ID: 1, Topic: "a", Attempts: outboxMaxAttempts - 1},
+			wantPending:  "mailer,webhooks",
+			wantAttempts: outboxMaxAttempts,
+			wantFailed:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeOutboxRepository{}
+			dispatcher, err := NewEventDispatcher([]Subscriber{
+				{Name: "mailer", Topic: "a", Handle: tt.mailer},
+				{Name: "webhooks", Topic: "a", Handle: tt.webhooks},
+			}, repo)
+			assert.NoError(t, err)
+
+			before := time.Now()
+			assert.NoError(t, dispatcher.(*eventDispatcher).deliver(context.Background(), tt.event))
+
+			if tt.wantDelivered {
+				assert.Equal(t, []int64{tt.event.ID}, repo.delivered)
+				assert.Empty(t, repo.retried)
+				return
+			}
+			assert.Empty(t, repo.delivered)
+			if assert.Len(t, repo.retried, 1) {
+				retry := repo.retried[0]
+				assert.Equal(t, tt.wantPending, retry.PendingSubscribers)
+				assert.Equal(t, tt.wantAttempts, retry.Attempts)
+				assert.NotNil(t, retry.LastError)
+				assert.False(t, retry.NextAttemptAt.Before(before.Add(outboxBackoff(tt.wantAttempts))))
+				assert.Equal(t, tt.wantFailed, retry.FailedAt != nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/miraicantsleep/myits-event-be/utils"
+	"github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+)
+
+// maxReminderOffset is the earliest a reminder can go out, 30 days ahead
+const maxReminderOffset = 43200 * time.Minute
+
+type (
+	EventReminderService interface {
+		GetReminders(ctx context.Context, eventId string, organizationId string) (dto.EventReminderResponse, error)
+		UpdateReminders(ctx context.Context, eventId string, organizationId string, req dto.EventReminderRequest) (dto.EventReminderResponse, error)
+		// SendDueReminders sends every reminder whose time has come and
+		// returns how many emails went out
+		SendDueReminders(ctx context.Context) (int, error)
+	}
+
+	eventReminderService struct {
+		reminderRepo repository.EventReminderRepository
+		eventRepo    repository.EventRepository
+		db           *gorm.DB
+	}
+)
+
+func NewEventReminderService(reminderRepo repository.EventReminderRepository, eventRepo repository.EventRepository, db *gorm.DB) EventReminderService {
+	return &eventReminderService{
+		reminderRepo: reminderRepo,
+		eventRepo:    eventRepo,
+		db:           db,
+	}
+}
+
+// SendEventRemindersJob sends due event reminders every five minutes
+func SendEventRemindersJob(reminderService EventReminderService) Job {
+	return Job{
+		Name:        "send_event_reminders",
+		Description: "email invitees the reminders configured on upcoming events",
+		Schedule:    "*/5 * * * *",
+		Run: func(ctx context.Context) (string, error) {
+			sent, err := reminderService.SendDueReminders(ctx)
+			return fmt.Sprintf("sent %d reminders", sent), err
+		},
+	}
+}
+
+func (s *eventReminderService) GetReminders(ctx context.Context, eventId string, organizationId string) (dto.EventReminderResponse, error) {
+	event, err := s.reminderRepo.GetSettings(ctx, nil, eventId)
+	if err != nil {
+		return dto.EventReminderResponse{}, dto.ErrEventNotFound
+	}
+	if err := checkEventOwnership(event, organizationId); err != nil {
+		return dto.EventReminderResponse{}, err
+	}
+
+	return s.toEventReminderResponse(ctx, event)
+}
+
+func (s *eventReminderService) UpdateReminders(ctx context.Context, eventId string, organizationId string, req dto.EventReminderRequest) (dto.EventReminderResponse, error) {
+	event, err := s.reminderRepo.GetSettings(ctx, nil, eventId)
+	if err != nil {
+		return dto.EventReminderResponse{}, dto.ErrEventNotFound
+	}
+	if err := checkEventOwnership(event, organizationId); err != nil {
+		return dto.EventReminderResponse{}, err
+	}
+
+	offsets := append([]int(nil), req.OffsetsMinutes...)
+	sort.Sort(sort.Reverse(sort.IntSlice(offsets)))
+	parts := make([]string, len(offsets))
+	for i, offset := range offsets {
+		if i > 0 && offset == offsets[i-1] {
+			return dto.EventReminderResponse{}, dto.ErrReminderOffsetDuplicate
+		}
+		parts[i] = strconv.Itoa(offset)
+	}
+
+	event.ReminderOffsets = strings.Join(parts, ",")
+	event.RemindPending = req.IncludePending
+	if err := s.eventRepo.UpdateReminders(ctx, nil, eventId, event.ReminderOffsets, event.RemindPending); err != nil {
+		return dto.EventReminderResponse{}, err
+	}
+
+	return s.toEventReminderResponse(ctx, event)
+}
+
+func (s *eventReminderService) toEventReminderResponse(ctx context.Context, event entity.Event) (dto.EventReminderResponse, error) {
+	counts, err := s.reminderRepo.GetDeliveryCounts(ctx, nil, event.ID.String())
+	if err != nil {
+		return dto.EventReminderResponse{}, err
+	}
+	sent := make(map[int]int64, len(counts))
+	for _, count := range counts {
+		sent[count.OffsetMinutes] = count.Sent
+	}
+
+	offsets := parseReminderOffsets(event.ReminderOffsets)
+	reminders := make([]dto.EventReminderSchedule, len(offsets))
+	for i, offset := range offsets {
+		reminders[i] = dto.EventReminderSchedule{
+			OffsetMinutes: offset,
+			SendAt:        event.Start_Time.Add(-time.Duration(offset) * time.Minute),
+			Sent:          sent[offset],
+		}
+	}
+
+	return dto.EventReminderResponse{
+		EventID:        event.ID.String(),
+		IncludePending: event.RemindPending,
+		Reminders:      reminders,
+	}, nil
+}
+
+// parseReminderOffsets reads the comma separated offsets stored on an event,
+// largest first. Malformed entries are skipped.
+func parseReminderOffsets(value string) []int {
+	var offsets []int
+	for _, part := range strings.Split(value, ",") {
+		if offset, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && offset > 0 {
+			offsets = append(offsets, offset)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(offsets)))
+	return offsets
+}
+
+func (s *eventReminderService) SendDueReminders(ctx context.Context) (int, error) {
+	now := time.Now()
+	events, err := s.reminderRepo.GetUpcomingEvents(ctx, nil, now, now.Add(maxReminderOffset))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, event := range events {
+		// only the latest due reminder is sent: an invitee added an hour
+		// before the start gets the H-1 hour reminder, not both
+		offset, ok := dueReminderOffset(event, now)
+		if !ok {
+			continue
+		}
+
+		n, err := s.sendEventReminders(ctx, event, offset)
+		sent += n
+		if err != nil {
+			return sent, fmt.Errorf("event %s: %w", event.ID, err)
+		}
+	}
+
+	return sent, nil
+}
+
+func dueReminderOffset(event entity.Event, now time.Time) (int, bool) {
+	offsets := parseReminderOffsets(event.ReminderOffsets)
+	for i := len(offsets) - 1; i >= 0; i-- {
+		if !event.Start_Time.Add(-time.Duration(offsets[i]) * time.Minute).After(now) {
+			return offsets[i], true
+		}
+	}
+	return 0, false
+}
+
+func (s *eventReminderService) sendEventReminders(ctx context.Context, event entity.Event, offset int) (int, error) {
+	statuses := []string{entity.RSVPStatusAccepted}
+	if event.RemindPending {
+		statuses = append(statuses, entity.RSVPStatusPending)
+	}
+
+	recipients, err := s.reminderRepo.GetRecipients(ctx, nil, event.ID.String(), offset, statuses)
+	if err != nil || len(recipients) == 0 {
+		return 0, err
+	}
+
+	rooms, err := s.reminderRepo.GetRooms(ctx, nil, event.ID.String())
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, recipient := range recipients {
+		// the delivery is recorded before sending, so a crash mid-send never
+		// produces a second email; a failed send releases it for the next run
+		delivery := entity.EventReminderDelivery{EventID: event.ID, UserID: recipient.UserID, OffsetMinutes: offset, SentAt: time.Now()}
+		claimed, err := s.reminderRepo.ClaimDelivery(ctx, nil, delivery)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := sendEventReminder(event, recipient, rooms); err != nil {
+			slog.ErrorContext(ctx, "failed to send event reminder", "event_id", event.ID, "email", recipient.UserEmail, "error", err)
+			if err := s.reminderRepo.ReleaseDelivery(ctx, nil, delivery); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+func sendEventReminder(event entity.Event, recipient dto.EventReminderRecipient, rooms []string) error {
+	online := revealsMeeting(event.Event_Type, recipient.RSVPStatus, recipient.AttendanceMode)
+	// the QR code is the ticket for checking in on site
+	showQR := !online && event.Event_Type != entity.EventTypeOnline
+	startsIn := reminderStartsIn(time.Until(event.Start_Time))
+	apiBaseURL := invitationApiBaseURL()
+
+	templateData := map[string]interface{}{
+		"UserName":  recipient.UserName,
+		"EventName": event.Name,
+		"StartTime": event.Start_Time.Format("Monday, 02 January 2006 15:04"),
+		"StartsIn":  startsIn,
+		"Year":      time.Now().Year(),
+		"IsPending": recipient.RSVPStatus == entity.RSVPStatusPending,
+		"ShowQR":    showQR,
+		"Location":  strings.Join(rooms, ", "),
+	}
+	if recipient.RSVPStatus == entity.RSVPStatusPending {
+		templateData["AcceptLink"] = apiBaseURL + "/api/invitation/rsvp/accept/" + recipient.QRCode
+		templateData["DeclineLink"] = apiBaseURL + "/api/invitation/rsvp/decline/" + recipient.QRCode
+	}
+	if online && event.MeetingURL != "" {
+		templateData["MeetingURL"] = event.MeetingURL
+		templateData["MeetingPasscode"] = event.MeetingPasscode
+		templateData["MeetingPlatform"] = meetingPlatformLabel(event.MeetingPlatform)
+		templateData["JoinLink"] = apiBaseURL + "/api/invitation/join/" + recipient.QRCode
+	}
+
+	var pngData []byte
+	if showQR {
+		var err error
+		if pngData, err = qrcode.Encode(recipient.QRCode, qrcode.Medium, 256); err != nil {
+			return err
+		}
+	}
+
+	return utils.SendQRCodeMail(recipient.UserEmail, "Reminder: "+event.Name+" starts "+startsIn, "reminder_mail.html", templateData, pngData)
+}
+
+// reminderStartsIn describes the time left in the largest whole unit
+func reminderStartsIn(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("in %d days", int(d.Hours()/24))
+	case d >= 24*time.Hour:
+		return "in 1 day"
+	case d >= 2*time.Hour:
+		return fmt.Sprintf("in %d hours", int(d.Hours()))
+	case d >= time.Hour:
+		return "in 1 hour"
+	case d >= 2*time.Minute:
+		return fmt.Sprintf("in %d minutes", int(d.Minutes()))
+	default:
+		return "soon"
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseReminderOffsets(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []int
+	}{
+		{"empty disables reminders", "", nil},
+		{"largest first", "60,1440,10", []int{1440, 60, 10}},
+		{"spaces", " 1440 , 60 ", []int{1440, 60}},
+		{"invalid entries are skipped", "1440,soon,0,-5,60", []int{1440, 60}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseReminderOffsets(tt.value))
+		})
+	}
+}
+
+func Test_DueReminderOffset(t *testing.T) {
+	start := time.Date(2026, time.October, 20, 9, 0, 0, 0, time.UTC)
+	event := entity.Event{Start_Time: start, ReminderOffsets: "1440,60"}
+
+	tests := []struct {
+		name       string
+		event      entity.Event
+		now        time.Time
+		wantOffset int
+		wantDue    bool
+	}{
+		{"before the first reminder", event, start.Add(-25 * time.Hour), 0, false},
+		{"day before", event, start.Add(-24 * time.Hour), 1440, true},
+		{"between the reminders", event, start.Add(-2 * time.Hour), 1440, true},
+		{"hour before wins over the day before", event, start.Add(-time.Hour), 60, true},
+		{"just before the start", event, start.Add(-time.Minute), 60, true},
+		{"reminders disabled", entity.Event{Start_Time: start}, start.Add(-time.Hour), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, due := dueReminderOffset(tt.event, tt.now)
+			assert.Equal(t, tt.wantDue, due)
+			assert.Equal(t, tt.wantOffset, offset)
+		})
+	}
+}
+
+func Test_ReminderStartsIn(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{72 * time.Hour, "in 3 days"},
+		{48 * time.Hour, "in 2 days"},
+		{30 * time.Hour, "in 1 day"},
+		{24 * time.Hour, "in 1 day"},
+		{5 * time.Hour, "in 5 hours"},
+		{90 * time.Minute, "in 1 hour"},
+		{59 * time.Minute, "in 59 minutes"},
+		{2 * time.Minute, "in 2 minutes"},
+		{time.Minute, "soon"},
+		{0, "soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, reminderStartsIn(tt.in))
+		})
+	}
+}
@@ -1,9 +1,15 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"errors"
-	"log"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -12,38 +18,76 @@ import (
 	"github.com/miraicantsleep/myits-event-be/dto"
 	"github.com/miraicantsleep/myits-event-be/entity"
 	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/miraicantsleep/myits-event-be/utils"
 )
 
+// attachmentTypes are the MIME types accepted for event attachments, mapped
+// to the extension used when storing them.
+var attachmentTypes = map[string]string{
+	"application/pdf": "pdf",
+	"image/jpeg":      "jpg",
+	"image/png":       "png",
+}
+
+// officeTypes covers documents that sniff as plain zip archives; for those the
+// file extension decides the type.
+var officeTypes = map[string]string{
+	"docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+}
+
 type (
 	EventService interface {
-		Create(ctx context.Context, req dto.EventCreateRequest, userId string) (dto.EventResponse, error)
-		GetAllEventWithPagination(ctx context.Context, req dto.PaginationRequest, user_role string, user_id string) (dto.EventPaginationResponse, error)
-		GetEventById(ctx context.Context, eventId string) (dto.EventResponse, error)
-		Update(ctx context.Context, req dto.EventUpdateRequest, eventId string) (dto.EventResponse, error)
-		Delete(ctx context.Context, eventId string) error
-		GetEventAttendees(ctx context.Context, eventId string) ([]dto.UserAttendanceResponse, error)
+		Create(ctx context.Context, req dto.EventCreateRequest, userId string, organizationId string) (dto.EventResponse, error)
+		GetAllEventWithPagination(ctx context.Context, req dto.PaginationRequest, user_role string, organization_id string) (dto.EventPaginationResponse, error)
+		GetEventById(ctx context.Context, eventId string, organizationId string) (dto.EventResponse, error)
+		Update(ctx context.Context, req dto.EventUpdateRequest, eventId string, organizationId string) (dto.EventResponse, error)
+		Delete(ctx context.Context, eventId string, organizationId string) error
+		GetEventAttendees(ctx context.Context, eventId string, organizationId string) ([]dto.UserAttendanceResponse, error)
 		GetAllUserAttendances(ctx context.Context, req dto.PaginationRequest) (dto.UserAttendancePaginationResponse, error)
+		UploadPoster(ctx context.Context, eventId string, organizationId string, file *multipart.FileHeader) (dto.EventResponse, error)
+		DeletePoster(ctx context.Context, eventId string, organizationId string) error
+		UploadAttachment(ctx context.Context, eventId string, organizationId string, file *multipart.FileHeader) (dto.EventAttachmentResponse, error)
+		DeleteAttachment(ctx context.Context, eventId string, attachmentId string, organizationId string) error
+		OpenAttachment(ctx context.Context, eventId string, attachmentId string) (dto.EventAttachmentResponse, io.ReadCloser, error)
+		GetAttendanceStats(ctx context.Context, eventId string, organizationId string) (dto.EventAttendanceStatsResponse, error)
 	}
 	eventService struct {
-		eventRepo  repository.EventRepository
-		jwtService JWTService
-		db         *gorm.DB
+		eventRepo          repository.EventRepository
+		attachmentRepo     repository.EventAttachmentRepository
+		bookingRequestRepo repository.BookingRequestRepository
+		scheduleService    RoomScheduleService
+		storage            utils.Storage
+		jwtService         JWTService
+		eventBus           EventBus
+		db                 *gorm.DB
 	}
 )
 
 func NewEventService(
 	eventRepo repository.EventRepository,
+	attachmentRepo repository.EventAttachmentRepository,
+	bookingRequestRepo repository.BookingRequestRepository,
+	scheduleService RoomScheduleService,
+	storage utils.Storage,
 	jwtService JWTService,
+	eventBus EventBus,
 	db *gorm.DB,
 ) EventService {
 	return &eventService{
-		eventRepo:  eventRepo,
-		jwtService: jwtService,
-		db:         db,
+		eventRepo:          eventRepo,
+		attachmentRepo:     attachmentRepo,
+		bookingRequestRepo: bookingRequestRepo,
+		scheduleService:    scheduleService,
+		storage:            storage,
+		jwtService:         jwtService,
+		eventBus:           eventBus,
+		db:                 db,
 	}
 }
 
-func (s *eventService) Create(ctx context.Context, req dto.EventCreateRequest, userId string) (dto.EventResponse, error) {
+func (s *eventService) Create(ctx context.Context, req dto.EventCreateRequest, userId string, organizationId string) (dto.EventResponse, error) {
 	id, err := uuid.Parse(userId)
 	if err != nil {
 		return dto.EventResponse{}, err
@@ -66,12 +110,26 @@ func (s *eventService) Create(ctx context.Context, req dto.EventCreateRequest, u
 	// }
 
 	event := entity.Event{
-		Name:        req.Name,
-		Description: req.Description,
-		Start_Time:  startTime,
-		End_Time:    endTime,
-		Event_Type:  req.Event_Type,
-		Created_By:  id,
+		Name:            req.Name,
+		Description:     req.Description,
+		Start_Time:      startTime,
+		End_Time:        endTime,
+		Event_Type:      req.Event_Type,
+		Created_By:      id,
+		MeetingURL:      req.MeetingURL,
+		MeetingPasscode: req.MeetingPasscode,
+		MeetingPlatform: req.MeetingPlatform,
+		OnlineQuota:     req.OnlineQuota,
+	}
+	if event.Event_Type != entity.EventTypeOffline && event.MeetingURL == "" {
+		return dto.EventResponse{}, dto.ErrMeetingURLRequired
+	}
+	if organizationId != "" {
+		orgId, err := uuid.Parse(organizationId)
+		if err != nil {
+			return dto.EventResponse{}, dto.ErrOrganizationNotFound
+		}
+		event.OrganizationID = &orgId
 	}
 
 	eventReg, err := s.eventRepo.Create(ctx, nil, event)
@@ -79,25 +137,17 @@ func (s *eventService) Create(ctx context.Context, req dto.EventCreateRequest, u
 		return dto.EventResponse{}, errors.New(err.Error())
 	}
 
-	return dto.EventResponse{
-		ID:          eventReg.ID.String(),
-		Name:        eventReg.Name,
-		Description: eventReg.Description,
-		Start_Time:  eventReg.Start_Time.Format(time.RFC3339),
-		End_Time:    eventReg.End_Time.Format(time.RFC3339),
-		Created_By:  eventReg.Creator_Name,
-		Event_Type:  eventReg.Event_Type,
-	}, nil
+	return s.toEventResponse(eventReg), nil
 }
 
-func (s *eventService) GetAllEventWithPagination(ctx context.Context, req dto.PaginationRequest, user_role string, user_id string) (dto.EventPaginationResponse, error) {
+func (s *eventService) GetAllEventWithPagination(ctx context.Context, req dto.PaginationRequest, user_role string, organization_id string) (dto.EventPaginationResponse, error) {
 
-	// if role ormawa only show events created by that ormawa
+	// if role ormawa only show events owned by the active organization
 	if user_role == "ormawa" {
-		// get events by user id
-		Events, err := s.eventRepo.GetEventByUserId(ctx, nil, user_id)
+		// get events by organization id
+		Events, err := s.eventRepo.GetEventByOrganizationId(ctx, nil, organization_id)
 		if err != nil {
-			return dto.EventPaginationResponse{}, errors.New("failed to get events by user id")
+			return dto.EventPaginationResponse{}, errors.New("failed to get events by organization id")
 		}
 		if len(Events) == 0 {
 			return dto.EventPaginationResponse{
@@ -113,16 +163,7 @@ func (s *eventService) GetAllEventWithPagination(ctx context.Context, req dto.Pa
 		// map events to event responses
 		var eventResponses []dto.EventResponse
 		for _, event := range Events {
-			eventResponses = append(eventResponses, dto.EventResponse{
-				ID:          event.ID.String(),
-				Name:        event.Name,
-				Description: event.Description,
-				Start_Time:  event.Start_Time.Format(time.RFC3339),
-				End_Time:    event.End_Time.Format(time.RFC3339),
-				Created_By:  event.Creator_Name,
-				Event_Type:  event.Event_Type,
-				Duration:    event.DurationInMinutes,
-			})
+			eventResponses = append(eventResponses, s.toEventResponse(event))
 		}
 		return dto.EventPaginationResponse{
 			Data: eventResponses,
@@ -142,16 +183,11 @@ func (s *eventService) GetAllEventWithPagination(ctx context.Context, req dto.Pa
 
 	var eventResponses []dto.EventResponse
 	for _, event := range EventsWithPagination.Events {
-		eventResponses = append(eventResponses, dto.EventResponse{
-			ID:          event.ID,
-			Name:        event.Name,
-			Description: event.Description,
-			Start_Time:  event.Start_Time,
-			End_Time:    event.End_Time,
-			Created_By:  event.Created_By,
-			Event_Type:  event.Event_Type,
-			Duration:    event.Duration,
-		})
+		event.DescriptionHTML = utils.RenderMarkdown(event.Description)
+		if event.PosterPath != "" {
+			event.PosterURL = s.storage.URL(event.PosterPath)
+		}
+		eventResponses = append(eventResponses, event)
 	}
 
 	return dto.EventPaginationResponse{
@@ -165,34 +201,37 @@ func (s *eventService) GetAllEventWithPagination(ctx context.Context, req dto.Pa
 	}, nil
 }
 
-func (s *eventService) GetEventById(ctx context.Context, eventId string) (dto.EventResponse, error) {
-	event, err := s.eventRepo.GetEventById(ctx, nil, eventId)
+func (s *eventService) GetEventById(ctx context.Context, eventId string, organizationId string) (dto.EventResponse, error) {
+	event, err := s.getOwnedEvent(ctx, eventId, organizationId)
 	if err != nil {
-		return dto.EventResponse{}, dto.ErrGetEventById
+		return dto.EventResponse{}, err
 	}
 
-	return dto.EventResponse{
-		ID:          event.ID.String(),
-		Name:        event.Name,
-		Description: event.Description,
-		Start_Time:  event.Start_Time.Format(time.RFC3339),
-		End_Time:    event.End_Time.Format(time.RFC3339),
-		Created_By:  event.Creator_Name,
-		Event_Type:  event.Event_Type,
-	}, nil
+	attachments, err := s.attachmentRepo.GetEventAttachments(ctx, nil, event.ID)
+	if err != nil {
+		return dto.EventResponse{}, err
+	}
+
+	response := s.toEventResponse(event)
+	for _, attachment := range attachments {
+		response.Attachments = append(response.Attachments, toEventAttachmentResponse(attachment))
+	}
+	return response, nil
 }
-func (s *eventService) Update(ctx context.Context, req dto.EventUpdateRequest, eventId string) (dto.EventResponse, error) {
+func (s *eventService) Update(ctx context.Context, req dto.EventUpdateRequest, eventId string, organizationId string) (dto.EventResponse, error) {
 	id, err := uuid.Parse(eventId)
-	log.Println("eventId:", eventId, "parsed ID:", id)
+	slog.DebugContext(ctx, "get event", "event_id", id)
 	if err != nil {
 		return dto.EventResponse{}, err
 	}
 
-	event, err := s.eventRepo.GetEventById(ctx, nil, id.String())
+	event, err := s.getOwnedEvent(ctx, id.String(), organizationId)
 	if err != nil {
-		return dto.EventResponse{}, dto.ErrEventNotFound
+		return dto.EventResponse{}, err
 	}
 
+	previousStart, previousEnd := event.Start_Time, event.End_Time
+
 	if req.Name != "" {
 		event.Name = req.Name
 	}
@@ -216,6 +255,18 @@ func (s *eventService) Update(ctx context.Context, req dto.EventUpdateRequest, e
 	if req.Event_Type != "" {
 		event.Event_Type = req.Event_Type
 	}
+	if req.MeetingURL != "" {
+		event.MeetingURL = req.MeetingURL
+	}
+	if req.MeetingPasscode != "" {
+		event.MeetingPasscode = req.MeetingPasscode
+	}
+	if req.MeetingPlatform != "" {
+		event.MeetingPlatform = req.MeetingPlatform
+	}
+	if event.Event_Type != entity.EventTypeOffline && event.MeetingURL == "" {
+		return dto.EventResponse{}, dto.ErrMeetingURLRequired
+	}
 
 	// check if event with the same name already exists
 	exists, _ := s.eventRepo.CheckEventExist(ctx, nil, event.Name)
@@ -223,41 +274,126 @@ func (s *eventService) Update(ctx context.Context, req dto.EventUpdateRequest, e
 		return dto.EventResponse{}, errors.New("event with the same name already exists")
 	}
 
-	updatedEvent, err := s.eventRepo.Update(ctx, nil, event)
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	updatedEvent, err := s.eventRepo.Update(ctx, tx, event)
 	if err != nil {
+		tx.Rollback()
 		return dto.EventResponse{}, dto.ErrUpdateEvent
 	}
-	return dto.EventResponse{
-		ID:          updatedEvent.ID.String(),
-		Name:        updatedEvent.Name,
-		Description: updatedEvent.Description,
-		Start_Time:  updatedEvent.Start_Time.Format(time.RFC3339),
-		End_Time:    updatedEvent.End_Time.Format(time.RFC3339),
-		Created_By:  updatedEvent.Creator_Name,
-		Event_Type:  updatedEvent.Event_Type,
-	}, nil
+	if req.OnlineQuota != nil {
+		if err := s.eventRepo.UpdateOnlineQuota(ctx, tx, eventId, *req.OnlineQuota); err != nil {
+			tx.Rollback()
+			return dto.EventResponse{}, dto.ErrUpdateEvent
+		}
+		updatedEvent.OnlineQuota = *req.OnlineQuota
+	}
+
+	updated := dto.EventUpdated{
+		EventID:     event.ID,
+		EventName:   event.Name,
+		StartTime:   event.Start_Time,
+		EndTime:     event.End_Time,
+		Rescheduled: !event.Start_Time.Equal(previousStart) || !event.End_Time.Equal(previousEnd),
+	}
+
+	// the booked rooms must still be open at the new time
+	if updated.Rescheduled {
+		bookingRequests, err := s.bookingRequestRepo.GetActiveBookingRequestsByEvent(ctx, tx, event.ID)
+		if err != nil {
+			tx.Rollback()
+			return dto.EventResponse{}, dto.ErrUpdateEvent
+		}
+		for _, bookingRequest := range bookingRequests {
+			for _, room := range bookingRequest.Rooms {
+				if err := checkBookedRoom(ctx, tx, s.scheduleService, room, event); err != nil {
+					tx.Rollback()
+					return dto.EventResponse{}, err
+				}
+			}
+		}
+	}
+	if err := s.eventBus.Publish(ctx, tx, updated); err != nil {
+		tx.Rollback()
+		return dto.EventResponse{}, dto.ErrUpdateEvent
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return dto.EventResponse{}, dto.ErrUpdateEvent
+	}
+	return s.toEventResponse(updatedEvent), nil
 }
 
-func (s *eventService) Delete(ctx context.Context, eventId string) error {
+// GetAttendanceStats reports accepted and attended invitees per attendance
+// mode. In-person capacity comes from the rooms of approved bookings and
+// online capacity from the event's online quota; 0 means unlimited.
+func (s *eventService) GetAttendanceStats(ctx context.Context, eventId string, organizationId string) (dto.EventAttendanceStatsResponse, error) {
+	event, err := s.getOwnedEvent(ctx, eventId, organizationId)
+	if err != nil {
+		return dto.EventAttendanceStatsResponse{}, err
+	}
+
+	capacity, err := s.eventRepo.GetApprovedRoomCapacity(ctx, nil, eventId)
+	if err != nil {
+		return dto.EventAttendanceStatsResponse{}, err
+	}
+
+	counts, err := s.eventRepo.GetAttendanceModeCounts(ctx, nil, eventId)
+	if err != nil {
+		return dto.EventAttendanceStatsResponse{}, err
+	}
+
+	response := dto.EventAttendanceStatsResponse{
+		EventID:   eventId,
+		EventType: event.Event_Type,
+		InPerson:  dto.AttendanceModeStats{Capacity: capacity},
+		Online:    dto.AttendanceModeStats{Capacity: event.OnlineQuota},
+	}
+	for _, count := range counts {
+		stats := &response.InPerson
+		if count.Mode == entity.AttendanceModeOnline {
+			stats = &response.Online
+		}
+		stats.Accepted += count.Accepted
+		stats.Attended += count.Attended
+	}
+	return response, nil
+}
+
+func (s *eventService) Delete(ctx context.Context, eventId string, organizationId string) error {
 	id, err := uuid.Parse(eventId)
 	if err != nil {
 		return err
 	}
 
-	event, err := s.eventRepo.GetEventById(ctx, nil, id.String())
+	event, err := s.getOwnedEvent(ctx, id.String(), organizationId)
 	if err != nil {
-		return dto.ErrGetEventById
+		return err
 	}
 
-	err = s.eventRepo.Delete(ctx, nil, event.ID.String())
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	err = s.eventRepo.Delete(ctx, tx, event.ID.String())
 	if err != nil {
+		tx.Rollback()
 		return dto.ErrDeleteEvent
 	}
 
-	return nil
+	if err := s.eventBus.Publish(ctx, tx, dto.EventCancelled{EventID: event.ID, EventName: event.Name}); err != nil {
+		tx.Rollback()
+		return dto.ErrDeleteEvent
+	}
+
+	return tx.Commit().Error
 }
 
-func (s *eventService) GetEventAttendees(ctx context.Context, eventId string) ([]dto.UserAttendanceResponse, error) {
+func (s *eventService) GetEventAttendees(ctx context.Context, eventId string, organizationId string) ([]dto.UserAttendanceResponse, error) {
+	if _, err := s.getOwnedEvent(ctx, eventId, organizationId); err != nil {
+		return nil, err
+	}
+
 	attendees, err := s.eventRepo.GetEventAttendees(ctx, nil, eventId)
 	if err != nil {
 		return nil, err
@@ -276,3 +412,193 @@ func (s *eventService) GetAllUserAttendances(ctx context.Context, req dto.Pagina
 		PaginationResponse: result.PaginationResponse,
 	}, nil
 }
+
+func (s *eventService) UploadPoster(ctx context.Context, eventId string, organizationId string, file *multipart.FileHeader) (dto.EventResponse, error) {
+	if file == nil {
+		return dto.EventResponse{}, dto.ErrFileRequired
+	}
+
+	event, err := s.getOwnedEvent(ctx, eventId, organizationId)
+	if err != nil {
+		return dto.EventResponse{}, err
+	}
+
+	data, mimeType, err := readUpload(file)
+	if err != nil {
+		return dto.EventResponse{}, err
+	}
+	ext, ok := utils.ImageExtensions[mimeType]
+	if !ok {
+		return dto.EventResponse{}, dto.ErrEventPosterInvalidType
+	}
+
+	key := fmt.Sprintf("events/%s/poster-%s.%s", event.ID, uuid.New(), ext)
+	if err := s.storage.Save(key, bytes.NewReader(data)); err != nil {
+		return dto.EventResponse{}, err
+	}
+
+	if err := s.eventRepo.UpdatePoster(ctx, nil, event.ID.String(), key); err != nil {
+		_ = s.storage.Delete(key)
+		return dto.EventResponse{}, err
+	}
+	if event.PosterPath != "" {
+		_ = s.storage.Delete(event.PosterPath)
+	}
+
+	event.PosterPath = key
+	return s.toEventResponse(event), nil
+}
+
+func (s *eventService) DeletePoster(ctx context.Context, eventId string, organizationId string) error {
+	event, err := s.getOwnedEvent(ctx, eventId, organizationId)
+	if err != nil {
+		return err
+	}
+	if event.PosterPath == "" {
+		return dto.ErrEventPosterNotFound
+	}
+
+	if err := s.eventRepo.UpdatePoster(ctx, nil, event.ID.String(), ""); err != nil {
+		return err
+	}
+	_ = s.storage.Delete(event.PosterPath)
+	return nil
+}
+
+func (s *eventService) UploadAttachment(ctx context.Context, eventId string, organizationId string, file *multipart.FileHeader) (dto.EventAttachmentResponse, error) {
+	if file == nil {
+		return dto.EventAttachmentResponse{}, dto.ErrFileRequired
+	}
+
+	event, err := s.getOwnedEvent(ctx, eventId, organizationId)
+	if err != nil {
+		return dto.EventAttachmentResponse{}, err
+	}
+
+	data, mimeType, err := readUpload(file)
+	if err != nil {
+		return dto.EventAttachmentResponse{}, err
+	}
+
+	name := filepath.Base(filepath.Clean(strings.ReplaceAll(file.Filename, "\\", "/")))
+	ext, ok := attachmentTypes[mimeType]
+	if !ok && mimeType == "application/zip" {
+		ext = strings.ToLower(strings.TrimPrefix(filepath.Ext(name), "."))
+		mimeType, ok = officeTypes[ext]
+	}
+	if !ok {
+		return dto.EventAttachmentResponse{}, dto.ErrAttachmentInvalidType
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+
+	key := fmt.Sprintf("events/%s/attachments/%s.%s", event.ID, uuid.New(), ext)
+	if err := s.storage.Save(key, bytes.NewReader(data)); err != nil {
+		return dto.EventAttachmentResponse{}, err
+	}
+
+	attachment, err := s.attachmentRepo.Create(ctx, nil, entity.EventAttachment{
+		EventID:  event.ID,
+		Name:     name,
+		Path:     key,
+		MimeType: mimeType,
+		Size:     int64(len(data)),
+	})
+	if err != nil {
+		_ = s.storage.Delete(key)
+		return dto.EventAttachmentResponse{}, err
+	}
+
+	return toEventAttachmentResponse(attachment), nil
+}
+
+func (s *eventService) DeleteAttachment(ctx context.Context, eventId string, attachmentId string, organizationId string) error {
+	if _, err := s.getOwnedEvent(ctx, eventId, organizationId); err != nil {
+		return err
+	}
+
+	attachment, err := s.getEventAttachment(ctx, eventId, attachmentId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.attachmentRepo.Delete(ctx, nil, attachment.ID); err != nil {
+		return err
+	}
+	_ = s.storage.Delete(attachment.Path)
+	return nil
+}
+
+func (s *eventService) OpenAttachment(ctx context.Context, eventId string, attachmentId string) (dto.EventAttachmentResponse, io.ReadCloser, error) {
+	attachment, err := s.getEventAttachment(ctx, eventId, attachmentId)
+	if err != nil {
+		return dto.EventAttachmentResponse{}, nil, err
+	}
+
+	reader, err := s.storage.Open(attachment.Path)
+	if err != nil {
+		return dto.EventAttachmentResponse{}, nil, dto.ErrAttachmentNotFound
+	}
+	return toEventAttachmentResponse(attachment), reader, nil
+}
+
+// getOwnedEvent loads an event on behalf of the caller's active organization.
+// An empty organizationId (admin) may act on any event.
+func (s *eventService) getOwnedEvent(ctx context.Context, eventId string, organizationId string) (entity.Event, error) {
+	event, err := s.eventRepo.GetEventById(ctx, nil, eventId)
+	if err != nil {
+		return entity.Event{}, dto.ErrEventNotFound
+	}
+	if err := checkEventOwnership(event, organizationId); err != nil {
+		return entity.Event{}, err
+	}
+	return event, nil
+}
+
+func (s *eventService) getEventAttachment(ctx context.Context, eventId string, attachmentId string) (entity.EventAttachment, error) {
+	attachment, err := s.attachmentRepo.GetAttachmentByID(ctx, nil, attachmentId)
+	if err != nil {
+		return entity.EventAttachment{}, err
+	}
+	if attachment.EventID.String() != eventId {
+		return entity.EventAttachment{}, dto.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+func (s *eventService) toEventResponse(event entity.Event) dto.EventResponse {
+	response := dto.EventResponse{
+		ID:              event.ID.String(),
+		Name:            event.Name,
+		Description:     event.Description,
+		DescriptionHTML: utils.RenderMarkdown(event.Description),
+		Start_Time:      event.Start_Time.Format(time.RFC3339),
+		End_Time:        event.End_Time.Format(time.RFC3339),
+		Created_By:      event.Creator_Name,
+		Event_Type:      event.Event_Type,
+		Duration:        event.DurationInMinutes,
+		OnlineQuota:     event.OnlineQuota,
+		MeetingURL:      event.MeetingURL,
+		MeetingPasscode: event.MeetingPasscode,
+		MeetingPlatform: event.MeetingPlatform,
+		PosterPath:      event.PosterPath,
+	}
+	if event.OrganizationID != nil {
+		response.OrganizationID = event.OrganizationID.String()
+	}
+	if event.PosterPath != "" {
+		response.PosterURL = s.storage.URL(event.PosterPath)
+	}
+	return response
+}
+
+func toEventAttachmentResponse(attachment entity.EventAttachment) dto.EventAttachmentResponse {
+	return dto.EventAttachmentResponse{
+		ID:          attachment.ID.String(),
+		Name:        attachment.Name,
+		MimeType:    attachment.MimeType,
+		Size:        attachment.Size,
+		DownloadURL: fmt.Sprintf("/api/event/%s/attachments/%s", attachment.EventID, attachment.ID),
+	}
+}
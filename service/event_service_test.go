@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// fakeEventRepository serves one event by id, with the capacity of its
+// approved rooms
+type fakeEventRepository struct {
+	repository.EventRepository
+	event    entity.Event
+	capacity int
+}
+
+func (r *fakeEventRepository) GetApprovedRoomCapacity(ctx context.Context, tx *gorm.DB, eventId string) (int, error) {
+	return r.capacity, nil
+}
+
+func (r *fakeEventRepository) GetEventById(ctx context.Context, tx *gorm.DB, eventId string) (entity.Event, error) {
+	if eventId != r.event.ID.String() {
+		return entity.Event{}, gorm.ErrRecordNotFound
+	}
+	return r.event, nil
+}
+
+// fakeEventAttachmentRepository has no attachments
+type fakeEventAttachmentRepository struct {
+	repository.EventAttachmentRepository
+}
+
+func (r *fakeEventAttachmentRepository) GetEventAttachments(ctx context.Context, tx *gorm.DB, eventID uuid.UUID) ([]entity.EventAttachment, error) {
+	return nil, nil
+}
+
+func Test_EventService_GetEventById(t *testing.T) {
+	owner := uuid.New()
+	event := entity.Event{ID: uuid.New(), Name: "Seminar", OrganizationID: &owner}
+
+	tests := []struct {
+		name           string
+		eventId        string
+		organizationId string
+		wantErr        error
+	}{
+		{"admin", event.ID.String(), "", nil},
+		{"owning organization", event.ID.String(), owner.String(), nil},
+		{"other organization", event.ID.String(), uuid.NewString(), dto.ErrOrganizationForbidden},
+		{"unknown event", uuid.NewString(), owner.String(), dto.ErrEventNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewEventService(&fakeEventRepository{event: event}, &fakeEventAttachmentRepository{}, nil, nil, nil, nil, nil, nil)
+
+			got, err := s.GetEventById(context.Background(), tt.eventId, tt.organizationId)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, event.Name, got.Name)
+		})
+	}
+}
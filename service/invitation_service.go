@@ -3,11 +3,13 @@ package service
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/miraicantsleep/myits-event-be/config" // Added for EmailConfig
+	"github.com/miraicantsleep/myits-event-be/constants"
 	"github.com/miraicantsleep/myits-event-be/dto"
 	"github.com/miraicantsleep/myits-event-be/entity"
 	"github.com/miraicantsleep/myits-event-be/repository"
@@ -18,44 +20,86 @@ import (
 
 type (
 	InvitationService interface {
-		Create(ctx context.Context, req dto.CreateInvitationRequest) (dto.CreateInvitationResponse, error)
-		GetInvitationByID(ctx context.Context, invitationID string) ([]dto.InvitationResponse, error)
-		GetInvitationByEventID(ctx context.Context, eventID string) ([]dto.InvitationResponse, error)
-		GetInvitationByUserID(ctx context.Context, userID string) ([]dto.InvitationResponse, error)
+		Create(ctx context.Context, req dto.CreateInvitationRequest, organizationId string) (dto.CreateInvitationResponse, error)
+		// GetInvitationByID lists the invitees of an invitation to admins and
+		// the organization owning the event; an invitee only sees themselves
+		GetInvitationByID(ctx context.Context, invitationID string, userId string, role string, organizationId string) ([]dto.InvitationResponse, error)
+		GetInvitationByEventID(ctx context.Context, eventID string, organizationId string) ([]dto.InvitationResponse, error)
+		// GetInvitationByUserID lists the invitations of a user to the user
+		// themselves, to admins, and to an organization for its own events
+		GetInvitationByUserID(ctx context.Context, userID string, callerId string, role string, organizationId string) ([]dto.InvitationResponse, error)
 		GetAllInvitations(ctx context.Context) ([]dto.InvitationResponse, error)
-		Update(ctx context.Context, invitationID string, req dto.UpdateInvitationRequest) (dto.InvitationResponse, error)
-		Delete(ctx context.Context, invitationID string) error
+		Update(ctx context.Context, invitationID string, req dto.UpdateInvitationRequest, organizationId string) (dto.InvitationResponse, error)
+		Delete(ctx context.Context, invitationID string, organizationId string) error
 		ScanQRCode(ctx context.Context, qrCode string) (dto.ScanQRCodeResponse, error)
-		ProcessRSVP(ctx context.Context, qrCodeToken string, newRsvpStatus string) error // New method
+		ProcessRSVP(ctx context.Context, qrCodeToken string, newRsvpStatus string, attendanceMode string) error // New method
+		JoinOnlineEvent(ctx context.Context, token string) (string, error)
+		ResendInvitationEmail(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) error
+		// SendInvitationEmails sends the invitation email to each of the
+		// users and records the outcome per user
+		SendInvitationEmails(ctx context.Context, invitationID uuid.UUID, userIDs []uuid.UUID) error
+		// SendMeetingDetails mails the meeting link to an invitee who accepted
+		// to attend online; it does nothing for anyone else
+		SendMeetingDetails(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) error
 	}
 
 	invitationService struct {
 		invitationRepo repository.InvitationRepository
+		eventRepo      repository.EventRepository
 		jwtService     JWTService
+		eventBus       EventBus
 		db             *gorm.DB
 	}
 )
 
 func NewInvitationService(
 	invitationRepo repository.InvitationRepository,
+	eventRepo repository.EventRepository,
 	jwtService JWTService,
+	eventBus EventBus,
 	db *gorm.DB,
 ) InvitationService {
 	return &invitationService{
 		invitationRepo: invitationRepo,
+		eventRepo:      eventRepo,
 		jwtService:     jwtService,
+		eventBus:       eventBus,
 		db:             db,
 	}
 }
 
+// InvitationSubscribers send the emails that follow an invitation or an RSVP
+func InvitationSubscribers(invitationService InvitationService) []Subscriber {
+	return []Subscriber{
+		Subscribe("send_invitation_emails", func(ctx context.Context, event dto.InvitationCreated) error {
+			return invitationService.SendInvitationEmails(ctx, event.InvitationID, event.UserIDs)
+		}),
+		Subscribe("send_meeting_details", func(ctx context.Context, event dto.RSVPChanged) error {
+			if event.RSVPStatus != entity.RSVPStatusAccepted {
+				return nil
+			}
+			return invitationService.SendMeetingDetails(ctx, event.InvitationID, event.UserID)
+		}),
+	}
+}
+
 // Create handles invitation creation, skipping already-invited users
-func (s *invitationService) Create(ctx context.Context, req dto.CreateInvitationRequest) (dto.CreateInvitationResponse, error) {
+func (s *invitationService) Create(ctx context.Context, req dto.CreateInvitationRequest, organizationId string) (dto.CreateInvitationResponse, error) {
 	// parse event ID
 	eventID, err := uuid.Parse(req.EventID)
 	if err != nil {
 		return dto.CreateInvitationResponse{}, err
 	}
 
+	// only members of the owning organization may invite to an event
+	event, err := s.eventRepo.GetEventById(ctx, nil, eventID.String())
+	if err != nil {
+		return dto.CreateInvitationResponse{}, dto.ErrEventNotFound
+	}
+	if err := checkEventOwnership(event, organizationId); err != nil {
+		return dto.CreateInvitationResponse{}, err
+	}
+
 	// prepare user entities
 	users := make([]entity.User, len(req.UserIDs))
 	for i, id := range req.UserIDs {
@@ -82,92 +126,34 @@ func (s *invitationService) Create(ctx context.Context, req dto.CreateInvitation
 		return dto.CreateInvitationResponse{}, dto.ErrInvitationAlreadyExists
 	}
 
-	// create invitation with filtered users
-	inv, err := s.invitationRepo.Create(ctx, nil, entity.Invitation{EventID: eventID, Users: toInvite})
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	// create invitation with filtered users; the id is chosen here since the
+	// reloaded invitation also carries the event's columns
+	invitationID := uuid.New()
+	inv, err := s.invitationRepo.Create(ctx, tx, entity.Invitation{ID: invitationID, EventID: eventID, Users: toInvite})
 	if err != nil {
+		tx.Rollback()
 		return dto.CreateInvitationResponse{}, err
 	}
-	// assemble response and send emails to invited users
-	names := make([]string, len(inv.Users))
-	for i, u := range inv.Users { // u is entity.User
-		names[i] = u.Name // This was already there
-
-		// Fetch the UserInvitation to get the QRCode
-		userInvitation, errUI := s.invitationRepo.GetUserInvitation(ctx, nil, inv.ID, u.ID)
-		var pngData []byte // Declare pngData to be accessible for the final send
-		var errQR error
-
-		if errUI != nil {
-			log.Printf("Error fetching UserInvitation for user %s (email: %s): %v. Sending plain invitation.", u.ID, u.Email, errUI)
-			// Fallback to old SendMail or a simplified text email if QR fetch fails
-			plainBody := "You have been invited to " + inv.Event.Name + ". Please contact support if you did not receive your QR code (fetch error)."
-			errSend := utils.SendMail(u.Email, "Event Invitation: "+inv.Event.Name, plainBody)
-			if errSend != nil {
-				log.Println("Failed to send plain invitation email to", u.Email, ":", errSend)
-			}
-			continue // Move to next user
-		}
-
-		if userInvitation.QRCode == "" {
-			log.Printf("QRCode is empty for UserInvitation UserID: %s, InvitationID: %s. Sending plain invitation.", userInvitation.UserID, userInvitation.InvitationID)
-			// Fallback to old SendMail or a simplified text email if QR is empty
-			plainBody := "You have been invited to " + inv.Event.Name + ". Please contact support if you did not receive your QR code (empty QR)."
-			errSend := utils.SendMail(u.Email, "Event Invitation: "+inv.Event.Name, plainBody)
-			if errSend != nil {
-				log.Println("Failed to send plain invitation email to", u.Email, ":", errSend)
-			}
-			continue // Move to next user
-		}
 
-		log.Printf("Generating QR code image for UserID: %s, InvitationID: %s, QRCode: %s", u.ID, inv.ID, userInvitation.QRCode)
-		pngData, errQR = qrcode.Encode(userInvitation.QRCode, qrcode.Medium, 256) // pngData is []byte
-		if errQR != nil {
-			log.Printf("Error generating QR code image for user %s (email: %s): %v. Sending plain invitation.", u.ID, u.Email, errQR)
-			// Fallback to old SendMail or a simplified text email if QR generation fails
-			plainBody := "You have been invited to " + inv.Event.Name + ". Please contact support if you did not receive your QR code (generation error)."
-			errSend := utils.SendMail(u.Email, "Event Invitation: "+inv.Event.Name, plainBody)
-			if errSend != nil {
-				log.Println("Failed to send plain invitation email to", u.Email, ":", errSend)
-			}
-			continue // Move to next user
-		}
-
-		log.Printf("Successfully generated QR code image for user %s, size: %d bytes", u.ID, len(pngData))
-
-		// Load EmailConfig to get ApiBaseUrl
-		emailCfg, errCfg := config.NewEmailConfig() // This loads .env and unmarshals
-		apiBaseURL := ""
-		if errCfg != nil {
-			log.Printf("Warning: Could not load email config to get API_BASE_URL: %v. RSVP links may be relative/broken.", errCfg)
-		} else {
-			apiBaseURL = emailCfg.ApiBaseUrl
-		}
-
-		if apiBaseURL == "" {
-			log.Println("Warning: API_BASE_URL is not set in config. RSVP links in email will be relative or may not work as expected.")
-		}
-
-		acceptLink := apiBaseURL + "/api/invitation/rsvp/accept/" + userInvitation.QRCode
-		declineLink := apiBaseURL + "/api/invitation/rsvp/decline/" + userInvitation.QRCode
-		// The userInvitation.QRCode is the token
+	names := make([]string, len(inv.Users))
+	userIDs := make([]uuid.UUID, len(inv.Users))
+	for i, u := range inv.Users {
+		names[i] = u.Name
+		userIDs[i] = u.ID
+	}
 
-		templateData := map[string]interface{}{
-			"UserName":    u.Name,
-			"EventName":   inv.Event.Name,
-			"Year":        time.Now().Year(),
-			"AcceptLink":  acceptLink,
-			"DeclineLink": declineLink,
-		}
+	// emails and notifications go out once the invitation is committed
+	created := dto.InvitationCreated{InvitationID: invitationID, EventID: eventID, EventName: event.Name, UserIDs: userIDs}
+	if err := s.eventBus.Publish(ctx, tx, created); err != nil {
+		tx.Rollback()
+		return dto.CreateInvitationResponse{}, err
+	}
 
-		emailSubject := "You're Invited to " + inv.Event.Name + "!"
-		errSend := utils.SendInvitationMail(u.Email, emailSubject, templateData, pngData)
-		if errSend != nil {
-			log.Println("Failed to send styled invitation email with QR to", u.Email, ":", errSend)
-			// Optional: Fallback to plain text email if styled email fails?
-			// For now, just log the error and assume if SendInvitationMail fails, it's a more significant issue.
-		} else {
-			log.Println("Successfully sent invitation with QR code to", u.Email)
-		}
+	if err := tx.Commit().Error; err != nil {
+		return dto.CreateInvitationResponse{}, err
 	}
 
 	now := time.Now().Format(time.RFC3339)
@@ -180,7 +166,7 @@ func (s *invitationService) Create(ctx context.Context, req dto.CreateInvitation
 	}, nil
 }
 
-func (s *invitationService) GetInvitationByID(ctx context.Context, invitationID string) ([]dto.InvitationResponse, error) {
+func (s *invitationService) GetInvitationByID(ctx context.Context, invitationID string, userId string, role string, organizationId string) ([]dto.InvitationResponse, error) {
 	// Parse invitation ID
 	id, err := uuid.Parse(invitationID)
 	if err != nil {
@@ -193,9 +179,26 @@ func (s *invitationService) GetInvitationByID(ctx context.Context, invitationID
 		return nil, err
 	}
 
-	// If no invitation is found, return an empty slice.
 	if len(invitationDetails) == 0 {
-		return []dto.InvitationResponse{}, nil
+		return nil, dto.ErrInvitationNotFound
+	}
+
+	// invitees who do not manage the event only see their own row
+	manages, err := s.managesInvitations(ctx, invitationDetails[0].EventID, role, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	if !manages {
+		var own []dto.InvitationDetailResponse
+		for _, detail := range invitationDetails {
+			if detail.UserID.String() == userId {
+				own = append(own, detail)
+			}
+		}
+		if len(own) == 0 {
+			return nil, dto.ErrInvitationForbidden
+		}
+		invitationDetails = own
 	}
 
 	// 2. Assemble the response by mapping the flat details to the response DTO.
@@ -204,24 +207,70 @@ func (s *invitationService) GetInvitationByID(ctx context.Context, invitationID
 	for i, detail := range invitationDetails {
 		resp[i] = dto.InvitationResponse{
 			ID:         detail.InvitationID.String(),
+			EventID:    detail.EventID.String(),
 			EventName:  detail.EventName,
 			Name:       detail.UserName,
 			InvitedAt:  detail.InvitedAt.Format(time.RFC3339),
 			RSVPStatus: detail.RSVPStatus,
 			RsvpAt:     utils.FormatTimePointer(detail.RsvpAt),
-			QRCode:     detail.QRCode,
+			EventType:  detail.EventType,
+
+			AttendanceMode: detail.AttendanceMode,
+		}
+		if detail.UserID.String() == userId {
+			resp[i].QRCode = detail.QRCode
+			revealMeeting(&resp[i], detail.MeetingURL, detail.MeetingPasscode, detail.MeetingPlatform)
 		}
 	}
 
 	return resp, nil
 }
 
-func (s *invitationService) GetInvitationByEventID(ctx context.Context, eventID string) ([]dto.InvitationResponse, error) {
+// managesInvitations reports whether the caller sees every invitee of the
+// event: admins do, and ormawa acting for the organization owning it
+func (s *invitationService) managesInvitations(ctx context.Context, eventID uuid.UUID, role string, organizationId string) (bool, error) {
+	switch {
+	case role == constants.ENUM_ROLE_ADMIN:
+		return true, nil
+	case role != constants.ENUM_ROLE_ORMAWA || organizationId == "":
+		return false, nil
+	}
+
+	event, err := s.eventRepo.GetEventById(ctx, nil, eventID.String())
+	if err != nil {
+		return false, dto.ErrEventNotFound
+	}
+	return checkEventOwnership(event, organizationId) == nil, nil
+}
+
+// revealMeeting fills the meeting details and join link of the caller's own
+// invitation once revealsMeeting allows it; the join link carries their
+// token, so it must never be built for anyone else
+func revealMeeting(resp *dto.InvitationResponse, meetingURL string, meetingPasscode string, meetingPlatform string) {
+	if resp.QRCode == "" || !revealsMeeting(resp.EventType, resp.RSVPStatus, resp.AttendanceMode) {
+		return
+	}
+	resp.MeetingURL = meetingURL
+	resp.MeetingPasscode = meetingPasscode
+	resp.MeetingPlatform = meetingPlatform
+	resp.JoinURL = invitationApiBaseURL() + "/api/invitation/join/" + resp.QRCode
+}
+
+func (s *invitationService) GetInvitationByEventID(ctx context.Context, eventID string, organizationId string) ([]dto.InvitationResponse, error) {
 	// parse event ID
 	id, err := uuid.Parse(eventID)
 	if err != nil {
 		return nil, err
 	}
+
+	event, err := s.eventRepo.GetEventById(ctx, nil, id.String())
+	if err != nil {
+		return nil, dto.ErrEventNotFound
+	}
+	if err := checkEventOwnership(event, organizationId); err != nil {
+		return nil, err
+	}
+
 	// fetch invitations by event ID
 	invitations, err := s.invitationRepo.GetInvitationByEvent(ctx, nil, id)
 	if err != nil {
@@ -236,19 +285,49 @@ func (s *invitationService) GetAllInvitations(ctx context.Context) ([]dto.Invita
 	return []dto.InvitationResponse{}, dto.ErrGetAllInvitations
 }
 
-func (s *invitationService) Update(ctx context.Context, invitationID string, req dto.UpdateInvitationRequest) (dto.InvitationResponse, error) {
+func (s *invitationService) Update(ctx context.Context, invitationID string, req dto.UpdateInvitationRequest, organizationId string) (dto.InvitationResponse, error) {
+	if _, err := s.getOwnedInvitation(ctx, invitationID, organizationId); err != nil {
+		return dto.InvitationResponse{}, err
+	}
+
 	// not implemented
 	return dto.InvitationResponse{}, nil
 }
 
-func (s *invitationService) Delete(ctx context.Context, invitationID string) error {
-	id := uuid.MustParse(invitationID)
-	if err := s.invitationRepo.Delete(ctx, nil, id); err != nil {
+func (s *invitationService) Delete(ctx context.Context, invitationID string, organizationId string) error {
+	invitation, err := s.getOwnedInvitation(ctx, invitationID, organizationId)
+	if err != nil {
+		return err
+	}
+	if err := s.invitationRepo.Delete(ctx, nil, invitation.ID); err != nil {
 		return err
 	}
 	return nil
 }
 
+// getOwnedInvitation loads an invitation, rejecting one whose event belongs
+// to another organization than the caller acts for
+func (s *invitationService) getOwnedInvitation(ctx context.Context, invitationID string, organizationId string) (entity.Invitation, error) {
+	id, err := uuid.Parse(invitationID)
+	if err != nil {
+		return entity.Invitation{}, err
+	}
+
+	invitation, err := s.invitationRepo.GetInvitation(ctx, nil, id)
+	if err != nil {
+		return entity.Invitation{}, dto.ErrInvitationNotFound
+	}
+
+	event, err := s.eventRepo.GetEventById(ctx, nil, invitation.EventID.String())
+	if err != nil {
+		return entity.Invitation{}, dto.ErrEventNotFound
+	}
+	if err := checkEventOwnership(event, organizationId); err != nil {
+		return entity.Invitation{}, err
+	}
+	return invitation, nil
+}
+
 func (s *invitationService) ScanQRCode(ctx context.Context, qrCode string) (dto.ScanQRCodeResponse, error) {
 	userInvitation, err := s.invitationRepo.GetUserInvitationByQRCode(ctx, nil, qrCode)
 	if err != nil {
@@ -261,17 +340,39 @@ func (s *invitationService) ScanQRCode(ctx context.Context, qrCode string) (dto.
 	if userInvitation.AttendedAt != nil {
 		return dto.ScanQRCodeResponse{}, errors.New("QR code already used")
 	}
+	if userInvitation.AttendanceMode != nil && *userInvitation.AttendanceMode == entity.AttendanceModeOnline {
+		return dto.ScanQRCodeResponse{}, dto.ErrAttendingOnline
+	}
+
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
 
 	now := time.Now()
 	userInvitation.AttendedAt = &now
-	updatedUserInvitation, err := s.invitationRepo.UpdateUserInvitation(ctx, nil, userInvitation)
+	updatedUserInvitation, err := s.invitationRepo.UpdateUserInvitation(ctx, tx, userInvitation)
 	if err != nil {
+		tx.Rollback()
 		return dto.ScanQRCodeResponse{}, err // Error during update
 	}
 
+	invitation, err := s.invitationRepo.GetInvitationDetailByQRCode(ctx, tx, qrCode)
+	if err != nil {
+		tx.Rollback()
+		return dto.ScanQRCodeResponse{}, err
+	}
+	marked := dto.AttendanceMarked{InvitationID: userInvitation.InvitationID, EventID: invitation.EventID, UserID: userInvitation.UserID, AttendedAt: now}
+	if err := s.eventBus.Publish(ctx, tx, marked); err != nil {
+		tx.Rollback()
+		return dto.ScanQRCodeResponse{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return dto.ScanQRCodeResponse{}, err
+	}
+
 	var details dto.InvitationDetailResponse
 	if err := s.db.WithContext(ctx).Table("full_invitation_details").Where("qr_code = ?", qrCode).First(&details).Error; err != nil {
-		log.Printf("Error fetching full details for ScanQRCode response: %v", err)
+		slog.WarnContext(ctx, "failed to fetch details for scan response", "error", err)
 		return dto.ScanQRCodeResponse{
 			UserID:     updatedUserInvitation.UserID.String(),
 			AttendedAt: updatedUserInvitation.AttendedAt.Format(time.RFC3339),
@@ -289,60 +390,449 @@ func (s *invitationService) ScanQRCode(ctx context.Context, qrCode string) (dto.
 }
 
 // ProcessRSVP handles updating the RSVP status for an invitation based on a token.
-func (s *invitationService) ProcessRSVP(ctx context.Context, qrCodeToken string, newRsvpStatus string) error {
-	userInvitation, err := s.invitationRepo.GetUserInvitationByQRCode(ctx, nil, qrCodeToken)
+// Accepting a hybrid event requires an attendance mode, and is checked against
+// the quota of that mode while the event row is locked so two invitees cannot
+// take the last seat at the same time.
+func (s *invitationService) ProcessRSVP(ctx context.Context, qrCodeToken string, newRsvpStatus string, attendanceMode string) error {
+	detail, err := s.invitationRepo.GetInvitationDetailByQRCode(ctx, nil, qrCodeToken)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("sorry, this RSVP link appears to be invalid or has expired")
 		}
-		log.Printf("Error fetching UserInvitation by QRCode token %s: %v", qrCodeToken, err)
+		slog.WarnContext(ctx, "failed to fetch invitation by qr code token", "error", err)
+		return errors.New("an unexpected error occurred while processing your RSVP. Please try again later")
+	}
+
+	// Validate newRsvpStatus (though controller should send correct ones)
+	if newRsvpStatus != entity.RSVPStatusAccepted && newRsvpStatus != entity.RSVPStatusDeclined {
+		slog.WarnContext(ctx, "invalid rsvp status", "rsvp_status", newRsvpStatus)
+		return errors.New("an internal error occurred. Invalid RSVP status provided") // Should not happen if called from our controller
+	}
+
+	// the mode only matters for hybrid events, other links ignore it
+	if detail.EventType != entity.EventTypeHybrid || newRsvpStatus != entity.RSVPStatusAccepted {
+		attendanceMode = ""
+	} else if attendanceMode == "" {
+		return dto.ErrAttendanceModeRequired
+	}
+
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	if err := s.eventRepo.LockEvent(ctx, tx, detail.EventID.String()); err != nil {
+		tx.Rollback()
+		return errors.New("an unexpected error occurred while processing your RSVP. Please try again later")
+	}
+
+	userInvitation, err := s.invitationRepo.GetUserInvitationByQRCode(ctx, tx, qrCodeToken)
+	if err != nil {
+		tx.Rollback()
+		slog.WarnContext(ctx, "failed to fetch user invitation by qr code token", "error", err)
 		return errors.New("an unexpected error occurred while processing your RSVP. Please try again later")
 	}
 
 	// Check if already RSVP'd
 	if userInvitation.RSVPStatus != entity.RSVPStatusPending {
+		tx.Rollback()
 		// You could customize the message further if needed, e.g. "You have already accepted this invitation on [date]."
 		return errors.New("your RSVP has already been recorded as: " + userInvitation.RSVPStatus)
 	}
 
-	// Validate newRsvpStatus (though controller should send correct ones)
-	if newRsvpStatus != entity.RSVPStatusAccepted && newRsvpStatus != entity.RSVPStatusDeclined {
-		log.Printf("Invalid newRsvpStatus '%s' provided for token %s", newRsvpStatus, qrCodeToken)
-		return errors.New("an internal error occurred. Invalid RSVP status provided") // Should not happen if called from our controller
+	if attendanceMode != "" {
+		if err := s.checkAttendanceQuota(ctx, tx, detail.EventID, attendanceMode); err != nil {
+			tx.Rollback()
+			return err
+		}
+		userInvitation.AttendanceMode = &attendanceMode
 	}
 
 	now := time.Now()
 	userInvitation.RSVPStatus = newRsvpStatus
 	userInvitation.RsvpAt = &now
 
-	_, err = s.invitationRepo.UpdateUserInvitation(ctx, nil, userInvitation)
+	_, err = s.invitationRepo.UpdateUserInvitation(ctx, tx, userInvitation)
 	if err != nil {
-		log.Printf("Error updating UserInvitation for token %s during RSVP: %v", qrCodeToken, err)
+		tx.Rollback()
+		slog.ErrorContext(ctx, "failed to update user invitation during rsvp", "error", err)
+		return errors.New("an unexpected error occurred while saving your RSVP. Please try again later")
+	}
+
+	// the meeting details mail is sent by a subscriber once this commits
+	changed := dto.RSVPChanged{
+		InvitationID:   userInvitation.InvitationID,
+		EventID:        detail.EventID,
+		UserID:         userInvitation.UserID,
+		RSVPStatus:     newRsvpStatus,
+		AttendanceMode: attendanceMode,
+		RsvpAt:         now,
+	}
+	if err := s.eventBus.Publish(ctx, tx, changed); err != nil {
+		tx.Rollback()
+		slog.ErrorContext(ctx, "failed to publish rsvp change", "error", err)
+		return errors.New("an unexpected error occurred while saving your RSVP. Please try again later")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.ErrorContext(ctx, "failed to commit rsvp", "error", err)
 		return errors.New("an unexpected error occurred while saving your RSVP. Please try again later")
 	}
 
-	log.Printf("RSVP successful for token %s, new status: %s", qrCodeToken, newRsvpStatus)
+	slog.InfoContext(ctx, "rsvp recorded", "rsvp_status", newRsvpStatus)
 	return nil // Success
 }
 
+// checkAttendanceQuota fails when the chosen mode of a hybrid event is full.
+// In-person seats are limited by the capacity of the approved rooms, and are
+// not open until a room is approved. Online seats are limited by the event's
+// online quota, where 0 means unlimited.
+func (s *invitationService) checkAttendanceQuota(ctx context.Context, tx *gorm.DB, eventID uuid.UUID, mode string) error {
+	var limit int
+	var errFull error
+	switch mode {
+	case entity.AttendanceModeInPerson:
+		capacity, err := s.eventRepo.GetApprovedRoomCapacity(ctx, tx, eventID.String())
+		if err != nil {
+			return err
+		}
+		if capacity == 0 {
+			return dto.ErrInPersonNotOpen
+		}
+		limit, errFull = capacity, dto.ErrInPersonQuotaFull
+	case entity.AttendanceModeOnline:
+		event, err := s.eventRepo.GetEventById(ctx, tx, eventID.String())
+		if err != nil {
+			return err
+		}
+		if event.OnlineQuota == 0 {
+			return nil
+		}
+		limit, errFull = event.OnlineQuota, dto.ErrOnlineQuotaFull
+	default:
+		return dto.ErrInvalidAttendanceMode
+	}
+
+	accepted, err := s.invitationRepo.CountAcceptedByMode(ctx, tx, eventID, mode)
+	if err != nil {
+		return err
+	}
+	if accepted >= int64(limit) {
+		return errFull
+	}
+	return nil
+}
+
+// JoinOnlineEvent records attendance for an accepted invitee of an online event,
+// or of a hybrid event they chose to attend online, and returns the meeting URL to redirect them to.
+func (s *invitationService) JoinOnlineEvent(ctx context.Context, token string) (string, error) {
+	detail, err := s.invitationRepo.GetInvitationDetailByQRCode(ctx, nil, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", dto.ErrJoinLinkInvalid
+		}
+		return "", err
+	}
+
+	if detail.EventType == entity.EventTypeOffline || detail.MeetingURL == "" {
+		return "", dto.ErrEventNotOnline
+	}
+	if !revealsMeeting(detail.EventType, detail.RSVPStatus, detail.AttendanceMode) {
+		return "", dto.ErrJoinNotAccepted
+	}
+
+	now := time.Now()
+	if now.Before(detail.StartTime.Add(-joinOpensBeforeStart)) {
+		return "", dto.ErrJoinTooEarly
+	}
+	if now.After(detail.EndTime) {
+		return "", dto.ErrJoinEventEnded
+	}
+
+	// rejoining after a dropped connection keeps the first join time
+	if detail.AttendedAt != nil {
+		return detail.MeetingURL, nil
+	}
+
+	userInvitation, err := s.invitationRepo.GetUserInvitationByQRCode(ctx, nil, token)
+	if err != nil {
+		return "", err
+	}
+
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	userInvitation.AttendedAt = &now
+	if _, err := s.invitationRepo.UpdateUserInvitation(ctx, tx, userInvitation); err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	marked := dto.AttendanceMarked{InvitationID: userInvitation.InvitationID, EventID: detail.EventID, UserID: userInvitation.UserID, AttendedAt: now}
+	if err := s.eventBus.Publish(ctx, tx, marked); err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return "", err
+	}
+
+	return detail.MeetingURL, nil
+}
+
+func (s *invitationService) SendMeetingDetails(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) error {
+	details, err := s.invitationRepo.GetInvitationByID(ctx, nil, invitationID)
+	if err != nil {
+		return err
+	}
+
+	for _, detail := range details {
+		if detail.UserID == userID {
+			return sendMeetingDetails(detail)
+		}
+	}
+	return dto.ErrInvitationNotFound
+}
+
+func sendMeetingDetails(detail dto.InvitationDetailResponse) error {
+	if !revealsMeeting(detail.EventType, detail.RSVPStatus, detail.AttendanceMode) || detail.MeetingURL == "" {
+		return nil
+	}
+
+	templateData := map[string]interface{}{
+		"UserName":        detail.UserName,
+		"EventName":       detail.EventName,
+		"Year":            time.Now().Year(),
+		"ShowRSVP":        false,
+		"IsOnline":        true,
+		"MeetingURL":      detail.MeetingURL,
+		"MeetingPasscode": detail.MeetingPasscode,
+		"MeetingPlatform": meetingPlatformLabel(detail.MeetingPlatform),
+		"JoinLink":        invitationApiBaseURL() + "/api/invitation/join/" + detail.QRCode,
+	}
+
+	emailSubject := "Joining details for " + detail.EventName
+	return utils.SendInvitationMail(detail.UserEmail, emailSubject, templateData, nil)
+}
+
 // GetInvitationByUserID retrieves all invitations for a specific user
-func (s *invitationService) GetInvitationByUserID(ctx context.Context, userID string) ([]dto.InvitationResponse, error) {
+func (s *invitationService) GetInvitationByUserID(ctx context.Context, userID string, callerId string, role string, organizationId string) ([]dto.InvitationResponse, error) {
 	// Parse the user ID
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return nil, err
 	}
 
+	self := uid.String() == callerId
+	if !self && role != constants.ENUM_ROLE_ADMIN && (role != constants.ENUM_ROLE_ORMAWA || organizationId == "") {
+		return nil, dto.ErrInvitationForbidden
+	}
+
 	// Get invitations from repository
 	invitations, err := s.invitationRepo.GetInvitationByUserId(ctx, nil, uid)
 	if err != nil {
 		return nil, dto.ErrGetInvitationByUserID
 	}
-	log.Println(invitations)
+	slog.DebugContext(ctx, "invitations of user", "count", len(invitations))
 
 	if len(invitations) == 0 {
 		return []dto.InvitationResponse{}, nil
 	}
 
-	return invitations, nil
+	// an organization only sees the invitations to its own events
+	if !self && role == constants.ENUM_ROLE_ORMAWA {
+		owned := map[string]bool{}
+		var visible []dto.InvitationResponse
+		for _, invitation := range invitations {
+			manages, ok := owned[invitation.EventID]
+			if !ok {
+				eventID, err := uuid.Parse(invitation.EventID)
+				if err != nil {
+					return nil, err
+				}
+				if manages, err = s.managesInvitations(ctx, eventID, role, organizationId); err != nil {
+					return nil, err
+				}
+				owned[invitation.EventID] = manages
+			}
+			if manages {
+				visible = append(visible, invitation)
+			}
+		}
+		invitations = visible
+	}
+
+	resp := make([]dto.InvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		meetingURL, meetingPasscode, meetingPlatform := invitation.MeetingURL, invitation.MeetingPasscode, invitation.MeetingPlatform
+		invitation.MeetingURL, invitation.MeetingPasscode, invitation.MeetingPlatform = "", "", ""
+		if !self {
+			invitation.QRCode = ""
+		}
+		revealMeeting(&invitation, meetingURL, meetingPasscode, meetingPlatform)
+		resp[i] = invitation
+	}
+
+	return resp, nil
+}
+
+// joinOpensBeforeStart is how early the join link starts redirecting.
+const joinOpensBeforeStart = 30 * time.Minute
+
+// invitationApiBaseURL returns API_BASE_URL, used to build links in emails.
+func invitationApiBaseURL() string {
+	emailCfg, err := config.NewEmailConfig() // This loads .env and unmarshals
+	if err != nil {
+		slog.Warn("could not load email config, links in emails may be broken", "error", err)
+		return ""
+	}
+	if emailCfg.ApiBaseUrl == "" {
+		slog.Warn("API_BASE_URL is not set, links in emails may be broken")
+	}
+	return emailCfg.ApiBaseUrl
+}
+
+// revealsMeeting reports whether an invitee may see the meeting details: they
+// accepted, and either the event is online or they chose to attend online.
+func revealsMeeting(eventType string, rsvpStatus string, attendanceMode string) bool {
+	if rsvpStatus != entity.RSVPStatusAccepted {
+		return false
+	}
+	return eventType == entity.EventTypeOnline ||
+		(eventType == entity.EventTypeHybrid && attendanceMode == entity.AttendanceModeOnline)
+}
+
+func meetingPlatformLabel(platform string) string {
+	switch platform {
+	case entity.MeetingPlatformZoom:
+		return "Zoom"
+	case entity.MeetingPlatformGoogleMeet:
+		return "Google Meet"
+	case entity.MeetingPlatformTeams:
+		return "Microsoft Teams"
+	default:
+		return ""
+	}
+}
+
+// ResendInvitationEmail sends the invitation email of one invitee again and
+// records the outcome
+func (s *invitationService) ResendInvitationEmail(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) error {
+	details, err := s.invitationRepo.GetInvitationByID(ctx, nil, invitationID)
+	if err != nil {
+		return err
+	}
+
+	for _, detail := range details {
+		if detail.UserID != userID {
+			continue
+		}
+
+		event, err := s.eventRepo.GetEventById(ctx, nil, detail.EventID.String())
+		if err != nil {
+			return dto.ErrEventNotFound
+		}
+
+		user := entity.User{ID: detail.UserID, Name: detail.UserName, Email: detail.UserEmail}
+		errSend := sendInvitationEmail(event, user, detail.QRCode)
+		if err := s.invitationRepo.SetEmailStatus(ctx, nil, invitationID, userID, errSend); err != nil {
+			return err
+		}
+		return errSend
+	}
+
+	return dto.ErrInvitationNotFound
+}
+
+// SendInvitationEmails falls back to a plain email when the QR code cannot be
+// attached. Failed sends are recorded for the resend_failed_invitations script
+// rather than returned, so the users who did get their email are not mailed
+// again.
+func (s *invitationService) SendInvitationEmails(ctx context.Context, invitationID uuid.UUID, userIDs []uuid.UUID) error {
+	details, err := s.invitationRepo.GetInvitationByID(ctx, nil, invitationID)
+	if err != nil {
+		return err
+	}
+	if len(details) == 0 {
+		return dto.ErrInvitationNotFound
+	}
+
+	event, err := s.eventRepo.GetEventById(ctx, nil, details[0].EventID.String())
+	if err != nil {
+		return dto.ErrEventNotFound
+	}
+
+	invited := make(map[uuid.UUID]bool, len(userIDs))
+	for _, id := range userIDs {
+		invited[id] = true
+	}
+
+	for _, detail := range details {
+		if !invited[detail.UserID] {
+			continue
+		}
+
+		user := entity.User{ID: detail.UserID, Name: detail.UserName, Email: detail.UserEmail}
+		var errSend error
+		if detail.QRCode == "" {
+			slog.WarnContext(ctx, "qr code is empty, sending plain invitation", "invitation_id", invitationID, "invitee_id", detail.UserID)
+			errSend = sendPlainInvitationEmail(event, user, "empty QR")
+		} else {
+			errSend = sendInvitationEmail(event, user, detail.QRCode)
+			if errors.Is(errSend, errInvitationQRCode) {
+				slog.WarnContext(ctx, "failed to generate qr code image, sending plain invitation", "invitation_id", invitationID, "invitee_id", user.ID, "error", errSend)
+				errSend = sendPlainInvitationEmail(event, user, "generation error")
+			}
+		}
+
+		if errSend != nil {
+			slog.ErrorContext(ctx, "failed to send invitation email", "invitation_id", invitationID, "email", user.Email, "error", errSend)
+		} else {
+			slog.InfoContext(ctx, "invitation email sent", "invitation_id", invitationID, "email", user.Email)
+		}
+		if err := s.invitationRepo.SetEmailStatus(ctx, nil, invitationID, user.ID, errSend); err != nil {
+			slog.ErrorContext(ctx, "failed to record invitation email status", "invitation_id", invitationID, "email", user.Email, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// sendPlainInvitationEmail is the fallback when no QR code can be attached
+func sendPlainInvitationEmail(event entity.Event, user entity.User, reason string) error {
+	plainBody := "You have been invited to " + event.Name + ". Please contact support if you did not receive your QR code (" + reason + ")."
+	return utils.SendMail(user.Email, "Event Invitation: "+event.Name, plainBody)
+}
+
+var errInvitationQRCode = errors.New("failed to generate invitation qr code")
+
+// sendInvitationEmail sends the styled invitation with the QR code attached.
+// The QR code doubles as the RSVP token.
+func sendInvitationEmail(event entity.Event, user entity.User, qrCode string) error {
+	pngData, err := qrcode.Encode(qrCode, qrcode.Medium, 256)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInvitationQRCode, err)
+	}
+
+	apiBaseURL := invitationApiBaseURL()
+	acceptLink := apiBaseURL + "/api/invitation/rsvp/accept/" + qrCode
+	declineLink := apiBaseURL + "/api/invitation/rsvp/decline/" + qrCode
+
+	// meeting details are only sent once the invitee has accepted
+	templateData := map[string]interface{}{
+		"UserName":    user.Name,
+		"EventName":   event.Name,
+		"Year":        time.Now().Year(),
+		"AcceptLink":  acceptLink,
+		"DeclineLink": declineLink,
+		"ShowRSVP":    true,
+		"IsOnline":    event.Event_Type == entity.EventTypeOnline,
+		"IsHybrid":    event.Event_Type == entity.EventTypeHybrid,
+	}
+	// hybrid invitees pick their attendance mode when accepting
+	if event.Event_Type == entity.EventTypeHybrid {
+		templateData["AcceptInPersonLink"] = acceptLink + "/in-person"
+		templateData["AcceptOnlineLink"] = acceptLink + "/online"
+	}
+
+	return utils.SendInvitationMail(user.Email, "You're Invited to "+event.Name+"!", templateData, pngData)
 }
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// fakeInvitationRepository serves the invitation of one token and records
+// attendance updates
+type fakeInvitationRepository struct {
+	repository.InvitationRepository
+	detail         dto.InvitationDetailResponse
+	userInvitation entity.UserInvitation
+	updated        []entity.UserInvitation
+	invitations    []dto.InvitationResponse
+	accepted       map[string]int64
+}
+
+func (r *fakeInvitationRepository) CountAcceptedByMode(ctx context.Context, tx *gorm.DB, eventID uuid.UUID, mode string) (int64, error) {
+	return r.accepted[mode], nil
+}
+
+func (r *fakeInvitationRepository) GetInvitationByEvent(ctx context.Context, tx *gorm.DB, eventID uuid.UUID) ([]dto.InvitationResponse, error) {
+	return r.invitations, nil
+}
+
+func (r *fakeInvitationRepository) GetInvitationDetailByQRCode(ctx context.Context, tx *gorm.DB, qrCode string) (dto.InvitationDetailResponse, error) {
+	if qrCode != r.detail.QRCode {
+		return dto.InvitationDetailResponse{}, gorm.ErrRecordNotFound
+	}
+	return r.detail, nil
+}
+
+func (r *fakeInvitationRepository) GetUserInvitationByQRCode(ctx context.Context, tx *gorm.DB, qrCode string) (entity.UserInvitation, error) {
+	return r.userInvitation, nil
+}
+
+func (r *fakeInvitationRepository) UpdateUserInvitation(ctx context.Context, tx *gorm.DB, userInvitation entity.UserInvitation) (entity.UserInvitation, error) {
+	r.updated = append(r.updated, userInvitation)
+	return userInvitation, nil
+}
+
+func Test_RevealsMeeting(t *testing.T) {
+	tests := []struct {
+		name           string
+		eventType      string
+		rsvpStatus     string
+		attendanceMode string
+		want           bool
+	}{
+		{"online accepted", entity.EventTypeOnline, entity.RSVPStatusAccepted, "", true},
+		{"online pending", entity.EventTypeOnline, entity.RSVPStatusPending, "", false},
+		{"online declined", entity.EventTypeOnline, entity.RSVPStatusDeclined, "", false},
+		{"offline accepted", entity.EventTypeOffline, entity.RSVPStatusAccepted, "", false},
+		{"hybrid accepted online", entity.EventTypeHybrid, entity.RSVPStatusAccepted, entity.AttendanceModeOnline, true},
+		{"hybrid accepted in person", entity.EventTypeHybrid, entity.RSVPStatusAccepted, entity.AttendanceModeInPerson, false},
+		{"hybrid pending online", entity.EventTypeHybrid, entity.RSVPStatusPending, entity.AttendanceModeOnline, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, revealsMeeting(tt.eventType, tt.rsvpStatus, tt.attendanceMode))
+		})
+	}
+}
+
+func Test_RevealMeeting(t *testing.T) {
+	tests := []struct {
+		name   string
+		resp   dto.InvitationResponse
+		reveal bool
+	}{
+		{
+			name:   "own accepted online invitation",
+			resp:   dto.InvitationResponse{QRCode: "token", EventType: entity.EventTypeOnline, RSVPStatus: entity.RSVPStatusAccepted},
+			reveal: true,
+		},
+		{
+			name:   "someone else's invitation has no token",
+			resp:   dto.InvitationResponse{EventType: entity.EventTypeOnline, RSVPStatus: entity.RSVPStatusAccepted},
+			reveal: false,
+		},
+		{
+			name:   "own pending invitation",
+			resp:   dto.InvitationResponse{QRCode: "token", EventType: entity.EventTypeOnline, RSVPStatus: entity.RSVPStatusPending},
+			reveal: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := tt.resp
+			revealMeeting(&resp, "https://meet.test/abc", "1234", entity.MeetingPlatformZoom)
+			if !tt.reveal {
+				assert.Empty(t, resp.MeetingURL)
+				assert.Empty(t, resp.MeetingPasscode)
+				assert.Empty(t, resp.MeetingPlatform)
+				assert.Empty(t, resp.JoinURL)
+				return
+			}
+			assert.Equal(t, "https://meet.test/abc", resp.MeetingURL)
+			assert.Equal(t, "1234", resp.MeetingPasscode)
+			assert.Equal(t, entity.MeetingPlatformZoom, resp.MeetingPlatform)
+			assert.True(t, strings.HasSuffix(resp.JoinURL, "/api/invitation/join/"+resp.QRCode), resp.JoinURL)
+		})
+	}
+}
+
+func Test_InvitationService_JoinOnlineEvent(t *testing.T) {
+	now := time.Now()
+	joined := now.Add(-10 * time.Minute)
+	detail := dto.InvitationDetailResponse{
+		QRCode:     "token",
+		EventID:    uuid.New(),
+		EventType:  entity.EventTypeOnline,
+		RSVPStatus: entity.RSVPStatusAccepted,
+		StartTime:  now.Add(-15 * time.Minute),
+		EndTime:    now.Add(time.Hour),
+		MeetingURL: "https://meet.test/abc",
+	}
+
+	tests := []struct {
+		name       string
+		detail     func(d *dto.InvitationDetailResponse)
+		token      string
+		wantErr    error
+		wantMarked bool
+	}{
+		{"first join", func(d *dto.InvitationDetailResponse) {}, "token", nil, true},
+		{"hybrid online", func(d *dto.InvitationDetailResponse) {
+			d.EventType, d.AttendanceMode = entity.EventTypeHybrid, entity.AttendanceModeOnline
+		}, "token", nil, true},
+		{"rejoin keeps the first join", func(d *dto.InvitationDetailResponse) { d.AttendedAt = &joined }, "token", nil, false},
+		{"unknown token", func(d *dto.InvitationDetailResponse) {}, "other", dto.ErrJoinLinkInvalid, false},
+		{"offline event", func(d *dto.InvitationDetailResponse) { d.EventType = entity.EventTypeOffline }, "token", dto.ErrEventNotOnline, false},
+		{"not accepted", func(d *dto.InvitationDetailResponse) { d.RSVPStatus = entity.RSVPStatusPending }, "token", dto.ErrJoinNotAccepted, false},
+		{"too early", func(d *dto.InvitationDetailResponse) { d.StartTime = now.Add(time.Hour) }, "token", dto.ErrJoinTooEarly, false},
+		{"ended", func(d *dto.InvitationDetailResponse) { d.EndTime = now.Add(-time.Minute) }, "token", dto.ErrJoinEventEnded, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, pool := newDryRunDB(t)
+			bus := &fakeEventBus{}
+			repo := &fakeInvitationRepository{
+				detail:         detail,
+				userInvitation: entity.UserInvitation{InvitationID: uuid.New(), UserID: uuid.New()},
+			}
+			tt.detail(&repo.detail)
+			s := NewInvitationService(repo, nil, nil, bus, db)
+
+			url, err := s.JoinOnlineEvent(context.Background(), tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, repo.updated)
+				assert.Empty(t, bus.published)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, detail.MeetingURL, url)
+
+			if !tt.wantMarked {
+				assert.Empty(t, repo.updated)
+				assert.Empty(t, bus.published)
+				return
+			}
+			// the attendance and its event are committed together
+			assert.Equal(t, 1, pool.committed)
+			if assert.Len(t, repo.updated, 1) && assert.Len(t, bus.published, 1) {
+				marked := bus.published[0].(dto.AttendanceMarked)
+				assert.Equal(t, repo.userInvitation.InvitationID, marked.InvitationID)
+				assert.Equal(t, repo.userInvitation.UserID, marked.UserID)
+				assert.Equal(t, detail.EventID, marked.EventID)
+				assert.Equal(t, *repo.updated[0].AttendedAt, marked.AttendedAt)
+			}
+		})
+	}
+}
+
+func Test_InvitationService_GetInvitationByEventID(t *testing.T) {
+	owner := uuid.New()
+	event := entity.Event{ID: uuid.New(), OrganizationID: &owner}
+	invitations := []dto.InvitationResponse{{RSVPStatus: entity.RSVPStatusAccepted}}
+
+	tests := []struct {
+		name           string
+		eventId        string
+		organizationId string
+		wantErr        error
+	}{
+		{"admin", event.ID.String(), "", nil},
+		{"owning organization", event.ID.String(), owner.String(), nil},
+		{"other organization", event.ID.String(), uuid.NewString(), dto.ErrOrganizationForbidden},
+		{"unknown event", uuid.NewString(), owner.String(), dto.ErrEventNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeInvitationRepository{invitations: invitations}
+			s := NewInvitationService(repo, &fakeEventRepository{event: event}, nil, nil, nil)
+
+			got, err := s.GetInvitationByEventID(context.Background(), tt.eventId, tt.organizationId)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, invitations, got)
+		})
+	}
+}
+
+func Test_InvitationService_CheckAttendanceQuota(t *testing.T) {
+	inPerson, online := entity.AttendanceModeInPerson, entity.AttendanceModeOnline
+
+	tests := []struct {
+		name        string
+		mode        string
+		capacity    int
+		onlineQuota int
+		accepted    map[string]int64
+		wantErr     error
+	}{
+		{"in-person with seats left", inPerson, 40, 0, map[string]int64{inPerson: 39}, nil},
+		{"in-person full", inPerson, 40, 0, map[string]int64{inPerson: 40}, dto.ErrInPersonQuotaFull},
+		{"in-person without an approved room", inPerson, 0, 0, nil, dto.ErrInPersonNotOpen},
+		{"online seats are separate", inPerson, 40, 10, map[string]int64{online: 10}, nil},
+		{"online with seats left", online, 0, 10, map[string]int64{online: 9}, nil},
+		{"online full", online, 0, 10, map[string]int64{online: 10}, dto.ErrOnlineQuotaFull},
+		{"online unlimited", online, 0, 0, map[string]int64{online: 5000}, nil},
+		{"unknown mode", "hologram", 40, 10, nil, dto.ErrInvalidAttendanceMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := entity.Event{ID: uuid.New(), Event_Type: entity.EventTypeHybrid, OnlineQuota: tt.onlineQuota}
+			s := &invitationService{
+				invitationRepo: &fakeInvitationRepository{accepted: tt.accepted},
+				eventRepo:      &fakeEventRepository{event: event, capacity: tt.capacity},
+			}
+
+			err := s.checkAttendanceQuota(context.Background(), nil, event.ID, tt.mode)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -1,58 +1,233 @@
 package service
 
 import (
+	"context"
+	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
+	"encoding/pem"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math/big"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/repository"
 )
 
 type JWTService interface {
-	GenerateAccessToken(userId string, role string) string
+	GenerateAccessToken(userId string, role string, organization *ActiveOrganization, sessionId string) string
 	GenerateRefreshToken() (string, time.Time)
 	ValidateToken(token string) (*jwt.Token, error)
 	GetUserIDByToken(token string) (string, error)
 	GetRoleByToken(token string) (string, error)
+	GetOrganizationByToken(token string) (*ActiveOrganization, error)
+	GetSessionIDByToken(token string) (string, error)
+	ValidateSession(ctx context.Context, sessionId string) error
+	JWKS() dto.JWKSResponse
+}
+
+// ActiveOrganization is the organization a member is currently acting for.
+type ActiveOrganization struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
 }
 
 type jwtCustomClaim struct {
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	UserID       string              `json:"user_id"`
+	Role         string              `json:"role"`
+	Organization *ActiveOrganization `json:"organization,omitempty"`
+	SessionID    string              `json:"sid"`
 	jwt.RegisteredClaims
 }
 
+// signingKey is one key tokens can be signed or verified with. Verification
+// only keys, such as the previous key during a rotation, have no private part.
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private any
+	public  any
+}
+
 type jwtService struct {
-	secretKey     string
+	sessionRepo   repository.RefreshTokenRepository
+	signing       *signingKey
+	keys          map[string]*signingKey
 	issuer        string
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
 }
 
-func NewJWTService() JWTService {
-	return &jwtService{
-		secretKey:     getSecretKey(),
-		issuer:        "Template",
+const (
+	JWT_DEFAULT_ISSUER = "myits-event"
+
+	// hmacKeyID is the kid of the JWT_SECRET key. Tokens signed before key ids
+	// were introduced carry no kid and are verified with the same secret.
+	hmacKeyID = "hs256"
+)
+
+// NewJWTService loads its keys from the environment:
+//   - JWT_PRIVATE_KEY_FILE: PEM RSA (RS256) or Ed25519 (EdDSA) key to sign with
+//   - JWT_KEY_ID: kid of that key, derived from the public key when empty
+//   - JWT_PUBLIC_KEY_FILES: comma separated PEM public keys that are still
+//     accepted, so tokens signed with a retired key stay valid until they expire
+//   - JWT_SECRET: HS256 secret, used to sign only when no private key is set
+//   - JWT_ISSUER: iss claim of issued tokens
+//
+// Production refuses to start without a key; elsewhere a temporary Ed25519 key
+// is generated, so tokens do not survive a restart.
+func NewJWTService(sessionRepo repository.RefreshTokenRepository) (JWTService, error) {
+	j := &jwtService{
+		sessionRepo:   sessionRepo,
+		keys:          make(map[string]*signingKey),
+		issuer:        os.Getenv("JWT_ISSUER"),
 		accessExpiry:  time.Minute * 15,
 		refreshExpiry: time.Hour * 24 * 7,
 	}
+	if j.issuer == "" {
+		j.issuer = JWT_DEFAULT_ISSUER
+	}
+
+	if path := os.Getenv("JWT_PRIVATE_KEY_FILE"); path != "" {
+		key, err := loadPrivateKey(path, os.Getenv("JWT_KEY_ID"))
+		if err != nil {
+			return nil, err
+		}
+		j.signing = key
+		j.keys[key.id] = key
+	}
+
+	for _, path := range strings.Split(os.Getenv("JWT_PUBLIC_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		key, err := loadPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		j.keys[key.id] = key
+	}
+
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		key := &signingKey{id: hmacKeyID, method: jwt.SigningMethodHS256, private: []byte(secret), public: []byte(secret)}
+		j.keys[key.id] = key
+		j.keys[""] = key
+		if j.signing == nil {
+			j.signing = key
+		}
+	}
+
+	if j.signing == nil {
+		if os.Getenv("APP_ENV") == constants.ENUM_RUN_PRODUCTION {
+			return nil, ErrNoSigningKey
+		}
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		slog.Warn("no JWT key configured, signing with a temporary key; tokens will not survive a restart")
+		key := &signingKey{id: keyID(public), method: jwt.SigningMethodEdDSA, private: private, public: public}
+		j.signing = key
+		j.keys[key.id] = key
+	}
+
+	return j, nil
+}
+
+var ErrNoSigningKey = errors.New("no JWT signing key configured, set JWT_PRIVATE_KEY_FILE or JWT_SECRET")
+
+func loadPrivateKey(path string, id string) (*signingKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var private any
+	if private, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+		if private, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return nil, fmt.Errorf("parse JWT private key %s: %w", path, err)
+		}
+	}
+
+	key := &signingKey{private: private}
+	switch private := private.(type) {
+	case *rsa.PrivateKey:
+		key.method, key.public = jwt.SigningMethodRS256, &private.PublicKey
+	case ed25519.PrivateKey:
+		key.method, key.public = jwt.SigningMethodEdDSA, private.Public()
+	default:
+		return nil, fmt.Errorf("JWT private key %s must be RSA or Ed25519", path)
+	}
+
+	key.id = id
+	if key.id == "" {
+		key.id = keyID(key.public)
+	}
+	return key, nil
+}
+
+func loadPublicKey(path string) (*signingKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse JWT public key %s: %w", path, err)
+	}
+
+	key := &signingKey{id: keyID(public), public: public}
+	switch public.(type) {
+	case *rsa.PublicKey:
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("JWT public key %s must be RSA or Ed25519", path)
+	}
+	return key, nil
 }
 
-func getSecretKey() string {
-	secretKey := os.Getenv("JWT_SECRET")
-	if secretKey == "" {
-		secretKey = "Template"
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
 	}
-	return secretKey
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+	return block, nil
 }
 
-func (j *jwtService) GenerateAccessToken(userId string, role string) string {
+// keyID derives a stable kid from the public key, so the same key gets the
+// same id on every instance without configuring one
+func keyID(public any) string {
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func (j *jwtService) GenerateAccessToken(userId string, role string, organization *ActiveOrganization, sessionId string) string {
 	claims := jwtCustomClaim{
 		userId,
 		role,
+		organization,
+		sessionId,
 		jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.accessExpiry)),
 			Issuer:    j.issuer,
@@ -60,10 +235,11 @@ func (j *jwtService) GenerateAccessToken(userId string, role string) string {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tx, err := token.SignedString([]byte(j.secretKey))
+	token := jwt.NewWithClaims(j.signing.method, claims)
+	token.Header["kid"] = j.signing.id
+	tx, err := token.SignedString(j.signing.private)
 	if err != nil {
-		log.Println(err)
+		slog.Error("failed to sign access token", "error", err)
 	}
 	return tx
 }
@@ -72,7 +248,7 @@ func (j *jwtService) GenerateRefreshToken() (string, time.Time) {
 	b := make([]byte, 32)
 	_, err := rand.Read(b)
 	if err != nil {
-		log.Println(err)
+		slog.Error("failed to generate refresh token", "error", err)
 		return "", time.Time{}
 	}
 
@@ -82,17 +258,57 @@ func (j *jwtService) GenerateRefreshToken() (string, time.Time) {
 	return refreshToken, expiresAt
 }
 
+// parseToken picks the verification key by kid and only accepts the algorithm
+// that key was configured with
 func (j *jwtService) parseToken(t_ *jwt.Token) (any, error) {
-	if _, ok := t_.Method.(*jwt.SigningMethodHMAC); !ok {
+	kid, _ := t_.Header["kid"].(string)
+	key, ok := j.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if t_.Method.Alg() != key.method.Alg() {
 		return nil, fmt.Errorf("unexpected signing method %v", t_.Header["alg"])
 	}
-	return []byte(j.secretKey), nil
+	return key.public, nil
 }
 
 func (j *jwtService) ValidateToken(token string) (*jwt.Token, error) {
 	return jwt.Parse(token, j.parseToken)
 }
 
+// JWKS publishes the public keys tokens are verified with. HS256 secrets are
+// never published, so tokens signed with JWT_SECRET can only be verified here.
+func (j *jwtService) JWKS() dto.JWKSResponse {
+	response := dto.JWKSResponse{Keys: []dto.JWK{}}
+	for kid, key := range j.keys {
+		if kid != key.id {
+			continue
+		}
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			response.Keys = append(response.Keys, dto.JWK{
+				Kty: "RSA",
+				Kid: key.id,
+				Use: "sig",
+				Alg: key.method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			response.Keys = append(response.Keys, dto.JWK{
+				Kty: "OKP",
+				Kid: key.id,
+				Use: "sig",
+				Alg: key.method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+	sort.Slice(response.Keys, func(a, b int) bool { return response.Keys[a].Kid < response.Keys[b].Kid })
+	return response
+}
+
 func (j *jwtService) GetUserIDByToken(token string) (string, error) {
 	tToken, err := j.ValidateToken(token)
 	if err != nil {
@@ -114,3 +330,50 @@ func (j *jwtService) GetRoleByToken(token string) (string, error) {
 	id := fmt.Sprintf("%v", claims["role"])
 	return id, nil
 }
+
+// GetOrganizationByToken returns nil when the token does not carry an active
+// organization.
+func (j *jwtService) GetOrganizationByToken(token string) (*ActiveOrganization, error) {
+	tToken, err := j.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := tToken.Claims.(jwt.MapClaims)
+	organization, ok := claims["organization"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	return &ActiveOrganization{
+		ID:   fmt.Sprintf("%v", organization["id"]),
+		Role: fmt.Sprintf("%v", organization["role"]),
+	}, nil
+}
+
+func (j *jwtService) GetSessionIDByToken(token string) (string, error) {
+	tToken, err := j.ValidateToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	claims := tToken.Claims.(jwt.MapClaims)
+	sessionId, _ := claims["sid"].(string)
+	return sessionId, nil
+}
+
+// ValidateSession rejects tokens whose session was revoked or has expired, so
+// logging out or revoking a session takes effect on the very next request.
+func (j *jwtService) ValidateSession(ctx context.Context, sessionId string) error {
+	if sessionId == "" {
+		return dto.ErrSessionRevoked
+	}
+
+	active, err := j.sessionRepo.IsActive(ctx, nil, sessionId)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return dto.ErrSessionRevoked
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/miraicantsleep/myits-event-be/constants"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// writePEM stores der under t's temp dir and returns the file path
+func writePEM(t *testing.T, name string, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writeKeyPair writes private as PKCS#8 and its public key as PKIX
+func writeKeyPair(t *testing.T, private any, public any) (string, string) {
+	t.Helper()
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writePEM(t, "private.pem", "PRIVATE KEY", privateDER), writePEM(t, "public.pem", "PUBLIC KEY", publicDER)
+}
+
+// setJWTEnv clears every JWT variable and sets the given ones
+func setJWTEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, name := range []string{"JWT_PRIVATE_KEY_FILE", "JWT_KEY_ID", "JWT_PUBLIC_KEY_FILES", "JWT_SECRET", "JWT_ISSUER", "APP_ENV"} {
+		t.Setenv(name, env[name])
+	}
+}
+
+func Test_NewJWTService(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPrivate, _ := writeKeyPair(t, rsaKey, &rsaKey.PublicKey)
+	rsaPKCS1 := writePEM(t, "pkcs1.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPrivate, _ := writeKeyPair(t, edKey, edPublic)
+	notPEM := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(notPEM, []byte("not a key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantAlg string
+		wantKid string
+		wantErr bool
+	}{
+		{"rsa key", map[string]string{"JWT_PRIVATE_KEY_FILE": rsaPrivate}, "RS256", keyID(&rsaKey.PublicKey), false},
+		{"pkcs1 rsa key", map[string]string{"JWT_PRIVATE_KEY_FILE": rsaPKCS1}, "RS256", keyID(&rsaKey.PublicKey), false},
+		{"ed25519 key", map[string]string{"JWT_PRIVATE_KEY_FILE": edPrivate}, "EdDSA", keyID(edPublic), false},
+		{"configured key id", map[string]string{"JWT_PRIVATE_KEY_FILE": edPrivate, "JWT_KEY_ID": "2026-10"}, "EdDSA", "2026-10", false},
+		{"private key wins over the secret", map[string]string{"JWT_PRIVATE_KEY_FILE": edPrivate, "JWT_SECRET": "secret"}, "EdDSA", keyID(edPublic), false},
+		{"secret only", map[string]string{"JWT_SECRET": "secret"}, "HS256", hmacKeyID, false},
+		{"no key outside production", map[string]string{}, "EdDSA", "", false},
+		{"no key in production", map[string]string{"APP_ENV": constants.ENUM_RUN_PRODUCTION}, "", "", true},
+		{"missing key file", map[string]string{"JWT_PRIVATE_KEY_FILE": filepath.Join(t.TempDir(), "missing.pem")}, "", "", true},
+		{"not a pem file", map[string]string{"JWT_PRIVATE_KEY_FILE": notPEM}, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setJWTEnv(t, tt.env)
+
+			service, err := NewJWTService(nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			token, err := service.ValidateToken(service.GenerateAccessToken("user", "admin", nil, "session"))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAlg, token.Method.Alg())
+			if tt.wantKid != "" {
+				assert.Equal(t, tt.wantKid, token.Header["kid"])
+			}
+		})
+	}
+}
+
+func Test_JWTService_KeyRotation(t *testing.T) {
+	oldPublic, oldKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldPrivatePath, oldPublicPath := writeKeyPair(t, oldKey, oldPublic)
+	newPublic, newKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newPrivatePath, _ := writeKeyPair(t, newKey, newPublic)
+
+	setJWTEnv(t, map[string]string{"JWT_PRIVATE_KEY_FILE": oldPrivatePath})
+	oldService, err := NewJWTService(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldToken := oldService.GenerateAccessToken("user", "admin", nil, "session")
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+	}{
+		{"old key still accepted", map[string]string{"JWT_PRIVATE_KEY_FILE": newPrivatePath, "JWT_PUBLIC_KEY_FILES": " " + oldPublicPath + " ,"}, false},
+		{"old key retired", map[string]string{"JWT_PRIVATE_KEY_FILE": newPrivatePath}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setJWTEnv(t, tt.env)
+			service, err := NewJWTService(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = service.ValidateToken(oldToken)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// new tokens are always signed with the new key
+			token, err := service.ValidateToken(service.GenerateAccessToken("user", "admin", nil, "session"))
+			assert.NoError(t, err)
+			assert.Equal(t, keyID(newPublic), token.Header["kid"])
+		})
+	}
+}
+
+func Test_JWTService_RejectsForgedTokens(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privatePath, publicPath := writeKeyPair(t, rsaKey, &rsaKey.PublicKey)
+	publicPEM, err := os.ReadFile(publicPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setJWTEnv(t, map[string]string{"JWT_PRIVATE_KEY_FILE": privatePath, "JWT_SECRET": "secret"})
+	service, err := NewJWTService(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(method jwt.SigningMethod, kid any, key any) string {
+		token := jwt.NewWithClaims(method, jwt.MapClaims{"user_id": "user", "role": "admin"})
+		if kid != nil {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"rsa token", sign(jwt.SigningMethodRS256, keyID(&rsaKey.PublicKey), rsaKey), false},
+		{"legacy secret token without kid", sign(jwt.SigningMethodHS256, nil, []byte("secret")), false},
+		{"hs256 signed with the rsa public key", sign(jwt.SigningMethodHS256, keyID(&rsaKey.PublicKey), publicPEM), true},
+		{"unknown kid", sign(jwt.SigningMethodRS256, "unknown", rsaKey), true},
+		{"signed by another key", sign(jwt.SigningMethodRS256, keyID(&rsaKey.PublicKey), otherKey), true},
+		{"wrong secret", sign(jwt.SigningMethodHS256, hmacKeyID, []byte("guess")), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ValidateToken(tt.token)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_JWTService_JWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPrivate, _ := writeKeyPair(t, rsaKey, &rsaKey.PublicKey)
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edPublicPath := writeKeyPair(t, edKey, edPublic)
+
+	setJWTEnv(t, map[string]string{"JWT_PRIVATE_KEY_FILE": rsaPrivate, "JWT_PUBLIC_KEY_FILES": edPublicPath, "JWT_SECRET": "secret"})
+	service, err := NewJWTService(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := map[string]string{}
+	for _, key := range service.JWKS().Keys {
+		keys[key.Kid] = key.Alg
+	}
+	// the HS256 secret is never published
+	assert.Equal(t, map[string]string{
+		keyID(&rsaKey.PublicKey): "RS256",
+		keyID(edPublic):          "EdDSA",
+	}, keys)
+}
+
+// fakeSessionRepository answers the session lookups made by the JWT, session
+// and user services and records whose sessions were removed
+type fakeSessionRepository struct {
+	repository.RefreshTokenRepository
+	active     map[string]bool
+	err        error
+	sessions   []entity.RefreshToken
+	deletedFor []string
+}
+
+func (r *fakeSessionRepository) IsActive(ctx context.Context, tx *gorm.DB, id string) (bool, error) {
+	return r.active[id], r.err
+}
+
+func (r *fakeSessionRepository) FindByTokenHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.RefreshToken, error) {
+	for _, session := range r.sessions {
+		if session.TokenHash == tokenHash {
+			return session, nil
+		}
+	}
+	return entity.RefreshToken{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeSessionRepository) DeleteByUserID(ctx context.Context, tx *gorm.DB, userID string) error {
+	r.deletedFor = append(r.deletedFor, userID)
+	return nil
+}
+
+func Test_JWTService_Claims(t *testing.T) {
+	setJWTEnv(t, map[string]string{"JWT_SECRET": "secret"})
+	service, err := NewJWTService(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		organization *ActiveOrganization
+		sessionId    string
+	}{
+		{"without organization", nil, "session-1"},
+		{"acting for an organization", &ActiveOrganization{ID: "org-1", Role: "chair"}, "session-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := service.GenerateAccessToken("user-1", "ormawa", tt.organization, tt.sessionId)
+
+			userId, err := service.GetUserIDByToken(token)
+			assert.NoError(t, err)
+			assert.Equal(t, "user-1", userId)
+
+			role, err := service.GetRoleByToken(token)
+			assert.NoError(t, err)
+			assert.Equal(t, "ormawa", role)
+
+			organization, err := service.GetOrganizationByToken(token)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.organization, organization)
+
+			sessionId, err := service.GetSessionIDByToken(token)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.sessionId, sessionId)
+		})
+	}
+}
+
+func Test_JWTService_ValidateSession(t *testing.T) {
+	repoErr := errors.New("database is down")
+
+	tests := []struct {
+		name      string
+		sessionId string
+		repo      *fakeSessionRepository
+		wantErr   error
+	}{
+		{"active session", "session-1", &fakeSessionRepository{active: map[string]bool{"session-1": true}}, nil},
+		{"revoked session", "session-1", &fakeSessionRepository{active: map[string]bool{}}, dto.ErrSessionRevoked},
+		{"token without session", "", &fakeSessionRepository{active: map[string]bool{"": true}}, dto.ErrSessionRevoked},
+		{"repository error", "session-1", &fakeSessionRepository{err: repoErr}, repoErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &jwtService{sessionRepo: tt.repo}
+			err := service.ValidateSession(context.Background(), tt.sessionId)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
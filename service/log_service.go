@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/config"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/repository"
+)
+
+const (
+	// logMaxMatches bounds the entries held for one request; past it only the
+	// newest matches are paginated
+	logMaxMatches = 10000
+	// logMaxLineSize is the longest line read, a longer one ends its file
+	logMaxLineSize = 1 << 20
+)
+
+type (
+	LogService interface {
+		// GetAll reads the log files of this instance, newest entry first
+		GetAll(ctx context.Context, req dto.LogListRequest) (dto.LogPaginationResponse, error)
+	}
+
+	logService struct {
+		dir string
+	}
+
+	logFilter struct {
+		level     slog.Level
+		from, to  time.Time
+		search    string
+		route     string
+		slow      int64
+		requestID string
+		userID    string
+	}
+)
+
+func NewLogService(dir string) LogService {
+	return &logService{
+		dir: dir,
+	}
+}
+
+func (s *logService) GetAll(ctx context.Context, req dto.LogListRequest) (dto.LogPaginationResponse, error) {
+	req.Default()
+
+	from, to, err := req.TimeRange(time.Now())
+	if err != nil {
+		return dto.LogPaginationResponse{}, err
+	}
+
+	filter := logFilter{
+		level:     slog.LevelDebug,
+		from:      from,
+		to:        to,
+		search:    strings.ToLower(req.Search),
+		route:     req.Route,
+		slow:      req.SlowMs,
+		requestID: req.RequestID,
+		userID:    req.UserID,
+	}
+	if req.Level != "" {
+		if err := filter.level.UnmarshalText([]byte(req.Level)); err != nil {
+			return dto.LogPaginationResponse{}, err
+		}
+	}
+
+	files, err := config.LogFiles(s.dir)
+	if err != nil {
+		return dto.LogPaginationResponse{}, err
+	}
+
+	// files are named after the local day they were opened on, an entry can
+	// only be in the file of its own day
+	firstDay := from.In(time.Local).Format(time.DateOnly)
+	lastDay := to.In(time.Local).Format(time.DateOnly)
+
+	var entries []dto.LogEntryResponse
+	for i := len(files) - 1; i >= 0 && len(entries) < logMaxMatches; i-- {
+		day := files[i].Day.Format(time.DateOnly)
+		if day < firstDay || day > lastDay {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return dto.LogPaginationResponse{}, err
+		}
+
+		matches, err := readLogFile(files[i].Path, filter)
+		if err != nil {
+			return dto.LogPaginationResponse{}, err
+		}
+		slices.Reverse(matches)
+		entries = append(entries, matches...)
+	}
+	if len(entries) > logMaxMatches {
+		entries = entries[:logMaxMatches]
+	}
+
+	// rotated files of one day can interleave with a restarted instance
+	slices.SortStableFunc(entries, func(a, b dto.LogEntryResponse) int {
+		return b.Time.Compare(a.Time)
+	})
+
+	count := int64(len(entries))
+	start := min(req.GetOffset(), len(entries))
+	end := min(start+req.GetLimit(), len(entries))
+
+	return dto.LogPaginationResponse{
+		Data: entries[start:end],
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.Page,
+			PerPage: req.PerPage,
+			MaxPage: repository.TotalPage(count, int64(req.PerPage)),
+			Count:   count,
+		},
+	}, nil
+}
+
+// readLogFile returns the entries of a file that match filter, oldest first.
+// Lines that are not JSON entries are skipped.
+func readLogFile(path string, filter logFilter) ([]dto.LogEntryResponse, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// pruned since it was listed
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []dto.LogEntryResponse
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), logMaxLineSize)
+	for scanner.Scan() {
+		var fields map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &fields); err != nil {
+			continue
+		}
+		if entry, ok := filter.match(fields); ok {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil && err != bufio.ErrTooLong {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (f logFilter) match(fields map[string]any) (dto.LogEntryResponse, bool) {
+	raw, _ := fields["time"].(string)
+	at, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil || at.Before(f.from) || !at.Before(f.to) {
+		return dto.LogEntryResponse{}, false
+	}
+
+	levelName, _ := fields["level"].(string)
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(levelName)); err != nil || level < f.level {
+		return dto.LogEntryResponse{}, false
+	}
+
+	msg, _ := fields["msg"].(string)
+	if f.search != "" {
+		sql, _ := fields["sql"].(string)
+		if !strings.Contains(strings.ToLower(msg), f.search) && !strings.Contains(strings.ToLower(sql), f.search) {
+			return dto.LogEntryResponse{}, false
+		}
+	}
+	if f.route != "" && fields["route"] != f.route {
+		return dto.LogEntryResponse{}, false
+	}
+	if f.requestID != "" && fields["request_id"] != f.requestID {
+		return dto.LogEntryResponse{}, false
+	}
+	if f.userID != "" && fields["user_id"] != f.userID {
+		return dto.LogEntryResponse{}, false
+	}
+	if f.slow > 0 {
+		// JSON numbers decode as float64
+		latency, ok := fields["latency_ms"].(float64)
+		if !ok || int64(latency) < f.slow {
+			return dto.LogEntryResponse{}, false
+		}
+	}
+
+	delete(fields, "time")
+	delete(fields, "level")
+	delete(fields, "msg")
+	return dto.LogEntryResponse{Time: at, Level: levelName, Message: msg, Fields: fields}, true
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LogFilter_Match(t *testing.T) {
+	from := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	entry := func(fields map[string]any) map[string]any {
+		base := map[string]any{"time": "2026-10-19T10:00:00.5Z", "level": "INFO", "msg": "request"}
+		for k, v := range fields {
+			base[k] = v
+		}
+		return base
+	}
+
+	tests := []struct {
+		name   string
+		filter logFilter
+		fields map[string]any
+		want   bool
+	}{
+		{"in range", logFilter{from: from, to: to}, entry(nil), true},
+		{"before the range", logFilter{from: from, to: to}, entry(map[string]any{"time": "2026-10-18T23:59:59Z"}), false},
+		{"end of the range is excluded", logFilter{from: from, to: to}, entry(map[string]any{"time": "2026-10-20T00:00:00Z"}), false},
+		{"no time", logFilter{from: from, to: to}, entry(map[string]any{"time": nil}), false},
+		{"below the level", logFilter{level: slog.LevelWarn, from: from, to: to}, entry(nil), false},
+		{"at the level", logFilter{level: slog.LevelWarn, from: from, to: to}, entry(map[string]any{"level": "WARN"}), true},
+		{"unknown level", logFilter{from: from, to: to}, entry(map[string]any{"level": "LOUD"}), false},
+		{"search in the message", logFilter{from: from, to: to, search: "req"}, entry(nil), true},
+		{"search in the sql", logFilter{from: from, to: to, search: "bookings"}, entry(map[string]any{"msg": "slow query", "sql": "SELECT * FROM BOOKINGS"}), true},
+		{"search not found", logFilter{from: from, to: to, search: "panic"}, entry(nil), false},
+		{"route", logFilter{from: from, to: to, route: "GET /events"}, entry(map[string]any{"route": "GET /events"}), true},
+		{"other route", logFilter{from: from, to: to, route: "GET /events"}, entry(map[string]any{"route": "GET /rooms"}), false},
+		{"request id", logFilter{from: from, to: to, requestID: "trace-1"}, entry(map[string]any{"request_id": "trace-2"}), false},
+		{"user id", logFilter{from: from, to: to, userID: "alice"}, entry(map[string]any{"user_id": "alice"}), true},
+		{"slow enough", logFilter{from: from, to: to, slow: 500}, entry(map[string]any{"latency_ms": float64(500)}), true},
+		{"too fast", logFilter{from: from, to: to, slow: 500}, entry(map[string]any{"latency_ms": float64(499)}), false},
+		{"no latency", logFilter{from: from, to: to, slow: 500}, entry(nil), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.filter.match(tt.fields)
+			assert.Equal(t, tt.want, ok)
+			if !ok {
+				return
+			}
+			assert.False(t, got.Time.IsZero())
+			assert.NotEmpty(t, got.Level)
+			assert.NotContains(t, got.Fields, "time")
+			assert.NotContains(t, got.Fields, "level")
+			assert.NotContains(t, got.Fields, "msg")
+		})
+	}
+}
+
+func Test_ReadLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app-2026-10-19.log")
+	lines := []string{
+		`{"time":"2026-10-19T10:00:00Z","level":"INFO","msg":"first"}`,
+		`not json`,
+		`{"time":"2026-10-19T10:00:01Z","level":"DEBUG","msg":"query"}`,
+		`{"time":"2026-10-19T10:00:02Z","level":"ERROR","msg":"second"}`,
+		// a line past logMaxLineSize ends the file without failing the request
+		`{"msg":"` + strings.Repeat("a", logMaxLineSize) + `"}`,
+		`{"time":"2026-10-19T10:00:03Z","level":"ERROR","msg":"unread"}`,
+	}
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644))
+
+	from := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	entries, err := readLogFile(path, logFilter{level: slog.LevelInfo, from: from, to: from.Add(24 * time.Hour)})
+	assert.NoError(t, err)
+
+	var messages []string
+	for _, entry := range entries {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{"first", "second"}, messages)
+
+	// pruned since it was listed
+	entries, err = readLogFile(filepath.Join(t.TempDir(), "missing.log"), logFilter{})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
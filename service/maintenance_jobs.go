@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/repository"
+)
+
+// PurgeRefreshTokensJob deletes refresh tokens past their expiry
+func PurgeRefreshTokensJob(refreshTokenRepo repository.RefreshTokenRepository) Job {
+	return Job{
+		Name:        "purge_refresh_tokens",
+		Description: "delete expired refresh tokens",
+		Schedule:    "15 * * * *",
+		Run: func(ctx context.Context) (string, error) {
+			return "", refreshTokenRepo.DeleteExpired(ctx, nil)
+		},
+	}
+}
+
+// RejectStaleBookingsJob rejects booking requests nobody decided on before
+// their event started
+func RejectStaleBookingsJob(bookingRequestRepo repository.BookingRequestRepository) Job {
+	return Job{
+		Name:        "reject_stale_bookings",
+		Description: "reject pending booking requests of events that have already started",
+		Schedule:    "*/30 * * * *",
+		Run: func(ctx context.Context) (string, error) {
+			rejected, err := bookingRequestRepo.RejectPendingForPastEvents(ctx, nil, time.Now())
+			return fmt.Sprintf("rejected %d booking requests", rejected), err
+		},
+	}
+}
+
+// PruneJobRunsJob keeps the run history from growing without bound
+func PruneJobRunsJob(jobRunRepo repository.JobRunRepository, retention time.Duration) Job {
+	return Job{
+		Name:        "prune_job_runs",
+		Description: "delete job run history older than JOB_RUN_RETENTION_DAYS",
+		Schedule:    "30 3 * * *",
+		Run: func(ctx context.Context) (string, error) {
+			deleted, err := jobRunRepo.DeleteBefore(ctx, nil, time.Now().Add(-retention))
+			return fmt.Sprintf("deleted %d runs", deleted), err
+		},
+	}
+}
+
+// PruneOutboxEventsJob deletes delivered events, failed ones are kept for
+// inspection
+func PruneOutboxEventsJob(outboxRepo repository.OutboxRepository, retention time.Duration) Job {
+	return Job{
+		Name:        "prune_outbox_events",
+		Description: "delete delivered outbox events older than OUTBOX_RETENTION_DAYS",
+		Schedule:    "45 3 * * *",
+		Run: func(ctx context.Context) (string, error) {
+			deleted, err := outboxRepo.DeleteDeliveredBefore(ctx, nil, time.Now().Add(-retention))
+			return fmt.Sprintf("deleted %d events", deleted), err
+		},
+	}
+}
+
+// PruneWebhookDeliveriesJob deletes finished deliveries past the retention
+func PruneWebhookDeliveriesJob(webhookRepo repository.WebhookRepository, retention time.Duration) Job {
+	return Job{
+		Name:        "prune_webhook_deliveries",
+		Description: "delete finished webhook deliveries older than WEBHOOK_DELIVERY_RETENTION_DAYS",
+		Schedule:    "50 3 * * *",
+		Run: func(ctx context.Context) (string, error) {
+			deleted, err := webhookRepo.DeleteDeliveriesBefore(ctx, nil, time.Now().Add(-retention))
+			return fmt.Sprintf("deleted %d deliveries", deleted), err
+		},
+	}
+}
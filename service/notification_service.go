@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"gorm.io/gorm"
+)
+
+type (
+	NotificationService interface {
+		// Notify stores a copy of notification for every user and wakes their
+		// open streams
+		Notify(ctx context.Context, userIds []string, notification entity.Notification) error
+		// NotifyEventInvitees notifies everyone invited to the event who has
+		// not declined
+		NotifyEventInvitees(ctx context.Context, eventId string, notification entity.Notification) error
+		GetNotifications(ctx context.Context, userId string, req dto.NotificationListRequest) (dto.NotificationPaginationResponse, error)
+		GetUnreadCount(ctx context.Context, userId string) (dto.NotificationUnreadResponse, error)
+		// GetSince returns notifications created after since, oldest first
+		GetSince(ctx context.Context, userId string, since time.Time) ([]dto.NotificationResponse, error)
+		MarkRead(ctx context.Context, userId string, notificationId string) (dto.MarkNotificationsReadResponse, error)
+		MarkAllRead(ctx context.Context, userId string) (dto.MarkNotificationsReadResponse, error)
+		// Subscribe returns a channel that is signalled whenever this instance
+		// changes the user's notifications. The cancel func must be called
+		// once the subscriber is gone.
+		Subscribe(userId string) (<-chan struct{}, func())
+	}
+
+	notificationService struct {
+		notificationRepo repository.NotificationRepository
+		db               *gorm.DB
+
+		mu          sync.Mutex
+		subscribers map[string]map[chan struct{}]struct{}
+	}
+)
+
+// NewNotificationService is provided once through the injector, since open
+// streams are only woken by the instance they subscribed to
+func NewNotificationService(notificationRepo repository.NotificationRepository, db *gorm.DB) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		db:               db,
+		subscribers:      make(map[string]map[chan struct{}]struct{}),
+	}
+}
+
+// NotificationSubscribers turn domain events into in-app notifications
+func NotificationSubscribers(notificationService NotificationService) []Subscriber {
+	return []Subscriber{
+		Subscribe("notify_invited_users", func(ctx context.Context, event dto.InvitationCreated) error {
+			userIds := make([]string, len(event.UserIDs))
+			for i, id := range event.UserIDs {
+				userIds[i] = id.String()
+			}
+			return notificationService.Notify(ctx, userIds, entity.Notification{
+				Type:    entity.NotificationInvitationReceived,
+				Title:   "New invitation",
+				Body:    "You have been invited to " + event.EventName + ".",
+				EventID: &event.EventID,
+			})
+		}),
+		Subscribe("notify_event_creator", func(ctx context.Context, event dto.BookingApproved) error {
+			return notificationService.Notify(ctx, []string{event.EventCreatedBy.String()}, entity.Notification{
+				Type:    entity.NotificationBookingApproved,
+				Title:   "Booking approved",
+				Body:    "The room booking for " + event.EventName + " has been approved.",
+				EventID: &event.EventID,
+			})
+		}),
+		Subscribe("notify_event_creator", func(ctx context.Context, event dto.BookingRejected) error {
+			return notificationService.Notify(ctx, []string{event.EventCreatedBy.String()}, entity.Notification{
+				Type:    entity.NotificationBookingRejected,
+				Title:   "Booking rejected",
+				Body:    "The room booking for " + event.EventName + " has been rejected.",
+				EventID: &event.EventID,
+			})
+		}),
+		Subscribe("notify_invitees", func(ctx context.Context, event dto.EventUpdated) error {
+			if !event.Rescheduled {
+				return nil
+			}
+			return notificationService.NotifyEventInvitees(ctx, event.EventID.String(), entity.Notification{
+				Type:    entity.NotificationEventRescheduled,
+				Title:   "Event rescheduled",
+				Body:    event.EventName + " now takes place on " + event.StartTime.Format("Monday, 02 January 2006 15:04") + ".",
+				EventID: &event.EventID,
+			})
+		}),
+		// events are soft deleted, so the invitations are still there to notify
+		Subscribe("notify_invitees", func(ctx context.Context, event dto.EventCancelled) error {
+			return notificationService.NotifyEventInvitees(ctx, event.EventID.String(), entity.Notification{
+				Type:    entity.NotificationEventCancelled,
+				Title:   "Event cancelled",
+				Body:    event.EventName + " has been cancelled.",
+				EventID: &event.EventID,
+			})
+		}),
+	}
+}
+
+func (s *notificationService) Notify(ctx context.Context, userIds []string, notification entity.Notification) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	notifications := make([]entity.Notification, 0, len(userIds))
+	for _, userId := range userIds {
+		id, err := uuid.Parse(userId)
+		if err != nil {
+			return err
+		}
+		n := notification
+		n.ID = uuid.New()
+		n.UserID = id
+		n.CreatedAt = now
+		notifications = append(notifications, n)
+	}
+
+	if err := s.notificationRepo.CreateMany(ctx, nil, notifications); err != nil {
+		return err
+	}
+
+	for _, userId := range userIds {
+		s.wake(userId)
+	}
+	return nil
+}
+
+func (s *notificationService) NotifyEventInvitees(ctx context.Context, eventId string, notification entity.Notification) error {
+	userIds, err := s.notificationRepo.GetEventInviteeIDs(ctx, nil, eventId)
+	if err != nil {
+		return err
+	}
+	return s.Notify(ctx, userIds, notification)
+}
+
+func (s *notificationService) GetNotifications(ctx context.Context, userId string, req dto.NotificationListRequest) (dto.NotificationPaginationResponse, error) {
+	notifications, pagination, err := s.notificationRepo.GetAllWithPagination(ctx, nil, userId, req.PaginationRequest, req.UnreadOnly)
+	if err != nil {
+		return dto.NotificationPaginationResponse{}, err
+	}
+
+	unread, err := s.notificationRepo.CountUnread(ctx, nil, userId)
+	if err != nil {
+		return dto.NotificationPaginationResponse{}, err
+	}
+
+	data := make([]dto.NotificationResponse, len(notifications))
+	for i, notification := range notifications {
+		data[i] = toNotificationResponse(notification)
+	}
+
+	return dto.NotificationPaginationResponse{
+		Data: data,
+		Meta: dto.NotificationMeta{PaginationResponse: pagination, UnreadCount: unread},
+	}, nil
+}
+
+func (s *notificationService) GetUnreadCount(ctx context.Context, userId string) (dto.NotificationUnreadResponse, error) {
+	unread, err := s.notificationRepo.CountUnread(ctx, nil, userId)
+	if err != nil {
+		return dto.NotificationUnreadResponse{}, err
+	}
+	return dto.NotificationUnreadResponse{UnreadCount: unread}, nil
+}
+
+func (s *notificationService) GetSince(ctx context.Context, userId string, since time.Time) ([]dto.NotificationResponse, error) {
+	notifications, err := s.notificationRepo.GetSince(ctx, nil, userId, since)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.NotificationResponse, len(notifications))
+	for i, notification := range notifications {
+		res[i] = toNotificationResponse(notification)
+	}
+	return res, nil
+}
+
+// MarkRead is idempotent: marking a read notification again changes nothing
+func (s *notificationService) MarkRead(ctx context.Context, userId string, notificationId string) (dto.MarkNotificationsReadResponse, error) {
+	if _, err := uuid.Parse(notificationId); err != nil {
+		return dto.MarkNotificationsReadResponse{}, dto.ErrNotificationNotFound
+	}
+
+	marked, err := s.notificationRepo.MarkRead(ctx, nil, userId, notificationId, time.Now())
+	if err != nil {
+		return dto.MarkNotificationsReadResponse{}, err
+	}
+	return s.markedResponse(ctx, userId, marked)
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, userId string) (dto.MarkNotificationsReadResponse, error) {
+	marked, err := s.notificationRepo.MarkAllRead(ctx, nil, userId, time.Now())
+	if err != nil {
+		return dto.MarkNotificationsReadResponse{}, err
+	}
+	return s.markedResponse(ctx, userId, marked)
+}
+
+func (s *notificationService) markedResponse(ctx context.Context, userId string, marked int64) (dto.MarkNotificationsReadResponse, error) {
+	if marked > 0 {
+		// other open tabs update their unread badge
+		s.wake(userId)
+	}
+
+	unread, err := s.notificationRepo.CountUnread(ctx, nil, userId)
+	if err != nil {
+		return dto.MarkNotificationsReadResponse{}, err
+	}
+	return dto.MarkNotificationsReadResponse{Marked: marked, UnreadCount: unread}, nil
+}
+
+func (s *notificationService) Subscribe(userId string) (<-chan struct{}, func()) {
+	// one pending signal is enough, the stream reads everything new on wake
+	ch := make(chan struct{}, 1)
+
+	s.mu.Lock()
+	if s.subscribers[userId] == nil {
+		s.subscribers[userId] = make(map[chan struct{}]struct{})
+	}
+	s.subscribers[userId][ch] = struct{}{}
+	s.mu.Unlock()
+
+	return ch, func() {
+		s.mu.Lock()
+		delete(s.subscribers[userId], ch)
+		if len(s.subscribers[userId]) == 0 {
+			delete(s.subscribers, userId)
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *notificationService) wake(userId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.subscribers[userId] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func toNotificationResponse(notification entity.Notification) dto.NotificationResponse {
+	res := dto.NotificationResponse{
+		ID:        notification.ID.String(),
+		Type:      notification.Type,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+	if notification.EventID != nil {
+		res.EventID = notification.EventID.String()
+	}
+	return res
+}
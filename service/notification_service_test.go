@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// fakeNotificationRepository records created notifications, the other
+// methods are not used by Notify
+type fakeNotificationRepository struct {
+	repository.NotificationRepository
+	created []entity.Notification
+	err     error
+}
+
+func (r *fakeNotificationRepository) CreateMany(ctx context.Context, tx *gorm.DB, notifications []entity.Notification) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.created = append(r.created, notifications...)
+	return nil
+}
+
+// woken reports whether ch has a pending signal
+func woken(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func Test_NotificationService_Notify(t *testing.T) {
+	alice, bob := uuid.New().String(), uuid.New().String()
+	repoErr := errors.New("database is down")
+
+	tests := []struct {
+		name        string
+		userIds     []string
+		repoErr     error
+		wantErr     bool
+		wantCreated int
+		wantWoken   map[string]bool
+	}{
+		{"no recipients", nil, nil, false, 0, map[string]bool{alice: false, bob: false}},
+		{"one recipient", []string{alice}, nil, false, 1, map[string]bool{alice: true, bob: false}},
+		{"several recipients", []string{alice, bob}, nil, false, 2, map[string]bool{alice: true, bob: true}},
+		{"malformed user id", []string{alice, "not-a-uuid"}, nil, true, 0, map[string]bool{alice: false, bob: false}},
+		{"not stored", []string{alice}, repoErr, true, 0, map[string]bool{alice: false, bob: false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeNotificationRepository{err: tt.repoErr}
+			s := NewNotificationService(repo, nil).(*notificationService)
+
+			streams := make(map[string]<-chan struct{})
+			for userId := range tt.wantWoken {
+				ch, unsubscribe := s.Subscribe(userId)
+				defer unsubscribe()
+				streams[userId] = ch
+			}
+
+			err := s.Notify(context.Background(), tt.userIds, entity.Notification{Title: "New invitation"})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Len(t, repo.created, tt.wantCreated)
+			for i, notification := range repo.created {
+				assert.Equal(t, tt.userIds[i], notification.UserID.String())
+				assert.Equal(t, "New invitation", notification.Title)
+				assert.NotEqual(t, uuid.Nil, notification.ID)
+			}
+			for userId, want := range tt.wantWoken {
+				assert.Equal(t, want, woken(streams[userId]), userId)
+			}
+		})
+	}
+}
+
+func Test_NotificationService_Subscribe(t *testing.T) {
+	s := NewNotificationService(nil, nil).(*notificationService)
+	user := uuid.New().String()
+
+	first, unsubscribeFirst := s.Subscribe(user)
+	second, unsubscribeSecond := s.Subscribe(user)
+
+	// every open stream of a user is woken
+	s.wake(user)
+	assert.True(t, woken(first))
+	assert.True(t, woken(second))
+
+	// signals do not pile up while a stream is busy
+	s.wake(user)
+	s.wake(user)
+	assert.True(t, woken(first))
+	assert.False(t, woken(first))
+
+	// a closed stream is no longer woken, the user is forgotten with the last one
+	unsubscribeFirst()
+	s.wake(user)
+	assert.False(t, woken(first))
+	assert.True(t, woken(second))
+	unsubscribeSecond()
+	assert.Empty(t, s.subscribers)
+}
@@ -0,0 +1,469 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	oidcStateTTL     = 10 * time.Minute
+	oidcDiscoveryTTL = time.Hour
+	// an unknown kid triggers a JWKS refetch at most this often, so forged
+	// tokens cannot make us hammer the provider
+	oidcKeysRefetch = time.Minute
+)
+
+type (
+	// OIDCConfig describes the OpenID Connect provider users can log in with.
+	// Login is disabled when Issuer is empty.
+	OIDCConfig struct {
+		Issuer       string
+		ClientID     string
+		ClientSecret string
+		// RedirectURL is the frontend page the provider sends the user back
+		// to; it posts the code and state to the callback endpoint
+		RedirectURL string
+		Scopes      []string
+
+		EmailClaim string
+		NameClaim  string
+		// RoleClaim holds a string or a list of strings matched against
+		// RoleMapping in order; the first match wins
+		RoleClaim   string
+		RoleMapping []OIDCRoleMapping
+		// RequireVerifiedEmail refuses accounts whose email_verified claim is
+		// not true, since accounts are linked by email
+		RequireVerifiedEmail bool
+
+		HTTPClient *http.Client
+	}
+
+	OIDCRoleMapping struct {
+		Value string
+		Role  entity.UserRole
+	}
+
+	OIDCService interface {
+		AuthorizationURL(ctx context.Context) (string, error)
+		Callback(ctx context.Context, req dto.OIDCCallbackRequest, client dto.ClientInfo) (dto.TokenResponse, error)
+	}
+
+	oidcService struct {
+		config           OIDCConfig
+		oidcRepo         repository.OIDCRepository
+		userRepo         repository.UserRepository
+		organizationRepo repository.OrganizationRepository
+		refreshTokenRepo repository.RefreshTokenRepository
+		jwtService       JWTService
+		db               *gorm.DB
+		provider         *oidcProvider
+	}
+)
+
+// OIDCConfigFromEnv reads the provider settings:
+//   - OIDC_ISSUER, OIDC_CLIENT_ID, OIDC_CLIENT_SECRET (empty for public clients)
+//   - OIDC_REDIRECT_URL: defaults to FRONTEND_URL/auth/sso/callback
+//   - OIDC_SCOPES: space separated, "openid email profile" by default
+//   - OIDC_EMAIL_CLAIM, OIDC_NAME_CLAIM: "email" and "name" by default
+//   - OIDC_ROLE_CLAIM and OIDC_ROLE_MAPPING ("claim value=role,..."): the role
+//     given to accounts provisioned on first login, "user" when nothing matches
+//   - OIDC_REQUIRE_VERIFIED_EMAIL: true unless set to false
+func OIDCConfigFromEnv() (OIDCConfig, error) {
+	config := OIDCConfig{
+		Issuer:               os.Getenv("OIDC_ISSUER"),
+		ClientID:             os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret:         os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:          os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:               strings.Fields(os.Getenv("OIDC_SCOPES")),
+		EmailClaim:           os.Getenv("OIDC_EMAIL_CLAIM"),
+		NameClaim:            os.Getenv("OIDC_NAME_CLAIM"),
+		RoleClaim:            os.Getenv("OIDC_ROLE_CLAIM"),
+		RequireVerifiedEmail: os.Getenv("OIDC_REQUIRE_VERIFIED_EMAIL") != "false",
+	}
+	if config.Issuer == "" {
+		return config, nil
+	}
+	if config.ClientID == "" {
+		return OIDCConfig{}, errors.New("OIDC_CLIENT_ID is required when OIDC_ISSUER is set")
+	}
+	if config.RedirectURL == "" {
+		baseURL := os.Getenv("FRONTEND_URL")
+		if baseURL == "" {
+			baseURL = LOCAL_URL
+		}
+		config.RedirectURL = baseURL + "/auth/sso/callback"
+	}
+
+	mapping, err := parseOIDCRoleMapping(os.Getenv("OIDC_ROLE_MAPPING"))
+	if err != nil {
+		return OIDCConfig{}, err
+	}
+	config.RoleMapping = mapping
+	return config, nil
+}
+
+// parseOIDCRoleMapping reads "value=role" pairs. Departemen accounts need a
+// department record, so they are still created by an admin.
+func parseOIDCRoleMapping(raw string) ([]OIDCRoleMapping, error) {
+	var mapping []OIDCRoleMapping
+	for _, pair := range strings.Split(raw, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		value, role, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("OIDC_ROLE_MAPPING entry %q must be value=role", pair)
+		}
+		switch entity.UserRole(role) {
+		case entity.RoleUser, entity.RoleOrmawa, entity.RoleAdmin:
+		default:
+			return nil, fmt.Errorf("OIDC_ROLE_MAPPING role %q must be user, ormawa or admin", role)
+		}
+		mapping = append(mapping, OIDCRoleMapping{Value: strings.TrimSpace(value), Role: entity.UserRole(role)})
+	}
+	return mapping, nil
+}
+
+func NewOIDCService(
+	config OIDCConfig,
+	oidcRepo repository.OIDCRepository,
+	userRepo repository.UserRepository,
+	organizationRepo repository.OrganizationRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	jwtService JWTService,
+	db *gorm.DB,
+) OIDCService {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	if config.EmailClaim == "" {
+		config.EmailClaim = "email"
+	}
+	if config.NameClaim == "" {
+		config.NameClaim = "name"
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &oidcService{
+		config:           config,
+		oidcRepo:         oidcRepo,
+		userRepo:         userRepo,
+		organizationRepo: organizationRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		jwtService:       jwtService,
+		db:               db,
+		provider:         &oidcProvider{issuer: config.Issuer, client: config.HTTPClient},
+	}
+}
+
+// AuthorizationURL starts a login: it stores a fresh state with its PKCE
+// verifier and nonce and returns the provider URL to send the user to.
+func (s *oidcService) AuthorizationURL(ctx context.Context) (string, error) {
+	if s.config.Issuer == "" {
+		return "", dto.ErrOIDCDisabled
+	}
+
+	discovery, err := s.provider.discover(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "oidc discovery failed", "error", err)
+		return "", dto.ErrOIDCProvider
+	}
+
+	now := time.Now()
+	if err := s.oidcRepo.DeleteExpiredStates(ctx, nil, now); err != nil {
+		slog.ErrorContext(ctx, "failed to delete expired oidc states", "error", err)
+	}
+
+	state := entity.OIDCLoginState{ExpiresAt: now.Add(oidcStateTTL)}
+	for _, value := range []*string{&state.State, &state.CodeVerifier, &state.Nonce} {
+		b, err := randomBytes()
+		if err != nil {
+			return "", err
+		}
+		*value = base64.RawURLEncoding.EncodeToString(b)
+	}
+	if err := s.oidcRepo.CreateState(ctx, nil, state); err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(state.CodeVerifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {s.config.ClientID},
+		"redirect_uri":          {s.config.RedirectURL},
+		"scope":                 {strings.Join(s.config.Scopes, " ")},
+		"state":                 {state.State},
+		"nonce":                 {state.Nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Callback finishes a login. The user is found by their identity at the
+// provider, then by email, and is provisioned when neither exists.
+func (s *oidcService) Callback(ctx context.Context, req dto.OIDCCallbackRequest, client dto.ClientInfo) (dto.TokenResponse, error) {
+	if s.config.Issuer == "" {
+		return dto.TokenResponse{}, dto.ErrOIDCDisabled
+	}
+
+	now := time.Now()
+	state, err := s.oidcRepo.ConsumeState(ctx, nil, req.State)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.TokenResponse{}, dto.ErrOIDCStateInvalid
+		}
+		return dto.TokenResponse{}, err
+	}
+	if state.ExpiresAt.Before(now) {
+		return dto.TokenResponse{}, dto.ErrOIDCStateInvalid
+	}
+
+	tokens, err := s.provider.exchange(ctx, s.config, req.Code, state.CodeVerifier)
+	if err != nil {
+		slog.WarnContext(ctx, "oidc code exchange failed", "error", err)
+		return dto.TokenResponse{}, dto.ErrOIDCCodeExchange
+	}
+
+	claims, err := s.verifyIDToken(ctx, tokens.IDToken, state.Nonce)
+	if err != nil {
+		slog.WarnContext(ctx, "oidc id token rejected", "error", err)
+		return dto.TokenResponse{}, dto.ErrOIDCIDTokenInvalid
+	}
+
+	// some providers only put profile claims in the userinfo response
+	if claimString(claims, s.config.EmailClaim) == "" && tokens.AccessToken != "" {
+		userinfo, err := s.provider.userinfo(ctx, tokens.AccessToken)
+		if err != nil {
+			slog.WarnContext(ctx, "oidc userinfo failed", "error", err)
+		} else if claimString(userinfo, "sub") == claimString(claims, "sub") {
+			for key, value := range userinfo {
+				if _, ok := claims[key]; !ok {
+					claims[key] = value
+				}
+			}
+		}
+	}
+
+	subject := claimString(claims, "sub")
+	email := strings.TrimSpace(claimString(claims, s.config.EmailClaim))
+	if email == "" {
+		return dto.TokenResponse{}, dto.ErrOIDCEmailMissing
+	}
+	if s.config.RequireVerifiedEmail && !claimBool(claims, "email_verified") {
+		return dto.TokenResponse{}, dto.ErrOIDCEmailUnverified
+	}
+
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	user, identity, err := s.resolveUser(ctx, tx, subject, email, claims)
+	if err != nil {
+		tx.Rollback()
+		return dto.TokenResponse{}, err
+	}
+
+	if user.DisabledAt != nil {
+		tx.Rollback()
+		return dto.TokenResponse{}, dto.ErrAccountDisabled
+	}
+
+	// the provider vouches for the address
+	if !user.IsVerified {
+		if err := s.userRepo.MarkVerified(ctx, tx, user.ID.String()); err != nil {
+			tx.Rollback()
+			return dto.TokenResponse{}, dto.ErrUpdateUser
+		}
+		user.IsVerified = true
+	}
+
+	identity.Email = email
+	identity.LastLoginAt = now
+	if err := s.oidcRepo.TouchIdentity(ctx, tx, identity); err != nil {
+		tx.Rollback()
+		return dto.TokenResponse{}, err
+	}
+
+	organization, err := defaultOrganization(ctx, tx, s.organizationRepo, user)
+	if err != nil {
+		tx.Rollback()
+		return dto.TokenResponse{}, err
+	}
+
+	response, err := startSession(ctx, tx, s.refreshTokenRepo, s.jwtService, user, organization, client)
+	if err != nil {
+		tx.Rollback()
+		return dto.TokenResponse{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return dto.TokenResponse{}, err
+	}
+	return response, nil
+}
+
+// resolveUser finds the account of the provider identity, linking an account
+// with the same email or provisioning a new one on the first login
+func (s *oidcService) resolveUser(ctx context.Context, tx *gorm.DB, subject string, email string, claims jwt.MapClaims) (entity.User, entity.UserIdentity, error) {
+	identity, err := s.oidcRepo.GetIdentity(ctx, tx, s.config.Issuer, subject)
+	if err == nil {
+		return identity.User, identity, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.User{}, entity.UserIdentity{}, err
+	}
+
+	user, err := s.userRepo.GetUserByEmail(ctx, tx, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		password, err := randomBytes()
+		if err != nil {
+			return entity.User{}, entity.UserIdentity{}, err
+		}
+		user, err = s.userRepo.Register(ctx, tx, entity.User{
+			Name:  s.displayName(claims, email),
+			Email: email,
+			// nobody knows this password; the user can set one through the
+			// forgot password flow to log in without the provider
+			Password:   hex.EncodeToString(password),
+			Role:       s.mapRole(claims),
+			IsVerified: true,
+		})
+		if err != nil {
+			return entity.User{}, entity.UserIdentity{}, dto.ErrCreateUser
+		}
+	} else if err != nil {
+		return entity.User{}, entity.UserIdentity{}, err
+	}
+
+	identity, err = s.oidcRepo.CreateIdentity(ctx, tx, entity.UserIdentity{
+		UserID:      user.ID,
+		Issuer:      s.config.Issuer,
+		Subject:     subject,
+		Email:       email,
+		LastLoginAt: time.Now(),
+	})
+	if err != nil {
+		return entity.User{}, entity.UserIdentity{}, err
+	}
+	return user, identity, nil
+}
+
+func (s *oidcService) displayName(claims jwt.MapClaims, email string) string {
+	name := strings.TrimSpace(claimString(claims, s.config.NameClaim))
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	return name
+}
+
+func (s *oidcService) mapRole(claims jwt.MapClaims) entity.UserRole {
+	if s.config.RoleClaim == "" {
+		return entity.RoleUser
+	}
+
+	var values []string
+	switch value := claims[s.config.RoleClaim].(type) {
+	case string:
+		values = []string{value}
+	case []any:
+		for _, v := range value {
+			if v, ok := v.(string); ok {
+				values = append(values, v)
+			}
+		}
+	}
+
+	for _, mapping := range s.config.RoleMapping {
+		for _, value := range values {
+			if value == mapping.Value {
+				return mapping.Role
+			}
+		}
+	}
+	return entity.RoleUser
+}
+
+// verifyIDToken checks the signature against the provider keys and the
+// issuer, audience, expiry and nonce of the token
+func (s *oidcService) verifyIDToken(ctx context.Context, raw string, nonce string) (jwt.MapClaims, error) {
+	if raw == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	parser := jwt.NewParser(jwt.WithValidMethods(oidcSigningMethods))
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(raw, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return s.provider.key(ctx, kid)
+	}); err != nil {
+		return nil, err
+	}
+
+	if !claims.VerifyIssuer(s.config.Issuer, true) {
+		return nil, errors.New("issuer mismatch")
+	}
+	if !claims.VerifyAudience(s.config.ClientID, true) {
+		return nil, errors.New("audience mismatch")
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("token has no expiry")
+	}
+	if claimString(claims, "nonce") != nonce {
+		return nil, errors.New("nonce mismatch")
+	}
+	if claimString(claims, "sub") == "" {
+		return nil, errors.New("token has no subject")
+	}
+	return claims, nil
+}
+
+func claimString(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// claimBool also accepts "true", which some providers send
+func claimBool(claims jwt.MapClaims, name string) bool {
+	switch value := claims[name].(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	}
+	return false
+}
+
+func randomBytes() ([]byte, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseOIDCRoleMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []OIDCRoleMapping
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{
+			name: "pairs in order",
+			raw:  " staff=admin, ormawa-member=ormawa ,student=user,",
+			want: []OIDCRoleMapping{
+				{Value: "staff", Role: entity.RoleAdmin},
+				{Value: "ormawa-member", Role: entity.RoleOrmawa},
+				{Value: "student", Role: entity.RoleUser},
+			},
+		},
+		{"missing role", "staff", nil, true},
+		{"departemen cannot be provisioned", "dept=departemen", nil, true},
+		{"unknown role", "staff=root", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, err := parseOIDCRoleMapping(tt.raw)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, mapping)
+		})
+	}
+}
+
+func Test_OIDCService_MapRole(t *testing.T) {
+	mapping := []OIDCRoleMapping{
+		{Value: "staff", Role: entity.RoleAdmin},
+		{Value: "ormawa", Role: entity.RoleOrmawa},
+	}
+
+	tests := []struct {
+		name      string
+		roleClaim string
+		claims    jwt.MapClaims
+		want      entity.UserRole
+	}{
+		{"no role claim configured", "", jwt.MapClaims{"groups": "staff"}, entity.RoleUser},
+		{"string claim", "groups", jwt.MapClaims{"groups": "ormawa"}, entity.RoleOrmawa},
+		{"list claim", "groups", jwt.MapClaims{"groups": []any{"student", "ormawa"}}, entity.RoleOrmawa},
+		{"first mapping wins", "groups", jwt.MapClaims{"groups": []any{"ormawa", "staff"}}, entity.RoleAdmin},
+		{"no match", "groups", jwt.MapClaims{"groups": []any{"student"}}, entity.RoleUser},
+		{"claim missing", "groups", jwt.MapClaims{}, entity.RoleUser},
+		{"claim of another type", "groups", jwt.MapClaims{"groups": 1.0}, entity.RoleUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &oidcService{config: OIDCConfig{RoleClaim: tt.roleClaim, RoleMapping: mapping}}
+			assert.Equal(t, tt.want, s.mapRole(tt.claims))
+		})
+	}
+}
+
+func Test_OIDCService_DisplayName(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		email  string
+		want   string
+	}{
+		{"name claim", jwt.MapClaims{"name": "  Budi  "}, "budi@its.ac.id", "Budi"},
+		{"falls back to the email", jwt.MapClaims{}, "budi@its.ac.id", "budi"},
+		{"blank name", jwt.MapClaims{"name": " "}, "budi@its.ac.id", "budi"},
+		{"long name is cut", jwt.MapClaims{"name": strings.Repeat("a", 150)}, "budi@its.ac.id", strings.Repeat("a", 100)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &oidcService{config: OIDCConfig{NameClaim: "name"}}
+			assert.Equal(t, tt.want, s.displayName(tt.claims, tt.email))
+		})
+	}
+}
+
+func Test_ClaimBool(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   bool
+	}{
+		{"true", jwt.MapClaims{"email_verified": true}, true},
+		{"false", jwt.MapClaims{"email_verified": false}, false},
+		{"string true", jwt.MapClaims{"email_verified": "true"}, true},
+		{"other string", jwt.MapClaims{"email_verified": "yes"}, false},
+		{"missing", jwt.MapClaims{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, claimBool(tt.claims, "email_verified"))
+		})
+	}
+}
+
+// newTestOIDCProvider serves a discovery document and a JWKS with key
+func newTestOIDCProvider(t *testing.T, kid string, key *rsa.PublicKey) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body any
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			body = oidcDiscovery{
+				Issuer:                server.URL,
+				AuthorizationEndpoint: server.URL + "/authorize",
+				TokenEndpoint:         server.URL + "/token",
+				JWKSURI:               server.URL + "/jwks",
+			}
+		case "/jwks":
+			body = map[string]any{"keys": []oidcJWK{{
+				Kid: kid,
+				Kty: "RSA",
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}}}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func Test_OIDCService_VerifyIDToken(t *testing.T) {
+	providerKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newTestOIDCProvider(t, "provider-key", &providerKey.PublicKey)
+	config := OIDCConfig{Issuer: server.URL, ClientID: "myits-event", ClientSecret: "client-secret"}
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   server.URL,
+			"aud":   "myits-event",
+			"sub":   "subject-1",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": "nonce-1",
+		}
+	}
+	with := func(change func(claims jwt.MapClaims)) jwt.MapClaims {
+		claims := valid()
+		change(claims)
+		return claims
+	}
+	sign := func(method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"valid", sign(jwt.SigningMethodRS256, "provider-key", providerKey, valid()), false},
+		{"empty", "", true},
+		{"other issuer", sign(jwt.SigningMethodRS256, "provider-key", providerKey, with(func(c jwt.MapClaims) { c["iss"] = "https://evil.test" })), true},
+		{"other audience", sign(jwt.SigningMethodRS256, "provider-key", providerKey, with(func(c jwt.MapClaims) { c["aud"] = "other-client" })), true},
+		{"expired", sign(jwt.SigningMethodRS256, "provider-key", providerKey, with(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() })), true},
+		{"no expiry", sign(jwt.SigningMethodRS256, "provider-key", providerKey, with(func(c jwt.MapClaims) { delete(c, "exp") })), true},
+		{"other nonce", sign(jwt.SigningMethodRS256, "provider-key", providerKey, with(func(c jwt.MapClaims) { c["nonce"] = "replayed" })), true},
+		{"no subject", sign(jwt.SigningMethodRS256, "provider-key", providerKey, with(func(c jwt.MapClaims) { delete(c, "sub") })), true},
+		{"signed by another key", sign(jwt.SigningMethodRS256, "provider-key", otherKey, valid()), true},
+		{"unknown kid", sign(jwt.SigningMethodRS256, "other-key", otherKey, valid()), true},
+		{"hs256 keyed with the client secret", sign(jwt.SigningMethodHS256, "provider-key", []byte("client-secret"), valid()), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewOIDCService(config, nil, nil, nil, nil, nil, nil).(*oidcService)
+			claims, err := s.verifyIDToken(context.Background(), tt.token, "nonce-1")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "subject-1", claimString(claims, "sub"))
+		})
+	}
+}
@@ -0,0 +1,439 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"gorm.io/gorm"
+)
+
+type (
+	OrganizationService interface {
+		Create(ctx context.Context, req dto.OrganizationCreateRequest) (dto.OrganizationResponse, error)
+		GetAllOrganizations(ctx context.Context) ([]dto.OrganizationResponse, error)
+		GetOrganizationByID(ctx context.Context, organizationId string) (dto.OrganizationResponse, error)
+		Update(ctx context.Context, organizationId string, req dto.OrganizationUpdateRequest, userId string, role string) (dto.OrganizationResponse, error)
+		GetMyOrganizations(ctx context.Context, userId string) ([]dto.OrganizationResponse, error)
+		SwitchOrganization(ctx context.Context, organizationId string, userId string, sessionId string) (dto.TokenResponse, error)
+
+		GetMembers(ctx context.Context, organizationId string, userId string, role string) ([]dto.OrganizationMemberResponse, error)
+		AddMember(ctx context.Context, organizationId string, req dto.OrganizationMemberAddRequest, userId string, role string) (dto.OrganizationMemberResponse, error)
+		UpdateMember(ctx context.Context, organizationId string, memberUserId string, req dto.OrganizationMemberUpdateRequest, userId string, role string) (dto.OrganizationMemberResponse, error)
+		RemoveMember(ctx context.Context, organizationId string, memberUserId string, userId string, role string) error
+	}
+
+	organizationService struct {
+		organizationRepo repository.OrganizationRepository
+		userRepo         repository.UserRepository
+		refreshTokenRepo repository.RefreshTokenRepository
+		jwtService       JWTService
+		db               *gorm.DB
+	}
+)
+
+func NewOrganizationService(
+	organizationRepo repository.OrganizationRepository,
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	jwtService JWTService,
+	db *gorm.DB,
+) OrganizationService {
+	return &organizationService{
+		organizationRepo: organizationRepo,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		jwtService:       jwtService,
+		db:               db,
+	}
+}
+
+// Create sets up an organization with an existing account as its first chair.
+func (s *organizationService) Create(ctx context.Context, req dto.OrganizationCreateRequest) (dto.OrganizationResponse, error) {
+	exists, err := s.organizationRepo.CheckOrganizationName(ctx, nil, req.Name)
+	if err != nil {
+		return dto.OrganizationResponse{}, err
+	}
+	if exists {
+		return dto.OrganizationResponse{}, dto.ErrOrganizationNameExists
+	}
+
+	chair, err := s.userRepo.GetUserByEmail(ctx, nil, req.ChairEmail)
+	if err != nil {
+		return dto.OrganizationResponse{}, dto.ErrUserNotFound
+	}
+	if !canJoinOrganization(chair) {
+		return dto.OrganizationResponse{}, dto.ErrOrganizationMemberRole
+	}
+
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	organization, err := s.organizationRepo.Create(ctx, tx, entity.Organization{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		tx.Rollback()
+		return dto.OrganizationResponse{}, err
+	}
+
+	if _, err := s.organizationRepo.AddMember(ctx, tx, entity.OrganizationMember{
+		OrganizationID: organization.ID,
+		UserID:         chair.ID,
+		Role:           entity.OrganizationRoleChair,
+	}); err != nil {
+		tx.Rollback()
+		return dto.OrganizationResponse{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return dto.OrganizationResponse{}, err
+	}
+	return toOrganizationResponse(organization), nil
+}
+
+func (s *organizationService) GetAllOrganizations(ctx context.Context) ([]dto.OrganizationResponse, error) {
+	organizations, err := s.organizationRepo.GetAllOrganizations(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.OrganizationResponse, len(organizations))
+	for i, organization := range organizations {
+		responses[i] = toOrganizationResponse(organization)
+	}
+	return responses, nil
+}
+
+func (s *organizationService) GetOrganizationByID(ctx context.Context, organizationId string) (dto.OrganizationResponse, error) {
+	organization, err := s.organizationRepo.GetOrganizationByID(ctx, nil, organizationId)
+	if err != nil {
+		return dto.OrganizationResponse{}, dto.ErrOrganizationNotFound
+	}
+	return toOrganizationResponse(organization), nil
+}
+
+func (s *organizationService) Update(ctx context.Context, organizationId string, req dto.OrganizationUpdateRequest, userId string, role string) (dto.OrganizationResponse, error) {
+	organization, err := s.organizationRepo.GetOrganizationByID(ctx, nil, organizationId)
+	if err != nil {
+		return dto.OrganizationResponse{}, dto.ErrOrganizationNotFound
+	}
+
+	if err := s.authorize(ctx, organization.ID, userId, role, entity.OrganizationRoleChair, entity.OrganizationRoleSecretary); err != nil {
+		return dto.OrganizationResponse{}, err
+	}
+
+	if req.Name != "" && req.Name != organization.Name {
+		exists, err := s.organizationRepo.CheckOrganizationName(ctx, nil, req.Name)
+		if err != nil {
+			return dto.OrganizationResponse{}, err
+		}
+		if exists {
+			return dto.OrganizationResponse{}, dto.ErrOrganizationNameExists
+		}
+		organization.Name = req.Name
+	}
+	if req.Description != "" {
+		organization.Description = req.Description
+	}
+
+	updated, err := s.organizationRepo.Update(ctx, nil, organization)
+	if err != nil {
+		return dto.OrganizationResponse{}, err
+	}
+	return toOrganizationResponse(updated), nil
+}
+
+func (s *organizationService) GetMyOrganizations(ctx context.Context, userId string) ([]dto.OrganizationResponse, error) {
+	uid, err := uuid.Parse(userId)
+	if err != nil {
+		return nil, dto.ErrUserNotFound
+	}
+
+	organizations, err := s.organizationRepo.GetMembershipsByUserID(ctx, nil, uid)
+	if err != nil {
+		return nil, err
+	}
+	if organizations == nil {
+		organizations = []dto.OrganizationResponse{}
+	}
+	return organizations, nil
+}
+
+// SwitchOrganization issues an access token acting for another organization
+// the user belongs to.
+func (s *organizationService) SwitchOrganization(ctx context.Context, organizationId string, userId string, sessionId string) (dto.TokenResponse, error) {
+	user, err := s.userRepo.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return dto.TokenResponse{}, dto.ErrUserNotFound
+	}
+
+	organizationUUID, err := uuid.Parse(organizationId)
+	if err != nil {
+		return dto.TokenResponse{}, dto.ErrOrganizationNotFound
+	}
+
+	member, err := s.organizationRepo.GetMember(ctx, nil, organizationUUID, user.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.TokenResponse{}, dto.ErrOrganizationMemberMissing
+		}
+		return dto.TokenResponse{}, err
+	}
+
+	// the session remembers the organization, so refreshed tokens keep acting for it
+	if err := s.refreshTokenRepo.SetOrganization(ctx, nil, sessionId, &member.OrganizationID); err != nil {
+		return dto.TokenResponse{}, err
+	}
+
+	return issueAccessToken(s.jwtService, user, &ActiveOrganization{
+		ID:   member.OrganizationID.String(),
+		Role: member.Role,
+	}, sessionId), nil
+}
+
+func (s *organizationService) GetMembers(ctx context.Context, organizationId string, userId string, role string) ([]dto.OrganizationMemberResponse, error) {
+	organization, err := s.organizationRepo.GetOrganizationByID(ctx, nil, organizationId)
+	if err != nil {
+		return nil, dto.ErrOrganizationNotFound
+	}
+
+	// every member may see who else is in the organization
+	if err := s.authorize(ctx, organization.ID, userId, role,
+		entity.OrganizationRoleChair, entity.OrganizationRoleSecretary,
+		entity.OrganizationRoleCommittee, entity.OrganizationRoleScanner,
+	); err != nil {
+		return nil, err
+	}
+
+	return s.organizationRepo.GetMembers(ctx, nil, organization.ID)
+}
+
+func (s *organizationService) AddMember(ctx context.Context, organizationId string, req dto.OrganizationMemberAddRequest, userId string, role string) (dto.OrganizationMemberResponse, error) {
+	organization, err := s.organizationRepo.GetOrganizationByID(ctx, nil, organizationId)
+	if err != nil {
+		return dto.OrganizationMemberResponse{}, dto.ErrOrganizationNotFound
+	}
+
+	if err := s.authorize(ctx, organization.ID, userId, role, entity.OrganizationRoleChair); err != nil {
+		return dto.OrganizationMemberResponse{}, err
+	}
+
+	user, err := s.userRepo.GetUserByEmail(ctx, nil, req.Email)
+	if err != nil {
+		return dto.OrganizationMemberResponse{}, dto.ErrUserNotFound
+	}
+	if !canJoinOrganization(user) {
+		return dto.OrganizationMemberResponse{}, dto.ErrOrganizationMemberRole
+	}
+
+	if _, err := s.organizationRepo.GetMember(ctx, nil, organization.ID, user.ID); err == nil {
+		return dto.OrganizationMemberResponse{}, dto.ErrOrganizationMemberExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.OrganizationMemberResponse{}, err
+	}
+
+	member, err := s.organizationRepo.AddMember(ctx, nil, entity.OrganizationMember{
+		OrganizationID: organization.ID,
+		UserID:         user.ID,
+		Role:           req.Role,
+	})
+	if err != nil {
+		return dto.OrganizationMemberResponse{}, err
+	}
+	return toOrganizationMemberResponse(member, user), nil
+}
+
+func (s *organizationService) UpdateMember(ctx context.Context, organizationId string, memberUserId string, req dto.OrganizationMemberUpdateRequest, userId string, role string) (dto.OrganizationMemberResponse, error) {
+	organization, err := s.organizationRepo.GetOrganizationByID(ctx, nil, organizationId)
+	if err != nil {
+		return dto.OrganizationMemberResponse{}, dto.ErrOrganizationNotFound
+	}
+
+	if err := s.authorize(ctx, organization.ID, userId, role, entity.OrganizationRoleChair); err != nil {
+		return dto.OrganizationMemberResponse{}, err
+	}
+
+	user, err := s.userRepo.GetUserById(ctx, nil, memberUserId)
+	if err != nil {
+		return dto.OrganizationMemberResponse{}, dto.ErrUserNotFound
+	}
+
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	member, err := s.organizationRepo.GetMember(ctx, tx, organization.ID, user.ID)
+	if err != nil {
+		tx.Rollback()
+		return dto.OrganizationMemberResponse{}, dto.ErrOrganizationMemberMissing
+	}
+
+	if member.Role == entity.OrganizationRoleChair && req.Role != entity.OrganizationRoleChair {
+		if err := s.ensureAnotherChair(ctx, tx, organization.ID); err != nil {
+			tx.Rollback()
+			return dto.OrganizationMemberResponse{}, err
+		}
+	}
+
+	if err := s.organizationRepo.UpdateMemberRole(ctx, tx, member.ID, req.Role); err != nil {
+		tx.Rollback()
+		return dto.OrganizationMemberResponse{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return dto.OrganizationMemberResponse{}, err
+	}
+
+	member.Role = req.Role
+	return toOrganizationMemberResponse(member, user), nil
+}
+
+func (s *organizationService) RemoveMember(ctx context.Context, organizationId string, memberUserId string, userId string, role string) error {
+	organization, err := s.organizationRepo.GetOrganizationByID(ctx, nil, organizationId)
+	if err != nil {
+		return dto.ErrOrganizationNotFound
+	}
+
+	if err := s.authorize(ctx, organization.ID, userId, role, entity.OrganizationRoleChair); err != nil {
+		return err
+	}
+
+	memberUUID, err := uuid.Parse(memberUserId)
+	if err != nil {
+		return dto.ErrOrganizationMemberMissing
+	}
+
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	member, err := s.organizationRepo.GetMember(ctx, tx, organization.ID, memberUUID)
+	if err != nil {
+		tx.Rollback()
+		return dto.ErrOrganizationMemberMissing
+	}
+
+	if member.Role == entity.OrganizationRoleChair {
+		if err := s.ensureAnotherChair(ctx, tx, organization.ID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := s.organizationRepo.RemoveMember(ctx, tx, member.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// authorize lets admins through and otherwise requires the user to hold one
+// of the given roles in the organization.
+func (s *organizationService) authorize(ctx context.Context, organizationID uuid.UUID, userId string, role string, allowed ...string) error {
+	if role == "admin" {
+		return nil
+	}
+
+	userUUID, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.ErrOrganizationForbidden
+	}
+
+	member, err := s.organizationRepo.GetMember(ctx, nil, organizationID, userUUID)
+	if err != nil {
+		return dto.ErrOrganizationForbidden
+	}
+	for _, r := range allowed {
+		if member.Role == r {
+			return nil
+		}
+	}
+	return dto.ErrOrganizationForbidden
+}
+
+func (s *organizationService) ensureAnotherChair(ctx context.Context, tx *gorm.DB, organizationID uuid.UUID) error {
+	chairs, err := s.organizationRepo.CountMembersByRole(ctx, tx, organizationID, entity.OrganizationRoleChair)
+	if err != nil {
+		return err
+	}
+	if chairs <= 1 {
+		return dto.ErrLastChair
+	}
+	return nil
+}
+
+// canJoinOrganization keeps staff accounts out of organizations. Legacy ormawa
+// accounts stay eligible so they can chair the organization they became.
+func canJoinOrganization(user entity.User) bool {
+	return user.Role == entity.RoleUser || user.Role == entity.RoleOrmawa
+}
+
+// defaultOrganization is the organization a member acts for right after
+// logging in: their oldest one
+func defaultOrganization(ctx context.Context, tx *gorm.DB, organizationRepo repository.OrganizationRepository, user entity.User) (*ActiveOrganization, error) {
+	if !canJoinOrganization(user) {
+		return nil, nil
+	}
+
+	memberships, err := organizationRepo.GetMembershipsByUserID(ctx, tx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(memberships) == 0 {
+		return nil, nil
+	}
+	return &ActiveOrganization{ID: memberships[0].ID, Role: memberships[0].Role}, nil
+}
+
+// issueAccessToken signs a token for the user. Acting for an organization
+// grants the ormawa role so members can use the ormawa endpoints.
+func issueAccessToken(jwtService JWTService, user entity.User, organization *ActiveOrganization, sessionId string) dto.TokenResponse {
+	role := string(user.Role)
+	if organization != nil {
+		role = string(entity.RoleOrmawa)
+	}
+
+	response := dto.TokenResponse{
+		AccessToken: jwtService.GenerateAccessToken(user.ID.String(), role, organization, sessionId),
+		Role:        role,
+	}
+	if organization != nil {
+		response.OrganizationID = organization.ID
+		response.OrganizationRole = organization.Role
+	}
+	return response
+}
+
+func toOrganizationResponse(organization entity.Organization) dto.OrganizationResponse {
+	return dto.OrganizationResponse{
+		ID:          organization.ID.String(),
+		Name:        organization.Name,
+		Description: organization.Description,
+	}
+}
+
+func toOrganizationMemberResponse(member entity.OrganizationMember, user entity.User) dto.OrganizationMemberResponse {
+	return dto.OrganizationMemberResponse{
+		ID:        member.ID.String(),
+		UserID:    user.ID.String(),
+		UserName:  user.Name,
+		UserEmail: user.Email,
+		Role:      member.Role,
+	}
+}
+
+// checkEventOwnership rejects events not owned by the caller's active
+// organization. Admins act without one and may touch any event.
+func checkEventOwnership(event entity.Event, organizationId string) error {
+	if organizationId == "" {
+		return nil
+	}
+	if event.OrganizationID == nil || event.OrganizationID.String() != organizationId {
+		return dto.ErrOrganizationForbidden
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CheckEventOwnership(t *testing.T) {
+	owner := uuid.New()
+	other := uuid.New()
+
+	tests := []struct {
+		name           string
+		event          entity.Event
+		organizationId string
+		wantErr        error
+	}{
+		{"no acting organization", entity.Event{OrganizationID: &owner}, "", nil},
+		{"owning organization", entity.Event{OrganizationID: &owner}, owner.String(), nil},
+		{"other organization", entity.Event{OrganizationID: &owner}, other.String(), dto.ErrOrganizationForbidden},
+		{"event without organization", entity.Event{}, owner.String(), dto.ErrOrganizationForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkEventOwnership(tt.event, tt.organizationId)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_CanJoinOrganization(t *testing.T) {
+	tests := []struct {
+		role entity.UserRole
+		want bool
+	}{
+		{entity.RoleUser, true},
+		{entity.RoleOrmawa, true},
+		{entity.RoleDepartemen, false},
+		{entity.RoleAdmin, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			assert.Equal(t, tt.want, canJoinOrganization(entity.User{Role: tt.role}))
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/miraicantsleep/myits-event-be/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultUploadMaxSizeMB = 5
+	maxRoomImagesPerUpload = 10
+	roomThumbnailSize      = 320
+)
+
+type (
+	RoomImageService interface {
+		Upload(ctx context.Context, roomId string, kind string, files []*multipart.FileHeader, userId string, role string) ([]dto.RoomImageResponse, error)
+		GetRoomImages(ctx context.Context, roomId string) ([]dto.RoomImageResponse, error)
+		Delete(ctx context.Context, roomId string, imageId string, userId string, role string) error
+	}
+
+	roomImageService struct {
+		roomImageRepo  repository.RoomImageRepository
+		roomRepo       repository.RoomRepository
+		departmentRepo repository.DepartmentRepository
+		storage        utils.Storage
+		db             *gorm.DB
+	}
+)
+
+func NewRoomImageService(
+	roomImageRepo repository.RoomImageRepository,
+	roomRepo repository.RoomRepository,
+	departmentRepo repository.DepartmentRepository,
+	storage utils.Storage,
+	db *gorm.DB,
+) RoomImageService {
+	return &roomImageService{
+		roomImageRepo:  roomImageRepo,
+		roomRepo:       roomRepo,
+		departmentRepo: departmentRepo,
+		storage:        storage,
+		db:             db,
+	}
+}
+
+func (s *roomImageService) Upload(ctx context.Context, roomId string, kind string, files []*multipart.FileHeader, userId string, role string) ([]dto.RoomImageResponse, error) {
+	if len(files) == 0 {
+		return nil, dto.ErrFileRequired
+	}
+	if len(files) > maxRoomImagesPerUpload {
+		return nil, dto.ErrRoomImageTooManyFiles
+	}
+	if kind == entity.RoomImageKindFloorPlan && len(files) > 1 {
+		return nil, dto.ErrFloorPlanSingleFile
+	}
+
+	room, err := s.roomRepo.GetRoomByID(ctx, roomId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, room, userId, role); err != nil {
+		return nil, err
+	}
+
+	// a room has a single floor plan, so the previous one is replaced
+	var replaced []entity.RoomImage
+	if kind == entity.RoomImageKindFloorPlan {
+		replaced, err = s.roomImageRepo.GetRoomImages(ctx, nil, room.ID, entity.RoomImageKindFloorPlan)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var stored []string
+	cleanup := func() {
+		for _, key := range stored {
+			_ = s.storage.Delete(key)
+		}
+	}
+
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer SafeRollback(tx)
+
+	responses := make([]dto.RoomImageResponse, 0, len(files))
+	for _, file := range files {
+		image, keys, err := s.storeImage(room.ID, kind, file)
+		stored = append(stored, keys...)
+		if err != nil {
+			tx.Rollback()
+			cleanup()
+			return nil, err
+		}
+
+		image, err = s.roomImageRepo.Create(ctx, tx, image)
+		if err != nil {
+			tx.Rollback()
+			cleanup()
+			return nil, err
+		}
+		responses = append(responses, s.toRoomImageResponse(image))
+	}
+
+	for _, old := range replaced {
+		if err := s.roomImageRepo.Delete(ctx, tx, old.ID); err != nil {
+			tx.Rollback()
+			cleanup()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	for _, old := range replaced {
+		s.deleteFiles(old)
+	}
+
+	return responses, nil
+}
+
+func (s *roomImageService) GetRoomImages(ctx context.Context, roomId string) ([]dto.RoomImageResponse, error) {
+	room, err := s.roomRepo.GetRoomByID(ctx, roomId)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := s.roomImageRepo.GetRoomImages(ctx, nil, room.ID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.RoomImageResponse, 0, len(images))
+	for _, image := range images {
+		responses = append(responses, s.toRoomImageResponse(image))
+	}
+	return responses, nil
+}
+
+func (s *roomImageService) Delete(ctx context.Context, roomId string, imageId string, userId string, role string) error {
+	room, err := s.roomRepo.GetRoomByID(ctx, roomId)
+	if err != nil {
+		return err
+	}
+	if err := s.authorize(ctx, room, userId, role); err != nil {
+		return err
+	}
+
+	image, err := s.roomImageRepo.GetRoomImageByID(ctx, nil, imageId)
+	if err != nil {
+		return err
+	}
+	if image.RoomID != room.ID {
+		return dto.ErrRoomImageNotFound
+	}
+
+	if err := s.roomImageRepo.Delete(ctx, nil, image.ID); err != nil {
+		return err
+	}
+
+	s.deleteFiles(image)
+	return nil
+}
+
+// storeImage validates an uploaded file and writes it together with its
+// thumbnail to storage. The returned keys are the files written so far, so
+// the caller can clean up even when an error is returned.
+func (s *roomImageService) storeImage(roomID uuid.UUID, kind string, file *multipart.FileHeader) (entity.RoomImage, []string, error) {
+	data, mimeType, err := readUpload(file)
+	if err != nil {
+		return entity.RoomImage{}, nil, err
+	}
+
+	ext, ok := utils.ImageExtensions[mimeType]
+	if !ok {
+		return entity.RoomImage{}, nil, dto.ErrRoomImageInvalidType
+	}
+
+	thumbnail, thumbExt, err := utils.MakeThumbnail(bytes.NewReader(data), roomThumbnailSize)
+	if errors.Is(err, utils.ErrImageTooLarge) {
+		return entity.RoomImage{}, nil, dto.ErrRoomImageTooLarge
+	}
+	if err != nil {
+		return entity.RoomImage{}, nil, dto.ErrRoomImageInvalidType
+	}
+
+	fileID := uuid.New().String()
+	key := fmt.Sprintf("rooms/%s/%s.%s", roomID, fileID, ext)
+	thumbKey := fmt.Sprintf("rooms/%s/thumbs/%s.%s", roomID, fileID, thumbExt)
+
+	var stored []string
+	if err := s.storage.Save(key, bytes.NewReader(data)); err != nil {
+		return entity.RoomImage{}, stored, err
+	}
+	stored = append(stored, key)
+
+	if err := s.storage.Save(thumbKey, bytes.NewReader(thumbnail)); err != nil {
+		return entity.RoomImage{}, stored, err
+	}
+	stored = append(stored, thumbKey)
+
+	return entity.RoomImage{
+		RoomID:        roomID,
+		Kind:          kind,
+		Path:          key,
+		ThumbnailPath: thumbKey,
+		MimeType:      mimeType,
+		Size:          int64(len(data)),
+	}, stored, nil
+}
+
+func (s *roomImageService) deleteFiles(image entity.RoomImage) {
+	_ = s.storage.Delete(image.Path)
+	_ = s.storage.Delete(image.ThumbnailPath)
+}
+
+func (s *roomImageService) authorize(ctx context.Context, room entity.Room, userId string, role string) error {
+	if role == "admin" {
+		return nil
+	}
+
+	department, err := s.departmentRepo.GetDepartmentByUserId(ctx, nil, userId)
+	if err != nil {
+		return err
+	}
+	if department.ID != room.DepartmentID {
+		return dto.ErrRoomImageForbidden
+	}
+	return nil
+}
+
+func (s *roomImageService) toRoomImageResponse(image entity.RoomImage) dto.RoomImageResponse {
+	return dto.RoomImageResponse{
+		ID:           image.ID.String(),
+		Kind:         image.Kind,
+		URL:          s.storage.URL(image.Path),
+		ThumbnailURL: s.storage.URL(image.ThumbnailPath),
+		MimeType:     image.MimeType,
+		Size:         image.Size,
+	}
+}
+
+// readUpload reads an uploaded file into memory, enforcing the upload size
+// limit, and sniffs its MIME type from the content.
+func readUpload(file *multipart.FileHeader) ([]byte, string, error) {
+	maxSize := uploadMaxSize()
+	if file.Size > maxSize {
+		return nil, "", dto.ErrFileTooLarge
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, "", err
+	}
+	defer src.Close()
+
+	// the header size comes from the client, so enforce the limit on read too
+	data, err := io.ReadAll(io.LimitReader(src, maxSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, "", dto.ErrFileTooLarge
+	}
+
+	return data, utils.DetectContentType(data), nil
+}
+
+// uploadMaxSize reads UPLOAD_MAX_SIZE_MB, falling back to 5 MB.
+func uploadMaxSize() int64 {
+	sizeMB, err := strconv.Atoi(os.Getenv("UPLOAD_MAX_SIZE_MB"))
+	if err != nil || sizeMB <= 0 {
+		sizeMB = defaultUploadMaxSizeMB
+	}
+	return int64(sizeMB) << 20
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"gorm.io/gorm"
+)
+
+const operatingHourLayout = "15:04"
+
+type (
+	RoomScheduleService interface {
+		GetRoomOperatingHours(ctx context.Context, roomId string) ([]dto.OperatingHourResponse, error)
+		SetRoomOperatingHours(ctx context.Context, roomId string, req dto.OperatingHoursUpdateRequest, userId string, role string) ([]dto.OperatingHourResponse, error)
+		GetDepartmentOperatingHours(ctx context.Context, departmentId string) ([]dto.OperatingHourResponse, error)
+		SetDepartmentOperatingHours(ctx context.Context, departmentId string, req dto.OperatingHoursUpdateRequest, userId string, role string) ([]dto.OperatingHourResponse, error)
+		GetRoomBlackouts(ctx context.Context, roomId string) ([]dto.BlackoutResponse, error)
+		CreateRoomBlackout(ctx context.Context, roomId string, req dto.BlackoutCreateRequest, userId string, role string) (dto.BlackoutResponse, error)
+		GetDepartmentBlackouts(ctx context.Context, departmentId string) ([]dto.BlackoutResponse, error)
+		CreateDepartmentBlackout(ctx context.Context, departmentId string, req dto.BlackoutCreateRequest, userId string, role string) (dto.BlackoutResponse, error)
+		DeleteBlackout(ctx context.Context, blackoutId string, userId string, role string) error
+		GetAvailableRooms(ctx context.Context, req dto.RoomAvailabilityRequest) ([]dto.RoomResponse, error)
+		// CheckRoomSchedule returns an error when the room is closed (outside its
+		// operating hours or blacked out) at any point of [start, end).
+		CheckRoomSchedule(ctx context.Context, tx *gorm.DB, room entity.Room, start time.Time, end time.Time) error
+	}
+
+	roomScheduleService struct {
+		scheduleRepo   repository.RoomScheduleRepository
+		roomRepo       repository.RoomRepository
+		departmentRepo repository.DepartmentRepository
+		db             *gorm.DB
+	}
+)
+
+func NewRoomScheduleService(
+	scheduleRepo repository.RoomScheduleRepository,
+	roomRepo repository.RoomRepository,
+	departmentRepo repository.DepartmentRepository,
+	db *gorm.DB,
+) RoomScheduleService {
+	return &roomScheduleService{
+		scheduleRepo:   scheduleRepo,
+		roomRepo:       roomRepo,
+		departmentRepo: departmentRepo,
+		db:             db,
+	}
+}
+
+func (s *roomScheduleService) GetRoomOperatingHours(ctx context.Context, roomId string) ([]dto.OperatingHourResponse, error) {
+	room, err := s.roomRepo.GetRoomByID(ctx, roomId)
+	if err != nil {
+		return nil, err
+	}
+
+	hours, err := s.scheduleRepo.GetRoomOperatingHours(ctx, nil, room.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toOperatingHourResponses(hours), nil
+}
+
+func (s *roomScheduleService) SetRoomOperatingHours(ctx context.Context, roomId string, req dto.OperatingHoursUpdateRequest, userId string, role string) ([]dto.OperatingHourResponse, error) {
+	room, err := s.roomRepo.GetRoomByID(ctx, roomId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeDepartment(ctx, room.DepartmentID, userId, role); err != nil {
+		return nil, err
+	}
+
+	hours, err := parseOperatingHours(req.Hours)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.ReplaceRoomOperatingHours(ctx, nil, room.ID, hours); err != nil {
+		return nil, err
+	}
+	return toOperatingHourResponses(hours), nil
+}
+
+func (s *roomScheduleService) GetDepartmentOperatingHours(ctx context.Context, departmentId string) ([]dto.OperatingHourResponse, error) {
+	department, err := s.departmentRepo.GetDepartmentById(ctx, nil, departmentId)
+	if err != nil {
+		return nil, dto.ErrDepartmentNotFound
+	}
+
+	hours, err := s.scheduleRepo.GetDepartmentOperatingHours(ctx, nil, department.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toOperatingHourResponses(hours), nil
+}
+
+func (s *roomScheduleService) SetDepartmentOperatingHours(ctx context.Context, departmentId string, req dto.OperatingHoursUpdateRequest, userId string, role string) ([]dto.OperatingHourResponse, error) {
+	department, err := s.departmentRepo.GetDepartmentById(ctx, nil, departmentId)
+	if err != nil {
+		return nil, dto.ErrDepartmentNotFound
+	}
+	if err := s.authorizeDepartment(ctx, department.ID, userId, role); err != nil {
+		return nil, err
+	}
+
+	hours, err := parseOperatingHours(req.Hours)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.ReplaceDepartmentOperatingHours(ctx, nil, department.ID, hours); err != nil {
+		return nil, err
+	}
+	return toOperatingHourResponses(hours), nil
+}
+
+func (s *roomScheduleService) GetRoomBlackouts(ctx context.Context, roomId string) ([]dto.BlackoutResponse, error) {
+	room, err := s.roomRepo.GetRoomByID(ctx, roomId)
+	if err != nil {
+		return nil, err
+	}
+
+	blackouts, err := s.scheduleRepo.GetRoomBlackouts(ctx, nil, room.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toBlackoutResponses(blackouts), nil
+}
+
+func (s *roomScheduleService) CreateRoomBlackout(ctx context.Context, roomId string, req dto.BlackoutCreateRequest, userId string, role string) (dto.BlackoutResponse, error) {
+	room, err := s.roomRepo.GetRoomByID(ctx, roomId)
+	if err != nil {
+		return dto.BlackoutResponse{}, err
+	}
+	if err := s.authorizeDepartment(ctx, room.DepartmentID, userId, role); err != nil {
+		return dto.BlackoutResponse{}, err
+	}
+
+	blackout, err := parseBlackout(req)
+	if err != nil {
+		return dto.BlackoutResponse{}, err
+	}
+	blackout.RoomID = &room.ID
+
+	result, err := s.scheduleRepo.CreateBlackout(ctx, nil, blackout)
+	if err != nil {
+		return dto.BlackoutResponse{}, err
+	}
+	return toBlackoutResponse(result), nil
+}
+
+func (s *roomScheduleService) GetDepartmentBlackouts(ctx context.Context, departmentId string) ([]dto.BlackoutResponse, error) {
+	department, err := s.departmentRepo.GetDepartmentById(ctx, nil, departmentId)
+	if err != nil {
+		return nil, dto.ErrDepartmentNotFound
+	}
+
+	blackouts, err := s.scheduleRepo.GetDepartmentBlackouts(ctx, nil, department.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toBlackoutResponses(blackouts), nil
+}
+
+func (s *roomScheduleService) CreateDepartmentBlackout(ctx context.Context, departmentId string, req dto.BlackoutCreateRequest, userId string, role string) (dto.BlackoutResponse, error) {
+	department, err := s.departmentRepo.GetDepartmentById(ctx, nil, departmentId)
+	if err != nil {
+		return dto.BlackoutResponse{}, dto.ErrDepartmentNotFound
+	}
+	if err := s.authorizeDepartment(ctx, department.ID, userId, role); err != nil {
+		return dto.BlackoutResponse{}, err
+	}
+
+	blackout, err := parseBlackout(req)
+	if err != nil {
+		return dto.BlackoutResponse{}, err
+	}
+	blackout.DepartmentID = &department.ID
+
+	result, err := s.scheduleRepo.CreateBlackout(ctx, nil, blackout)
+	if err != nil {
+		return dto.BlackoutResponse{}, err
+	}
+	return toBlackoutResponse(result), nil
+}
+
+func (s *roomScheduleService) DeleteBlackout(ctx context.Context, blackoutId string, userId string, role string) error {
+	blackout, err := s.scheduleRepo.GetBlackoutByID(ctx, nil, blackoutId)
+	if err != nil {
+		return err
+	}
+
+	departmentID := uuid.Nil
+	if blackout.DepartmentID != nil {
+		departmentID = *blackout.DepartmentID
+	} else if blackout.RoomID != nil {
+		room, err := s.roomRepo.GetRoomByID(ctx, blackout.RoomID.String())
+		if err != nil {
+			return err
+		}
+		departmentID = room.DepartmentID
+	}
+	if err := s.authorizeDepartment(ctx, departmentID, userId, role); err != nil {
+		return err
+	}
+
+	return s.scheduleRepo.DeleteBlackout(ctx, nil, blackoutId)
+}
+
+func (s *roomScheduleService) GetAvailableRooms(ctx context.Context, req dto.RoomAvailabilityRequest) ([]dto.RoomResponse, error) {
+	startTime, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	endTime, err := time.Parse(time.RFC3339, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	if !endTime.After(startTime) {
+		return nil, dto.ErrInvalidAvailabilityRange
+	}
+
+	rooms, err := s.roomRepo.GetAllRoom(ctx, req.RoomFilterRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	available := []dto.RoomResponse{}
+	for _, room := range rooms {
+		roomID, err := uuid.Parse(room.ID)
+		if err != nil {
+			return nil, err
+		}
+		departmentID, err := uuid.Parse(room.DepartmentID)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.CheckRoomSchedule(ctx, nil, entity.Room{ID: roomID, DepartmentID: departmentID}, startTime, endTime)
+		if err != nil {
+			if err == dto.ErrRoomOutsideOperatingHours || err == dto.ErrRoomBlackedOut {
+				continue
+			}
+			return nil, err
+		}
+
+		booked, err := s.scheduleRepo.IsRoomBooked(ctx, nil, roomID, startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+		if booked {
+			continue
+		}
+
+		available = append(available, room)
+	}
+
+	return available, nil
+}
+
+func (s *roomScheduleService) CheckRoomSchedule(ctx context.Context, tx *gorm.DB, room entity.Room, start time.Time, end time.Time) error {
+	hours, err := s.scheduleRepo.GetRoomOperatingHours(ctx, tx, room.ID)
+	if err != nil {
+		return err
+	}
+	if len(hours) == 0 {
+		hours, err = s.scheduleRepo.GetDepartmentOperatingHours(ctx, tx, room.DepartmentID)
+		if err != nil {
+			return err
+		}
+	}
+	if !withinOperatingHours(hours, start, end) {
+		return dto.ErrRoomOutsideOperatingHours
+	}
+
+	blackedOut, err := s.scheduleRepo.HasOverlappingBlackout(ctx, tx, room.ID, room.DepartmentID, start, end)
+	if err != nil {
+		return err
+	}
+	if blackedOut {
+		return dto.ErrRoomBlackedOut
+	}
+
+	return nil
+}
+
+// authorizeDepartment lets admins manage every schedule and departemen users
+// only the schedule of their own department.
+func (s *roomScheduleService) authorizeDepartment(ctx context.Context, departmentID uuid.UUID, userId string, role string) error {
+	if role == "admin" {
+		return nil
+	}
+
+	department, err := s.departmentRepo.GetDepartmentByUserId(ctx, nil, userId)
+	if err != nil {
+		return err
+	}
+	if department.ID != departmentID {
+		return dto.ErrScheduleForbidden
+	}
+	return nil
+}
+
+// withinOperatingHours reports whether [start, end) fits inside the opening
+// window of a single day. No configured hours means the room is always open.
+func withinOperatingHours(hours []entity.OperatingHour, start time.Time, end time.Time) bool {
+	if len(hours) == 0 {
+		return true
+	}
+
+	sy, sm, sd := start.Date()
+	ey, em, ed := end.Date()
+	sameDay := sy == ey && sm == em && sd == ed
+	// an event ending exactly at midnight still belongs to the previous day
+	endsAtMidnight := end.Equal(time.Date(sy, sm, sd+1, 0, 0, 0, 0, start.Location()))
+	if !sameDay && !endsAtMidnight {
+		return false
+	}
+
+	startClock := start.Format(operatingHourLayout)
+	endClock := end.Format(operatingHourLayout)
+	if endsAtMidnight {
+		endClock = "24:00"
+	}
+
+	for _, h := range hours {
+		if h.DayOfWeek != int(start.Weekday()) {
+			continue
+		}
+		if startClock >= h.OpenTime && endClock <= h.CloseTime {
+			return true
+		}
+	}
+	return false
+}
+
+func parseOperatingHours(reqs []dto.OperatingHourRequest) ([]entity.OperatingHour, error) {
+	seen := make(map[int]bool)
+	hours := make([]entity.OperatingHour, 0, len(reqs))
+	for _, req := range reqs {
+		if seen[req.DayOfWeek] {
+			return nil, dto.ErrDuplicateOperatingDay
+		}
+		seen[req.DayOfWeek] = true
+
+		open, err := time.Parse(operatingHourLayout, req.OpenTime)
+		if err != nil {
+			return nil, dto.ErrInvalidOperatingHour
+		}
+		closeTime := req.CloseTime
+		if closeTime != "24:00" {
+			closing, err := time.Parse(operatingHourLayout, closeTime)
+			if err != nil || !closing.After(open) {
+				return nil, dto.ErrInvalidOperatingHour
+			}
+		}
+
+		hours = append(hours, entity.OperatingHour{
+			DayOfWeek: req.DayOfWeek,
+			OpenTime:  open.Format(operatingHourLayout),
+			CloseTime: closeTime,
+		})
+	}
+	return hours, nil
+}
+
+func parseBlackout(req dto.BlackoutCreateRequest) (entity.BlackoutPeriod, error) {
+	startTime, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		return entity.BlackoutPeriod{}, err
+	}
+	endTime, err := time.Parse(time.RFC3339, req.EndTime)
+	if err != nil {
+		return entity.BlackoutPeriod{}, err
+	}
+	if !endTime.After(startTime) {
+		return entity.BlackoutPeriod{}, dto.ErrInvalidBlackoutRange
+	}
+
+	return entity.BlackoutPeriod{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Reason:    req.Reason,
+	}, nil
+}
+
+func toOperatingHourResponses(hours []entity.OperatingHour) []dto.OperatingHourResponse {
+	response := make([]dto.OperatingHourResponse, 0, len(hours))
+	for _, h := range hours {
+		response = append(response, dto.OperatingHourResponse{
+			DayOfWeek: h.DayOfWeek,
+			OpenTime:  h.OpenTime,
+			CloseTime: h.CloseTime,
+		})
+	}
+	return response
+}
+
+func toBlackoutResponse(blackout entity.BlackoutPeriod) dto.BlackoutResponse {
+	response := dto.BlackoutResponse{
+		ID:        blackout.ID.String(),
+		StartTime: blackout.StartTime.Format(time.RFC3339),
+		EndTime:   blackout.EndTime.Format(time.RFC3339),
+		Reason:    blackout.Reason,
+	}
+	if blackout.RoomID != nil {
+		response.RoomID = blackout.RoomID.String()
+	}
+	if blackout.DepartmentID != nil {
+		response.DepartmentID = blackout.DepartmentID.String()
+	}
+	return response
+}
+
+func toBlackoutResponses(blackouts []entity.BlackoutPeriod) []dto.BlackoutResponse {
+	response := make([]dto.BlackoutResponse, 0, len(blackouts))
+	for _, b := range blackouts {
+		response = append(response, toBlackoutResponse(b))
+	}
+	return response
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithinOperatingHours(t *testing.T) {
+	// 2026-10-19 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.October, day, hour, minute, 0, 0, time.UTC)
+	}
+	weekdays := []entity.OperatingHour{
+		{DayOfWeek: int(time.Monday), OpenTime: "08:00", CloseTime: "17:00"},
+		{DayOfWeek: int(time.Tuesday), OpenTime: "08:00", CloseTime: "24:00"},
+	}
+
+	tests := []struct {
+		name  string
+		hours []entity.OperatingHour
+		start time.Time
+		end   time.Time
+		want  bool
+	}{
+		{"no hours configured", nil, at(19, 2, 0), at(19, 3, 0), true},
+		{"inside the window", weekdays, at(19, 9, 0), at(19, 12, 0), true},
+		{"exactly the window", weekdays, at(19, 8, 0), at(19, 17, 0), true},
+		{"starts before opening", weekdays, at(19, 7, 59), at(19, 10, 0), false},
+		{"ends after closing", weekdays, at(19, 16, 0), at(19, 17, 1), false},
+		{"closed day", weekdays, at(21, 9, 0), at(21, 10, 0), false},
+		{"ends at midnight on a day open until 24:00", weekdays, at(20, 20, 0), at(21, 0, 0), true},
+		{"ends at midnight on a day closing earlier", weekdays, at(19, 16, 0), at(20, 0, 0), false},
+		{"spans two days", weekdays, at(20, 20, 0), at(21, 1, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, withinOperatingHours(tt.hours, tt.start, tt.end))
+		})
+	}
+}
+
+func Test_ParseOperatingHours(t *testing.T) {
+	tests := []struct {
+		name    string
+		reqs    []dto.OperatingHourRequest
+		want    []entity.OperatingHour
+		wantErr error
+	}{
+		{
+			name: "valid days",
+			reqs: []dto.OperatingHourRequest{
+				{DayOfWeek: 1, OpenTime: "08:00", CloseTime: "17:00"},
+				{DayOfWeek: 2, OpenTime: "00:00", CloseTime: "24:00"},
+			},
+			want: []entity.OperatingHour{
+				{DayOfWeek: 1, OpenTime: "08:00", CloseTime: "17:00"},
+				{DayOfWeek: 2, OpenTime: "00:00", CloseTime: "24:00"},
+			},
+		},
+		{
+			name: "duplicate day",
+			reqs: []dto.OperatingHourRequest{
+				{DayOfWeek: 1, OpenTime: "08:00", CloseTime: "12:00"},
+				{DayOfWeek: 1, OpenTime: "13:00", CloseTime: "17:00"},
+			},
+			wantErr: dto.ErrDuplicateOperatingDay,
+		},
+		{
+			name:    "closes before opening",
+			reqs:    []dto.OperatingHourRequest{{DayOfWeek: 1, OpenTime: "17:00", CloseTime: "08:00"}},
+			wantErr: dto.ErrInvalidOperatingHour,
+		},
+		{
+			name:    "closes when opening",
+			reqs:    []dto.OperatingHourRequest{{DayOfWeek: 1, OpenTime: "08:00", CloseTime: "08:00"}},
+			wantErr: dto.ErrInvalidOperatingHour,
+		},
+		{
+			name:    "not a clock time",
+			reqs:    []dto.OperatingHourRequest{{DayOfWeek: 1, OpenTime: "8am", CloseTime: "17:00"}},
+			wantErr: dto.ErrInvalidOperatingHour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hours, err := parseOperatingHours(tt.reqs)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, hours)
+		})
+	}
+}
@@ -3,7 +3,7 @@ package service
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
 
 	"github.com/google/uuid"
 	"github.com/miraicantsleep/myits-event-be/dto"
@@ -18,7 +18,7 @@ type (
 		GetRoomByID(ctx context.Context, id string) (dto.RoomResponse, error)
 		GetRoomByName(ctx context.Context, name string) (dto.RoomResponse, error)
 		// get all room without pagination
-		GetAllRoom(ctx context.Context) ([]dto.RoomResponse, error)
+		GetAllRoom(ctx context.Context, filter dto.RoomFilterRequest) ([]dto.RoomResponse, error)
 		Update(ctx context.Context, id string, req dto.RoomUpdateRequest) (dto.RoomResponse, error)
 		Delete(ctx context.Context, id string) error
 	}
@@ -41,8 +41,12 @@ func NewRoomService(roomRepository repository.RoomRepository, jwtService JWTServ
 
 func (s *roomService) Create(ctx context.Context, req dto.RoomCreateRequest, userId string, role string) (dto.RoomResponse, error) {
 	roomEntity := entity.Room{
-		Name:     req.Name,
-		Capacity: req.Capacity,
+		Name:                 req.Name,
+		Capacity:             req.Capacity,
+		HasProjector:         req.HasProjector,
+		HasSoundSystem:       req.HasSoundSystem,
+		HasAC:                req.HasAC,
+		WheelchairAccessible: req.WheelchairAccessible,
 	}
 	if role == "departemen" {
 		departmentId, err := s.departmentRepository.GetDepartmentByUserId(ctx, nil, userId)
@@ -69,21 +73,14 @@ func (s *roomService) Create(ctx context.Context, req dto.RoomCreateRequest, use
 		return dto.RoomResponse{}, err
 	}
 
-	response := dto.RoomResponse{
-		ID:           result.ID.String(),
-		Name:         result.Name,
-		Department:   department.Name,
-		DepartmentID: result.DepartmentID.String(),
-		Capacity:     result.Capacity,
-	}
-	return response, nil
+	return toRoomResponse(result, department.Name), nil
 }
 
 func (s *roomService) GetRoomByID(ctx context.Context, id string) (dto.RoomResponse, error) {
 	if id == "" {
 		return dto.RoomResponse{}, errors.New("room ID is required")
 	}
-	log.Println("GetRoomByID called with ID:", id)
+	slog.DebugContext(ctx, "get room", "room_id", id)
 	result, err := s.roomRepository.GetRoomByID(ctx, id)
 	if err != nil {
 		return dto.RoomResponse{}, err
@@ -95,40 +92,53 @@ func (s *roomService) GetRoomByID(ctx context.Context, id string) (dto.RoomRespo
 		return dto.RoomResponse{}, err
 	}
 
-	response := dto.RoomResponse{
-		ID:           result.ID.String(),
-		Name:         result.Name,
-		Department:   department.Name,
-		DepartmentID: result.DepartmentID.String(),
-		Capacity:     result.Capacity,
-	}
-	return response, nil
+	return toRoomResponse(result, department.Name), nil
 }
 
-func (s *roomService) GetAllRoom(ctx context.Context) ([]dto.RoomResponse, error) {
-	result, err := s.roomRepository.GetAllRoom(ctx)
+func (s *roomService) GetAllRoom(ctx context.Context, filter dto.RoomFilterRequest) ([]dto.RoomResponse, error) {
+	result, err := s.roomRepository.GetAllRoom(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
-	var response []dto.RoomResponse
-	for _, room := range result {
-		response = append(response, dto.RoomResponse{
-			ID:           room.ID,
-			Name:         room.Name,
-			Department:   room.Department,
-			DepartmentID: room.DepartmentID,
-			Capacity:     room.Capacity,
-		})
-	}
-	return response, nil
+	return result, nil
 }
 
 func (s *roomService) Update(ctx context.Context, id string, req dto.RoomUpdateRequest) (dto.RoomResponse, error) {
-	roomEntity := entity.Room{
-		Name:         req.Name,
-		DepartmentID: uuid.MustParse(req.DepartmentID),
-		Capacity:     req.Capacity,
+	roomEntity, err := s.roomRepository.GetRoomByID(ctx, id)
+	if err != nil {
+		return dto.RoomResponse{}, err
+	}
+
+	if req.Name != "" {
+		roomEntity.Name = req.Name
+	}
+	if req.Capacity != 0 {
+		if req.Capacity < 0 {
+			return dto.RoomResponse{}, dto.ErrRoomInvalidCapacity
+		}
+		roomEntity.Capacity = req.Capacity
 	}
+	if req.DepartmentID != "" {
+		departmentID, err := uuid.Parse(req.DepartmentID)
+		if err != nil {
+			return dto.RoomResponse{}, err
+		}
+		roomEntity.DepartmentID = departmentID
+	}
+	if req.HasProjector != nil {
+		roomEntity.HasProjector = *req.HasProjector
+	}
+	if req.HasSoundSystem != nil {
+		roomEntity.HasSoundSystem = *req.HasSoundSystem
+	}
+	if req.HasAC != nil {
+		roomEntity.HasAC = *req.HasAC
+	}
+	if req.WheelchairAccessible != nil {
+		roomEntity.WheelchairAccessible = *req.WheelchairAccessible
+	}
+	roomEntity.Department = entity.Department{}
+
 	result, err := s.roomRepository.Update(ctx, id, roomEntity)
 	if err != nil {
 		return dto.RoomResponse{}, err
@@ -140,14 +150,7 @@ func (s *roomService) Update(ctx context.Context, id string, req dto.RoomUpdateR
 		return dto.RoomResponse{}, err
 	}
 
-	response := dto.RoomResponse{
-		ID:           result.ID.String(),
-		Name:         result.Name,
-		Department:   department.Name,
-		DepartmentID: result.DepartmentID.String(),
-		Capacity:     result.Capacity,
-	}
-	return response, nil
+	return toRoomResponse(result, department.Name), nil
 }
 
 func (s *roomService) Delete(ctx context.Context, id string) error {
@@ -170,12 +173,19 @@ func (s *roomService) GetRoomByName(ctx context.Context, name string) (dto.RoomR
 		return dto.RoomResponse{}, err
 	}
 
-	response := dto.RoomResponse{
-		ID:           result.ID.String(),
-		Name:         result.Name,
-		Department:   department.Name,
-		DepartmentID: result.DepartmentID.String(),
-		Capacity:     result.Capacity,
+	return toRoomResponse(result, department.Name), nil
+}
+
+func toRoomResponse(room entity.Room, departmentName string) dto.RoomResponse {
+	return dto.RoomResponse{
+		ID:                   room.ID.String(),
+		Name:                 room.Name,
+		Department:           departmentName,
+		DepartmentID:         room.DepartmentID.String(),
+		Capacity:             room.Capacity,
+		HasProjector:         room.HasProjector,
+		HasSoundSystem:       room.HasSoundSystem,
+		HasAC:                room.HasAC,
+		WheelchairAccessible: room.WheelchairAccessible,
 	}
-	return response, nil
 }
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/miraicantsleep/myits-event-be/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultJobTimeout = 30 * time.Minute
+	jobRunHistory     = 50
+)
+
+// errJobTickTaken is returned when another instance already ran a tick
+var errJobTickTaken = errors.New("scheduled run already taken")
+
+type (
+	// Job is a periodic task. Jobs are provided to the injector under
+	// constants.JobPrefix + Name and picked up by the scheduler at startup.
+	Job struct {
+		Name        string
+		Description string
+		// Schedule is a cron expression, see utils.ParseCron. It can be
+		// overridden with JOB_<NAME>_SCHEDULE.
+		Schedule string
+		// Timeout bounds a run, 30 minutes when zero
+		Timeout time.Duration
+		// Run returns a short summary that is kept in the run history
+		Run func(ctx context.Context) (string, error)
+	}
+
+	SchedulerService interface {
+		// Start runs every job on its schedule until ctx is done
+		Start(ctx context.Context)
+		GetJobs(ctx context.Context) ([]dto.JobResponse, error)
+		GetRuns(ctx context.Context, name string) ([]dto.JobRunResponse, error)
+		// Trigger starts a run of the job in the background
+		Trigger(ctx context.Context, name string, userId string) (dto.JobRunResponse, error)
+	}
+
+	scheduledJob struct {
+		Job
+		schedule utils.CronSchedule
+	}
+
+	schedulerService struct {
+		jobs       map[string]scheduledJob
+		jobRunRepo repository.JobRunRepository
+		db         *gorm.DB
+		instance   string
+		started    bool
+	}
+)
+
+func NewSchedulerService(jobs []Job, jobRunRepo repository.JobRunRepository, db *gorm.DB) (SchedulerService, error) {
+	s := &schedulerService{
+		jobs:       make(map[string]scheduledJob, len(jobs)),
+		jobRunRepo: jobRunRepo,
+		db:         db,
+		instance:   jobInstance(),
+	}
+
+	for _, job := range jobs {
+		if _, ok := s.jobs[job.Name]; ok {
+			return nil, fmt.Errorf("job %s is registered twice", job.Name)
+		}
+		if spec := os.Getenv("JOB_" + strings.ToUpper(job.Name) + "_SCHEDULE"); spec != "" {
+			job.Schedule = spec
+		}
+		schedule, err := utils.ParseCron(job.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %w", job.Name, err)
+		}
+		if job.Timeout == 0 {
+			job.Timeout = defaultJobTimeout
+		}
+		s.jobs[job.Name] = scheduledJob{Job: job, schedule: schedule}
+	}
+
+	return s, nil
+}
+
+func jobInstance() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+func (s *schedulerService) Start(ctx context.Context) {
+	s.started = true
+	for _, job := range s.jobs {
+		go s.loop(ctx, job)
+	}
+	slog.Info("scheduler started", "jobs", len(s.jobs))
+}
+
+func (s *schedulerService) loop(ctx context.Context, job scheduledJob) {
+	for {
+		next := job.schedule.Next(time.Now())
+		if next.IsZero() {
+			slog.Warn("schedule never fires, not scheduling job", "job", job.Name)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// every instance fires the same tick; the advisory lock and the
+		// unique scheduled_at pick one of them
+		conn, run, err := s.begin(ctx, job, entity.JobRun{Trigger: entity.JobTriggerSchedule, ScheduledAt: &next})
+		if err != nil {
+			if !errors.Is(err, dto.ErrJobRunning) && !errors.Is(err, errJobTickTaken) {
+				slog.Error("failed to start job", "job", job.Name, "error", err)
+			}
+			continue
+		}
+		s.finish(job, conn, run)
+	}
+}
+
+// begin takes the job's advisory lock and records the run. The lock lives on
+// the returned connection until finish releases it.
+func (s *schedulerService) begin(ctx context.Context, job scheduledJob, run entity.JobRun) (*sql.Conn, entity.JobRun, error) {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return nil, entity.JobRun{}, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, entity.JobRun{}, err
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", jobLockKey(job.Name)).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, entity.JobRun{}, err
+	}
+	if !locked {
+		conn.Close()
+		return nil, entity.JobRun{}, dto.ErrJobRunning
+	}
+
+	// holding the lock means no other run of the job is alive
+	if err := s.jobRunRepo.FailRunning(ctx, nil, job.Name, "interrupted before finishing"); err != nil {
+		s.unlock(job, conn)
+		return nil, entity.JobRun{}, err
+	}
+
+	run.Job = job.Name
+	run.Status = entity.JobRunRunning
+	run.Instance = s.instance
+	run.StartedAt = time.Now()
+	run, created, err := s.jobRunRepo.Create(ctx, nil, run)
+	if err == nil && !created {
+		err = errJobTickTaken
+	}
+	if err != nil {
+		s.unlock(job, conn)
+		return nil, entity.JobRun{}, err
+	}
+
+	return conn, run, nil
+}
+
+// finish runs the job, records the outcome and releases the lock
+func (s *schedulerService) finish(job scheduledJob, conn *sql.Conn, run entity.JobRun) {
+	defer s.unlock(job, conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), job.Timeout)
+	defer cancel()
+
+	result, err := runJob(ctx, job.Job)
+	finishedAt := time.Now()
+	run.Result = result
+	run.FinishedAt = &finishedAt
+	run.Status = entity.JobRunSucceeded
+	if err != nil {
+		message := err.Error()
+		run.Status = entity.JobRunFailed
+		run.Error = &message
+		slog.Error("job failed", "job", job.Name, "error", err)
+	}
+
+	if err := s.jobRunRepo.Finish(context.Background(), nil, run); err != nil {
+		slog.Error("failed to record job run", "job", job.Name, "run_id", run.ID, "error", err)
+	}
+}
+
+// runJob turns a panic in the job into a failed run
+func runJob(ctx context.Context, job Job) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+func (s *schedulerService) unlock(job scheduledJob, conn *sql.Conn) {
+	if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", jobLockKey(job.Name)); err != nil {
+		slog.Error("failed to release job lock", "job", job.Name, "error", err)
+	}
+	conn.Close()
+}
+
+// jobLockKey maps a job name to its advisory lock
+func jobLockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("job:" + name))
+	return int64(h.Sum64())
+}
+
+func (s *schedulerService) GetJobs(ctx context.Context) ([]dto.JobResponse, error) {
+	lastRuns, err := s.jobRunRepo.GetLastRuns(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	lastRunByJob := make(map[string]entity.JobRun, len(lastRuns))
+	for _, run := range lastRuns {
+		lastRunByJob[run.Job] = run
+	}
+
+	jobs := make([]dto.JobResponse, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		res := dto.JobResponse{
+			Name:        job.Name,
+			Description: job.Description,
+			Schedule:    job.Schedule,
+		}
+		if s.started {
+			next := job.schedule.Next(time.Now())
+			if !next.IsZero() {
+				res.NextRunAt = &next
+			}
+		}
+		if run, ok := lastRunByJob[job.Name]; ok {
+			lastRun := toJobRunResponse(run)
+			res.LastRun = &lastRun
+		}
+		jobs = append(jobs, res)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+
+	return jobs, nil
+}
+
+func (s *schedulerService) GetRuns(ctx context.Context, name string) ([]dto.JobRunResponse, error) {
+	if _, ok := s.jobs[name]; !ok {
+		return nil, dto.ErrJobNotFound
+	}
+
+	runs, err := s.jobRunRepo.GetByJob(ctx, nil, name, jobRunHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.JobRunResponse, len(runs))
+	for i, run := range runs {
+		res[i] = toJobRunResponse(run)
+	}
+	return res, nil
+}
+
+func (s *schedulerService) Trigger(ctx context.Context, name string, userId string) (dto.JobRunResponse, error) {
+	job, ok := s.jobs[name]
+	if !ok {
+		return dto.JobRunResponse{}, dto.ErrJobNotFound
+	}
+
+	userUUID, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.JobRunResponse{}, err
+	}
+
+	conn, run, err := s.begin(ctx, job, entity.JobRun{Trigger: entity.JobTriggerManual, TriggeredBy: &userUUID})
+	if err != nil {
+		return dto.JobRunResponse{}, err
+	}
+	go s.finish(job, conn, run)
+
+	return toJobRunResponse(run), nil
+}
+
+func toJobRunResponse(run entity.JobRun) dto.JobRunResponse {
+	res := dto.JobRunResponse{
+		ID:          run.ID.String(),
+		Job:         run.Job,
+		Trigger:     run.Trigger,
+		ScheduledAt: run.ScheduledAt,
+		Status:      run.Status,
+		Result:      run.Result,
+		Instance:    run.Instance,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+	}
+	if run.TriggeredBy != nil {
+		res.TriggeredBy = run.TriggeredBy.String()
+	}
+	if run.Error != nil {
+		res.Error = *run.Error
+	}
+	if run.FinishedAt != nil {
+		duration := run.FinishedAt.Sub(run.StartedAt).Milliseconds()
+		res.DurationMs = &duration
+	}
+	return res
+}
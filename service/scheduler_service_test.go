@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewSchedulerService(t *testing.T) {
+	noop := func(ctx context.Context) (string, error) { return "", nil }
+
+	tests := []struct {
+		name         string
+		jobs         []Job
+		env          map[string]string
+		wantErr      bool
+		wantSchedule string
+		wantTimeout  time.Duration
+	}{
+		{
+			name:         "defaults",
+			jobs:         []Job{{Name: "purge", Schedule: "@daily", Run: noop}},
+			wantSchedule: "@daily",
+			wantTimeout:  defaultJobTimeout,
+		},
+		{
+			name:         "schedule overridden from the environment",
+			jobs:         []Job{{Name: "purge", Schedule: "@daily", Timeout: time.Minute, Run: noop}},
+			env:          map[string]string{"JOB_PURGE_SCHEDULE": "*/5 * * * *"},
+			wantSchedule: "*/5 * * * *",
+			wantTimeout:  time.Minute,
+		},
+		{
+			name:    "invalid schedule",
+			jobs:    []Job{{Name: "purge", Schedule: "every day", Run: noop}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid override",
+			jobs:    []Job{{Name: "purge", Schedule: "@daily", Run: noop}},
+			env:     map[string]string{"JOB_PURGE_SCHEDULE": "@every 1s"},
+			wantErr: true,
+		},
+		{
+			name:    "registered twice",
+			jobs:    []Job{{Name: "purge", Schedule: "@daily", Run: noop}, {Name: "purge", Schedule: "@hourly", Run: noop}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JOB_PURGE_SCHEDULE", tt.env["JOB_PURGE_SCHEDULE"])
+
+			service, err := NewSchedulerService(tt.jobs, nil, nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			job := service.(*schedulerService).jobs["purge"]
+			assert.Equal(t, tt.wantSchedule, job.Schedule)
+			assert.Equal(t, tt.wantTimeout, job.Timeout)
+		})
+	}
+}
+
+func Test_RunJob(t *testing.T) {
+	jobErr := errors.New("database is down")
+
+	tests := []struct {
+		name       string
+		run        func(ctx context.Context) (string, error)
+		wantResult string
+		wantErr    string
+	}{
+		{"success", func(ctx context.Context) (string, error) { return "purged 3 tokens", nil }, "purged 3 tokens", ""},
+		{"error", func(ctx context.Context) (string, error) { return "", jobErr }, "", jobErr.Error()},
+		{"panic", func(ctx context.Context) (string, error) { panic("nil map") }, "", "panic: nil map"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runJob(context.Background(), Job{Name: "test", Run: tt.run})
+			assert.Equal(t, tt.wantResult, result)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_JobLockKey(t *testing.T) {
+	names := []string{"purge_refresh_tokens", "reject_stale_bookings", "prune_job_runs"}
+
+	keys := make(map[int64]string, len(names))
+	for _, name := range names {
+		key := jobLockKey(name)
+		assert.Equal(t, key, jobLockKey(name), name)
+		if other, ok := keys[key]; ok {
+			t.Fatalf("%s and %s share a lock key", other, name)
+		}
+		keys[key] = name
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+)
+
+type (
+	SessionService interface {
+		Refresh(ctx context.Context, req dto.RefreshTokenRequest, client dto.ClientInfo) (dto.TokenResponse, error)
+		Logout(ctx context.Context, sessionId string) error
+		GetSessions(ctx context.Context, userId string, currentSessionId string) ([]dto.SessionResponse, error)
+		RevokeSession(ctx context.Context, sessionId string, userId string) error
+		RevokeAllSessions(ctx context.Context, userId string) error
+	}
+
+	sessionService struct {
+		refreshTokenRepo repository.RefreshTokenRepository
+		userRepo         repository.UserRepository
+		organizationRepo repository.OrganizationRepository
+		jwtService       JWTService
+		db               *gorm.DB
+	}
+)
+
+func NewSessionService(
+	refreshTokenRepo repository.RefreshTokenRepository,
+	userRepo repository.UserRepository,
+	organizationRepo repository.OrganizationRepository,
+	jwtService JWTService,
+	db *gorm.DB,
+) SessionService {
+	return &sessionService{
+		refreshTokenRepo: refreshTokenRepo,
+		userRepo:         userRepo,
+		organizationRepo: organizationRepo,
+		jwtService:       jwtService,
+		db:               db,
+	}
+}
+
+// Refresh trades a refresh token for a new token pair. The refresh token is
+// rotated, so a stolen one stops working once its owner refreshes. The
+// session keeps acting for its organization while the user is still a member.
+func (s *sessionService) Refresh(ctx context.Context, req dto.RefreshTokenRequest, client dto.ClientInfo) (dto.TokenResponse, error) {
+	session, err := s.refreshTokenRepo.FindByTokenHash(ctx, nil, hashToken(req.RefreshToken))
+	if err != nil {
+		return dto.TokenResponse{}, dto.ErrRefreshTokenInvalid
+	}
+
+	now := time.Now()
+	if session.ExpiresAt.Before(now) {
+		_ = s.refreshTokenRepo.DeleteByID(ctx, nil, session.ID.String())
+		return dto.TokenResponse{}, dto.ErrRefreshTokenExpired
+	}
+	if session.User.DisabledAt != nil {
+		return dto.TokenResponse{}, dto.ErrAccountDisabled
+	}
+
+	var organization *ActiveOrganization
+	if session.OrganizationID != nil {
+		member, err := s.organizationRepo.GetMember(ctx, nil, *session.OrganizationID, session.UserID)
+		switch {
+		case err == nil:
+			organization = &ActiveOrganization{ID: member.OrganizationID.String(), Role: member.Role}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			session.OrganizationID = nil
+		default:
+			return dto.TokenResponse{}, err
+		}
+	}
+
+	refreshToken, expiresAt := s.jwtService.GenerateRefreshToken()
+	if refreshToken == "" {
+		return dto.TokenResponse{}, dto.ErrCreateSession
+	}
+	session.TokenHash = hashToken(refreshToken)
+	session.ExpiresAt = expiresAt
+	session.LastUsedAt = now
+	session.IP = client.IP
+	if err := s.refreshTokenRepo.Rotate(ctx, nil, session); err != nil {
+		return dto.TokenResponse{}, err
+	}
+
+	response := issueAccessToken(s.jwtService, session.User, organization, session.ID.String())
+	response.RefreshToken = refreshToken
+	return response, nil
+}
+
+func (s *sessionService) Logout(ctx context.Context, sessionId string) error {
+	return s.refreshTokenRepo.DeleteByID(ctx, nil, sessionId)
+}
+
+func (s *sessionService) GetSessions(ctx context.Context, userId string, currentSessionId string) ([]dto.SessionResponse, error) {
+	if _, err := s.userRepo.GetUserById(ctx, nil, userId); err != nil {
+		return nil, dto.ErrUserNotFound
+	}
+
+	sessions, err := s.refreshTokenRepo.GetActiveByUserID(ctx, nil, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, dto.SessionResponse{
+			ID:         session.ID.String(),
+			Device:     session.Device,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID.String() == currentSessionId,
+		})
+	}
+	return responses, nil
+}
+
+// RevokeSession ends one of the user's own sessions
+func (s *sessionService) RevokeSession(ctx context.Context, sessionId string, userId string) error {
+	if _, err := uuid.Parse(sessionId); err != nil {
+		return dto.ErrSessionNotFound
+	}
+
+	session, err := s.refreshTokenRepo.GetByID(ctx, nil, sessionId)
+	if err != nil || session.UserID.String() != userId {
+		return dto.ErrSessionNotFound
+	}
+
+	return s.refreshTokenRepo.DeleteByID(ctx, nil, sessionId)
+}
+
+// RevokeAllSessions logs the user out everywhere
+func (s *sessionService) RevokeAllSessions(ctx context.Context, userId string) error {
+	if _, err := s.userRepo.GetUserById(ctx, nil, userId); err != nil {
+		return dto.ErrUserNotFound
+	}
+
+	return s.refreshTokenRepo.DeleteByUserID(ctx, nil, userId)
+}
+
+// startSession records a login session and returns its token pair
+func startSession(
+	ctx context.Context,
+	tx *gorm.DB,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	jwtService JWTService,
+	user entity.User,
+	organization *ActiveOrganization,
+	client dto.ClientInfo,
+) (dto.TokenResponse, error) {
+	refreshToken, expiresAt := jwtService.GenerateRefreshToken()
+	if refreshToken == "" {
+		return dto.TokenResponse{}, dto.ErrCreateSession
+	}
+
+	device := client.UserAgent
+	if len(device) > 255 {
+		device = device[:255]
+	}
+	session := entity.RefreshToken{
+		UserID:     user.ID,
+		TokenHash:  hashToken(refreshToken),
+		ExpiresAt:  expiresAt,
+		Device:     device,
+		IP:         client.IP,
+		LastUsedAt: time.Now(),
+	}
+	if organization != nil {
+		organizationId, err := uuid.Parse(organization.ID)
+		if err != nil {
+			return dto.TokenResponse{}, dto.ErrOrganizationNotFound
+		}
+		session.OrganizationID = &organizationId
+	}
+
+	session, err := refreshTokenRepo.Create(ctx, tx, session)
+	if err != nil {
+		return dto.TokenResponse{}, dto.ErrCreateSession
+	}
+
+	response := issueAccessToken(jwtService, user, organization, session.ID.String())
+	response.RefreshToken = refreshToken
+	return response, nil
+}
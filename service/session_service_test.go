@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+// only the rejections are covered, a refresh that goes through needs the
+// database to rotate the token
+func Test_SessionService_Refresh(t *testing.T) {
+	disabledAt := time.Now().Add(-time.Hour)
+	sessions := &fakeSessionRepository{sessions: []entity.RefreshToken{
+		{
+			ID:        uuid.New(),
+			TokenHash: hashToken("disabled"),
+			ExpiresAt: time.Now().Add(time.Hour),
+			User:      entity.User{DisabledAt: &disabledAt},
+		},
+	}}
+	s := &sessionService{refreshTokenRepo: sessions}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"unknown token", "unknown", dto.ErrRefreshTokenInvalid},
+		{"disabled user", "disabled", dto.ErrAccountDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Refresh(context.Background(), dto.RefreshTokenRequest{RefreshToken: tt.token}, dto.ClientInfo{})
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
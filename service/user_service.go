@@ -2,14 +2,25 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"github.com/miraicantsleep/myits-event-be/dto"
 	"github.com/miraicantsleep/myits-event-be/entity"
 	"github.com/miraicantsleep/myits-event-be/helpers"
 	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/miraicantsleep/myits-event-be/utils"
 )
 
 type (
@@ -20,31 +31,83 @@ type (
 		GetUserByEmail(ctx context.Context, email string) (dto.UserResponse, error)
 		Update(ctx context.Context, req dto.UserUpdateRequest, userId string) (dto.UserUpdateResponse, error)
 		Delete(ctx context.Context, userId string) error
-		Verify(ctx context.Context, req dto.UserLoginRequest) (dto.TokenResponse, error)
+		Verify(ctx context.Context, req dto.UserLoginRequest, client dto.ClientInfo) (dto.TokenResponse, error)
+		SendVerificationEmail(ctx context.Context, req dto.SendVerificationEmailRequest) error
+		VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) (dto.VerifyEmailResponse, error)
+		ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error
+		ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error
+
+		// Admin account management
+		CreateAccount(ctx context.Context, req dto.AdminUserCreateRequest, adminId string) (dto.UserResponse, error)
+		ChangeRole(ctx context.Context, userId string, req dto.UserRoleUpdateRequest, adminId string) (dto.UserResponse, error)
+		DisableUser(ctx context.Context, userId string, adminId string) (dto.UserResponse, error)
+		EnableUser(ctx context.Context, userId string, adminId string) (dto.UserResponse, error)
+		GetRoleChanges(ctx context.Context, userId string) ([]dto.RoleChangeResponse, error)
+		UnlockLogin(ctx context.Context, userId string) error
+		UnlockIP(ctx context.Context, ip string) error
 	}
 
 	userService struct {
-		userRepo   repository.UserRepository
-		jwtService JWTService
-		db         *gorm.DB
+		userRepo         repository.UserRepository
+		userTokenRepo    repository.UserTokenRepository
+		refreshTokenRepo repository.RefreshTokenRepository
+		departmentRepo   repository.DepartmentRepository
+		roleChangeRepo   repository.RoleChangeRepository
+		organizationRepo repository.OrganizationRepository
+		throttleRepo     repository.LoginThrottleRepository
+		jwtService       JWTService
+		db               *gorm.DB
 	}
 )
 
 func NewUserService(
 	userRepo repository.UserRepository,
+	userTokenRepo repository.UserTokenRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	departmentRepo repository.DepartmentRepository,
+	roleChangeRepo repository.RoleChangeRepository,
+	organizationRepo repository.OrganizationRepository,
+	throttleRepo repository.LoginThrottleRepository,
 	jwtService JWTService,
 	db *gorm.DB,
 ) UserService {
 	return &userService{
-		userRepo:   userRepo,
-		jwtService: jwtService,
-		db:         db,
+		userRepo:         userRepo,
+		userTokenRepo:    userTokenRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		departmentRepo:   departmentRepo,
+		roleChangeRepo:   roleChangeRepo,
+		organizationRepo: organizationRepo,
+		throttleRepo:     throttleRepo,
+		jwtService:       jwtService,
+		db:               db,
 	}
 }
 
 const (
-	LOCAL_URL          = "http://localhost:3000"
-	VERIFY_EMAIL_ROUTE = "register/verify_email"
+	LOCAL_URL            = "http://localhost:3000"
+	VERIFY_EMAIL_ROUTE   = "register/verify_email"
+	RESET_PASSWORD_ROUTE = "reset_password"
+
+	verifyEmailTokenTTL   = 24 * time.Hour
+	resetPasswordTokenTTL = time.Hour
+
+	// Failed logins are tracked per email address and per client IP. After
+	// loginDelayAfter failures every further attempt has to wait, doubling up
+	// to loginMaxDelay; reaching the lock threshold locks the key for
+	// loginLockDuration. IPs get a higher threshold since campus networks
+	// put many users behind one address.
+	loginDelayAfter     = 3
+	loginMaxDelay       = 30 * time.Second
+	loginEmailLockAfter = 10
+	loginIPLockAfter    = 100
+	loginLockDuration   = 15 * time.Minute
+	loginFailureWindow  = time.Hour
+
+	// loginDummyHash is compared against when the email is unknown, so such a
+	// login takes as long as a wrong password. It uses the cost of
+	// helpers.HashPassword.
+	loginDummyHash = "$2a$04$OrtQNlVh4OrYzH.SNI2V.ONH2ABvFZIbNa2tbNpPkZNr/dsleF67O"
 )
 
 func SafeRollback(tx *gorm.DB) {
@@ -68,21 +131,27 @@ func (s *userService) Register(ctx context.Context, req dto.UserCreateRequest) (
 
 	user := entity.User{
 		Name:     req.Name,
-		Role:     entity.UserRole(req.Role), // This is **absolutely not recommended** in a real life application
+		Role:     entity.RoleUser, // other roles are provisioned by an admin through CreateAccount
 		Email:    req.Email,
 		Password: req.Password,
 	}
 
 	userReg, err := s.userRepo.Register(ctx, nil, user)
 	if err != nil {
-		return dto.UserResponse{}, errors.New("asd")
+		return dto.UserResponse{}, dto.ErrCreateUser
+	}
+
+	// the account exists either way; the user can ask for a new link later
+	if err := s.sendVerificationEmail(ctx, userReg); err != nil {
+		slog.ErrorContext(ctx, "failed to send verification email", "email", userReg.Email, "error", err)
 	}
 
 	return dto.UserResponse{
-		ID:    userReg.ID.String(),
-		Name:  userReg.Name,
-		Role:  string(userReg.Role),
-		Email: userReg.Email,
+		ID:         userReg.ID.String(),
+		Name:       userReg.Name,
+		Role:       string(userReg.Role),
+		Email:      userReg.Email,
+		IsVerified: userReg.IsVerified,
 	}, nil
 }
 
@@ -99,10 +168,12 @@ func (s *userService) GetAllUserWithPagination(
 	var datas []dto.UserResponse
 	for _, user := range dataWithPaginate.Users {
 		data := dto.UserResponse{
-			ID:    user.ID.String(),
-			Name:  user.Name,
-			Email: user.Email,
-			Role:  string(user.Role),
+			ID:         user.ID.String(),
+			Name:       user.Name,
+			Email:      user.Email,
+			Role:       string(user.Role),
+			IsVerified: user.IsVerified,
+			IsDisabled: user.DisabledAt != nil,
 		}
 
 		datas = append(datas, data)
@@ -126,10 +197,12 @@ func (s *userService) GetUserById(ctx context.Context, userId string) (dto.UserR
 	}
 
 	return dto.UserResponse{
-		ID:    user.ID.String(),
-		Name:  user.Name,
-		Role:  string(user.Role),
-		Email: user.Email,
+		ID:         user.ID.String(),
+		Name:       user.Name,
+		Role:       string(user.Role),
+		Email:      user.Email,
+		IsVerified: user.IsVerified,
+		IsDisabled: user.DisabledAt != nil,
 	}, nil
 }
 
@@ -140,10 +213,11 @@ func (s *userService) GetUserByEmail(ctx context.Context, email string) (dto.Use
 	}
 
 	return dto.UserResponse{
-		ID:    emails.ID.String(),
-		Name:  emails.Name,
-		Role:  string(emails.Role),
-		Email: emails.Email,
+		ID:         emails.ID.String(),
+		Name:       emails.Name,
+		Role:       string(emails.Role),
+		Email:      emails.Email,
+		IsVerified: emails.IsVerified,
 	}, nil
 }
 
@@ -193,26 +267,593 @@ func (s *userService) Delete(ctx context.Context, userId string) error {
 	return nil
 }
 
-func (s *userService) Verify(ctx context.Context, req dto.UserLoginRequest) (dto.TokenResponse, error) {
+func (s *userService) Verify(ctx context.Context, req dto.UserLoginRequest, client dto.ClientInfo) (dto.TokenResponse, error) {
+	now := time.Now()
+	emailKey := entity.LoginThrottleKeyEmail + strings.ToLower(req.Email)
+	ipKey := entity.LoginThrottleKeyIP + client.IP
+	if err := s.checkLoginThrottle(ctx, now, emailKey, ipKey); err != nil {
+		return dto.TokenResponse{}, err
+	}
+
 	tx := s.db.Begin()
 	defer SafeRollback(tx)
 
 	user, err := s.userRepo.GetUserByEmail(ctx, tx, req.Email)
 	if err != nil {
 		tx.Rollback()
+		helpers.CheckPassword(loginDummyHash, []byte(req.Password))
+		s.recordLoginFailure(ctx, now, emailKey, ipKey)
 		return dto.TokenResponse{}, errors.New("invalid email or password")
 	}
 
 	checkPassword, err := helpers.CheckPassword(user.Password, []byte(req.Password))
 	if err != nil || !checkPassword {
 		tx.Rollback()
+		s.recordLoginFailure(ctx, now, emailKey, ipKey)
 		return dto.TokenResponse{}, errors.New("invalid email or password")
 	}
 
-	accessToken := s.jwtService.GenerateAccessToken(user.ID.String(), string(user.Role))
+	if user.DisabledAt != nil {
+		tx.Rollback()
+		return dto.TokenResponse{}, dto.ErrAccountDisabled
+	}
+
+	if !user.IsVerified {
+		tx.Rollback()
+		return dto.TokenResponse{}, dto.ErrAccountNotVerified
+	}
+
+	organization, err := defaultOrganization(ctx, tx, s.organizationRepo, user)
+	if err != nil {
+		tx.Rollback()
+		return dto.TokenResponse{}, err
+	}
+
+	response, err := startSession(ctx, tx, s.refreshTokenRepo, s.jwtService, user, organization, client)
+	if err != nil {
+		tx.Rollback()
+		return dto.TokenResponse{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return dto.TokenResponse{}, err
+	}
 
-	return dto.TokenResponse{
-		AccessToken: accessToken,
-		Role:        string(user.Role),
+	// only a login that went through clears earlier failures for this address,
+	// a disabled or unverified account keeps its throttle
+	if err := s.throttleRepo.Reset(ctx, nil, emailKey); err != nil {
+		slog.ErrorContext(ctx, "failed to reset login throttle", "key", emailKey, "error", err)
+	}
+	return response, nil
+}
+
+// SendVerificationEmail mails a new verification link. Like ForgotPassword it
+// succeeds for unknown and already verified emails without sending anything,
+// so the endpoint cannot be used to find out who has an account.
+func (s *userService) SendVerificationEmail(ctx context.Context, req dto.SendVerificationEmailRequest) error {
+	user, err := s.userRepo.GetUserByEmail(ctx, nil, req.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.IsVerified {
+		return nil
+	}
+
+	if err := s.sendVerificationEmail(ctx, user); err != nil {
+		slog.ErrorContext(ctx, "failed to send verification email", "email", user.Email, "error", err)
+		return dto.ErrSendEmail
+	}
+	return nil
+}
+
+func (s *userService) VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) (dto.VerifyEmailResponse, error) {
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	token, err := s.consumeToken(ctx, tx, req.Token, entity.UserTokenPurposeVerifyEmail)
+	if err != nil {
+		tx.Rollback()
+		return dto.VerifyEmailResponse{}, err
+	}
+
+	user, err := s.userRepo.GetUserById(ctx, tx, token.UserID.String())
+	if err != nil {
+		tx.Rollback()
+		return dto.VerifyEmailResponse{}, dto.ErrUserNotFound
+	}
+
+	if user.IsVerified {
+		tx.Rollback()
+		return dto.VerifyEmailResponse{}, dto.ErrAccountAlreadyVerified
+	}
+
+	if err := s.userRepo.MarkVerified(ctx, tx, user.ID.String()); err != nil {
+		tx.Rollback()
+		return dto.VerifyEmailResponse{}, dto.ErrUpdateUser
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return dto.VerifyEmailResponse{}, err
+	}
+
+	return dto.VerifyEmailResponse{
+		Email:      user.Email,
+		IsVerified: true,
 	}, nil
 }
+
+// ForgotPassword mails a reset link. Unknown emails are not reported so the
+// endpoint cannot be used to find out who has an account.
+func (s *userService) ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error {
+	user, err := s.userRepo.GetUserByEmail(ctx, nil, req.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.issueToken(ctx, user, entity.UserTokenPurposeResetPassword, resetPasswordTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	body, err := utils.RenderMailTemplate("reset_password_mail.html", map[string]string{
+		"Email":     user.Email,
+		"Reset":     frontendURL(RESET_PASSWORD_ROUTE, token),
+		"ExpiresIn": "1 hour",
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := utils.SendMail(user.Email, "Reset Your Password", body); err != nil {
+		slog.ErrorContext(ctx, "failed to send password reset email", "email", user.Email, "error", err)
+		return dto.ErrSendEmail
+	}
+	return nil
+}
+
+// ResetPassword sets a new password and signs the user out everywhere by
+// removing their refresh tokens.
+func (s *userService) ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error {
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	token, err := s.consumeToken(ctx, tx, req.Token, entity.UserTokenPurposeResetPassword)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	hashedPassword, err := helpers.HashPassword(req.Password)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, tx, token.UserID.String(), hashedPassword); err != nil {
+		tx.Rollback()
+		return dto.ErrUpdateUser
+	}
+
+	// the reset link proves the user owns the email address
+	if err := s.userRepo.MarkVerified(ctx, tx, token.UserID.String()); err != nil {
+		tx.Rollback()
+		return dto.ErrUpdateUser
+	}
+
+	if err := s.refreshTokenRepo.DeleteByUserID(ctx, tx, token.UserID.String()); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// CreateAccount provisions a departemen or ormawa account. The admin vouches
+// for the address, so the account is verified straight away. Ormawa accounts
+// get an organization they chair; more members can be added to it later.
+func (s *userService) CreateAccount(ctx context.Context, req dto.AdminUserCreateRequest, adminId string) (dto.UserResponse, error) {
+	switch entity.UserRole(req.Role) {
+	case entity.RoleDepartemen, entity.RoleOrmawa:
+	default:
+		return dto.UserResponse{}, dto.ErrInvalidRole
+	}
+	if req.Role == string(entity.RoleDepartemen) && req.Faculty == "" {
+		return dto.UserResponse{}, dto.ErrFacultyRequired
+	}
+
+	_, flag, err := s.userRepo.CheckEmail(ctx, nil, req.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.UserResponse{}, err
+	}
+	if flag {
+		return dto.UserResponse{}, dto.ErrEmailAlreadyExists
+	}
+
+	adminUUID, err := uuid.Parse(adminId)
+	if err != nil {
+		return dto.UserResponse{}, dto.ErrUserNotFound
+	}
+
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	user, err := s.userRepo.Register(ctx, tx, entity.User{
+		Name:       req.Name,
+		Email:      req.Email,
+		Password:   req.Password,
+		Role:       entity.UserRole(req.Role),
+		IsVerified: true,
+	})
+	if err != nil {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrCreateUser
+	}
+
+	if user.Role == entity.RoleDepartemen {
+		if _, err := s.departmentRepo.Create(ctx, tx, entity.Department{
+			Name:    req.Name,
+			Faculty: req.Faculty,
+			UserID:  user.ID,
+		}); err != nil {
+			tx.Rollback()
+			return dto.UserResponse{}, dto.ErrCreateDepartment
+		}
+	}
+
+	// an ormawa account becomes the chair of a new organization of the same name
+	if user.Role == entity.RoleOrmawa {
+		organization, err := s.organizationRepo.Create(ctx, tx, entity.Organization{Name: req.Name})
+		if err != nil {
+			tx.Rollback()
+			return dto.UserResponse{}, dto.ErrOrganizationNameExists
+		}
+		if _, err := s.organizationRepo.AddMember(ctx, tx, entity.OrganizationMember{
+			OrganizationID: organization.ID,
+			UserID:         user.ID,
+			Role:           entity.OrganizationRoleChair,
+		}); err != nil {
+			tx.Rollback()
+			return dto.UserResponse{}, err
+		}
+	}
+
+	if _, err := s.roleChangeRepo.Create(ctx, tx, entity.RoleChange{
+		UserID:    user.ID,
+		ChangedBy: adminUUID,
+		NewRole:   req.Role,
+		Reason:    req.Reason,
+	}); err != nil {
+		tx.Rollback()
+		return dto.UserResponse{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return dto.UserResponse{}, err
+	}
+	return toUserResponse(user), nil
+}
+
+// ChangeRole moves an account between user, ormawa and admin and records the
+// change. Departemen accounts own a department and are left alone.
+func (s *userService) ChangeRole(ctx context.Context, userId string, req dto.UserRoleUpdateRequest, adminId string) (dto.UserResponse, error) {
+	if userId == adminId {
+		return dto.UserResponse{}, dto.ErrChangeOwnAccount
+	}
+
+	switch entity.UserRole(req.Role) {
+	case entity.RoleUser, entity.RoleOrmawa, entity.RoleAdmin:
+	default:
+		return dto.UserResponse{}, dto.ErrInvalidRole
+	}
+
+	adminUUID, err := uuid.Parse(adminId)
+	if err != nil {
+		return dto.UserResponse{}, dto.ErrUserNotFound
+	}
+
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	user, err := s.userRepo.GetUserById(ctx, tx, userId)
+	if err != nil {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrUserNotFound
+	}
+
+	if string(user.Role) == req.Role {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrRoleUnchanged
+	}
+	if user.Role == entity.RoleDepartemen {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrDepartmentRoleChange
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, tx, userId, entity.UserRole(req.Role)); err != nil {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrUpdateUser
+	}
+
+	if _, err := s.roleChangeRepo.Create(ctx, tx, entity.RoleChange{
+		UserID:    user.ID,
+		ChangedBy: adminUUID,
+		OldRole:   string(user.Role),
+		NewRole:   req.Role,
+		Reason:    req.Reason,
+	}); err != nil {
+		tx.Rollback()
+		return dto.UserResponse{}, err
+	}
+
+	// the old role is baked into refresh sessions, so sign the user out
+	if err := s.refreshTokenRepo.DeleteByUserID(ctx, tx, userId); err != nil {
+		tx.Rollback()
+		return dto.UserResponse{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return dto.UserResponse{}, err
+	}
+
+	user.Role = entity.UserRole(req.Role)
+	return toUserResponse(user), nil
+}
+
+func (s *userService) DisableUser(ctx context.Context, userId string, adminId string) (dto.UserResponse, error) {
+	now := time.Now()
+	return s.setDisabledAt(ctx, userId, adminId, &now)
+}
+
+func (s *userService) EnableUser(ctx context.Context, userId string, adminId string) (dto.UserResponse, error) {
+	return s.setDisabledAt(ctx, userId, adminId, nil)
+}
+
+func (s *userService) GetRoleChanges(ctx context.Context, userId string) ([]dto.RoleChangeResponse, error) {
+	if _, err := s.userRepo.GetUserById(ctx, nil, userId); err != nil {
+		return nil, dto.ErrUserNotFound
+	}
+
+	return s.roleChangeRepo.GetRoleChangesByUserID(ctx, nil, userId)
+}
+
+// UnlockLogin lifts a lockout or delay on the user's email address
+func (s *userService) UnlockLogin(ctx context.Context, userId string) error {
+	user, err := s.userRepo.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return dto.ErrUserNotFound
+	}
+
+	return s.throttleRepo.Reset(ctx, nil, entity.LoginThrottleKeyEmail+strings.ToLower(user.Email))
+}
+
+// UnlockIP lifts a lockout or delay on a client IP
+func (s *userService) UnlockIP(ctx context.Context, ip string) error {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return dto.ErrInvalidIP
+	}
+
+	return s.throttleRepo.Reset(ctx, nil, entity.LoginThrottleKeyIP+parsed.String())
+}
+
+// checkLoginThrottle rejects a login attempt while any of keys is locked or
+// still waiting out its delay. A lockout outranks a delay, otherwise the
+// longest wait is reported.
+func (s *userService) checkLoginThrottle(ctx context.Context, now time.Time, keys ...string) error {
+	throttles, err := s.throttleRepo.GetByKeys(ctx, nil, keys...)
+	if err != nil {
+		return err
+	}
+
+	var throttleErr *dto.LoginThrottleError
+	for _, throttle := range throttles {
+		var current dto.LoginThrottleError
+		if throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+			current = dto.LoginThrottleError{Locked: true, RetryAfter: throttle.LockedUntil.Sub(now)}
+		} else if wait := throttle.LastFailureAt.Add(loginDelay(throttle.Failures)).Sub(now); wait > 0 {
+			current = dto.LoginThrottleError{RetryAfter: wait}
+		} else {
+			continue
+		}
+
+		if throttleErr == nil ||
+			(current.Locked && !throttleErr.Locked) ||
+			(current.Locked == throttleErr.Locked && current.RetryAfter > throttleErr.RetryAfter) {
+			throttleErr = &current
+		}
+	}
+
+	if throttleErr != nil {
+		return throttleErr
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt against both the email address
+// and the client IP and locks whichever reached its threshold. Errors are only
+// logged so they never reveal more than "invalid email or password".
+func (s *userService) recordLoginFailure(ctx context.Context, now time.Time, emailKey string, ipKey string) {
+	limits := map[string]int{emailKey: loginEmailLockAfter, ipKey: loginIPLockAfter}
+	for key, lockAfter := range limits {
+		throttle, err := s.throttleRepo.RecordFailure(ctx, nil, key, now, loginFailureWindow)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to record login failure", "key", key, "error", err)
+			continue
+		}
+		if throttle.Failures < lockAfter {
+			continue
+		}
+		if err := s.throttleRepo.Lock(ctx, nil, key, now.Add(loginLockDuration)); err != nil {
+			slog.ErrorContext(ctx, "failed to lock login", "key", key, "error", err)
+		}
+	}
+}
+
+// loginDelay is how long a key has to wait after its latest failure
+func loginDelay(failures int) time.Duration {
+	if failures < loginDelayAfter {
+		return 0
+	}
+	shift := failures - loginDelayAfter
+	if shift > 5 {
+		return loginMaxDelay
+	}
+	return min(time.Second<<shift, loginMaxDelay)
+}
+
+// setDisabledAt blocks or unblocks logins. Disabling also removes the user's
+// refresh tokens so existing sessions cannot be renewed.
+func (s *userService) setDisabledAt(ctx context.Context, userId string, adminId string, disabledAt *time.Time) (dto.UserResponse, error) {
+	if userId == adminId {
+		return dto.UserResponse{}, dto.ErrChangeOwnAccount
+	}
+
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	user, err := s.userRepo.GetUserById(ctx, tx, userId)
+	if err != nil {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrUserNotFound
+	}
+
+	// refusing a repeat keeps the original disabled_at and does not sign the
+	// user out again
+	if disabledAt != nil && user.DisabledAt != nil {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrAccountAlreadyDisabled
+	}
+	if disabledAt == nil && user.DisabledAt == nil {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrAccountNotDisabled
+	}
+
+	if err := s.userRepo.SetDisabledAt(ctx, tx, userId, disabledAt); err != nil {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrUpdateUser
+	}
+
+	if disabledAt != nil {
+		if err := s.refreshTokenRepo.DeleteByUserID(ctx, tx, userId); err != nil {
+			tx.Rollback()
+			return dto.UserResponse{}, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return dto.UserResponse{}, err
+	}
+
+	user.DisabledAt = disabledAt
+	return toUserResponse(user), nil
+}
+
+func toUserResponse(user entity.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:         user.ID.String(),
+		Name:       user.Name,
+		Email:      user.Email,
+		Role:       string(user.Role),
+		IsVerified: user.IsVerified,
+		IsDisabled: user.DisabledAt != nil,
+	}
+}
+
+func (s *userService) sendVerificationEmail(ctx context.Context, user entity.User) error {
+	token, err := s.issueToken(ctx, user, entity.UserTokenPurposeVerifyEmail, verifyEmailTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	body, err := utils.RenderMailTemplate("base_mail.html", map[string]string{
+		"Email":  user.Email,
+		"Verify": frontendURL(VERIFY_EMAIL_ROUTE, token),
+	})
+	if err != nil {
+		return err
+	}
+
+	return utils.SendMail(user.Email, "Verify Your Account", body)
+}
+
+// issueToken creates a random token for the user and stores its hash. Older
+// tokens of the same purpose stop working.
+func (s *userService) issueToken(ctx context.Context, user entity.User, purpose string, ttl time.Duration) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	tx := s.db.Begin()
+	defer SafeRollback(tx)
+
+	if err := s.userTokenRepo.InvalidateByUser(ctx, tx, user.ID, purpose); err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	if _, err := s.userTokenRepo.Create(ctx, tx, entity.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// consumeToken looks up a mailed token and marks it used within tx.
+func (s *userService) consumeToken(ctx context.Context, tx *gorm.DB, token string, purpose string) (entity.UserToken, error) {
+	userToken, err := s.userTokenRepo.FindByHash(ctx, tx, hashToken(token), purpose)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.UserToken{}, dto.ErrTokenInvalid
+		}
+		return entity.UserToken{}, err
+	}
+
+	if userToken.UsedAt != nil {
+		return entity.UserToken{}, dto.ErrTokenInvalid
+	}
+	if time.Now().After(userToken.ExpiresAt) {
+		return entity.UserToken{}, dto.ErrTokenExpired
+	}
+
+	used, err := s.userTokenRepo.MarkUsed(ctx, tx, userToken.ID)
+	if err != nil {
+		return entity.UserToken{}, err
+	}
+	if !used {
+		return entity.UserToken{}, dto.ErrTokenInvalid
+	}
+	return userToken, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// frontendURL builds a link to a page of the frontend, which reads the token
+// from the query string and calls the matching API endpoint.
+func frontendURL(route string, token string) string {
+	baseURL := os.Getenv("FRONTEND_URL")
+	if baseURL == "" {
+		baseURL = LOCAL_URL
+	}
+	return baseURL + "/" + route + "?token=" + url.QueryEscape(token)
+}
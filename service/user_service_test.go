@@ -0,0 +1,503 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/helpers"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func Test_HashToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{"empty", "", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"token", "abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, hashToken(tt.token))
+		})
+	}
+}
+
+func Test_FrontendURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		frontendURL string
+		route       string
+		token       string
+		want        string
+	}{
+		{"default base url", "", VERIFY_EMAIL_ROUTE, "abc", LOCAL_URL + "/register/verify_email?token=abc"},
+		{"configured base url", "https://event.its.ac.id", RESET_PASSWORD_ROUTE, "abc", "https://event.its.ac.id/reset_password?token=abc"},
+		{"token is query escaped", "https://event.its.ac.id", RESET_PASSWORD_ROUTE, "a+b/c=&d", "https://event.its.ac.id/reset_password?token=a%2Bb%2Fc%3D%26d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FRONTEND_URL", tt.frontendURL)
+			assert.Equal(t, tt.want, frontendURL(tt.route, tt.token))
+		})
+	}
+}
+
+// fakeLoginThrottleRepository keeps throttles in memory so the throttle rules
+// can be tested without a database
+type fakeLoginThrottleRepository struct {
+	throttles map[string]entity.LoginThrottle
+}
+
+func (r *fakeLoginThrottleRepository) GetByKeys(ctx context.Context, tx *gorm.DB, keys ...string) ([]entity.LoginThrottle, error) {
+	var throttles []entity.LoginThrottle
+	for _, key := range keys {
+		if throttle, ok := r.throttles[key]; ok {
+			throttles = append(throttles, throttle)
+		}
+	}
+	return throttles, nil
+}
+
+func (r *fakeLoginThrottleRepository) RecordFailure(ctx context.Context, tx *gorm.DB, key string, now time.Time, window time.Duration) (entity.LoginThrottle, error) {
+	throttle := r.throttles[key]
+	if throttle.Key == "" || now.Sub(throttle.LastFailureAt) > window {
+		throttle = entity.LoginThrottle{Key: key}
+	}
+	throttle.Failures++
+	throttle.LastFailureAt = now
+	r.throttles[key] = throttle
+	return throttle, nil
+}
+
+func (r *fakeLoginThrottleRepository) Lock(ctx context.Context, tx *gorm.DB, key string, until time.Time) error {
+	throttle := r.throttles[key]
+	throttle.LockedUntil = &until
+	r.throttles[key] = throttle
+	return nil
+}
+
+func (r *fakeLoginThrottleRepository) Reset(ctx context.Context, tx *gorm.DB, key string) error {
+	delete(r.throttles, key)
+	return nil
+}
+
+// fakeUserRepository keeps users in memory and records the role and status
+// changes made to them
+type fakeUserRepository struct {
+	repository.UserRepository
+	users    []entity.User
+	roles    map[string]entity.UserRole
+	disabled map[string]*time.Time
+}
+
+func (r *fakeUserRepository) Register(ctx context.Context, tx *gorm.DB, user entity.User) (entity.User, error) {
+	user.ID = uuid.New()
+	r.users = append(r.users, user)
+	return user, nil
+}
+
+func (r *fakeUserRepository) GetUserById(ctx context.Context, tx *gorm.DB, userId string) (entity.User, error) {
+	for _, user := range r.users {
+		if user.ID.String() == userId {
+			return user, nil
+		}
+	}
+	return entity.User{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) CheckEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, bool, error) {
+	user, err := r.GetUserByEmail(ctx, tx, email)
+	return user, err == nil, err
+}
+
+func (r *fakeUserRepository) UpdateRole(ctx context.Context, tx *gorm.DB, userId string, role entity.UserRole) error {
+	r.roles[userId] = role
+	return nil
+}
+
+func (r *fakeUserRepository) SetDisabledAt(ctx context.Context, tx *gorm.DB, userId string, disabledAt *time.Time) error {
+	r.disabled[userId] = disabledAt
+	return nil
+}
+
+func (r *fakeUserRepository) GetUserByEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return entity.User{}, gorm.ErrRecordNotFound
+}
+
+func Test_UserService_SendVerificationEmail(t *testing.T) {
+	repo := &fakeUserRepository{users: []entity.User{{Email: "verified@its.ac.id", IsVerified: true}}}
+	// no token repository, so a mail being sent would panic
+	s := &userService{userRepo: repo}
+
+	tests := []struct {
+		name  string
+		email string
+	}{
+		{"unknown email", "nobody@its.ac.id"},
+		{"already verified", "verified@its.ac.id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, s.SendVerificationEmail(context.Background(), dto.SendVerificationEmailRequest{Email: tt.email}))
+		})
+	}
+}
+
+func Test_LoginDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{loginDelayAfter - 1, 0},
+		{loginDelayAfter, time.Second},
+		{loginDelayAfter + 1, 2 * time.Second},
+		{loginDelayAfter + 4, 16 * time.Second},
+		{loginDelayAfter + 5, loginMaxDelay},
+		{loginDelayAfter + 100, loginMaxDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.failures), func(t *testing.T) {
+			assert.Equal(t, tt.want, loginDelay(tt.failures))
+		})
+	}
+}
+
+func Test_CheckLoginThrottle(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	lockedUntil := now.Add(10 * time.Minute)
+	expiredLock := now.Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		throttles []entity.LoginThrottle
+		want      *dto.LoginThrottleError
+	}{
+		{"no failures", nil, nil},
+		{
+			name:      "below the delay threshold",
+			throttles: []entity.LoginThrottle{{Key: "email", Failures: loginDelayAfter - 1, LastFailureAt: now}},
+		},
+		{
+			name:      "delay still running",
+			throttles: []entity.LoginThrottle{{Key: "email", Failures: loginDelayAfter + 1, LastFailureAt: now.Add(-500 * time.Millisecond)}},
+			want:      &dto.LoginThrottleError{RetryAfter: 1500 * time.Millisecond},
+		},
+		{
+			name:      "delay passed",
+			throttles: []entity.LoginThrottle{{Key: "email", Failures: loginDelayAfter + 1, LastFailureAt: now.Add(-time.Minute)}},
+		},
+		{
+			name:      "locked",
+			throttles: []entity.LoginThrottle{{Key: "email", Failures: loginEmailLockAfter, LastFailureAt: now, LockedUntil: &lockedUntil}},
+			want:      &dto.LoginThrottleError{Locked: true, RetryAfter: 10 * time.Minute},
+		},
+		{
+			name:      "lock expired",
+			throttles: []entity.LoginThrottle{{Key: "email", Failures: 1, LastFailureAt: now.Add(-time.Hour), LockedUntil: &expiredLock}},
+		},
+		{
+			name: "lock wins over a longer delay",
+			throttles: []entity.LoginThrottle{
+				{Key: "email", Failures: loginDelayAfter + 10, LastFailureAt: now},
+				{Key: "ip", Failures: loginIPLockAfter, LastFailureAt: now, LockedUntil: &lockedUntil},
+			},
+			want: &dto.LoginThrottleError{Locked: true, RetryAfter: 10 * time.Minute},
+		},
+		{
+			name: "longest delay wins",
+			throttles: []entity.LoginThrottle{
+				{Key: "email", Failures: loginDelayAfter, LastFailureAt: now},
+				{Key: "ip", Failures: loginDelayAfter + 2, LastFailureAt: now},
+			},
+			want: &dto.LoginThrottleError{RetryAfter: 4 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeLoginThrottleRepository{throttles: map[string]entity.LoginThrottle{}}
+			for _, throttle := range tt.throttles {
+				repo.throttles[throttle.Key] = throttle
+			}
+			s := &userService{throttleRepo: repo}
+
+			err := s.checkLoginThrottle(context.Background(), now, "email", "ip")
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var throttleErr *dto.LoginThrottleError
+			assert.ErrorAs(t, err, &throttleErr)
+			assert.Equal(t, tt.want, throttleErr)
+		})
+	}
+}
+
+func Test_RecordLoginFailure(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		attempts   int
+		wantLocked map[string]bool
+	}{
+		{"below both thresholds", loginEmailLockAfter - 1, map[string]bool{"email": false, "ip": false}},
+		{"email threshold", loginEmailLockAfter, map[string]bool{"email": true, "ip": false}},
+		{"ip threshold", loginIPLockAfter, map[string]bool{"email": true, "ip": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeLoginThrottleRepository{throttles: map[string]entity.LoginThrottle{}}
+			s := &userService{throttleRepo: repo}
+
+			for range tt.attempts {
+				s.recordLoginFailure(context.Background(), now, "email", "ip")
+			}
+
+			for key, locked := range tt.wantLocked {
+				throttle := repo.throttles[key]
+				assert.Equal(t, tt.attempts, throttle.Failures, key)
+				if locked {
+					assert.Equal(t, now.Add(loginLockDuration), *throttle.LockedUntil, key)
+				} else {
+					assert.Nil(t, throttle.LockedUntil, key)
+				}
+			}
+		})
+	}
+}
+
+// the dummy hash has to be a real bcrypt hash, a malformed one fails fast and
+// gives the timing difference back
+func Test_LoginDummyHash(t *testing.T) {
+	ok, err := helpers.CheckPassword(loginDummyHash, []byte("password123"))
+	assert.False(t, ok)
+	assert.ErrorIs(t, err, bcrypt.ErrMismatchedHashAndPassword)
+}
+
+type fakeRoleChangeRepository struct {
+	repository.RoleChangeRepository
+	changes []entity.RoleChange
+}
+
+func (r *fakeRoleChangeRepository) Create(ctx context.Context, tx *gorm.DB, change entity.RoleChange) (entity.RoleChange, error) {
+	r.changes = append(r.changes, change)
+	return change, nil
+}
+
+type fakeDepartmentRepository struct {
+	repository.DepartmentRepository
+	departments []entity.Department
+}
+
+func (r *fakeDepartmentRepository) Create(ctx context.Context, tx *gorm.DB, department entity.Department) (entity.Department, error) {
+	r.departments = append(r.departments, department)
+	return department, nil
+}
+
+type fakeOrganizationRepository struct {
+	repository.OrganizationRepository
+	organizations []entity.Organization
+	members       []entity.OrganizationMember
+}
+
+func (r *fakeOrganizationRepository) Create(ctx context.Context, tx *gorm.DB, organization entity.Organization) (entity.Organization, error) {
+	organization.ID = uuid.New()
+	r.organizations = append(r.organizations, organization)
+	return organization, nil
+}
+
+func (r *fakeOrganizationRepository) AddMember(ctx context.Context, tx *gorm.DB, member entity.OrganizationMember) (entity.OrganizationMember, error) {
+	r.members = append(r.members, member)
+	return member, nil
+}
+
+func Test_UserService_CreateAccount(t *testing.T) {
+	adminId := uuid.New().String()
+
+	tests := []struct {
+		name    string
+		req     dto.AdminUserCreateRequest
+		adminId string
+		wantErr error
+	}{
+		{"ormawa", dto.AdminUserCreateRequest{Name: "BEM", Email: "bem@its.ac.id", Role: "ormawa"}, adminId, nil},
+		{"departemen", dto.AdminUserCreateRequest{Name: "Informatika", Email: "if@its.ac.id", Role: "departemen", Faculty: "FTEIC"}, adminId, nil},
+		{"departemen without faculty", dto.AdminUserCreateRequest{Name: "Informatika", Email: "if@its.ac.id", Role: "departemen"}, adminId, dto.ErrFacultyRequired},
+		{"admin cannot be provisioned", dto.AdminUserCreateRequest{Name: "Root", Email: "root@its.ac.id", Role: "admin"}, adminId, dto.ErrInvalidRole},
+		{"unknown role", dto.AdminUserCreateRequest{Name: "Root", Email: "root@its.ac.id", Role: "superuser"}, adminId, dto.ErrInvalidRole},
+		{"duplicate email", dto.AdminUserCreateRequest{Name: "BEM", Email: "taken@its.ac.id", Role: "ormawa"}, adminId, dto.ErrEmailAlreadyExists},
+		{"admin id is not a uuid", dto.AdminUserCreateRequest{Name: "BEM", Email: "bem@its.ac.id", Role: "ormawa"}, "admin", dto.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, pool := newDryRunDB(t)
+			users := &fakeUserRepository{users: []entity.User{{ID: uuid.New(), Email: "taken@its.ac.id"}}}
+			roleChanges := &fakeRoleChangeRepository{}
+			departments := &fakeDepartmentRepository{}
+			organizations := &fakeOrganizationRepository{}
+			s := &userService{userRepo: users, roleChangeRepo: roleChanges, departmentRepo: departments, organizationRepo: organizations, db: db}
+
+			got, err := s.CreateAccount(context.Background(), tt.req, tt.adminId)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Len(t, users.users, 1)
+				assert.Empty(t, roleChanges.changes)
+				assert.Zero(t, pool.committed)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.req.Role, got.Role)
+			// provisioned accounts skip email verification
+			assert.True(t, got.IsVerified)
+			assert.Equal(t, 1, pool.committed)
+			if assert.Len(t, roleChanges.changes, 1) {
+				assert.Equal(t, "", roleChanges.changes[0].OldRole)
+				assert.Equal(t, tt.req.Role, roleChanges.changes[0].NewRole)
+				assert.Equal(t, tt.adminId, roleChanges.changes[0].ChangedBy.String())
+			}
+
+			switch tt.req.Role {
+			case "departemen":
+				assert.Len(t, departments.departments, 1)
+				assert.Empty(t, organizations.organizations)
+			case "ormawa":
+				assert.Empty(t, departments.departments)
+				assert.Len(t, organizations.organizations, 1)
+				if assert.Len(t, organizations.members, 1) {
+					assert.Equal(t, entity.OrganizationRoleChair, organizations.members[0].Role)
+				}
+			}
+		})
+	}
+}
+
+func Test_UserService_ChangeRole(t *testing.T) {
+	adminId := uuid.New().String()
+	member := entity.User{ID: uuid.New(), Role: entity.RoleUser}
+	department := entity.User{ID: uuid.New(), Role: entity.RoleDepartemen}
+
+	tests := []struct {
+		name    string
+		userId  string
+		role    string
+		wantErr error
+	}{
+		{"promote to ormawa", member.ID.String(), "ormawa", nil},
+		{"promote to admin", member.ID.String(), "admin", nil},
+		{"departemen cannot be given", member.ID.String(), "departemen", dto.ErrInvalidRole},
+		{"unknown role", member.ID.String(), "superuser", dto.ErrInvalidRole},
+		{"same role", member.ID.String(), "user", dto.ErrRoleUnchanged},
+		{"departemen account", department.ID.String(), "user", dto.ErrDepartmentRoleChange},
+		{"unknown user", uuid.New().String(), "user", dto.ErrUserNotFound},
+		{"own account", adminId, "user", dto.ErrChangeOwnAccount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, pool := newDryRunDB(t)
+			users := &fakeUserRepository{users: []entity.User{member, department}, roles: map[string]entity.UserRole{}}
+			roleChanges := &fakeRoleChangeRepository{}
+			sessions := &fakeSessionRepository{}
+			s := &userService{userRepo: users, roleChangeRepo: roleChanges, refreshTokenRepo: sessions, db: db}
+
+			got, err := s.ChangeRole(context.Background(), tt.userId, dto.UserRoleUpdateRequest{Role: tt.role}, adminId)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, users.roles)
+				assert.Empty(t, roleChanges.changes)
+				assert.Empty(t, sessions.deletedFor)
+				assert.Zero(t, pool.committed)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.role, got.Role)
+			assert.Equal(t, entity.UserRole(tt.role), users.roles[tt.userId])
+			if assert.Len(t, roleChanges.changes, 1) {
+				assert.Equal(t, "user", roleChanges.changes[0].OldRole)
+				assert.Equal(t, tt.role, roleChanges.changes[0].NewRole)
+			}
+			// sessions carrying the old role are signed out
+			assert.Equal(t, []string{tt.userId}, sessions.deletedFor)
+			assert.Equal(t, 1, pool.committed)
+		})
+	}
+}
+
+func Test_UserService_SetDisabledAt(t *testing.T) {
+	adminId := uuid.New().String()
+	disabledAt := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	active := entity.User{ID: uuid.New(), Role: entity.RoleUser}
+	disabled := entity.User{ID: uuid.New(), Role: entity.RoleUser, DisabledAt: &disabledAt}
+
+	tests := []struct {
+		name        string
+		userId      string
+		disable     bool
+		wantErr     error
+		wantSignOut bool
+	}{
+		{"disable", active.ID.String(), true, nil, true},
+		{"disable again", disabled.ID.String(), true, dto.ErrAccountAlreadyDisabled, false},
+		{"enable", disabled.ID.String(), false, nil, false},
+		{"enable an active account", active.ID.String(), false, dto.ErrAccountNotDisabled, false},
+		{"unknown user", uuid.New().String(), true, dto.ErrUserNotFound, false},
+		{"own account", adminId, true, dto.ErrChangeOwnAccount, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, pool := newDryRunDB(t)
+			users := &fakeUserRepository{users: []entity.User{active, disabled}, disabled: map[string]*time.Time{}}
+			sessions := &fakeSessionRepository{}
+			s := &userService{userRepo: users, refreshTokenRepo: sessions, db: db}
+
+			var got dto.UserResponse
+			var err error
+			if tt.disable {
+				got, err = s.DisableUser(context.Background(), tt.userId, adminId)
+			} else {
+				got, err = s.EnableUser(context.Background(), tt.userId, adminId)
+			}
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				// the original disabled_at is kept
+				assert.Empty(t, users.disabled)
+				assert.Empty(t, sessions.deletedFor)
+				assert.Zero(t, pool.committed)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.disable, got.IsDisabled)
+			assert.Equal(t, tt.disable, users.disabled[tt.userId] != nil)
+			if tt.wantSignOut {
+				assert.Equal(t, []string{tt.userId}, sessions.deletedFor)
+			} else {
+				assert.Empty(t, sessions.deletedFor)
+			}
+			assert.Equal(t, 1, pool.committed)
+		})
+	}
+}
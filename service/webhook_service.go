@@ -0,0 +1,552 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	webhookPollInterval = 2 * time.Second
+	webhookBatchSize    = 20
+	webhookTimeout      = 10 * time.Second
+	// webhookLease hides a claimed delivery from other instances while it is
+	// sent, so it must be well above webhookTimeout. Deliveries are claimed
+	// one at a time, so the lease covers a single send.
+	webhookLease         = time.Minute
+	webhookMaxAttempts   = 8
+	webhookMaxBackoff    = 6 * time.Hour
+	webhookResponseLimit = 4096
+)
+
+type (
+	WebhookService interface {
+		Create(ctx context.Context, req dto.WebhookCreateRequest, userId string) (dto.WebhookCreateResponse, error)
+		GetAll(ctx context.Context) ([]dto.WebhookResponse, error)
+		GetByID(ctx context.Context, webhookId string) (dto.WebhookResponse, error)
+		Update(ctx context.Context, webhookId string, req dto.WebhookUpdateRequest) (dto.WebhookResponse, error)
+		Delete(ctx context.Context, webhookId string) error
+		GetDeliveries(ctx context.Context, webhookId string, req dto.PaginationRequest) (dto.WebhookDeliveryPaginationResponse, error)
+		// Redeliver queues a copy of a past delivery, whatever its outcome
+		Redeliver(ctx context.Context, webhookId string, deliveryId string) (dto.WebhookDeliveryResponse, error)
+		// Ping queues a ping delivery regardless of the subscribed event types
+		Ping(ctx context.Context, webhookId string) (dto.WebhookDeliveryResponse, error)
+		// Enqueue queues a delivery for every active webhook subscribed to the
+		// event type. data is only called when there is at least one.
+		Enqueue(ctx context.Context, eventType string, data func() (any, error)) error
+		// Start sends due deliveries until ctx is done
+		Start(ctx context.Context)
+	}
+
+	webhookService struct {
+		webhookRepo repository.WebhookRepository
+		client      *http.Client
+		db          *gorm.DB
+	}
+)
+
+// NewWebhookService sends deliveries with client, or with a client limited
+// to webhookTimeout when it is nil
+func NewWebhookService(webhookRepo repository.WebhookRepository, client *http.Client, db *gorm.DB) WebhookService {
+	if client == nil {
+		client = &http.Client{Timeout: webhookTimeout}
+	}
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		client:      client,
+		db:          db,
+	}
+}
+
+// WebhookSubscribers queue webhook deliveries for domain events. Booking and
+// attendance payloads use the same shapes as the API responses.
+func WebhookSubscribers(webhookService WebhookService, bookingRequestService BookingRequestService, invitationRepo repository.InvitationRepository) []Subscriber {
+	bookingPayload := func(ctx context.Context, bookingRequestId uuid.UUID) func() (any, error) {
+		return func() (any, error) {
+			return bookingRequestService.GetBookingRequestByID(ctx, bookingRequestId.String())
+		}
+	}
+
+	return []Subscriber{
+		Subscribe("deliver_webhooks", func(ctx context.Context, event dto.InvitationCreated) error {
+			return webhookService.Enqueue(ctx, event.Topic(), func() (any, error) { return event, nil })
+		}),
+		Subscribe("deliver_webhooks", func(ctx context.Context, event dto.RSVPChanged) error {
+			return webhookService.Enqueue(ctx, event.Topic(), func() (any, error) { return event, nil })
+		}),
+		Subscribe("deliver_webhooks", func(ctx context.Context, event dto.AttendanceMarked) error {
+			return webhookService.Enqueue(ctx, event.Topic(), func() (any, error) {
+				details, err := invitationRepo.GetInvitationByID(ctx, nil, event.InvitationID)
+				if err != nil {
+					return nil, err
+				}
+				for _, detail := range details {
+					if detail.UserID == event.UserID {
+						return dto.ScanQRCodeResponse{
+							UserID:     detail.UserID.String(),
+							UserName:   detail.UserName,
+							EventName:  detail.EventName,
+							AttendedAt: event.AttendedAt.Format(time.RFC3339),
+							Message:    "Attendance marked successfully",
+						}, nil
+					}
+				}
+				return nil, dto.ErrInvitationNotFound
+			})
+		}),
+		Subscribe("deliver_webhooks", func(ctx context.Context, event dto.BookingApproved) error {
+			return webhookService.Enqueue(ctx, event.Topic(), bookingPayload(ctx, event.BookingRequestID))
+		}),
+		Subscribe("deliver_webhooks", func(ctx context.Context, event dto.BookingRejected) error {
+			return webhookService.Enqueue(ctx, event.Topic(), bookingPayload(ctx, event.BookingRequestID))
+		}),
+		Subscribe("deliver_webhooks", func(ctx context.Context, event dto.EventUpdated) error {
+			return webhookService.Enqueue(ctx, event.Topic(), func() (any, error) { return event, nil })
+		}),
+		Subscribe("deliver_webhooks", func(ctx context.Context, event dto.EventCancelled) error {
+			return webhookService.Enqueue(ctx, event.Topic(), func() (any, error) { return event, nil })
+		}),
+	}
+}
+
+func (s *webhookService) Create(ctx context.Context, req dto.WebhookCreateRequest, userId string) (dto.WebhookCreateResponse, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return dto.WebhookCreateResponse{}, err
+	}
+	eventTypes, err := normalizeWebhookEventTypes(req.EventTypes)
+	if err != nil {
+		return dto.WebhookCreateResponse{}, err
+	}
+
+	userUUID, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.WebhookCreateResponse{}, err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return dto.WebhookCreateResponse{}, err
+	}
+
+	webhook, err := s.webhookRepo.Create(ctx, nil, entity.Webhook{
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      entity.WebhookSecretPrefix + base64.RawURLEncoding.EncodeToString(b),
+		EventTypes:  eventTypes,
+		Active:      true,
+		CreatedBy:   userUUID,
+	})
+	if err != nil {
+		return dto.WebhookCreateResponse{}, err
+	}
+
+	return dto.WebhookCreateResponse{
+		WebhookResponse: toWebhookResponse(webhook),
+		Secret:          webhook.Secret,
+	}, nil
+}
+
+func (s *webhookService) GetAll(ctx context.Context) ([]dto.WebhookResponse, error) {
+	webhooks, err := s.webhookRepo.GetAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.WebhookResponse, len(webhooks))
+	for i, webhook := range webhooks {
+		res[i] = toWebhookResponse(webhook)
+	}
+	return res, nil
+}
+
+func (s *webhookService) GetByID(ctx context.Context, webhookId string) (dto.WebhookResponse, error) {
+	webhook, err := s.getWebhook(ctx, webhookId)
+	if err != nil {
+		return dto.WebhookResponse{}, err
+	}
+	return toWebhookResponse(webhook), nil
+}
+
+func (s *webhookService) Update(ctx context.Context, webhookId string, req dto.WebhookUpdateRequest) (dto.WebhookResponse, error) {
+	webhook, err := s.getWebhook(ctx, webhookId)
+	if err != nil {
+		return dto.WebhookResponse{}, err
+	}
+
+	if req.URL != "" {
+		if err := validateWebhookURL(req.URL); err != nil {
+			return dto.WebhookResponse{}, err
+		}
+		webhook.URL = req.URL
+	}
+	if req.Description != nil {
+		webhook.Description = *req.Description
+	}
+	if req.EventTypes != nil {
+		if webhook.EventTypes, err = normalizeWebhookEventTypes(req.EventTypes); err != nil {
+			return dto.WebhookResponse{}, err
+		}
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	webhook, err = s.webhookRepo.Update(ctx, nil, webhook)
+	if err != nil {
+		return dto.WebhookResponse{}, err
+	}
+	return toWebhookResponse(webhook), nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, webhookId string) error {
+	if _, err := s.getWebhook(ctx, webhookId); err != nil {
+		return err
+	}
+	return s.webhookRepo.Delete(ctx, nil, webhookId)
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, webhookId string, req dto.PaginationRequest) (dto.WebhookDeliveryPaginationResponse, error) {
+	if _, err := s.getWebhook(ctx, webhookId); err != nil {
+		return dto.WebhookDeliveryPaginationResponse{}, err
+	}
+
+	deliveries, pagination, err := s.webhookRepo.GetDeliveries(ctx, nil, webhookId, req)
+	if err != nil {
+		return dto.WebhookDeliveryPaginationResponse{}, err
+	}
+
+	data := make([]dto.WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		data[i] = toWebhookDeliveryResponse(delivery)
+	}
+	return dto.WebhookDeliveryPaginationResponse{Data: data, PaginationResponse: pagination}, nil
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, webhookId string, deliveryId string) (dto.WebhookDeliveryResponse, error) {
+	if _, err := s.getWebhook(ctx, webhookId); err != nil {
+		return dto.WebhookDeliveryResponse{}, err
+	}
+	if _, err := uuid.Parse(deliveryId); err != nil {
+		return dto.WebhookDeliveryResponse{}, dto.ErrWebhookDeliveryNotFound
+	}
+
+	original, err := s.webhookRepo.GetDelivery(ctx, nil, webhookId, deliveryId)
+	if err != nil {
+		return dto.WebhookDeliveryResponse{}, dto.ErrWebhookDeliveryNotFound
+	}
+
+	// the payload is copied as is, its id lets the receiver spot the repeat
+	now := time.Now()
+	delivery := entity.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     original.WebhookID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        entity.WebhookDeliveryPending,
+		NextAttemptAt: now,
+		RedeliveryOf:  &original.ID,
+		CreatedAt:     now,
+	}
+	if err := s.webhookRepo.CreateDeliveries(ctx, nil, []entity.WebhookDelivery{delivery}); err != nil {
+		return dto.WebhookDeliveryResponse{}, err
+	}
+	return toWebhookDeliveryResponse(delivery), nil
+}
+
+func (s *webhookService) Ping(ctx context.Context, webhookId string) (dto.WebhookDeliveryResponse, error) {
+	webhook, err := s.getWebhook(ctx, webhookId)
+	if err != nil {
+		return dto.WebhookDeliveryResponse{}, err
+	}
+
+	delivery, err := newWebhookDelivery(webhook, dto.WebhookEventPing, map[string]string{"webhook_id": webhook.ID.String()})
+	if err != nil {
+		return dto.WebhookDeliveryResponse{}, err
+	}
+	if err := s.webhookRepo.CreateDeliveries(ctx, nil, []entity.WebhookDelivery{delivery}); err != nil {
+		return dto.WebhookDeliveryResponse{}, err
+	}
+	return toWebhookDeliveryResponse(delivery), nil
+}
+
+func (s *webhookService) Enqueue(ctx context.Context, eventType string, data func() (any, error)) error {
+	webhooks, err := s.webhookRepo.GetActiveForEventType(ctx, nil, eventType)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	payload, err := data()
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]entity.WebhookDelivery, len(webhooks))
+	for i, webhook := range webhooks {
+		if deliveries[i], err = newWebhookDelivery(webhook, eventType, payload); err != nil {
+			return err
+		}
+	}
+	return s.webhookRepo.CreateDeliveries(ctx, nil, deliveries)
+}
+
+func (s *webhookService) getWebhook(ctx context.Context, webhookId string) (entity.Webhook, error) {
+	if _, err := uuid.Parse(webhookId); err != nil {
+		return entity.Webhook{}, dto.ErrWebhookNotFound
+	}
+
+	webhook, err := s.webhookRepo.GetByID(ctx, nil, webhookId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Webhook{}, dto.ErrWebhookNotFound
+		}
+		return entity.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func newWebhookDelivery(webhook entity.Webhook, eventType string, data any) (entity.WebhookDelivery, error) {
+	now := time.Now()
+	id := uuid.New()
+	payload, err := json.Marshal(dto.WebhookPayload{ID: id.String(), Type: eventType, CreatedAt: now, Data: data})
+	if err != nil {
+		return entity.WebhookDelivery{}, err
+	}
+
+	return entity.WebhookDelivery{
+		ID:            id,
+		WebhookID:     webhook.ID,
+		EventType:     eventType,
+		Payload:       string(payload),
+		Status:        entity.WebhookDeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+func (s *webhookService) Start(ctx context.Context) {
+	go s.loop(ctx)
+	slog.Info("webhook sender started")
+}
+
+func (s *webhookService) loop(ctx context.Context) {
+	for {
+		sent, err := s.sendDue(ctx)
+		if err != nil {
+			slog.Error("webhook sender failed", "error", err)
+		}
+		// a full batch means more may be waiting
+		if err == nil && sent == webhookBatchSize {
+			continue
+		}
+
+		timer := time.NewTimer(webhookPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// sendDue sends up to a batch of due deliveries. Each is claimed right before
+// it is sent, so its lease only has to outlast one send and never runs out
+// while earlier deliveries of the batch are waiting on slow receivers.
+func (s *webhookService) sendDue(ctx context.Context) (int, error) {
+	for sent := 0; sent < webhookBatchSize; sent++ {
+		if ctx.Err() != nil {
+			return sent, nil
+		}
+
+		now := time.Now()
+		deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, nil, now, now.Add(webhookLease), 1)
+		if err != nil {
+			return sent, err
+		}
+		if len(deliveries) == 0 {
+			return sent, nil
+		}
+		s.attempt(ctx, deliveries[0])
+	}
+	return webhookBatchSize, nil
+}
+
+// attempt sends a claimed delivery once and records the outcome. Deliveries
+// of deleted or deactivated webhooks fail without being sent.
+func (s *webhookService) attempt(ctx context.Context, delivery entity.WebhookDelivery) {
+	delivery.Attempts++
+
+	// giving up is only worth it when sending can never succeed
+	giveUp := false
+	webhook, err := s.webhookRepo.GetByID(ctx, nil, delivery.WebhookID.String())
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		err, giveUp = dto.ErrWebhookNotFound, true
+	case err == nil && !webhook.Active:
+		err, giveUp = errors.New("webhook is inactive"), true
+	case err == nil:
+		err = s.send(ctx, webhook, &delivery)
+	}
+
+	now := time.Now()
+	delivery.Error = nil
+	if err == nil {
+		delivery.Status = entity.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+	} else {
+		message := err.Error()
+		delivery.Error = &message
+		delivery.Status = entity.WebhookDeliveryPending
+		delivery.NextAttemptAt = now.Add(webhookBackoff(delivery.Attempts))
+		if giveUp || delivery.Attempts >= webhookMaxAttempts {
+			delivery.Status = entity.WebhookDeliveryFailed
+		}
+	}
+
+	if err := s.webhookRepo.FinishAttempt(context.Background(), nil, delivery); err != nil {
+		slog.Error("failed to record webhook attempt", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// send POSTs the payload and fills in the response on the delivery. Any
+// status outside 2xx is an error.
+func (s *webhookService) send(ctx context.Context, webhook entity.Webhook, delivery *entity.WebhookDelivery) error {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "myits-event-webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID.String())
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	duration := time.Since(start).Milliseconds()
+	delivery.DurationMs = &duration
+	if err != nil {
+		delivery.ResponseStatus = nil
+		delivery.ResponseBody = ""
+		return err
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	delivery.ResponseStatus = &resp.StatusCode
+	delivery.ResponseBody = strings.ToValidUTF8(string(responseBody), "")
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhookPayload returns the X-Webhook-Signature header value: the hex
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret. The
+// timestamp is signed too so a captured request cannot be replayed later.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff waits 30 seconds after the first failure and doubles from
+// there, up to six hours
+func webhookBackoff(attempts int) time.Duration {
+	if attempts > 16 {
+		return webhookMaxBackoff
+	}
+	return min(30*time.Second<<uint(attempts-1), webhookMaxBackoff)
+}
+
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return dto.ErrWebhookURL
+	}
+	return nil
+}
+
+// normalizeWebhookEventTypes checks the event types and joins them for storage
+func normalizeWebhookEventTypes(eventTypes []string) (string, error) {
+	var unique []string
+	for _, eventType := range eventTypes {
+		if !slices.Contains(dto.WebhookEventTypes, eventType) {
+			return "", fmt.Errorf("%w: %s", dto.ErrWebhookEventType, eventType)
+		}
+		if !slices.Contains(unique, eventType) {
+			unique = append(unique, eventType)
+		}
+	}
+	if len(unique) == 0 {
+		return "", dto.ErrWebhookEventType
+	}
+	return strings.Join(unique, ","), nil
+}
+
+func toWebhookResponse(webhook entity.Webhook) dto.WebhookResponse {
+	return dto.WebhookResponse{
+		ID:          webhook.ID.String(),
+		URL:         webhook.URL,
+		Description: webhook.Description,
+		EventTypes:  strings.Split(webhook.EventTypes, ","),
+		Active:      webhook.Active,
+		CreatedBy:   webhook.CreatedBy.String(),
+		CreatedAt:   webhook.CreatedAt,
+		UpdatedAt:   webhook.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryResponse(delivery entity.WebhookDelivery) dto.WebhookDeliveryResponse {
+	res := dto.WebhookDeliveryResponse{
+		ID:             delivery.ID.String(),
+		WebhookID:      delivery.WebhookID.String(),
+		EventType:      delivery.EventType,
+		Payload:        json.RawMessage(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		ResponseBody:   delivery.ResponseBody,
+		DurationMs:     delivery.DurationMs,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+	if delivery.Status == entity.WebhookDeliveryPending {
+		res.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	if delivery.RedeliveryOf != nil {
+		res.RedeliveryOf = delivery.RedeliveryOf.String()
+	}
+	if delivery.Error != nil {
+		res.Error = *delivery.Error
+	}
+	return res
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/miraicantsleep/myits-event-be/dto"
+	"github.com/miraicantsleep/myits-event-be/entity"
+	"github.com/miraicantsleep/myits-event-be/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// fakeWebhookRepository hands out queued deliveries and records, in order,
+// every claim and finished attempt
+type fakeWebhookRepository struct {
+	repository.WebhookRepository
+	queue []entity.WebhookDelivery
+	calls []string
+}
+
+func (r *fakeWebhookRepository) ClaimDueDeliveries(ctx context.Context, tx *gorm.DB, now time.Time, leaseUntil time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	r.calls = append(r.calls, "claim "+strconv.Itoa(limit))
+	if leaseUntil.Sub(now) != webhookLease {
+		return nil, errors.New("unexpected lease")
+	}
+	n := min(limit, len(r.queue))
+	claimed := r.queue[:n]
+	r.queue = r.queue[n:]
+	return claimed, nil
+}
+
+func (r *fakeWebhookRepository) GetByID(ctx context.Context, tx *gorm.DB, webhookId string) (entity.Webhook, error) {
+	return entity.Webhook{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeWebhookRepository) FinishAttempt(ctx context.Context, tx *gorm.DB, delivery entity.WebhookDelivery) error {
+	r.calls = append(r.calls, "finish")
+	return nil
+}
+
+func Test_SignWebhookPayload(t *testing.T) {
+	const (
+		secret    = "whsec_test"
+		timestamp = int64(1792400000)
+		body      = `{"id":"1"}`
+		// printf '1792400000.{"id":"1"}' | openssl dgst -sha256 -hmac whsec_test
+		want = "sha256=53ddb048ab0ba9b7f3bc9f5236c60336f8bfbe2be7b3222072fde5a50476dde8"
+	)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      string
+		wantSame  bool
+	}{
+		{"same input", secret, timestamp, body, true},
+		{"other secret", "whsec_other", timestamp, body, false},
+		{"replayed later", secret, timestamp + 1, body, false},
+		{"tampered body", secret, timestamp, `{"id":"2"}`, false},
+	}
+
+	assert.Equal(t, want, SignWebhookPayload(secret, timestamp, []byte(body)))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SignWebhookPayload(tt.secret, tt.timestamp, []byte(tt.body))
+			assert.Equal(t, tt.wantSame, got == want)
+		})
+	}
+}
+
+func Test_WebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, webhookMaxBackoff},
+		{17, webhookMaxBackoff},
+		{64, webhookMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.attempts), func(t *testing.T) {
+			assert.Equal(t, tt.want, webhookBackoff(tt.attempts))
+		})
+	}
+}
+
+func Test_ValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://example.com/hooks", false},
+		{"http://localhost:9000", false},
+		{"ftp://example.com", true},
+		{"example.com/hooks", true},
+		{"https://", true},
+		{"://broken", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := validateWebhookURL(tt.url)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, dto.ErrWebhookURL)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_NormalizeWebhookEventTypes(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventTypes []string
+		want       string
+		wantErr    bool
+	}{
+		{"one", []string{dto.TopicRSVPChanged}, dto.TopicRSVPChanged, false},
+		{"duplicates", []string{dto.TopicRSVPChanged, dto.TopicEventCancelled, dto.TopicRSVPChanged}, dto.TopicRSVPChanged + "," + dto.TopicEventCancelled, false},
+		{"none", nil, "", true},
+		{"ping cannot be subscribed", []string{dto.WebhookEventPing}, "", true},
+		{"unknown", []string{dto.TopicRSVPChanged, "user.deleted"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeWebhookEventTypes(tt.eventTypes)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, dto.ErrWebhookEventType)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_WebhookService_Send(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantErr    bool
+		wantStatus int
+	}{
+		{"accepted", http.StatusNoContent, false, http.StatusNoContent},
+		{"rejected", http.StatusInternalServerError, true, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const secret = "whsec_test"
+			delivery := entity.WebhookDelivery{ID: uuid.New(), EventType: dto.TopicRSVPChanged, Payload: `{"id":"1"}`}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				timestamp, err := strconv.ParseInt(r.Header.Get("X-Webhook-Timestamp"), 10, 64)
+				assert.NoError(t, err)
+				assert.Equal(t, SignWebhookPayload(secret, timestamp, body), r.Header.Get("X-Webhook-Signature"))
+				assert.Equal(t, delivery.EventType, r.Header.Get("X-Webhook-Event"))
+				assert.Equal(t, delivery.ID.String(), r.Header.Get("X-Webhook-Delivery"))
+				w.WriteHeader(tt.status)
+				w.Write([]byte("ok"))
+			}))
+			defer server.Close()
+
+			s := NewWebhookService(nil, server.Client(), nil).(*webhookService)
+			err := s.send(context.Background(), entity.Webhook{URL: server.URL, Secret: secret}, &delivery)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if assert.NotNil(t, delivery.ResponseStatus) {
+				assert.Equal(t, tt.wantStatus, *delivery.ResponseStatus)
+			}
+			assert.NotNil(t, delivery.DurationMs)
+		})
+	}
+}
+
+func Test_WebhookService_SendDue(t *testing.T) {
+	tests := []struct {
+		name      string
+		due       int
+		wantSent  int
+		wantCalls int
+	}{
+		{"nothing due", 0, 0, 1},
+		{"a few due", 3, 3, 3*2 + 1},
+		{"more than a batch", webhookBatchSize + 5, webhookBatchSize, webhookBatchSize * 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeWebhookRepository{}
+			for range tt.due {
+				repo.queue = append(repo.queue, entity.WebhookDelivery{ID: uuid.New(), WebhookID: uuid.New()})
+			}
+			s := NewWebhookService(repo, nil, nil).(*webhookService)
+
+			sent, err := s.sendDue(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSent, sent)
+			assert.Len(t, repo.calls, tt.wantCalls)
+
+			// a delivery is only claimed once the previous one is finished, so
+			// no lease has to outlast the sends before it
+			for i, call := range repo.calls {
+				if i%2 == 0 {
+					assert.Equal(t, "claim 1", call, "call %d", i)
+				} else {
+					assert.Equal(t, "finish", call, "call %d", i)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronDescriptors are the shorthands accepted in place of five fields
+var cronDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// cronBounds are the allowed values of minute, hour, day of month, month
+// and day of week
+var cronBounds = [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 6}}
+
+var ErrCronSpec = errors.New("invalid cron schedule")
+
+// CronSchedule is a parsed cron expression. It accepts the usual five fields
+// (minute hour day-of-month month day-of-week) with *, lists, ranges and
+// steps, the @hourly, @daily, @weekly and @monthly shorthands, and
+// "@every <duration>".
+type CronSchedule struct {
+	fields [5]uint64
+	// like cron, when both day fields are restricted a day matching either
+	// one is run
+	domAny, dowAny bool
+	every          time.Duration
+}
+
+func ParseCron(spec string) (CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if descriptor, ok := cronDescriptors[spec]; ok {
+		spec = descriptor
+	}
+
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || every < time.Minute {
+			return CronSchedule{}, fmt.Errorf("%w %q: @every needs a duration of at least 1m", ErrCronSpec, spec)
+		}
+		return CronSchedule{every: every}, nil
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != 5 {
+		return CronSchedule{}, fmt.Errorf("%w %q: expected 5 fields", ErrCronSpec, spec)
+	}
+
+	var schedule CronSchedule
+	for i, part := range parts {
+		bits, err := parseCronField(part, cronBounds[i][0], cronBounds[i][1])
+		if err != nil {
+			return CronSchedule{}, fmt.Errorf("%w %q: %v", ErrCronSpec, spec, err)
+		}
+		schedule.fields[i] = bits
+	}
+	schedule.domAny = parts[2] == "*"
+	schedule.dowAny = parts[4] == "*"
+	return schedule, nil
+}
+
+func parseCronField(field string, low, high int) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("bad step in %q", item)
+			}
+			step = n
+		}
+
+		start, end := low, high
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("bad value in %q", item)
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf("bad range in %q", item)
+				}
+			} else if hasStep {
+				end = high
+			}
+		}
+		if start < low || end > high || start > end {
+			return 0, fmt.Errorf("%q is outside %d-%d", item, low, high)
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time after t the schedule fires, or the zero time
+// when it never does. @every schedules are aligned to the Unix epoch, so
+// every instance computes the same times.
+func (s CronSchedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Truncate(s.every).Add(s.every)
+	}
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// a schedule that matches nothing, like 0 0 30 2 *, gives up after five years
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !s.has(3, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.has(1, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.has(0, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s CronSchedule) has(field int, value int) bool {
+	return s.fields[field]&(1<<uint(value)) != 0
+}
+
+func (s CronSchedule) matchesDay(t time.Time) bool {
+	dom, dow := s.has(2, t.Day()), s.has(4, int(t.Weekday()))
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseCron(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{"* * * * *", false},
+		{"*/15 * * * *", false},
+		{"0 8-17/2 * * 1-5", false},
+		{"0 0 1,15 * *", false},
+		{"5/10 * * * *", false},
+		{" @daily ", false},
+		{"@hourly", false},
+		{"@every 90m", false},
+		{"", true},
+		{"* * * *", true},
+		{"* * * * * *", true},
+		{"60 * * * *", true},
+		{"* 24 * * *", true},
+		{"* * 0 * *", true},
+		{"* * * 13 *", true},
+		{"* * * * 7", true},
+		{"5-1 * * * *", true},
+		{"*/0 * * * *", true},
+		{"a * * * *", true},
+		{"1-x * * * *", true},
+		{"@yearly", true},
+		{"@every 30s", true},
+		{"@every soon", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := ParseCron(tt.spec)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrCronSpec)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_CronSchedule_Next(t *testing.T) {
+	// 2026-10-19 is a Monday
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	now := at(time.October, 19, 10, 7).Add(30 * time.Second)
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"every minute", "* * * * *", now, at(time.October, 19, 10, 8)},
+		{"never the same minute again", "7 10 * * *", at(time.October, 19, 10, 7), at(time.October, 20, 10, 7)},
+		{"every quarter hour", "*/15 * * * *", now, at(time.October, 19, 10, 15)},
+		{"hourly", "@hourly", now, at(time.October, 19, 11, 0)},
+		{"daily", "@daily", now, at(time.October, 20, 0, 0)},
+		{"weekly on sunday", "@weekly", now, at(time.October, 25, 0, 0)},
+		{"monthly", "@monthly", now, at(time.November, 1, 0, 0)},
+		{"weekdays at eight", "0 8 * * 1-5", at(time.October, 23, 9, 0), at(time.October, 26, 8, 0)},
+		{"day of month or day of week", "0 0 1 * 3", now, at(time.October, 21, 0, 0)},
+		{"day of month and any weekday", "0 0 1 * *", now, at(time.November, 1, 0, 0)},
+		{"next year", "0 0 1 1 *", now, time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"31st skips short months", "0 0 31 * *", at(time.November, 1, 0, 0), at(time.December, 31, 0, 0)},
+		{"never", "0 0 30 2 *", now, time.Time{}},
+		{"every 90 minutes from the epoch", "@every 90m", now, at(time.October, 19, 10, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.spec)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(tt.from))
+		})
+	}
+}
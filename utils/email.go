@@ -1,7 +1,7 @@
 package utils
 
 import (
-	"bytes" // Required for parsing HTML template
+	"bytes"         // Required for parsing HTML template
 	"html/template" // Required for parsing HTML template
 	"io"            // Required for gomail.SetCopyFunc
 
@@ -39,6 +39,12 @@ func SendMail(toEmail string, subject string, body string) error {
 
 // SendInvitationMail sends a styled HTML invitation email with an embedded QR code.
 func SendInvitationMail(toEmail string, subject string, templateData map[string]interface{}, qrCodeImage []byte) error {
+	return SendQRCodeMail(toEmail, subject, "invitation_mail.html", templateData, qrCodeImage)
+}
+
+// SendQRCodeMail sends one of the HTML templates in utils/email-template with
+// the QR code embedded as cid:qr_code_image.
+func SendQRCodeMail(toEmail string, subject string, templateName string, templateData map[string]interface{}, qrCodeImage []byte) error {
 	emailConfig, err := config.NewEmailConfig()
 	if err != nil {
 		return err
@@ -49,7 +55,7 @@ func SendInvitationMail(toEmail string, subject string, templateData map[string]
 	// or adjust path as needed e.g. using an absolute path or relative to GOPATH/module root.
 	// For simplicity, let's assume it's in a known relative path for now.
 	// This path might need to be configurable or determined more robustly in a real app.
-	tmpl, err := template.ParseFiles("utils/email-template/invitation_mail.html")
+	tmpl, err := template.ParseFiles("utils/email-template/" + templateName)
 	if err != nil {
 		return err // Could not parse template
 	}
@@ -97,3 +103,18 @@ func SendInvitationMail(toEmail string, subject string, templateData map[string]
 
 	return nil
 }
+
+// RenderMailTemplate executes one of the HTML templates in utils/email-template
+// so the result can be sent with SendMail.
+func RenderMailTemplate(name string, templateData interface{}) (string, error) {
+	tmpl, err := template.ParseFiles("utils/email-template/" + name)
+	if err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, templateData); err != nil {
+		return "", err
+	}
+	return body.String(), nil
+}
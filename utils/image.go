@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif" // register the GIF decoder for image.Decode
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+)
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image format")
+	ErrImageTooLarge    = errors.New("image dimensions are too large")
+)
+
+// MaxImagePixels caps width x height of decoded images. A small compressed
+// file can claim huge dimensions and would allocate gigabytes when decoded.
+const MaxImagePixels = 40_000_000
+
+// ImageExtensions maps the accepted image MIME types to the extension used
+// when storing them.
+var ImageExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// DetectContentType sniffs the MIME type from the first 512 bytes of data
+// instead of trusting the Content-Type sent by the client.
+func DetectContentType(data []byte) string {
+	return http.DetectContentType(data)
+}
+
+// MakeThumbnail decodes an image and scales it down so that neither side
+// exceeds maxSize, keeping the aspect ratio. Images that are already small
+// enough are re-encoded unchanged. PNG input stays PNG, everything else is
+// encoded as JPEG. Images over MaxImagePixels are rejected before decoding.
+func MakeThumbnail(r io.Reader, maxSize int) ([]byte, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedImage
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxImagePixels {
+		return nil, "", ErrImageTooLarge
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedImage
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxSize || height > maxSize {
+		if width >= height {
+			height = max(1, height*maxSize/width)
+			width = maxSize
+		} else {
+			width = max(1, width*maxSize/height)
+			height = maxSize
+		}
+	}
+	thumb := scaleImage(src, width, height)
+
+	var buf bytes.Buffer
+	if format == "png" {
+		if err := png.Encode(&buf, thumb); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "png", nil
+	}
+	if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "jpg", nil
+}
+
+// scaleImage resizes src to width x height by averaging the source pixels
+// that fall into each destination pixel (box filter).
+func scaleImage(src image.Image, width int, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*srcH/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcH/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*srcW/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcW/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// claimPNGSize rewrites the IHDR chunk so the header claims width x height
+// while the pixel data stays tiny, like a decompression bomb would
+func claimPNGSize(data []byte, width, height uint32) []byte {
+	data = bytes.Clone(data)
+	// 8 byte signature, 4 byte length, then "IHDR" and its 13 data bytes
+	binary.BigEndian.PutUint32(data[16:20], width)
+	binary.BigEndian.PutUint32(data[20:24], height)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func Test_MakeThumbnail(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       []byte
+		maxSize    int
+		wantErr    error
+		wantFormat string
+		wantWidth  int
+		wantHeight int
+	}{
+		{"small png is kept", encodePNG(t, 20, 10), 100, nil, "png", 20, 10},
+		{"wide png is scaled down", encodePNG(t, 400, 100), 100, nil, "png", 100, 25},
+		{"tall png is scaled down", encodePNG(t, 50, 200), 100, nil, "png", 25, 100},
+		{"not an image", []byte("plain text"), 100, ErrUnsupportedImage, "", 0, 0},
+		{"claims 50000x50000 pixels", claimPNGSize(encodePNG(t, 1, 1), 50000, 50000), 100, ErrImageTooLarge, "", 0, 0},
+		{"claims just over the cap", claimPNGSize(encodePNG(t, 1, 1), 8000, 5001), 100, ErrImageTooLarge, "", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thumb, format, err := MakeThumbnail(bytes.NewReader(tt.data), tt.maxSize)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantFormat, format)
+
+			config, _, err := image.DecodeConfig(bytes.NewReader(thumb))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantWidth, config.Width)
+			assert.Equal(t, tt.wantHeight, config.Height)
+		})
+	}
+}
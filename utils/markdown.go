@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	mdHeadingRe     = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdOrderedRe     = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+	mdUnorderedRe   = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	mdRuleRe        = regexp.MustCompile(`^(-{3,}|\*{3,}|_{3,})$`)
+	mdLinkRe        = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdBoldRe        = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	mdItalicStarRe  = regexp.MustCompile(`\*([^*]+)\*`)
+	mdItalicUnderRe = regexp.MustCompile(`(^|[^\w])_([^_]+)_([^\w]|$)`)
+	mdStrikeRe      = regexp.MustCompile(`~~(.+?)~~`)
+)
+
+// RenderMarkdown converts a small, commonly used subset of Markdown
+// (headings, paragraphs, lists, block quotes, fenced code, emphasis and
+// links) to HTML. The source is HTML-escaped before any markup is added and
+// links are limited to http, https and mailto, so the output is safe to embed
+// without further sanitising.
+func RenderMarkdown(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	var out strings.Builder
+	var paragraph []string
+	listTag := ""
+	inCode := false
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + strings.Join(paragraph, "<br>") + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if listTag != "" {
+			out.WriteString("</" + listTag + ">\n")
+			listTag = ""
+		}
+	}
+	openList := func(tag string) {
+		if listTag != tag {
+			closeList()
+			out.WriteString("<" + tag + ">\n")
+			listTag = tag
+		}
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			if inCode {
+				out.WriteString("</code></pre>\n")
+			} else {
+				flushParagraph()
+				closeList()
+				out.WriteString("<pre><code>")
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out.WriteString(html.EscapeString(line) + "\n")
+			continue
+		}
+
+		if trimmed == "" {
+			flushParagraph()
+			closeList()
+			continue
+		}
+
+		if m := mdHeadingRe.FindStringSubmatch(trimmed); m != nil {
+			flushParagraph()
+			closeList()
+			level := string(rune('0' + len(m[1])))
+			out.WriteString("<h" + level + ">" + renderInline(m[2]) + "</h" + level + ">\n")
+			continue
+		}
+		if mdRuleRe.MatchString(trimmed) {
+			flushParagraph()
+			closeList()
+			out.WriteString("<hr>\n")
+			continue
+		}
+		if m := mdUnorderedRe.FindStringSubmatch(trimmed); m != nil {
+			flushParagraph()
+			openList("ul")
+			out.WriteString("<li>" + renderInline(m[1]) + "</li>\n")
+			continue
+		}
+		if m := mdOrderedRe.FindStringSubmatch(trimmed); m != nil {
+			flushParagraph()
+			openList("ol")
+			out.WriteString("<li>" + renderInline(m[1]) + "</li>\n")
+			continue
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			flushParagraph()
+			closeList()
+			quote := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			out.WriteString("<blockquote>" + renderInline(quote) + "</blockquote>\n")
+			continue
+		}
+
+		closeList()
+		paragraph = append(paragraph, renderInline(trimmed))
+	}
+
+	if inCode {
+		out.WriteString("</code></pre>\n")
+	}
+	flushParagraph()
+	closeList()
+
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+// renderInline escapes text and applies inline markup. Code spans are split
+// out first so their content is left untouched.
+func renderInline(text string) string {
+	parts := strings.Split(text, "`")
+	for i, part := range parts {
+		escaped := html.EscapeString(part)
+		// an unmatched trailing backtick is kept as text
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = "<code>" + escaped + "</code>"
+			continue
+		}
+		if i%2 == 1 {
+			escaped = "`" + escaped
+		}
+		parts[i] = renderEmphasis(escaped)
+	}
+	return strings.Join(parts, "")
+}
+
+func renderEmphasis(text string) string {
+	// links are swapped for placeholders so emphasis markers inside URLs
+	// are not rewritten
+	var links []string
+	text = mdLinkRe.ReplaceAllStringFunc(text, func(match string) string {
+		m := mdLinkRe.FindStringSubmatch(match)
+		if !isSafeLink(m[2]) {
+			return m[1]
+		}
+		links = append(links, `<a href="`+m[2]+`" rel="nofollow noopener" target="_blank">`+renderEmphasis(m[1])+`</a>`)
+		return "\x00" + strconv.Itoa(len(links)-1) + "\x00"
+	})
+
+	text = mdBoldRe.ReplaceAllString(text, "<strong>$2</strong>")
+	text = mdStrikeRe.ReplaceAllString(text, "<del>$1</del>")
+	text = mdItalicStarRe.ReplaceAllString(text, "<em>$1</em>")
+	text = mdItalicUnderRe.ReplaceAllString(text, "$1<em>$2</em>$3")
+
+	for i, link := range links {
+		text = strings.Replace(text, "\x00"+strconv.Itoa(i)+"\x00", link, 1)
+	}
+	return text
+}
+
+func isSafeLink(url string) bool {
+	lower := strings.ToLower(html.UnescapeString(url))
+	return strings.HasPrefix(lower, "http://") ||
+		strings.HasPrefix(lower, "https://") ||
+		strings.HasPrefix(lower, "mailto:")
+}
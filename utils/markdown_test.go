@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"paragraph", "hello\nworld", "<p>hello<br>world</p>"},
+		{"heading", "## Agenda", "<h2>Agenda</h2>"},
+		{"unordered list", "- one\n- two", "<ul>\n<li>one</li>\n<li>two</li>\n</ul>"},
+		{"ordered list", "1. one\n2) two", "<ol>\n<li>one</li>\n<li>two</li>\n</ol>"},
+		{"rule", "---", "<hr>"},
+		{"quote", "> quoted", "<blockquote>quoted</blockquote>"},
+		{"emphasis", "**bold** *it* ~~gone~~", "<p><strong>bold</strong> <em>it</em> <del>gone</del></p>"},
+		{"code span", "run `a*b*c`", "<p>run <code>a*b*c</code></p>"},
+		{"fenced code", "```\n<b>x</b>\n```", "<pre><code>&lt;b&gt;x&lt;/b&gt;\n</code></pre>"},
+		{"link", "[site](https://its.ac.id)", `<p><a href="https://its.ac.id" rel="nofollow noopener" target="_blank">site</a></p>`},
+		{"mailto link", "[mail](mailto:a@its.ac.id)", `<p><a href="mailto:a@its.ac.id" rel="nofollow noopener" target="_blank">mail</a></p>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RenderMarkdown(tt.src))
+		})
+	}
+}
+
+func Test_RenderMarkdown_Unsafe(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		forbidden []string
+	}{
+		{"script tag", "<script>alert(1)</script>", []string{"<script"}},
+		{"script tag in a heading", "# <script>alert(1)</script>", []string{"<script"}},
+		{"event handler", `<img src=x onerror="alert(1)">`, []string{"<img"}},
+		{"javascript link", "[click](javascript:alert(1))", []string{"href", "javascript:"}},
+		{"javascript link in mixed case", "[click](JavaScript:alert(1))", []string{"href"}},
+		{"data link", "[click](data:text/html;base64,PHNjcmlwdD4=)", []string{"href"}},
+		{"escaped javascript scheme", "[click](&#106;avascript:alert(1))", []string{"href"}},
+		{"quote breaking out of href", `[click](https://x.test/"onmouseover="alert(1))`, []string{`"onmouseover`}},
+		{"html in link text", "[<b onclick=alert(1)>x</b>](https://x.test)", []string{"<b"}},
+		{"html in a code span", "`<script>`", []string{"<script"}},
+		{"html in a list item", "- <iframe src=x>", []string{"<iframe"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderMarkdown(tt.src)
+			for _, s := range tt.forbidden {
+				assert.False(t, strings.Contains(got, s), "%q rendered as %q", tt.src, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var ErrInvalidStorageKey = errors.New("invalid storage key")
+
+// Storage abstracts where uploaded files live. Keys are slash separated,
+// relative paths such as "rooms/<room_id>/<file>".
+type Storage interface {
+	Save(key string, r io.Reader) error
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+	URL(key string) string
+}
+
+type localStorage struct {
+	root    string
+	baseURL string
+}
+
+// NewLocalStorage stores files below root on the local disk and serves them
+// from baseURL (the static route mounted in main.go).
+func NewLocalStorage(root string, baseURL string) Storage {
+	return &localStorage{
+		root:    root,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// CleanStorageKey normalises key and rejects anything that could escape the
+// storage root: absolute paths, ".." segments, backslashes and empty names.
+func CleanStorageKey(key string) (string, error) {
+	if key == "" || strings.Contains(key, "\\") || strings.ContainsRune(key, 0) {
+		return "", ErrInvalidStorageKey
+	}
+	if path.IsAbs(key) {
+		return "", ErrInvalidStorageKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." {
+			return "", ErrInvalidStorageKey
+		}
+	}
+
+	cleaned := path.Clean(key)
+	if cleaned == "." || strings.HasSuffix(key, "/") {
+		return "", ErrInvalidStorageKey
+	}
+	return cleaned, nil
+}
+
+func (s *localStorage) resolve(key string) (string, error) {
+	cleaned, err := CleanStorageKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
+
+func (s *localStorage) Save(key string, r io.Reader) error {
+	filePath, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	targetFile, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer targetFile.Close()
+
+	if _, err := io.Copy(targetFile, r); err != nil {
+		os.Remove(filePath)
+		return err
+	}
+	return nil
+}
+
+func (s *localStorage) Open(key string) (io.ReadCloser, error) {
+	filePath, err := s.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(filePath)
+}
+
+func (s *localStorage) Delete(key string) error {
+	filePath, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *localStorage) URL(key string) string {
+	cleaned, err := CleanStorageKey(key)
+	if err != nil {
+		return ""
+	}
+	return s.baseURL + "/" + cleaned
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CleanStorageKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{"plain key", "rooms/1/photo.jpg", "rooms/1/photo.jpg", false},
+		{"duplicate slashes", "rooms//1/photo.jpg", "rooms/1/photo.jpg", false},
+		{"current directory segments", "rooms/./1/photo.jpg", "rooms/1/photo.jpg", false},
+		{"empty", "", "", true},
+		{"absolute", "/etc/passwd", "", true},
+		{"parent segment", "../secret", "", true},
+		{"parent segment in the middle", "rooms/../../secret", "", true},
+		{"backslash", `rooms\..\secret`, "", true},
+		{"nul byte", "rooms/1/photo.jpg\x00.png", "", true},
+		{"trailing slash", "rooms/1/", "", true},
+		{"only a dot", ".", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CleanStorageKey(tt.key)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidStorageKey)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}